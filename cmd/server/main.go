@@ -93,9 +93,23 @@ func main() {
 	syncJobRepo := persistence.NewSyncJobRepository(db)
 	orderRepo := persistence.NewMarketplaceOrderRepository(db)
 	importedProductRepo := persistence.NewImportedProductRepository(db)
+	brandMappingRepo := persistence.NewBrandMappingRepository(db)
+	externalCategoryRepo := persistence.NewExternalCategoryRepository(db)
+	listingDriftRepo := persistence.NewListingDriftRepository(db)
+	pricingRuleRepo := persistence.NewPricingRuleRepository(db)
+	listingContentRepo := persistence.NewListingContentRepository(db)
+	autoListingRuleRepo := persistence.NewAutoListingRuleRepository(db)
+	saleCampaignRepo := persistence.NewSaleCampaignRepository(db)
+	warehouseRepo := persistence.NewWarehouseRepository(db)
+	inventoryDiscrepancyRepo := persistence.NewInventoryDiscrepancyRepository(db)
+	stockAllocationRuleRepo := persistence.NewStockAllocationRuleRepository(db)
+	inventorySyncLogRepo := persistence.NewInventorySyncLogRepository(db)
+	stockReservationRepo := persistence.NewStockReservationRepository(db)
+	orderSyncWatermarkRepo := persistence.NewOrderSyncWatermarkRepository(db)
 
 	// Initialize catalog client
 	catalogClient := clients.NewCatalogClient(cfg.Services.CatalogURL, logger)
+	inventoryClient := clients.NewInventoryClient(cfg.Services.InventoryURL, logger)
 
 	// Log repository initialization
 	logger.Info("Repositories initialized",
@@ -125,6 +139,25 @@ func main() {
 		logger.Fatal("Failed to initialize connection service", zap.Error(err))
 	}
 
+	// Initialize pricing service
+	pricingService := services.NewPricingService(connectionRepo, pricingRuleRepo, catalogClient, logger)
+
+	// Initialize stock allocation service (per-channel buffers, shares and caps)
+	stockAllocationService := services.NewStockAllocationService(
+		connectionRepo,
+		stockAllocationRuleRepo,
+		warehouseRepo,
+		stockReservationRepo,
+		catalogClient,
+		services.StockAllocationServiceConfig{
+			ReservationTTL: cfg.Sync.StockReservationTTL,
+		},
+		logger,
+	)
+
+	// Initialize listing content service (templates and per-product overrides)
+	listingContentService := services.NewListingContentService(connectionRepo, listingContentRepo, catalogClient, logger)
+
 	// Initialize product sync service
 	productSyncService, err := services.NewProductSyncService(
 		connectionRepo,
@@ -132,6 +165,16 @@ func main() {
 		categoryMappingRepo,
 		syncJobRepo,
 		importedProductRepo,
+		brandMappingRepo,
+		externalCategoryRepo,
+		listingDriftRepo,
+		autoListingRuleRepo,
+		saleCampaignRepo,
+		warehouseRepo,
+		inventorySyncLogRepo,
+		pricingService,
+		stockAllocationService,
+		listingContentService,
 		catalogClient,
 		&services.ProductSyncServiceConfig{
 			ShopeePartnerID:  cfg.Shopee.PartnerID,
@@ -147,10 +190,60 @@ func main() {
 		logger.Fatal("Failed to initialize product sync service", zap.Error(err))
 	}
 
+	// Resume product imports interrupted by a restart
+	productSyncService.ResumeImportJobs(context.Background())
+
+	// Drift checks interrupted by a restart would block new checks of their connection
+	productSyncService.FailInterruptedDriftChecks(context.Background())
+	productSyncService.FailInterruptedMappingImports(context.Background())
+
+	// Keep cached marketplace category trees fresh
+	categoryRefresher := services.NewCategoryRefresher(
+		connectionRepo,
+		externalCategoryRepo,
+		productSyncService,
+		services.CategoryRefresherConfig{
+			RefreshInterval: cfg.Sync.CategoryRefreshInterval,
+		},
+		logger,
+	)
+	if err := categoryRefresher.Start(context.Background()); err != nil {
+		logger.Warn("Failed to start category refresher", zap.Error(err))
+	}
+
+	// Periodically compare mapped listings with the catalog
+	driftChecker := services.NewDriftChecker(
+		connectionRepo,
+		productSyncService,
+		services.DriftCheckerConfig{
+			CheckInterval: cfg.Sync.DriftCheckInterval,
+		},
+		logger,
+	)
+	if err := driftChecker.Start(context.Background()); err != nil {
+		logger.Warn("Failed to start drift checker", zap.Error(err))
+	}
+
+	// Periodically renew sale campaigns capped by the marketplace's maximum duration
+	saleCampaignRenewer := services.NewSaleCampaignRenewer(
+		productSyncService,
+		services.SaleCampaignRenewerConfig{
+			RenewInterval: cfg.Sync.SaleRenewalInterval,
+		},
+		logger,
+	)
+	if err := saleCampaignRenewer.Start(context.Background()); err != nil {
+		logger.Warn("Failed to start sale campaign renewer", zap.Error(err))
+	}
+
 	// Initialize handlers
 	connectionHandler := handlers.NewConnectionHandler(connectionService, logger)
 	productHandler := handlers.NewProductHandler(productSyncService, logger)
 	categoryHandler := handlers.NewCategoryHandler(productSyncService, logger)
+	brandHandler := handlers.NewBrandHandler(productSyncService, logger)
+	pricingHandler := handlers.NewPricingHandler(pricingService, logger)
+	stockHandler := handlers.NewStockAllocationHandler(stockAllocationService, logger)
+	listingHandler := handlers.NewListingHandler(listingContentService, logger)
 
 	// Connect to NATS (optional - only if configured)
 	var natsConn *nats.Conn
@@ -171,14 +264,23 @@ func main() {
 	inventorySyncService, err := services.NewInventorySyncService(
 		connectionRepo,
 		productMappingRepo,
+		warehouseRepo,
+		inventoryDiscrepancyRepo,
+		inventorySyncLogRepo,
+		stockReservationRepo,
+		syncJobRepo,
+		inventoryClient,
+		stockAllocationService,
 		eventPublisher,
 		&services.InventorySyncServiceConfig{
-			ShopeePartnerID:  cfg.Shopee.PartnerID,
-			ShopeePartnerKey: cfg.Shopee.PartnerKey,
-			ShopeeSandbox:    cfg.Shopee.IsSandbox,
-			TikTokAppKey:     cfg.TikTok.AppKey,
-			TikTokAppSecret:  cfg.TikTok.AppSecret,
-			EncryptionKey:    cfg.Security.EncryptionKey,
+			ShopeePartnerID:   cfg.Shopee.PartnerID,
+			ShopeePartnerKey:  cfg.Shopee.PartnerKey,
+			ShopeeSandbox:     cfg.Shopee.IsSandbox,
+			TikTokAppKey:      cfg.TikTok.AppKey,
+			TikTokAppSecret:   cfg.TikTok.AppSecret,
+			EncryptionKey:     cfg.Security.EncryptionKey,
+			AutoCorrectStock:  cfg.Sync.InventoryAutoCorrect,
+			StockUpdateWindow: cfg.Sync.StockUpdateWindow,
 		},
 		logger,
 	)
@@ -186,19 +288,43 @@ func main() {
 		logger.Fatal("Failed to initialize inventory sync service", zap.Error(err))
 	}
 
+	// Reconciliations interrupted by a restart would block new ones of their connection
+	inventorySyncService.FailInterruptedReconciliations(context.Background())
+
+	// Periodically compare marketplace stock with the inventory service
+	inventoryReconciler := services.NewInventoryReconciler(
+		connectionRepo,
+		inventorySyncService,
+		services.InventoryReconcilerConfig{
+			ReconcileInterval: cfg.Sync.InventoryReconcileInterval,
+		},
+		logger,
+	)
+	if err := inventoryReconciler.Start(context.Background()); err != nil {
+		logger.Warn("Failed to start inventory reconciler", zap.Error(err))
+	}
+
 	// Initialize marketplace sync handler for auto-sync when products are updated
 	marketplaceSyncHandler, err := services.NewMarketplaceSyncHandler(
 		connectionRepo,
 		productMappingRepo,
 		categoryMappingRepo,
+		pricingService,
+		listingContentService,
+		productSyncService,
+		inventorySyncService,
 		catalogClient,
 		eventPublisher,
 		&services.MarketplaceSyncHandlerConfig{
 			ShopeePartnerID:  cfg.Shopee.PartnerID,
 			ShopeePartnerKey: cfg.Shopee.PartnerKey,
 			ShopeeSandbox:    cfg.Shopee.IsSandbox,
+			TikTokAppKey:     cfg.TikTok.AppKey,
+			TikTokAppSecret:  cfg.TikTok.AppSecret,
 			EncryptionKey:    cfg.Security.EncryptionKey,
 			AutoSyncEnabled:  true, // Enable auto-sync by default
+
+			PauseInactiveProducts: cfg.Sync.PauseInactiveProducts,
 		},
 		logger,
 	)
@@ -231,14 +357,19 @@ func main() {
 	orderSyncService, err := services.NewOrderSyncService(
 		connectionRepo,
 		orderRepo,
+		orderSyncWatermarkRepo,
+		syncJobRepo,
 		orderClient,
+		inventorySyncService,
 		&services.OrderSyncServiceConfig{
-			ShopeePartnerID:  cfg.Shopee.PartnerID,
-			ShopeePartnerKey: cfg.Shopee.PartnerKey,
-			ShopeeSandbox:    cfg.Shopee.IsSandbox,
-			TikTokAppKey:     cfg.TikTok.AppKey,
-			TikTokAppSecret:  cfg.TikTok.AppSecret,
-			EncryptionKey:    cfg.Security.EncryptionKey,
+			ShopeePartnerID:         cfg.Shopee.PartnerID,
+			ShopeePartnerKey:        cfg.Shopee.PartnerKey,
+			ShopeeSandbox:           cfg.Shopee.IsSandbox,
+			TikTokAppKey:            cfg.TikTok.AppKey,
+			TikTokAppSecret:         cfg.TikTok.AppSecret,
+			EncryptionKey:           cfg.Security.EncryptionKey,
+			PollOverlap:             cfg.Sync.OrderPollOverlap,
+			BackfillRequestInterval: cfg.Sync.OrderBackfillInterval,
 		},
 		logger,
 	)
@@ -246,11 +377,27 @@ func main() {
 		logger.Fatal("Failed to initialize order sync service", zap.Error(err))
 	}
 
+	// Resume order backfills interrupted by a restart
+	orderSyncService.ResumeBackfillJobs(context.Background())
+
+	// Periodically poll updated orders in case webhooks were missed
+	orderPoller := services.NewOrderPoller(
+		connectionRepo,
+		orderSyncService,
+		services.OrderPollerConfig{
+			PollInterval: cfg.Sync.OrderPollInterval,
+		},
+		logger,
+	)
+	if err := orderPoller.Start(context.Background()); err != nil {
+		logger.Warn("Failed to start order poller", zap.Error(err))
+	}
+
 	// Initialize order handler
 	orderHandler := handlers.NewOrderHandler(orderSyncService, logger)
 
 	// Initialize webhook handler
-	webhookHandler := handlers.NewWebhookHandler(orderSyncService, &handlers.WebhookConfig{
+	webhookHandler := handlers.NewWebhookHandler(orderSyncService, productSyncService, &handlers.WebhookConfig{
 		ShopeePartnerKey: cfg.Shopee.PartnerKey,
 		TikTokAppSecret:  cfg.TikTok.AppSecret,
 	}, logger)
@@ -296,7 +443,11 @@ func main() {
 		ConnectionHandler: connectionHandler,
 		ProductHandler:    productHandler,
 		CategoryHandler:   categoryHandler,
+		BrandHandler:      brandHandler,
+		PricingHandler:    pricingHandler,
+		ListingHandler:    listingHandler,
 		InventoryHandler:  inventoryHandler,
+		StockHandler:      stockHandler,
 		OrderHandler:      orderHandler,
 		WebhookHandler:    webhookHandler,
 		JWTManager:        jwtManager,
@@ -325,6 +476,13 @@ func main() {
 	<-quit
 
 	logger.Info("Shutting down server...")
+	categoryRefresher.Stop()
+	driftChecker.Stop()
+	saleCampaignRenewer.Stop()
+	inventoryReconciler.Stop()
+	orderPoller.Stop()
+	inventorySyncService.Stop()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/clients"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+)
+
+var ErrAutoListingRuleNotFound = errors.New("auto-listing rule not found")
+
+// GetAutoListingRule retrieves the auto-listing rule of a connection
+func (s *ProductSyncService) GetAutoListingRule(ctx context.Context, connectionID uuid.UUID) (*domain.AutoListingRule, error) {
+	rule, err := s.autoListingRuleRepo.GetByConnectionID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrAutoListingRuleNotFound
+	}
+	return rule, nil
+}
+
+// SaveAutoListingRule creates or replaces the auto-listing rule of a connection
+func (s *ProductSyncService) SaveAutoListingRule(ctx context.Context, connectionID uuid.UUID, req *domain.SaveAutoListingRuleRequest) (*domain.AutoListingRule, error) {
+	// Verify connection exists
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	rule, _ := s.autoListingRuleRepo.GetByConnectionID(ctx, connectionID)
+	if rule == nil {
+		rule = &domain.AutoListingRule{ConnectionID: connectionID}
+	}
+
+	categoryIDs := make([]string, 0, len(req.CategoryIDs))
+	for _, id := range req.CategoryIDs {
+		categoryIDs = append(categoryIDs, id.String())
+	}
+	rule.CategoryIDs = marshalJSON(categoryIDs, "[]")
+	rule.MinStock = req.MinStock
+	rule.RequireImages = true
+	if req.RequireImages != nil {
+		rule.RequireImages = *req.RequireImages
+	}
+	rule.InitialState = req.InitialState
+	if rule.InitialState == "" {
+		rule.InitialState = domain.AutoListDraft
+	}
+	rule.IsActive = true
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if err := s.autoListingRuleRepo.Save(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to save auto-listing rule: %w", err)
+	}
+	return rule, nil
+}
+
+// DeleteAutoListingRule deletes the auto-listing rule of a connection, turning auto-listing off
+func (s *ProductSyncService) DeleteAutoListingRule(ctx context.Context, connectionID uuid.UUID) error {
+	if _, err := s.autoListingRuleRepo.GetByConnectionID(ctx, connectionID); err != nil {
+		return ErrAutoListingRuleNotFound
+	}
+	return s.autoListingRuleRepo.Delete(ctx, connectionID)
+}
+
+// AutoListProduct enqueues a push job for a newly created catalog product on every active
+// connection whose auto-listing rule it matches. Products that do not match, are already
+// mapped or fail validation are skipped and logged.
+func (s *ProductSyncService) AutoListProduct(ctx context.Context, productID string) ([]*domain.SyncJob, error) {
+	rules, err := s.autoListingRuleRepo.GetActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load auto-listing rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	product, err := s.catalogClient.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product from catalog: %w", err)
+	}
+	internalID, err := uuid.Parse(product.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid product ID: %w", err)
+	}
+
+	// Category ancestors are only needed when a rule is limited to categories
+	var ancestors map[string]bool
+	for i := range rules {
+		if len(rules[i].GetCategoryIDs()) > 0 {
+			ancestors = s.categoryAncestors(ctx, product.CategoryID)
+			break
+		}
+	}
+
+	var jobs []*domain.SyncJob
+	for i := range rules {
+		rule := &rules[i]
+		logger := s.logger.With(
+			zap.String("connection_id", rule.ConnectionID.String()),
+			zap.String("product_id", product.ID),
+		)
+
+		if reason := autoListingMismatch(rule, product, ancestors); reason != "" {
+			logger.Debug("Product does not match auto-listing rule", zap.String("reason", reason))
+			continue
+		}
+
+		conn, err := s.connectionRepo.GetByID(ctx, rule.ConnectionID)
+		if err != nil || !conn.IsActive {
+			continue
+		}
+		if existing, _ := s.productMappingRepo.GetByConnectionAndInternalProduct(ctx, conn.ID, internalID); existing != nil {
+			logger.Debug("Product already mapped, skipping auto-listing")
+			continue
+		}
+
+		report, err := s.validateCatalogProducts(ctx, conn, []string{product.ID}, []clients.Product{*product})
+		if err != nil {
+			logger.Warn("Failed to validate product for auto-listing", zap.Error(err))
+			continue
+		}
+		if len(report.ValidProductIDs()) == 0 {
+			logger.Info("Product failed validation, skipping auto-listing",
+				zap.Any("errors", append(report.ShopErrors, report.Products[0].Errors...)),
+			)
+			continue
+		}
+
+		job, err := s.queueProductPush(ctx, conn, &domain.ProductPushPayload{
+			ProductIDs: []string{product.ID},
+			AsDraft:    rule.InitialState == domain.AutoListDraft,
+			Trigger:    domain.PushTriggerAutoListing,
+		})
+		if err != nil {
+			logger.Error("Failed to queue auto-listing push", zap.Error(err))
+			continue
+		}
+
+		logger.Info("Product queued for auto-listing",
+			zap.String("job_id", job.ID.String()),
+			zap.String("initial_state", rule.InitialState),
+		)
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// autoListingMismatch returns why a product does not match an auto-listing rule, or "" if it does
+func autoListingMismatch(rule *domain.AutoListingRule, product *clients.Product, ancestors map[string]bool) string {
+	if categoryIDs := rule.GetCategoryIDs(); len(categoryIDs) > 0 {
+		matched := false
+		for _, id := range categoryIDs {
+			if ancestors[id] {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return "category not included"
+		}
+	}
+	if product.StockQuantity < rule.MinStock {
+		return fmt.Sprintf("stock %d below minimum %d", product.StockQuantity, rule.MinStock)
+	}
+	if rule.RequireImages && len(product.Images) == 0 {
+		return "product has no images"
+	}
+	return ""
+}
+
+// categoryAncestors returns a category and all of its ancestors. If the catalog tree is
+// unavailable only the category itself is returned.
+func (s *ProductSyncService) categoryAncestors(ctx context.Context, categoryID string) map[string]bool {
+	ancestors := map[string]bool{categoryID: true}
+
+	categories, err := s.catalogClient.GetCategories(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to fetch categories, subcategories will not match auto-listing rules", zap.Error(err))
+		return ancestors
+	}
+	for _, id := range categoryAncestry(categories, categoryID) {
+		ancestors[id] = true
+	}
+	return ancestors
+}
+
+// categoryAncestry returns a category followed by its ancestors, nearest first
+func categoryAncestry(categories []clients.Category, categoryID string) []string {
+	parents := make(map[string]string, len(categories))
+	for _, cat := range categories {
+		parents[cat.ID] = cat.ParentID
+	}
+
+	ancestry := []string{categoryID}
+	seen := map[string]bool{categoryID: true}
+	for parent := parents[categoryID]; parent != "" && !seen[parent]; parent = parents[parent] {
+		seen[parent] = true
+		ancestry = append(ancestry, parent)
+	}
+	return ancestry
+}
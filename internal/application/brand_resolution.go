@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+	"github.com/Ecom-micro-template/service-marketplace/internal/utils"
+)
+
+var (
+	ErrBrandMappingNotFound  = errors.New("brand mapping not found")
+	ErrBrandCategoryRequired = errors.New("category_id is required to look up shopee brands")
+)
+
+// brandSuggestionThreshold is the minimum similarity score for a brand to be suggested
+const brandSuggestionThreshold = 0.6
+
+// brandLister fetches the marketplace brands available for a category and whether
+// the category requires a brand
+type brandLister func(ctx context.Context, categoryID string) ([]providers.ExternalBrand, bool, error)
+
+// brandListerFor returns the brand lookup function for a connection's platform
+func (s *ProductSyncService) brandListerFor(conn *domain.Connection, accessToken string) (brandLister, error) {
+	switch conn.Platform {
+	case "shopee":
+		shopID, _ := strconv.ParseInt(conn.ShopID, 10, 64)
+		_, productProvider := s.shopeeClientFactory(accessToken, shopID)
+		return func(ctx context.Context, categoryID string) ([]providers.ExternalBrand, bool, error) {
+			if categoryID == "" {
+				return nil, false, ErrBrandCategoryRequired
+			}
+			return productProvider.GetBrandList(ctx, categoryID)
+		}, nil
+
+	case "tiktok":
+		_, productProvider := s.tiktokClientFactory(accessToken, conn.ShopID)
+		return func(ctx context.Context, categoryID string) ([]providers.ExternalBrand, bool, error) {
+			brands, err := productProvider.GetBrands(ctx, categoryID)
+			return brands, false, err
+		}, nil
+
+	default:
+		return nil, ErrInvalidPlatform
+	}
+}
+
+// GetExternalBrands fetches brands from the marketplace for a category
+func (s *ProductSyncService) GetExternalBrands(ctx context.Context, connectionID uuid.UUID, categoryID string) ([]providers.ExternalBrand, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	// Decrypt access token
+	accessToken := conn.AccessToken
+	if s.encryptor != nil {
+		accessToken, err = s.encryptor.Decrypt(conn.AccessToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt token: %w", err)
+		}
+	}
+
+	listBrands, err := s.brandListerFor(conn, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	brands, _, err := listBrands(ctx, categoryID)
+	return brands, err
+}
+
+// GetBrandMappings retrieves brand mappings for a connection
+func (s *ProductSyncService) GetBrandMappings(ctx context.Context, connectionID uuid.UUID, filter *domain.BrandMappingFilter) ([]domain.BrandMapping, int64, error) {
+	return s.brandMappingRepo.GetByConnectionID(ctx, connectionID, filter)
+}
+
+// SuggestBrandMappings fuzzy-matches internal brands against the marketplace brand list
+// and stores the best match for each unconfirmed brand as a suggestion
+func (s *ProductSyncService) SuggestBrandMappings(ctx context.Context, connectionID uuid.UUID, req *domain.SuggestBrandMappingsRequest) ([]domain.BrandMapping, error) {
+	brands := req.Brands
+	if len(brands) == 0 {
+		products, err := s.catalogClient.GetAllProducts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch products from catalog: %w", err)
+		}
+		for _, p := range products {
+			brands = append(brands, p.Brand)
+		}
+	}
+
+	externalBrands, err := s.GetExternalBrands(ctx, connectionID, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]domain.BrandMapping, 0)
+	seen := make(map[string]bool)
+	for _, brand := range brands {
+		key := utils.NormalizeName(brand)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		// Never overwrite an admin decision
+		existing, _ := s.brandMappingRepo.GetByConnectionAndBrand(ctx, connectionID, brand)
+		if existing != nil && existing.IsConfirmed() {
+			continue
+		}
+
+		match, score := bestBrandMatch(brand, externalBrands)
+		if match == nil || score < brandSuggestionThreshold {
+			continue
+		}
+
+		mapping := &domain.BrandMapping{
+			ConnectionID:      connectionID,
+			InternalBrand:     brand,
+			ExternalBrandID:   match.BrandID,
+			ExternalBrandName: match.BrandName,
+			Status:            domain.BrandMappingStatusSuggested,
+			Confidence:        score,
+		}
+		if err := s.brandMappingRepo.Upsert(ctx, mapping); err != nil {
+			return nil, fmt.Errorf("failed to save brand suggestion: %w", err)
+		}
+		suggestions = append(suggestions, *mapping)
+	}
+
+	return suggestions, nil
+}
+
+// ConfirmBrandMapping marks a suggested brand mapping as confirmed
+func (s *ProductSyncService) ConfirmBrandMapping(ctx context.Context, connectionID, mappingID uuid.UUID) (*domain.BrandMapping, error) {
+	mapping, err := s.brandMappingRepo.GetByID(ctx, mappingID)
+	if err != nil || mapping.ConnectionID != connectionID {
+		return nil, ErrBrandMappingNotFound
+	}
+
+	mapping.Status = domain.BrandMappingStatusConfirmed
+	if err := s.brandMappingRepo.Update(ctx, mapping); err != nil {
+		return nil, fmt.Errorf("failed to confirm brand mapping: %w", err)
+	}
+
+	return mapping, nil
+}
+
+// SetBrandMapping creates or overrides a brand mapping as confirmed
+func (s *ProductSyncService) SetBrandMapping(ctx context.Context, connectionID uuid.UUID, req *domain.SetBrandMappingRequest) (*domain.BrandMapping, error) {
+	// Verify connection exists
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	mapping := &domain.BrandMapping{
+		ConnectionID:      connectionID,
+		InternalBrand:     req.InternalBrand,
+		ExternalBrandID:   req.ExternalBrandID,
+		ExternalBrandName: req.ExternalBrandName,
+		Status:            domain.BrandMappingStatusConfirmed,
+		Confidence:        1,
+	}
+
+	existing, _ := s.brandMappingRepo.GetByConnectionAndBrand(ctx, connectionID, req.InternalBrand)
+	if existing != nil {
+		existing.ExternalBrandID = mapping.ExternalBrandID
+		existing.ExternalBrandName = mapping.ExternalBrandName
+		existing.Status = mapping.Status
+		existing.Confidence = mapping.Confidence
+		if err := s.brandMappingRepo.Update(ctx, existing); err != nil {
+			return nil, fmt.Errorf("failed to update brand mapping: %w", err)
+		}
+		return existing, nil
+	}
+
+	if err := s.brandMappingRepo.Create(ctx, mapping); err != nil {
+		return nil, fmt.Errorf("failed to create brand mapping: %w", err)
+	}
+
+	return mapping, nil
+}
+
+// DeleteBrandMapping deletes a brand mapping of a connection
+func (s *ProductSyncService) DeleteBrandMapping(ctx context.Context, connectionID, mappingID uuid.UUID) error {
+	mapping, err := s.brandMappingRepo.GetByID(ctx, mappingID)
+	if err != nil || mapping.ConnectionID != connectionID {
+		return ErrBrandMappingNotFound
+	}
+	return s.brandMappingRepo.Delete(ctx, mappingID)
+}
+
+// brandResolver resolves internal brand names to marketplace brand IDs during a push job.
+// Brand lists are cached per category for the lifetime of the resolver.
+type brandResolver struct {
+	service      *ProductSyncService
+	connectionID uuid.UUID
+	listBrands   brandLister
+	cache        map[string]*categoryBrands
+}
+
+// categoryBrands is the cached brand list of a marketplace category
+type categoryBrands struct {
+	brands    []providers.ExternalBrand
+	mandatory bool
+}
+
+// brandLookup is the outcome of matching an internal brand against a category's brands
+type brandLookup struct {
+	BrandID   string                   // Brand ID to push, empty for "No Brand"
+	BrandName string                   // Marketplace name of BrandID
+	Mandatory bool                     // Whether the category requires a brand
+	Match     *providers.ExternalBrand // Best fuzzy match from the brand list, if any
+	Score     float64
+	existing  *domain.BrandMapping
+}
+
+// newBrandResolver creates a resolver for a single push job
+func (s *ProductSyncService) newBrandResolver(connectionID uuid.UUID, listBrands brandLister) *brandResolver {
+	return &brandResolver{
+		service:      s,
+		connectionID: connectionID,
+		listBrands:   listBrands,
+		cache:        make(map[string]*categoryBrands),
+	}
+}
+
+// categoryBrands returns the (cached) brand list for a category
+func (r *brandResolver) categoryBrands(ctx context.Context, categoryID string) *categoryBrands {
+	if cached, ok := r.cache[categoryID]; ok {
+		return cached
+	}
+
+	cached := &categoryBrands{}
+	if r.listBrands != nil {
+		brands, mandatory, err := r.listBrands(ctx, categoryID)
+		if err != nil {
+			r.service.logger.Warn("Failed to fetch marketplace brands",
+				zap.String("category_id", categoryID),
+				zap.Error(err),
+			)
+		}
+		cached.brands = brands
+		cached.mandatory = mandatory
+	}
+	r.cache[categoryID] = cached
+	return cached
+}
+
+// Lookup matches a brand without persisting anything.
+// Confirmed mappings always win. Otherwise only an exact (normalized) name match in the
+// category's brand list is used; weaker matches are returned as a suggestion.
+func (r *brandResolver) Lookup(ctx context.Context, brand, categoryID string) *brandLookup {
+	category := r.categoryBrands(ctx, categoryID)
+	result := &brandLookup{Mandatory: category.mandatory}
+
+	if utils.NormalizeName(brand) == "" {
+		return result
+	}
+
+	existing, _ := r.service.brandMappingRepo.GetByConnectionAndBrand(ctx, r.connectionID, brand)
+	result.existing = existing
+	if existing != nil && (existing.IsConfirmed() || existing.Confidence >= 1) {
+		result.BrandID = existing.ExternalBrandID
+		result.BrandName = existing.ExternalBrandName
+		result.Score = 1
+		return result
+	}
+
+	match, score := bestBrandMatch(brand, category.brands)
+	if match == nil || score < brandSuggestionThreshold {
+		return result
+	}
+
+	result.Match = match
+	result.Score = score
+	if score >= 1 {
+		result.BrandID = match.BrandID
+		result.BrandName = match.BrandName
+	}
+	return result
+}
+
+// Resolve returns the external brand ID and name for a brand, or "" to push as "No Brand".
+// New matches are recorded as suggestions for an admin to confirm.
+func (r *brandResolver) Resolve(ctx context.Context, brand, categoryID string) (string, string) {
+	lookup := r.Lookup(ctx, brand, categoryID)
+
+	if lookup.Match != nil && (lookup.existing == nil || lookup.Score > lookup.existing.Confidence) {
+		suggestion := &domain.BrandMapping{
+			ConnectionID:      r.connectionID,
+			InternalBrand:     brand,
+			ExternalBrandID:   lookup.Match.BrandID,
+			ExternalBrandName: lookup.Match.BrandName,
+			Status:            domain.BrandMappingStatusSuggested,
+			Confidence:        lookup.Score,
+		}
+		if err := r.service.brandMappingRepo.Upsert(ctx, suggestion); err != nil {
+			r.service.logger.Warn("Failed to save brand suggestion", zap.String("brand", brand), zap.Error(err))
+		}
+	}
+
+	return lookup.BrandID, lookup.BrandName
+}
+
+// bestBrandMatch returns the most similar marketplace brand and its score
+func bestBrandMatch(brand string, candidates []providers.ExternalBrand) (*providers.ExternalBrand, float64) {
+	var best *providers.ExternalBrand
+	bestScore := 0.0
+	for i := range candidates {
+		score := utils.NameSimilarity(brand, candidates[i].BrandName)
+		if candidates[i].DisplayName != "" {
+			score = max(score, utils.NameSimilarity(brand, candidates[i].DisplayName))
+		}
+		if score > bestScore {
+			best = &candidates[i]
+			bestScore = score
+		}
+	}
+	return best, bestScore
+}
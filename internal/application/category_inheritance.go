@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/clients"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+)
+
+// Sources of an effective category mapping
+const (
+	CategoryMappingDirect    = "direct"
+	CategoryMappingInherited = "inherited"
+	CategoryMappingNone      = "none"
+)
+
+// EffectiveCategoryMapping is the marketplace category that applies to an internal category
+type EffectiveCategoryMapping struct {
+	InternalCategoryID        string     `json:"internal_category_id"`
+	InternalCategoryName      string     `json:"internal_category_name"`
+	Breadcrumb                string     `json:"breadcrumb"`
+	Source                    string     `json:"source"` // direct, inherited or none
+	MappingID                 *uuid.UUID `json:"mapping_id,omitempty"`
+	ExternalCategoryID        string     `json:"external_category_id,omitempty"`
+	ExternalCategoryName      string     `json:"external_category_name,omitempty"`
+	InheritedFromCategoryID   string     `json:"inherited_from_category_id,omitempty"`
+	InheritedFromCategoryName string     `json:"inherited_from_category_name,omitempty"`
+	IsStale                   bool       `json:"is_stale"`
+}
+
+// categoryMappingResolver resolves the effective category mapping of internal categories.
+// A mapping applies to its own category and, when Inherit is set, to every descendant
+// without a mapping of its own. Mappings and the catalog tree are loaded once per resolver.
+type categoryMappingResolver struct {
+	mappings   map[string]*domain.CategoryMapping
+	categories map[string]*clients.Category
+}
+
+// newCategoryMappingResolver loads the connection's mappings and the catalog category tree.
+// If the catalog is unavailable only direct mappings are resolved.
+func (s *ProductSyncService) newCategoryMappingResolver(ctx context.Context, connectionID uuid.UUID) (*categoryMappingResolver, error) {
+	mappings, err := s.categoryMappingRepo.GetByConnectionID(ctx, connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load category mappings: %w", err)
+	}
+
+	r := &categoryMappingResolver{
+		mappings:   make(map[string]*domain.CategoryMapping, len(mappings)),
+		categories: make(map[string]*clients.Category),
+	}
+	for i := range mappings {
+		r.mappings[mappings[i].InternalCategoryID.String()] = &mappings[i]
+	}
+
+	categories, err := s.catalogClient.GetCategories(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to fetch categories, category mappings will not be inherited", zap.Error(err))
+		return r, nil
+	}
+	for i := range categories {
+		r.categories[categories[i].ID] = &categories[i]
+	}
+	return r, nil
+}
+
+// Resolve returns the mapping that applies to an internal category and the category it was
+// defined on, or nil if the category is unmapped
+func (r *categoryMappingResolver) Resolve(internalCategoryID string) (*domain.CategoryMapping, string) {
+	if mapping, ok := r.mappings[internalCategoryID]; ok {
+		return mapping, internalCategoryID
+	}
+
+	seen := map[string]bool{internalCategoryID: true}
+	cat := r.categories[internalCategoryID]
+	for cat != nil && cat.ParentID != "" && !seen[cat.ParentID] {
+		seen[cat.ParentID] = true
+		if mapping, ok := r.mappings[cat.ParentID]; ok {
+			if !mapping.Inherit {
+				return nil, ""
+			}
+			return mapping, cat.ParentID
+		}
+		cat = r.categories[cat.ParentID]
+	}
+	return nil, ""
+}
+
+// GetEffectiveCategoryMappings returns the effective mapping of every internal category,
+// including where inherited mappings come from
+func (s *ProductSyncService) GetEffectiveCategoryMappings(ctx context.Context, connectionID uuid.UUID) ([]EffectiveCategoryMapping, error) {
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	internalCategories, err := s.catalogClient.GetCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch categories from catalog: %w", err)
+	}
+
+	resolver, err := s.newCategoryMappingResolver(ctx, connectionID)
+	if err != nil {
+		return nil, err
+	}
+	internalPaths := buildInternalBreadcrumbs(internalCategories)
+
+	result := make([]EffectiveCategoryMapping, 0, len(internalCategories))
+	for _, cat := range internalCategories {
+		effective := EffectiveCategoryMapping{
+			InternalCategoryID:   cat.ID,
+			InternalCategoryName: cat.Name,
+			Breadcrumb:           internalPaths[cat.ID],
+			Source:               CategoryMappingNone,
+		}
+
+		mapping, fromID := resolver.Resolve(cat.ID)
+		if mapping != nil {
+			effective.MappingID = &mapping.ID
+			effective.ExternalCategoryID = mapping.ExternalCategoryID
+			effective.ExternalCategoryName = mapping.ExternalCategoryName
+			effective.IsStale = mapping.IsStale
+			effective.Source = CategoryMappingDirect
+			if fromID != cat.ID {
+				effective.Source = CategoryMappingInherited
+				effective.InheritedFromCategoryID = fromID
+				if from := resolver.categories[fromID]; from != nil {
+					effective.InheritedFromCategoryName = from.Name
+				}
+			}
+		}
+
+		result = append(result, effective)
+	}
+
+	return result, nil
+}
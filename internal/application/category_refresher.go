@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/infrastructure/persistence"
+)
+
+// CategoryRefresherConfig holds configuration for the category refresher.
+type CategoryRefresherConfig struct {
+	RefreshInterval time.Duration // How old a connection's category cache may get
+	CheckInterval   time.Duration // How often to look for stale caches
+}
+
+// CategoryRefresher keeps the cached marketplace category trees up to date.
+type CategoryRefresher struct {
+	connectionRepo       *persistence.ConnectionRepository
+	externalCategoryRepo *persistence.ExternalCategoryRepository
+	productSyncService   *ProductSyncService
+	config               CategoryRefresherConfig
+	logger               *zap.Logger
+
+	// Lifecycle management
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	running  bool
+	mu       sync.Mutex
+}
+
+// NewCategoryRefresher creates a new category refresher.
+func NewCategoryRefresher(
+	connectionRepo *persistence.ConnectionRepository,
+	externalCategoryRepo *persistence.ExternalCategoryRepository,
+	productSyncService *ProductSyncService,
+	cfg CategoryRefresherConfig,
+	logger *zap.Logger,
+) *CategoryRefresher {
+	// Set defaults
+	if cfg.RefreshInterval == 0 {
+		cfg.RefreshInterval = 24 * time.Hour
+	}
+	if cfg.CheckInterval == 0 {
+		cfg.CheckInterval = time.Hour
+	}
+
+	return &CategoryRefresher{
+		connectionRepo:       connectionRepo,
+		externalCategoryRepo: externalCategoryRepo,
+		productSyncService:   productSyncService,
+		config:               cfg,
+		logger:               logger,
+		stopChan:             make(chan struct{}),
+	}
+}
+
+// Start begins the background category refresh process.
+func (cr *CategoryRefresher) Start(ctx context.Context) error {
+	cr.mu.Lock()
+	if cr.running {
+		cr.mu.Unlock()
+		return fmt.Errorf("category refresher already running")
+	}
+	cr.running = true
+	cr.mu.Unlock()
+
+	cr.wg.Add(1)
+	go cr.run(ctx)
+
+	cr.logger.Info("category refresher started",
+		zap.Duration("check_interval", cr.config.CheckInterval),
+		zap.Duration("refresh_interval", cr.config.RefreshInterval),
+	)
+
+	return nil
+}
+
+// Stop gracefully stops the category refresher.
+func (cr *CategoryRefresher) Stop() {
+	cr.mu.Lock()
+	if !cr.running {
+		cr.mu.Unlock()
+		return
+	}
+	cr.running = false
+	cr.mu.Unlock()
+
+	close(cr.stopChan)
+	cr.wg.Wait()
+
+	cr.logger.Info("category refresher stopped")
+}
+
+// run is the main background loop.
+func (cr *CategoryRefresher) run(ctx context.Context) {
+	defer cr.wg.Done()
+
+	ticker := time.NewTicker(cr.config.CheckInterval)
+	defer ticker.Stop()
+
+	// Do an initial check
+	cr.refreshStaleConnections(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-cr.stopChan:
+			return
+		case <-ticker.C:
+			cr.refreshStaleConnections(ctx)
+		}
+	}
+}
+
+// refreshStaleConnections refreshes every active connection whose category cache is older than the refresh interval.
+func (cr *CategoryRefresher) refreshStaleConnections(ctx context.Context) {
+	connections, err := cr.connectionRepo.GetActiveConnections(ctx)
+	if err != nil {
+		cr.logger.Error("failed to get active connections", zap.Error(err))
+		return
+	}
+
+	threshold := time.Now().Add(-cr.config.RefreshInterval)
+	for i := range connections {
+		conn := &connections[i]
+
+		last, err := cr.externalCategoryRepo.GetLastRefreshedAt(ctx, conn.ID)
+		if err != nil {
+			cr.logger.Error("failed to read category cache age",
+				zap.String("connection_id", conn.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		if last != nil && last.After(threshold) {
+			continue
+		}
+
+		if _, err := cr.productSyncService.refreshCategoryCache(ctx, conn); err != nil {
+			cr.logger.Error("failed to refresh external categories",
+				zap.String("connection_id", conn.ID.String()),
+				zap.String("platform", conn.Platform),
+				zap.String("shop_id", conn.ShopID),
+				zap.Error(err),
+			)
+		}
+	}
+}
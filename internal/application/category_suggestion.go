@@ -0,0 +1,400 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/clients"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/utils"
+)
+
+// Category suggestion scoring weights (sum to 1)
+const (
+	categoryNameWeight    = 0.45 // Internal category name vs. marketplace leaf name
+	categoryPathWeight    = 0.20 // Internal breadcrumb vs. marketplace breadcrumb
+	categoryProductWeight = 0.15 // Product names in the category vs. marketplace leaf name
+	categoryHistoryWeight = 0.20 // Share of other connections that chose the same leaf
+
+	// categorySuggestionThreshold is the minimum score for a leaf to be suggested
+	categorySuggestionThreshold = 0.2
+
+	defaultCategorySuggestionLimit = 3
+	maxCategorySuggestionLimit     = 10
+)
+
+// CategorySuggestionOptions controls which categories are suggested
+type CategorySuggestionOptions struct {
+	Limit         int  // Suggestions per internal category
+	IncludeMapped bool // Also suggest for categories that already have a mapping
+}
+
+// CategorySuggestions holds the ranked marketplace categories for one internal category
+type CategorySuggestions struct {
+	InternalCategoryID   string               `json:"internal_category_id"`
+	InternalCategoryName string               `json:"internal_category_name"`
+	Breadcrumb           string               `json:"breadcrumb"`
+	ProductCount         int                  `json:"product_count"`
+	MappedCategoryID     string               `json:"mapped_category_id,omitempty"`
+	Suggestions          []CategorySuggestion `json:"suggestions"`
+}
+
+// CategorySuggestion is a ranked marketplace leaf category
+type CategorySuggestion struct {
+	ExternalCategoryID   string   `json:"external_category_id"`
+	ExternalCategoryName string   `json:"external_category_name"`
+	Breadcrumb           string   `json:"breadcrumb"`
+	Score                float64  `json:"score"`
+	Reasons              []string `json:"reasons"`
+}
+
+// AcceptCategorySuggestionsResult is the outcome of a bulk accept
+type AcceptCategorySuggestionsResult struct {
+	Created []domain.CategoryMapping `json:"created"`
+	Failed  []CategoryMappingFailure `json:"failed"`
+}
+
+// CategoryMappingFailure reports a suggestion that could not be accepted
+type CategoryMappingFailure struct {
+	InternalCategoryID uuid.UUID `json:"internal_category_id"`
+	ExternalCategoryID string    `json:"external_category_id"`
+	Error              string    `json:"error"`
+}
+
+// externalLeaf is a marketplace leaf category prepared for scoring
+type externalLeaf struct {
+	categoryID string
+	name       string
+	breadcrumb string
+	tokens     []string
+}
+
+// externalLeafIndex holds the leaf categories of a connection as of one category tree
+// refresh, indexed by name token so only leaves sharing a token with an internal category
+// are scored
+type externalLeafIndex struct {
+	refreshedAt time.Time
+	leaves      []externalLeaf
+	byID        map[string]int
+	byToken     map[string][]int
+}
+
+// newExternalLeafIndex indexes the leaves of a cached category tree
+func newExternalLeafIndex(categories []domain.ExternalCategory, refreshedAt time.Time) *externalLeafIndex {
+	index := &externalLeafIndex{
+		refreshedAt: refreshedAt,
+		leaves:      buildExternalLeaves(categories),
+		byToken:     make(map[string][]int),
+	}
+	index.byID = make(map[string]int, len(index.leaves))
+	for i := range index.leaves {
+		index.byID[index.leaves[i].categoryID] = i
+		seen := make(map[string]bool, len(index.leaves[i].tokens))
+		for _, t := range index.leaves[i].tokens {
+			if !seen[t] {
+				seen[t] = true
+				index.byToken[t] = append(index.byToken[t], i)
+			}
+		}
+	}
+	return index
+}
+
+// candidates returns the positions of the leaves whose names share one of the tokens or
+// whose category IDs are given, in tree order
+func (index *externalLeafIndex) candidates(tokens map[string]bool, categoryIDs map[string]int) []int {
+	found := make(map[int]bool)
+	for t := range tokens {
+		for _, i := range index.byToken[t] {
+			found[i] = true
+		}
+	}
+	for id := range categoryIDs {
+		if i, ok := index.byID[id]; ok {
+			found[i] = true
+		}
+	}
+
+	positions := make([]int, 0, len(found))
+	for i := range found {
+		positions = append(positions, i)
+	}
+	sort.Ints(positions)
+	return positions
+}
+
+// externalLeafIndexCache keeps the latest leaf index of every connection. The zero value is
+// ready to use.
+type externalLeafIndexCache struct {
+	mu      sync.Mutex
+	indexes map[uuid.UUID]*externalLeafIndex
+}
+
+// externalLeafIndex returns the leaf index of a connection's cached category tree, rebuilt
+// only after the tree was refreshed
+func (s *ProductSyncService) externalLeafIndex(ctx context.Context, conn *domain.Connection) (*externalLeafIndex, error) {
+	last, err := s.externalCategoryRepo.GetLastRefreshedAt(ctx, conn.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read category cache: %w", err)
+	}
+
+	cache := &s.leafIndexes
+	if last != nil {
+		cache.mu.Lock()
+		index := cache.indexes[conn.ID]
+		cache.mu.Unlock()
+		if index != nil && index.refreshedAt.Equal(*last) {
+			return index, nil
+		}
+	}
+
+	categories, err := s.cachedExternalCategories(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	if last == nil {
+		// The tree was fetched for the first time; it is cached from the next call on
+		return newExternalLeafIndex(categories, time.Time{}), nil
+	}
+	index := newExternalLeafIndex(categories, *last)
+
+	cache.mu.Lock()
+	if cache.indexes == nil {
+		cache.indexes = make(map[uuid.UUID]*externalLeafIndex)
+	}
+	cache.indexes[conn.ID] = index
+	cache.mu.Unlock()
+	return index, nil
+}
+
+// SuggestCategoryMappings ranks marketplace leaf categories for each internal category.
+// Only leaves whose names share a token with the category's name, breadcrumb or product
+// names, or that other connections chose for it, are scored.
+func (s *ProductSyncService) SuggestCategoryMappings(ctx context.Context, connectionID uuid.UUID, opts CategorySuggestionOptions) ([]CategorySuggestions, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	if opts.Limit <= 0 {
+		opts.Limit = defaultCategorySuggestionLimit
+	}
+	opts.Limit = min(opts.Limit, maxCategorySuggestionLimit)
+
+	internalCategories, err := s.catalogClient.GetCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch categories from catalog: %w", err)
+	}
+
+	index, err := s.externalLeafIndex(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Existing mappings on this connection
+	mapped := make(map[string]string)
+	if mappings, err := s.categoryMappingRepo.GetByConnectionID(ctx, connectionID); err == nil {
+		for _, m := range mappings {
+			mapped[m.InternalCategoryID.String()] = m.ExternalCategoryID
+		}
+	}
+
+	// What other shops on the same platform chose for each internal category
+	history := make(map[string]map[string]int)
+	historyTotal := make(map[string]int)
+	if others, err := s.categoryMappingRepo.GetByPlatform(ctx, conn.Platform, connectionID); err == nil {
+		for _, m := range others {
+			key := m.InternalCategoryID.String()
+			if history[key] == nil {
+				history[key] = make(map[string]int)
+			}
+			history[key][m.ExternalCategoryID]++
+			historyTotal[key]++
+		}
+	} else {
+		s.logger.Warn("Failed to load category mappings of other connections", zap.Error(err))
+	}
+
+	// Product names per internal category
+	productTokens := make(map[string]map[string]int)
+	productCounts := make(map[string]int)
+	if products, err := s.catalogClient.GetAllProducts(ctx); err == nil {
+		for _, p := range products {
+			productCounts[p.CategoryID]++
+			if productTokens[p.CategoryID] == nil {
+				productTokens[p.CategoryID] = make(map[string]int)
+			}
+			seen := make(map[string]bool)
+			for _, t := range utils.Tokenize(p.Name) {
+				if !seen[t] {
+					seen[t] = true
+					productTokens[p.CategoryID][t]++
+				}
+			}
+		}
+	} else {
+		s.logger.Warn("Failed to fetch products for category suggestions", zap.Error(err))
+	}
+
+	internalPaths := buildInternalBreadcrumbs(internalCategories)
+
+	results := make([]CategorySuggestions, 0, len(internalCategories))
+	for _, cat := range internalCategories {
+		if mapped[cat.ID] != "" && !opts.IncludeMapped {
+			continue
+		}
+
+		result := CategorySuggestions{
+			InternalCategoryID:   cat.ID,
+			InternalCategoryName: cat.Name,
+			Breadcrumb:           internalPaths[cat.ID],
+			ProductCount:         productCounts[cat.ID],
+			MappedCategoryID:     mapped[cat.ID],
+			Suggestions:          []CategorySuggestion{},
+		}
+
+		tokens := make(map[string]bool)
+		for _, t := range utils.Tokenize(internalPaths[cat.ID]) {
+			tokens[t] = true
+		}
+		for t := range productTokens[cat.ID] {
+			tokens[t] = true
+		}
+
+		for _, i := range index.candidates(tokens, history[cat.ID]) {
+			leaf := &index.leaves[i]
+			suggestion := scoreCategoryLeaf(leaf, cat.Name, internalPaths[cat.ID],
+				productTokens[cat.ID], productCounts[cat.ID],
+				history[cat.ID][leaf.categoryID], historyTotal[cat.ID])
+			if suggestion.Score >= categorySuggestionThreshold {
+				result.Suggestions = append(result.Suggestions, suggestion)
+			}
+		}
+
+		sort.SliceStable(result.Suggestions, func(i, j int) bool {
+			return result.Suggestions[i].Score > result.Suggestions[j].Score
+		})
+		if len(result.Suggestions) > opts.Limit {
+			result.Suggestions = result.Suggestions[:opts.Limit]
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// AcceptCategorySuggestions creates (or replaces) category mappings from chosen suggestions
+func (s *ProductSyncService) AcceptCategorySuggestions(ctx context.Context, connectionID uuid.UUID, req *domain.AcceptCategorySuggestionsRequest) (*AcceptCategorySuggestionsResult, error) {
+	// Verify connection exists
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	result := &AcceptCategorySuggestionsResult{
+		Created: make([]domain.CategoryMapping, 0, len(req.Mappings)),
+		Failed:  []CategoryMappingFailure{},
+	}
+
+	for i := range req.Mappings {
+		mapping, err := s.CreateCategoryMapping(ctx, connectionID, &req.Mappings[i])
+		if err != nil {
+			result.Failed = append(result.Failed, CategoryMappingFailure{
+				InternalCategoryID: req.Mappings[i].InternalCategoryID,
+				ExternalCategoryID: req.Mappings[i].ExternalCategoryID,
+				Error:              err.Error(),
+			})
+			continue
+		}
+		result.Created = append(result.Created, *mapping)
+	}
+
+	return result, nil
+}
+
+// scoreCategoryLeaf combines every signal into a single suggestion score
+func scoreCategoryLeaf(leaf *externalLeaf, name, breadcrumb string, productTokens map[string]int, productCount, votes, totalVotes int) CategorySuggestion {
+	suggestion := CategorySuggestion{
+		ExternalCategoryID:   leaf.categoryID,
+		ExternalCategoryName: leaf.name,
+		Breadcrumb:           leaf.breadcrumb,
+		Reasons:              []string{},
+	}
+
+	nameScore := utils.NameSimilarity(name, leaf.name)
+	if nameScore > 0 {
+		suggestion.Reasons = append(suggestion.Reasons, fmt.Sprintf("name similarity %.2f", nameScore))
+	}
+
+	pathScore := utils.TokenSimilarity(breadcrumb, leaf.breadcrumb)
+	if pathScore > 0 {
+		suggestion.Reasons = append(suggestion.Reasons, fmt.Sprintf("breadcrumb similarity %.2f", pathScore))
+	}
+
+	// Share of products whose names mention the leaf category
+	productScore := 0.0
+	if productCount > 0 {
+		for _, t := range leaf.tokens {
+			productScore = max(productScore, float64(productTokens[t])/float64(productCount))
+		}
+		if productScore > 0 {
+			suggestion.Reasons = append(suggestion.Reasons, fmt.Sprintf("%.0f%% of product names match", productScore*100))
+		}
+	}
+
+	historyScore := 0.0
+	if totalVotes > 0 && votes > 0 {
+		historyScore = float64(votes) / float64(totalVotes)
+		suggestion.Reasons = append(suggestion.Reasons, fmt.Sprintf("mapped by %d other connection(s)", votes))
+	}
+
+	suggestion.Score = categoryNameWeight*nameScore +
+		categoryPathWeight*pathScore +
+		categoryProductWeight*productScore +
+		categoryHistoryWeight*historyScore
+	return suggestion
+}
+
+// buildExternalLeaves prepares the cached leaf categories for scoring
+func buildExternalLeaves(categories []domain.ExternalCategory) []externalLeaf {
+	leaves := make([]externalLeaf, 0)
+	for _, cat := range categories {
+		if !cat.IsLeaf {
+			continue
+		}
+		leaves = append(leaves, externalLeaf{
+			categoryID: cat.CategoryID,
+			name:       cat.Name,
+			breadcrumb: cat.Breadcrumb,
+			tokens:     utils.Tokenize(cat.Name),
+		})
+	}
+	return leaves
+}
+
+// buildInternalBreadcrumbs returns the "Parent > Child" path of every catalog category
+func buildInternalBreadcrumbs(categories []clients.Category) map[string]string {
+	byID := make(map[string]*clients.Category, len(categories))
+	for i := range categories {
+		byID[categories[i].ID] = &categories[i]
+	}
+
+	paths := make(map[string]string, len(categories))
+	for i := range categories {
+		var path []string
+		seen := make(map[string]bool)
+		for cat := &categories[i]; cat != nil && !seen[cat.ID]; cat = byID[cat.ParentID] {
+			seen[cat.ID] = true
+			path = append([]string{cat.Name}, path...)
+		}
+		paths[categories[i].ID] = strings.Join(path, " > ")
+	}
+	return paths
+}
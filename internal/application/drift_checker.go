@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/infrastructure/persistence"
+)
+
+// DriftCheckerConfig holds configuration for the drift checker.
+type DriftCheckerConfig struct {
+	CheckInterval time.Duration // How often every connection's listings are compared with the catalog
+}
+
+// DriftChecker periodically starts drift checks for all active connections.
+type DriftChecker struct {
+	connectionRepo     *persistence.ConnectionRepository
+	productSyncService *ProductSyncService
+	config             DriftCheckerConfig
+	logger             *zap.Logger
+
+	// Lifecycle management
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	running  bool
+	mu       sync.Mutex
+}
+
+// NewDriftChecker creates a new drift checker.
+func NewDriftChecker(
+	connectionRepo *persistence.ConnectionRepository,
+	productSyncService *ProductSyncService,
+	cfg DriftCheckerConfig,
+	logger *zap.Logger,
+) *DriftChecker {
+	// Set defaults
+	if cfg.CheckInterval == 0 {
+		cfg.CheckInterval = 6 * time.Hour
+	}
+
+	return &DriftChecker{
+		connectionRepo:     connectionRepo,
+		productSyncService: productSyncService,
+		config:             cfg,
+		logger:             logger,
+		stopChan:           make(chan struct{}),
+	}
+}
+
+// Start begins the background drift check process.
+func (dc *DriftChecker) Start(ctx context.Context) error {
+	dc.mu.Lock()
+	if dc.running {
+		dc.mu.Unlock()
+		return fmt.Errorf("drift checker already running")
+	}
+	dc.running = true
+	dc.mu.Unlock()
+
+	dc.wg.Add(1)
+	go dc.run(ctx)
+
+	dc.logger.Info("drift checker started",
+		zap.Duration("check_interval", dc.config.CheckInterval),
+	)
+
+	return nil
+}
+
+// Stop gracefully stops the drift checker.
+func (dc *DriftChecker) Stop() {
+	dc.mu.Lock()
+	if !dc.running {
+		dc.mu.Unlock()
+		return
+	}
+	dc.running = false
+	dc.mu.Unlock()
+
+	close(dc.stopChan)
+	dc.wg.Wait()
+
+	dc.logger.Info("drift checker stopped")
+}
+
+// run is the main background loop. The first check runs after one interval
+// so a restart does not hit the marketplace APIs for every connection at once.
+func (dc *DriftChecker) run(ctx context.Context) {
+	defer dc.wg.Done()
+
+	ticker := time.NewTicker(dc.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-dc.stopChan:
+			return
+		case <-ticker.C:
+			dc.checkConnections(ctx)
+		}
+	}
+}
+
+// checkConnections starts a drift check for every active connection.
+func (dc *DriftChecker) checkConnections(ctx context.Context) {
+	connections, err := dc.connectionRepo.GetActiveConnections(ctx)
+	if err != nil {
+		dc.logger.Error("failed to get active connections", zap.Error(err))
+		return
+	}
+
+	for i := range connections {
+		conn := &connections[i]
+		if conn.Platform != "shopee" && conn.Platform != "tiktok" {
+			continue
+		}
+		if _, err := dc.productSyncService.StartDriftCheck(ctx, conn.ID); err != nil && !errors.Is(err, ErrDriftCheckInProgress) {
+			dc.logger.Error("failed to start drift check",
+				zap.String("connection_id", conn.ID.String()),
+				zap.String("platform", conn.Platform),
+				zap.Error(err),
+			)
+		}
+	}
+}
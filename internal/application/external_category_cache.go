@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+var ErrEmptyCategoryTree = errors.New("marketplace returned no categories")
+
+// CategoryRefreshResult is the outcome of refreshing a connection's category cache
+type CategoryRefreshResult struct {
+	ConnectionID  uuid.UUID `json:"connection_id"`
+	Total         int       `json:"total"`
+	Added         int       `json:"added"`
+	Removed       []string  `json:"removed"`
+	StaleMappings int64     `json:"stale_mappings"`
+	RefreshedAt   time.Time `json:"refreshed_at"`
+}
+
+// RefreshExternalCategories re-fetches the marketplace category tree into the cache,
+// deactivates categories that disappeared and flags the mappings that point at them
+func (s *ProductSyncService) RefreshExternalCategories(ctx context.Context, connectionID uuid.UUID) (*CategoryRefreshResult, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+	return s.refreshCategoryCache(ctx, conn)
+}
+
+// refreshCategoryCache replaces the cached category tree of a connection with the marketplace's
+func (s *ProductSyncService) refreshCategoryCache(ctx context.Context, conn *domain.Connection) (*CategoryRefreshResult, error) {
+	fetched, err := s.fetchExternalCategories(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch categories from marketplace: %w", err)
+	}
+
+	// An empty tree is almost certainly an API hiccup; keep the existing cache
+	flat := flattenExternalCategories(fetched)
+	if len(flat) == 0 {
+		return nil, ErrEmptyCategoryTree
+	}
+
+	known := make(map[string]bool)
+	if ids, err := s.externalCategoryRepo.GetActiveIDs(ctx, conn.ID); err == nil {
+		for _, id := range ids {
+			known[id] = true
+		}
+	}
+
+	seenAt := time.Now()
+	categories := buildCachedCategories(conn.ID, flat, seenAt)
+
+	result := &CategoryRefreshResult{
+		ConnectionID: conn.ID,
+		Total:        len(categories),
+		RefreshedAt:  seenAt,
+	}
+	for _, cat := range categories {
+		if !known[cat.CategoryID] {
+			result.Added++
+		}
+	}
+
+	if err := s.externalCategoryRepo.UpsertBatch(ctx, categories); err != nil {
+		return nil, fmt.Errorf("failed to save categories: %w", err)
+	}
+
+	result.Removed, err = s.externalCategoryRepo.DeactivateMissing(ctx, conn.ID, seenAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deactivate removed categories: %w", err)
+	}
+	if result.Removed == nil {
+		result.Removed = []string{}
+	}
+
+	result.StaleMappings, err = s.categoryMappingRepo.UpdateStaleFlags(ctx, conn.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to flag stale category mappings: %w", err)
+	}
+
+	s.logger.Info("External categories refreshed",
+		zap.String("connection_id", conn.ID.String()),
+		zap.Int("total", result.Total),
+		zap.Int("added", result.Added),
+		zap.Int("removed", len(result.Removed)),
+		zap.Int64("stale_mappings", result.StaleMappings),
+	)
+
+	return result, nil
+}
+
+// cachedExternalCategories returns the cached categories of a connection, filling the cache on first use
+func (s *ProductSyncService) cachedExternalCategories(ctx context.Context, conn *domain.Connection) ([]domain.ExternalCategory, error) {
+	last, err := s.externalCategoryRepo.GetLastRefreshedAt(ctx, conn.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read category cache: %w", err)
+	}
+	if last == nil {
+		if _, err := s.refreshCategoryCache(ctx, conn); err != nil {
+			return nil, err
+		}
+	}
+	return s.externalCategoryRepo.GetByConnectionID(ctx, conn.ID)
+}
+
+// GetExternalCategories returns the cached marketplace category tree.
+// With leafOnly the leaf categories are returned as a flat list with breadcrumbs.
+func (s *ProductSyncService) GetExternalCategories(ctx context.Context, connectionID uuid.UUID, leafOnly bool) ([]domain.ExternalCategoryResponse, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	categories, err := s.cachedExternalCategories(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if leafOnly {
+		leaves := make([]domain.ExternalCategoryResponse, 0)
+		for i := range categories {
+			if categories[i].IsLeaf {
+				leaves = append(leaves, categories[i].ToResponse())
+			}
+		}
+		return leaves, nil
+	}
+
+	return buildCategoryTree(categories), nil
+}
+
+// BrowseExternalCategories returns the cached children of a category, or the root categories if parentID is empty
+func (s *ProductSyncService) BrowseExternalCategories(ctx context.Context, connectionID uuid.UUID, parentID string, leafOnly bool) ([]domain.ExternalCategoryResponse, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	// Make sure the cache has been filled at least once
+	if _, err := s.cachedExternalCategories(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	children, err := s.externalCategoryRepo.GetChildren(ctx, connectionID, parentID, leafOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.ExternalCategoryResponse, 0, len(children))
+	for i := range children {
+		result = append(result, children[i].ToResponse())
+	}
+	return result, nil
+}
+
+// SearchExternalCategories searches the cached categories by name and breadcrumb
+func (s *ProductSyncService) SearchExternalCategories(ctx context.Context, connectionID uuid.UUID, filter *domain.ExternalCategoryFilter) ([]domain.ExternalCategoryResponse, int64, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, 0, ErrConnectionNotFound
+	}
+
+	if _, err := s.cachedExternalCategories(ctx, conn); err != nil {
+		return nil, 0, err
+	}
+
+	categories, total, err := s.externalCategoryRepo.Search(ctx, connectionID, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]domain.ExternalCategoryResponse, 0, len(categories))
+	for i := range categories {
+		result = append(result, categories[i].ToResponse())
+	}
+	return result, total, nil
+}
+
+// buildCachedCategories computes the breadcrumb and depth of every fetched category
+func buildCachedCategories(connectionID uuid.UUID, flat []providers.ExternalCategory, seenAt time.Time) []domain.ExternalCategory {
+	byID := make(map[string]*providers.ExternalCategory, len(flat))
+	for i := range flat {
+		byID[flat[i].CategoryID] = &flat[i]
+	}
+
+	categories := make([]domain.ExternalCategory, 0, len(flat))
+	added := make(map[string]bool, len(flat))
+	for i := range flat {
+		if added[flat[i].CategoryID] {
+			continue
+		}
+		added[flat[i].CategoryID] = true
+
+		var path []string
+		seen := make(map[string]bool)
+		for cat := &flat[i]; cat != nil && !seen[cat.CategoryID]; cat = byID[cat.ParentID] {
+			seen[cat.CategoryID] = true
+			path = append([]string{cat.CategoryName}, path...)
+		}
+
+		categories = append(categories, domain.ExternalCategory{
+			ConnectionID: connectionID,
+			CategoryID:   flat[i].CategoryID,
+			ParentID:     flat[i].ParentID,
+			Name:         flat[i].CategoryName,
+			Breadcrumb:   strings.Join(path, " > "),
+			Depth:        len(path) - 1,
+			IsLeaf:       flat[i].IsLeaf,
+			IsActive:     true,
+			LastSeenAt:   seenAt,
+		})
+	}
+	return categories
+}
+
+// buildCategoryTree nests cached categories under their parents
+func buildCategoryTree(categories []domain.ExternalCategory) []domain.ExternalCategoryResponse {
+	present := make(map[string]bool, len(categories))
+	children := make(map[string][]int)
+	for i := range categories {
+		present[categories[i].CategoryID] = true
+	}
+	var roots []int
+	for i := range categories {
+		parentID := categories[i].ParentID
+		if parentID == "" || !present[parentID] {
+			roots = append(roots, i)
+			continue
+		}
+		children[parentID] = append(children[parentID], i)
+	}
+
+	var build func(i int, seen map[string]bool) domain.ExternalCategoryResponse
+	build = func(i int, seen map[string]bool) domain.ExternalCategoryResponse {
+		node := categories[i].ToResponse()
+		seen[node.CategoryID] = true
+		for _, child := range children[node.CategoryID] {
+			if !seen[categories[child].CategoryID] {
+				node.Children = append(node.Children, build(child, seen))
+			}
+		}
+		return node
+	}
+
+	tree := make([]domain.ExternalCategoryResponse, 0, len(roots))
+	seen := make(map[string]bool, len(categories))
+	for _, i := range roots {
+		tree = append(tree, build(i, seen))
+	}
+	return tree
+}
+
+// flattenExternalCategories flattens nested categories into a single list
+func flattenExternalCategories(categories []providers.ExternalCategory) []providers.ExternalCategory {
+	flat := make([]providers.ExternalCategory, 0, len(categories))
+	for _, cat := range categories {
+		children := cat.Children
+		cat.Children = nil
+		flat = append(flat, cat)
+		for _, child := range flattenExternalCategories(children) {
+			if child.ParentID == "" {
+				child.ParentID = cat.CategoryID
+			}
+			flat = append(flat, child)
+		}
+	}
+	return flat
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/infrastructure/persistence"
+)
+
+// InventoryReconcilerConfig holds configuration for the inventory reconciler.
+type InventoryReconcilerConfig struct {
+	ReconcileInterval time.Duration // How often every connection's marketplace stock is compared with the inventory service
+}
+
+// InventoryReconciler periodically starts inventory reconciliations for all active connections.
+type InventoryReconciler struct {
+	connectionRepo *persistence.ConnectionRepository
+	inventorySync  *InventorySyncService
+	config         InventoryReconcilerConfig
+	logger         *zap.Logger
+
+	// Lifecycle management
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	running  bool
+	mu       sync.Mutex
+}
+
+// NewInventoryReconciler creates a new inventory reconciler.
+func NewInventoryReconciler(
+	connectionRepo *persistence.ConnectionRepository,
+	inventorySync *InventorySyncService,
+	cfg InventoryReconcilerConfig,
+	logger *zap.Logger,
+) *InventoryReconciler {
+	// Set defaults
+	if cfg.ReconcileInterval == 0 {
+		cfg.ReconcileInterval = 12 * time.Hour
+	}
+
+	return &InventoryReconciler{
+		connectionRepo: connectionRepo,
+		inventorySync:  inventorySync,
+		config:         cfg,
+		logger:         logger,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// Start begins the background inventory reconciliation process.
+func (ir *InventoryReconciler) Start(ctx context.Context) error {
+	ir.mu.Lock()
+	if ir.running {
+		ir.mu.Unlock()
+		return fmt.Errorf("inventory reconciler already running")
+	}
+	ir.running = true
+	ir.mu.Unlock()
+
+	ir.wg.Add(1)
+	go ir.run(ctx)
+
+	ir.logger.Info("inventory reconciler started",
+		zap.Duration("reconcile_interval", ir.config.ReconcileInterval),
+	)
+
+	return nil
+}
+
+// Stop gracefully stops the inventory reconciler.
+func (ir *InventoryReconciler) Stop() {
+	ir.mu.Lock()
+	if !ir.running {
+		ir.mu.Unlock()
+		return
+	}
+	ir.running = false
+	ir.mu.Unlock()
+
+	close(ir.stopChan)
+	ir.wg.Wait()
+
+	ir.logger.Info("inventory reconciler stopped")
+}
+
+// run is the main background loop. The first reconciliation runs after one interval
+// so a restart does not hit the marketplace APIs for every connection at once.
+func (ir *InventoryReconciler) run(ctx context.Context) {
+	defer ir.wg.Done()
+
+	ticker := time.NewTicker(ir.config.ReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ir.stopChan:
+			return
+		case <-ticker.C:
+			ir.reconcileConnections(ctx)
+		}
+	}
+}
+
+// reconcileConnections starts an inventory reconciliation for every active connection.
+func (ir *InventoryReconciler) reconcileConnections(ctx context.Context) {
+	connections, err := ir.connectionRepo.GetActiveConnections(ctx)
+	if err != nil {
+		ir.logger.Error("failed to get active connections", zap.Error(err))
+		return
+	}
+
+	for i := range connections {
+		conn := &connections[i]
+		if conn.Platform != "shopee" && conn.Platform != "tiktok" {
+			continue
+		}
+		if _, err := ir.inventorySync.StartReconciliation(ctx, conn.ID, nil); err != nil && !errors.Is(err, ErrReconcileInProgress) {
+			ir.logger.Error("failed to start inventory reconciliation",
+				zap.String("connection_id", conn.ID.String()),
+				zap.String("platform", conn.Platform),
+				zap.Error(err),
+			)
+		}
+	}
+}
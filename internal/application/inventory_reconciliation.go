@@ -0,0 +1,474 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/clients"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/infrastructure/persistence"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers/shopee"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers/tiktok"
+)
+
+var (
+	ErrReconcileInProgress    = errors.New("an inventory reconciliation is already running for this connection")
+	ErrInventoryNotConfigured = errors.New("inventory service is not configured")
+)
+
+// reconcilePageSize is the number of mappings compared per batch; Shopee reads at most 50 items at once
+const reconcilePageSize = 50
+
+// stockAccess reads and writes the marketplace stock of a connection
+type stockAccess struct {
+	// getStock returns the stock of listings: their totals, and per SKU where the platform reports it
+	getStock func(ctx context.Context, externalIDs []string) ([]providers.InventoryItem, error)
+	// getModelStock returns the stock per model of one listing; nil when getStock reports SKUs
+	getModelStock func(ctx context.Context, externalID string) ([]providers.InventoryItem, error)
+	updateStock   func(ctx context.Context, update providers.InventoryUpdate) error
+	// updateBatchStock updates many listings within the platform's batch limits
+	updateBatchStock func(ctx context.Context, updates []providers.InventoryUpdate) ([]providers.InventoryUpdateResult, error)
+}
+
+// stockAccessFor returns the stock access for a connection's platform
+func (s *InventorySyncService) stockAccessFor(conn *domain.Connection, accessToken string) (*stockAccess, error) {
+	switch conn.Platform {
+	case "shopee":
+		shopID, _ := strconv.ParseInt(conn.ShopID, 10, 64)
+		client, err := shopee.NewClient(&shopee.ClientConfig{
+			PartnerID:  s.shopeePartnerID,
+			PartnerKey: s.shopeePartnerKey,
+			IsSandbox:  s.shopeeSandbox,
+			Logger:     s.logger,
+		})
+		if err != nil {
+			return nil, err
+		}
+		client.SetTokens(accessToken, shopID)
+		inventoryProvider := shopee.NewInventoryProvider(client)
+		productProvider := shopee.NewProductProvider(client)
+		return &stockAccess{
+			getStock: inventoryProvider.GetStock,
+			getModelStock: func(ctx context.Context, externalID string) ([]providers.InventoryItem, error) {
+				itemID, err := strconv.ParseInt(externalID, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid item id: %w", err)
+				}
+				list, err := productProvider.GetModelList(ctx, itemID)
+				if err != nil {
+					return nil, err
+				}
+				items := make([]providers.InventoryItem, 0, len(list.Models))
+				for _, model := range list.Models {
+					items = append(items, providers.InventoryItem{
+						ExternalProductID: externalID,
+						ExternalSKU:       strconv.FormatInt(model.ModelID, 10),
+						Quantity:          model.Stock,
+					})
+				}
+				return items, nil
+			},
+			updateStock:      inventoryProvider.UpdateStock,
+			updateBatchStock: inventoryProvider.UpdateBatchStock,
+		}, nil
+
+	case "tiktok":
+		client := tiktok.NewClient(&tiktok.ClientConfig{
+			AppKey:    s.tiktokAppKey,
+			AppSecret: s.tiktokAppSecret,
+			Logger:    s.logger,
+		})
+		client.SetTokens(accessToken, conn.ShopID)
+		inventoryProvider := tiktok.NewInventoryProvider(client)
+		return &stockAccess{
+			getStock:         inventoryProvider.GetStock,
+			updateStock:      inventoryProvider.UpdateStock,
+			updateBatchStock: inventoryProvider.UpdateBatchStock,
+		}, nil
+
+	default:
+		return nil, ErrInvalidPlatform
+	}
+}
+
+// StartReconciliation queues a background comparison of the marketplace stock of every mapped
+// listing against the inventory service. With auto-correct, differing stock is pushed.
+func (s *InventorySyncService) StartReconciliation(ctx context.Context, connectionID uuid.UUID, req *domain.StartInventoryReconcileRequest) (*domain.SyncJob, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+	if conn.Platform != "shopee" && conn.Platform != "tiktok" {
+		return nil, ErrInvalidPlatform
+	}
+	if s.inventoryClient == nil {
+		return nil, ErrInventoryNotConfigured
+	}
+
+	payload := domain.InventoryReconcilePayload{AutoCorrect: s.autoCorrectStock}
+	if req != nil && req.AutoCorrect != nil {
+		payload.AutoCorrect = *req.AutoCorrect
+	}
+	data, _ := json.Marshal(payload)
+	job := &domain.SyncJob{
+		ConnectionID: connectionID,
+		JobType:      domain.JobTypeInventoryReconcile,
+		Payload:      data,
+		Status:       domain.JobStatusPending,
+		MaxAttempts:  1,
+	}
+
+	if active, err := s.syncJobRepo.CreateExclusive(ctx, job); err != nil {
+		if errors.Is(err, persistence.ErrActiveJobExists) {
+			return active, ErrReconcileInProgress
+		}
+		return nil, fmt.Errorf("failed to create sync job: %w", err)
+	}
+
+	go s.processReconcileJob(context.Background(), job, conn, payload)
+
+	return job, nil
+}
+
+// FailInterruptedReconciliations fails reconciliations left unfinished by a restart of the
+// service, so they no longer block new reconciliations of their connection
+func (s *InventorySyncService) FailInterruptedReconciliations(ctx context.Context) {
+	failed, err := s.syncJobRepo.FailUnfinishedByType(ctx, domain.JobTypeInventoryReconcile, "interrupted by a service restart")
+	if err != nil {
+		s.logger.Error("Failed to fail interrupted reconciliations", zap.Error(err))
+		return
+	}
+	if failed > 0 {
+		s.logger.Info("Failed interrupted reconciliations", zap.Int64("jobs", failed))
+	}
+}
+
+// GetReconcileJob returns an inventory reconciliation job of a connection, including its progress
+func (s *InventorySyncService) GetReconcileJob(ctx context.Context, connectionID, jobID uuid.UUID) (*domain.SyncJob, error) {
+	job, err := s.syncJobRepo.GetByID(ctx, jobID)
+	if err != nil || job.ConnectionID != connectionID || job.JobType != domain.JobTypeInventoryReconcile {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// GetInventoryDiscrepancies returns the per-SKU stock reports of a connection
+func (s *InventorySyncService) GetInventoryDiscrepancies(ctx context.Context, connectionID uuid.UUID, filter *domain.InventoryDiscrepancyFilter) ([]domain.InventoryDiscrepancy, int64, error) {
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return nil, 0, ErrConnectionNotFound
+	}
+	return s.discrepancyRepo.GetByConnectionID(ctx, connectionID, filter)
+}
+
+// processReconcileJob compares the mapped listings of a connection batch by batch
+func (s *InventorySyncService) processReconcileJob(ctx context.Context, job *domain.SyncJob, conn *domain.Connection, payload domain.InventoryReconcilePayload) {
+	// Mark as processing
+	if err := s.syncJobRepo.MarkProcessing(ctx, job.ID); err != nil {
+		s.logger.Error("Failed to mark job as processing", zap.Error(err))
+		return
+	}
+
+	// Decrypt access token
+	accessToken := conn.AccessToken
+	if s.encryptor != nil {
+		var err error
+		accessToken, err = s.encryptor.Decrypt(conn.AccessToken)
+		if err != nil {
+			s.syncJobRepo.MarkFailed(ctx, job.ID, "failed to decrypt token")
+			return
+		}
+	}
+
+	access, err := s.stockAccessFor(conn, accessToken)
+	if err != nil {
+		s.syncJobRepo.MarkFailed(ctx, job.ID, err.Error())
+		return
+	}
+
+	if _, total, err := s.productMappingRepo.GetByConnectionID(ctx, conn.ID, &domain.ProductMappingFilter{Page: 1, PageSize: 1}); err == nil {
+		job.TotalItems = int(total)
+	}
+
+	err = s.productMappingRepo.FindInBatchesByConnectionID(ctx, conn.ID, reconcilePageSize, func(mappings []domain.ProductMapping) error {
+		discrepancies, corrected, failed := s.reconcileBatch(ctx, conn, access, mappings, payload.AutoCorrect)
+		payload.Discrepancies += discrepancies
+		payload.Corrected += corrected
+		job.ProcessedItems += len(mappings)
+		job.FailedItems += failed
+		job.Payload, _ = json.Marshal(payload)
+		if err := s.syncJobRepo.UpdateProgress(ctx, job); err != nil {
+			s.logger.Warn("Failed to save reconciliation progress", zap.String("job_id", job.ID.String()), zap.Error(err))
+		}
+		return nil
+	})
+	if err != nil {
+		s.syncJobRepo.MarkFailed(ctx, job.ID, fmt.Sprintf("failed to load product mappings: %v", err))
+		return
+	}
+
+	s.syncJobRepo.MarkCompleted(ctx, job.ID)
+
+	s.logger.Info("Inventory reconciliation completed",
+		zap.String("job_id", job.ID.String()),
+		zap.String("connection_id", conn.ID.String()),
+		zap.Int("checked", job.ProcessedItems),
+		zap.Int("discrepancies", payload.Discrepancies),
+		zap.Int("corrected", payload.Corrected),
+		zap.Int("failed", job.FailedItems),
+	)
+}
+
+// reconcileBatch compares the marketplace stock of a batch of mappings with the inventory
+// service and stores a report per listed SKU: one per variant mapping, or one for the whole
+// listing. Returns the number of differing, corrected and uncomparable SKUs.
+func (s *InventorySyncService) reconcileBatch(ctx context.Context, conn *domain.Connection, access *stockAccess, mappings []domain.ProductMapping, autoCorrect bool) (int, int, int) {
+	externalIDs := make([]string, 0, len(mappings))
+	productIDs := make([]string, 0, len(mappings))
+	internalIDs := make([]uuid.UUID, 0, len(mappings))
+	for _, m := range mappings {
+		externalIDs = append(externalIDs, m.ExternalProductID)
+		productIDs = append(productIDs, m.InternalProductID.String())
+		internalIDs = append(internalIDs, m.InternalProductID)
+	}
+
+	actual, skuCounts, marketplaceErr := s.marketplaceStock(ctx, access, mappings, externalIDs)
+
+	// Expected stock, per warehouse, of products and of variants
+	var productLevels, variantLevels map[string][]domain.WarehouseStockLevel
+	var skus map[string]string
+	inventoryErr := ""
+	if levels, err := s.inventoryClient.GetStockLevels(ctx, productIDs); err != nil {
+		inventoryErr = err.Error()
+	} else {
+		productLevels, variantLevels, skus = groupStockLevels(levels)
+	}
+	s.recordStockLevels(ctx, productLevels)
+
+	warehouseMappings, _ := s.warehouseRepo.GetMappings(ctx, conn.ID)
+	policy := conn.GetSettings().UnmappedWarehouses
+	// Without the allocation inputs the expected stock is unknown, so nothing is compared
+	allocationErr := ""
+	rules, err := s.stockAllocation.rulesFor(ctx, conn.ID)
+	var categories map[string]string
+	if err == nil {
+		categories, err = s.stockAllocation.productCategories(ctx, rules, productIDs)
+	}
+	var reserved map[uuid.UUID]int
+	if err == nil {
+		reserved, err = s.stockAllocation.reservedStocks(ctx, internalIDs)
+	}
+	if err != nil {
+		allocationErr = err.Error()
+	}
+
+	discrepancies, corrected, failed := 0, 0, 0
+	checkedAt := time.Now()
+	for i := range mappings {
+		mapping := &mappings[i]
+
+		type target struct {
+			variantID         *uuid.UUID
+			externalVariantID string
+			sku               string
+			levels            []domain.WarehouseStockLevel
+			found             bool
+		}
+		var targets []target
+		if len(mapping.VariantMappings) == 0 {
+			id := mapping.InternalProductID.String()
+			levels, ok := productLevels[id]
+			targets = append(targets, target{sku: skus[id], levels: levels, found: ok})
+		}
+		for _, vm := range mapping.VariantMappings {
+			variantID := vm.InternalVariantID
+			levels, ok := variantLevels[variantID.String()]
+			targets = append(targets, target{variantID: &variantID, externalVariantID: vm.ExternalVariantID, sku: skus[variantID.String()], levels: levels, found: ok})
+		}
+
+		rule := rules.forCategory(categories[mapping.InternalProductID.String()])
+		// Reservations are per product, so they are taken from its variants in order
+		unreserved := reserved[mapping.InternalProductID]
+		for _, t := range targets {
+			report := &domain.InventoryDiscrepancy{
+				ConnectionID:      conn.ID,
+				ProductMappingID:  mapping.ID,
+				InternalProductID: mapping.InternalProductID,
+				InternalVariantID: t.variantID,
+				ExternalProductID: mapping.ExternalProductID,
+				ExternalVariantID: t.externalVariantID,
+				SKU:               t.sku,
+				CheckedAt:         checkedAt,
+			}
+
+			// Expected stock is what the connection's allocation rule publishes of the
+			// unreserved stock
+			warehouses, available := allocateWarehouseStock(warehouseMappings, policy, t.levels, sumStockLevels(t.levels))
+			taken := min(unreserved, max(available, 0))
+			unreserved -= taken
+			warehouses, available = unreservedStock(warehouses, available, taken)
+			warehouses, allocation := allocateStock(rule, warehouses, available)
+			expected := allocation.Quantity
+			quantity, listed := actual[stockKey(mapping.ExternalProductID, t.externalVariantID)]
+			switch {
+			case marketplaceErr != "":
+				report.CheckError = marketplaceErr
+			case !listed:
+				report.CheckError = "stock not returned by the marketplace"
+			case inventoryErr != "":
+				report.CheckError = inventoryErr
+			case allocationErr != "":
+				report.CheckError = allocationErr
+			case !t.found:
+				report.CheckError = "stock not found in the inventory service"
+			}
+			report.ExpectedQuantity = expected
+			report.ActualQuantity = quantity
+			report.HasDiscrepancy = report.CheckError == "" && expected != quantity
+
+			correctedNow := false
+			switch {
+			case report.CheckError != "":
+				failed++
+			case report.HasDiscrepancy:
+				discrepancies++
+				// A whole-listing quantity cannot be split across the listing's SKUs
+				if len(mapping.VariantMappings) == 0 && skuCounts[mapping.ExternalProductID] > 1 {
+					report.Uncorrectable = "listing has several SKUs, map its variants to correct stock per SKU"
+				} else if autoCorrect {
+					correctedNow = true
+					now := time.Now()
+					report.LastCorrectedAt = &now
+					report.LastCorrectionQuantity = &expected
+					update := providers.InventoryUpdate{
+						ExternalProductID: mapping.ExternalProductID,
+						ExternalSKU:       mapping.ExternalSKU,
+						ExternalVariantID: t.externalVariantID,
+						Quantity:          expected,
+						Warehouses:        warehouses,
+					}
+					err := access.updateStock(ctx, update)
+					entry := stockPushLog(conn.ID, mapping, update, domain.InventorySyncSourceReconciliation, err)
+					entry.PreviousQuantity = &report.ActualQuantity
+					recordStockPushes(ctx, s.syncLogRepo, s.logger, conn.ID, entry)
+					if err != nil {
+						report.LastCorrectionError = err.Error()
+					} else {
+						corrected++
+					}
+				}
+			}
+
+			if err := s.discrepancyRepo.Upsert(ctx, report, correctedNow); err != nil {
+				s.logger.Warn("Failed to save inventory discrepancy",
+					zap.String("mapping_id", mapping.ID.String()),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+
+	return discrepancies, corrected, failed
+}
+
+// marketplaceStock fetches the stock of a batch of listings keyed by stockKey: listing totals,
+// and per model or SKU for mappings with variant mappings. Also returns the number of SKUs of
+// each listing where the platform reports them.
+func (s *InventorySyncService) marketplaceStock(ctx context.Context, access *stockAccess, mappings []domain.ProductMapping, externalIDs []string) (map[string]int, map[string]int, string) {
+	items, err := access.getStock(ctx, externalIDs)
+	if err != nil {
+		return nil, nil, err.Error()
+	}
+
+	stock := make(map[string]int, len(items))
+	skuCounts := make(map[string]int, len(externalIDs))
+	for _, item := range items {
+		if item.ExternalSKU != "" {
+			stock[stockKey(item.ExternalProductID, item.ExternalSKU)] = item.Quantity
+			skuCounts[item.ExternalProductID]++
+		}
+		// SKU-level items add up to the listing total
+		stock[stockKey(item.ExternalProductID, "")] += item.Quantity
+	}
+
+	if access.getModelStock == nil {
+		return stock, skuCounts, ""
+	}
+	for _, mapping := range mappings {
+		if len(mapping.VariantMappings) == 0 {
+			continue
+		}
+		models, err := access.getModelStock(ctx, mapping.ExternalProductID)
+		if err != nil {
+			s.logger.Warn("Failed to get model stock",
+				zap.String("external_product_id", mapping.ExternalProductID),
+				zap.Error(err),
+			)
+			continue
+		}
+		for _, model := range models {
+			stock[stockKey(model.ExternalProductID, model.ExternalSKU)] = model.Quantity
+		}
+	}
+	return stock, skuCounts, ""
+}
+
+// recordStockLevels refreshes the warehouse stock ledger from the inventory service
+func (s *InventorySyncService) recordStockLevels(ctx context.Context, productLevels map[string][]domain.WarehouseStockLevel) {
+	for _, levels := range productLevels {
+		for i := range levels {
+			if levels[i].WarehouseID == "" {
+				continue
+			}
+			if err := s.warehouseRepo.UpsertStockLevel(ctx, &levels[i]); err != nil {
+				s.logger.Warn("Failed to record warehouse stock", zap.Error(err))
+				return
+			}
+		}
+	}
+}
+
+// groupStockLevels sums inventory service stock per warehouse, for each product (variants
+// included) and for each variant. Also returns the SKU of each product and variant.
+func groupStockLevels(levels []clients.StockLevel) (map[string][]domain.WarehouseStockLevel, map[string][]domain.WarehouseStockLevel, map[string]string) {
+	add := func(groups map[string][]domain.WarehouseStockLevel, key string, level clients.StockLevel) {
+		for i := range groups[key] {
+			if groups[key][i].WarehouseID == level.WarehouseID {
+				groups[key][i].Quantity += level.Available
+				return
+			}
+		}
+		productID, _ := uuid.Parse(level.ProductID)
+		groups[key] = append(groups[key], domain.WarehouseStockLevel{
+			InternalProductID: productID,
+			WarehouseID:       level.WarehouseID,
+			Quantity:          level.Available,
+		})
+	}
+
+	products := make(map[string][]domain.WarehouseStockLevel)
+	variants := make(map[string][]domain.WarehouseStockLevel)
+	skus := make(map[string]string)
+	for _, level := range levels {
+		add(products, level.ProductID, level)
+		if level.VariantID == "" {
+			skus[level.ProductID] = level.SKU
+			continue
+		}
+		add(variants, level.VariantID, level)
+		skus[level.VariantID] = level.SKU
+	}
+	return products, variants, skus
+}
+
+// stockKey identifies the stock of a listing, or of one of its models or SKUs
+func stockKey(externalProductID, externalVariantID string) string {
+	return externalProductID + "/" + externalVariantID
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/infrastructure/persistence"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+// GetInventorySyncLogs searches the stock push history
+func (s *InventorySyncService) GetInventorySyncLogs(ctx context.Context, filter *domain.InventorySyncLogFilter) ([]domain.InventorySyncLog, int64, error) {
+	return s.syncLogRepo.Search(ctx, filter)
+}
+
+// stockPushLog builds the log entry of a stock push to a listing. The mapping is nil for
+// listings without a product mapping.
+func stockPushLog(connectionID uuid.UUID, mapping *domain.ProductMapping, update providers.InventoryUpdate, source string, pushErr error) domain.InventorySyncLog {
+	entry := domain.InventorySyncLog{
+		ConnectionID:      connectionID,
+		ExternalProductID: update.ExternalProductID,
+		ExternalVariantID: update.ExternalVariantID,
+		Source:            source,
+		NewQuantity:       update.Quantity,
+		SyncStatus:        domain.InventorySyncStatusSuccess,
+	}
+	if mapping != nil {
+		mappingID, productID := mapping.ID, mapping.InternalProductID
+		if mappingID != uuid.Nil {
+			entry.ProductMappingID = &mappingID
+		}
+		entry.InternalProductID = &productID
+	}
+	if pushErr != nil {
+		entry.SyncStatus = domain.InventorySyncStatusFailed
+		entry.ErrorMessage = pushErr.Error()
+	}
+	return entry
+}
+
+// recordStockPushes stores the log entries of stock pushes to one connection. Entries without
+// a previous quantity get the last quantity successfully pushed to the same listing or variant.
+// Logging never fails a push, so errors are only logged.
+func recordStockPushes(ctx context.Context, repo *persistence.InventorySyncLogRepository, logger *zap.Logger, connectionID uuid.UUID, entries ...domain.InventorySyncLog) {
+	if repo == nil || len(entries) == 0 {
+		return
+	}
+
+	var externalIDs []string
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if e.PreviousQuantity == nil && !seen[e.ExternalProductID] {
+			seen[e.ExternalProductID] = true
+			externalIDs = append(externalIDs, e.ExternalProductID)
+		}
+	}
+	if len(externalIDs) > 0 {
+		latest, err := repo.GetLatestSuccessful(ctx, connectionID, externalIDs)
+		if err != nil {
+			logger.Warn("Failed to load previous stock pushes", zap.Error(err))
+		}
+		previous := make(map[string]int, len(latest))
+		for _, l := range latest {
+			previous[stockKey(l.ExternalProductID, l.ExternalVariantID)] = l.NewQuantity
+		}
+		for i := range entries {
+			if entries[i].PreviousQuantity != nil {
+				continue
+			}
+			if quantity, ok := previous[stockKey(entries[i].ExternalProductID, entries[i].ExternalVariantID)]; ok {
+				entries[i].PreviousQuantity = &quantity
+			}
+		}
+	}
+
+	if err := repo.CreateBatch(ctx, entries); err != nil {
+		logger.Warn("Failed to save inventory sync logs",
+			zap.String("connection_id", connectionID.String()),
+			zap.Int("entries", len(entries)),
+			zap.Error(err),
+		)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"github.com/Ecom-micro-template/service-marketplace/internal/clients"
 	"github.com/Ecom-micro-template/service-marketplace/internal/events"
 	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
 	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
@@ -27,6 +28,13 @@ var (
 type InventorySyncService struct {
 	connectionRepo     *persistence.ConnectionRepository
 	productMappingRepo *persistence.ProductMappingRepository
+	warehouseRepo      *persistence.WarehouseRepository
+	discrepancyRepo    *persistence.InventoryDiscrepancyRepository
+	syncLogRepo        *persistence.InventorySyncLogRepository
+	reservationRepo    *persistence.StockReservationRepository
+	syncJobRepo        *persistence.SyncJobRepository
+	inventoryClient    *clients.InventoryClient
+	stockAllocation    *StockAllocationService
 	encryptor          *utils.Encryptor
 	publisher          *events.Publisher
 	logger             *zap.Logger
@@ -36,6 +44,12 @@ type InventorySyncService struct {
 	shopeeSandbox    bool
 	tiktokAppKey     string
 	tiktokAppSecret  string
+
+	// Reconciliation pushes the expected stock where the marketplace differs
+	autoCorrectStock bool
+
+	// Coalesces stock change events into batched updates; nil pushes each event right away
+	updateBuffer *inventoryUpdateBuffer
 }
 
 // InventorySyncServiceConfig holds configuration
@@ -46,12 +60,26 @@ type InventorySyncServiceConfig struct {
 	TikTokAppKey     string
 	TikTokAppSecret  string
 	EncryptionKey    string
+
+	// AutoCorrectStock makes inventory reconciliation push corrections by default
+	AutoCorrectStock bool
+
+	// StockUpdateWindow is how long stock changes are coalesced before they are pushed in
+	// batches; zero pushes each change right away
+	StockUpdateWindow time.Duration
 }
 
 // NewInventorySyncService creates a new InventorySyncService
 func NewInventorySyncService(
 	connectionRepo *persistence.ConnectionRepository,
 	productMappingRepo *persistence.ProductMappingRepository,
+	warehouseRepo *persistence.WarehouseRepository,
+	discrepancyRepo *persistence.InventoryDiscrepancyRepository,
+	syncLogRepo *persistence.InventorySyncLogRepository,
+	reservationRepo *persistence.StockReservationRepository,
+	syncJobRepo *persistence.SyncJobRepository,
+	inventoryClient *clients.InventoryClient,
+	stockAllocation *StockAllocationService,
 	publisher *events.Publisher,
 	cfg *InventorySyncServiceConfig,
 	logger *zap.Logger,
@@ -65,9 +93,16 @@ func NewInventorySyncService(
 		}
 	}
 
-	return &InventorySyncService{
+	svc := &InventorySyncService{
 		connectionRepo:     connectionRepo,
 		productMappingRepo: productMappingRepo,
+		warehouseRepo:      warehouseRepo,
+		discrepancyRepo:    discrepancyRepo,
+		syncLogRepo:        syncLogRepo,
+		reservationRepo:    reservationRepo,
+		syncJobRepo:        syncJobRepo,
+		inventoryClient:    inventoryClient,
+		stockAllocation:    stockAllocation,
 		encryptor:          encryptor,
 		publisher:          publisher,
 		logger:             logger,
@@ -76,12 +111,43 @@ func NewInventorySyncService(
 		shopeeSandbox:      cfg.ShopeeSandbox,
 		tiktokAppKey:       cfg.TikTokAppKey,
 		tiktokAppSecret:    cfg.TikTokAppSecret,
-	}, nil
+		autoCorrectStock:   cfg.AutoCorrectStock,
+	}
+	if cfg.StockUpdateWindow > 0 {
+		svc.updateBuffer = newInventoryUpdateBuffer(cfg.StockUpdateWindow, svc.flushStockUpdates)
+	}
+
+	return svc, nil
+}
+
+// Stop pushes stock updates still waiting in the update buffer
+func (s *InventorySyncService) Stop() {
+	if s.updateBuffer != nil {
+		s.updateBuffer.Stop()
+	}
 }
 
 // HandleStockChanged implements events.EventHandler
 func (s *InventorySyncService) HandleStockChanged(event *events.StockChangedEvent) error {
-	ctx := context.Background()
+	return s.SyncStockChange(context.Background(), event)
+}
+
+// SyncStockChange pushes the stock of a changed product to every marketplace it is listed on.
+// The stock of an event with a warehouse is recorded, and pushed per marketplace warehouse
+// on connections with warehouse mappings. Stock reservations the event accounts for are
+// released.
+func (s *InventorySyncService) SyncStockChange(ctx context.Context, event *events.StockChangedEvent) error {
+	s.releaseReservations(ctx, event)
+
+	if event.WarehouseID != "" {
+		if err := s.warehouseRepo.UpsertStockLevel(ctx, &domain.WarehouseStockLevel{
+			InternalProductID: event.ProductID,
+			WarehouseID:       event.WarehouseID,
+			Quantity:          event.NewQuantity,
+		}); err != nil {
+			return fmt.Errorf("failed to record warehouse stock: %w", err)
+		}
+	}
 
 	// Find all product mappings for this product
 	mappings, err := s.productMappingRepo.GetByInternalProductID(ctx, event.ProductID)
@@ -96,12 +162,65 @@ func (s *InventorySyncService) HandleStockChanged(event *events.StockChangedEven
 
 	// Update each marketplace
 	for _, mapping := range mappings {
-		go s.syncInventoryForMapping(ctx, &mapping, event.NewQuantity)
+		if s.updateBuffer != nil {
+			s.bufferInventoryUpdate(ctx, mapping, event.NewQuantity)
+			continue
+		}
+		go s.syncInventoryForMapping(ctx, &mapping, event.NewQuantity, domain.InventorySyncSourceEvent)
 	}
 
 	return nil
 }
 
+// bufferInventoryUpdate queues the stock of a mapping for the next batched push of its connection
+func (s *InventorySyncService) bufferInventoryUpdate(ctx context.Context, mapping domain.ProductMapping, quantity int) {
+	conn, err := s.connectionRepo.GetByID(ctx, mapping.ConnectionID)
+	if err != nil {
+		s.logger.Error("Failed to get connection", zap.Error(err))
+		return
+	}
+
+	if !conn.IsActive {
+		s.logger.Debug("Connection is inactive, skipping", zap.String("connection_id", conn.ID.String()))
+		return
+	}
+
+	update, err := s.inventoryUpdateFor(ctx, conn, &mapping, quantity)
+	if err != nil {
+		// The listing keeps its last pushed stock until the next stock event
+		s.logger.Error("Failed to allocate stock",
+			zap.String("product_id", mapping.InternalProductID.String()),
+			zap.Error(err),
+		)
+		s.publishSyncFailed(conn, &mapping, err.Error())
+		return
+	}
+	s.updateBuffer.Add(conn.ID, mapping, update)
+}
+
+// inventoryUpdateFor builds the stock update of a mapping: split over the connection's
+// marketplace warehouses, less stock reserved by orders and limited by its stock allocation rules
+func (s *InventorySyncService) inventoryUpdateFor(ctx context.Context, conn *domain.Connection, mapping *domain.ProductMapping, quantity int) (providers.InventoryUpdate, error) {
+	update := providers.InventoryUpdate{
+		ExternalProductID: mapping.ExternalProductID,
+		ExternalSKU:       mapping.ExternalSKU,
+	}
+	warehouses, total := warehouseStock(ctx, s.warehouseRepo, conn, mapping.InternalProductID, quantity)
+	var err error
+	update.Warehouses, update.Quantity, err = s.stockAllocation.Allocate(ctx, conn.ID, mapping.InternalProductID, "", warehouses, total)
+	return update, err
+}
+
+// HandleProductCreated implements events.EventHandler
+// Note: For auto-listing support, use MarketplaceSyncHandler instead
+func (s *InventorySyncService) HandleProductCreated(event *events.ProductCreatedEvent) error {
+	s.logger.Debug("Product created event received by InventorySyncService",
+		zap.String("product_id", event.ProductID),
+		zap.String("note", "Use MarketplaceSyncHandler for auto-listing"),
+	)
+	return nil
+}
+
 // HandleProductUpdated implements events.EventHandler
 // Note: For full product sync support, use MarketplaceSyncHandler instead
 func (s *InventorySyncService) HandleProductUpdated(event *events.ProductUpdatedEvent) error {
@@ -123,7 +242,7 @@ func (s *InventorySyncService) HandleProductDeleted(event *events.ProductDeleted
 }
 
 // syncInventoryForMapping syncs inventory to a single marketplace
-func (s *InventorySyncService) syncInventoryForMapping(ctx context.Context, mapping *domain.ProductMapping, quantity int) {
+func (s *InventorySyncService) syncInventoryForMapping(ctx context.Context, mapping *domain.ProductMapping, quantity int, source string) {
 	// Get connection
 	conn, err := s.connectionRepo.GetByID(ctx, mapping.ConnectionID)
 	if err != nil {
@@ -147,17 +266,30 @@ func (s *InventorySyncService) syncInventoryForMapping(ctx context.Context, mapp
 		}
 	}
 
+	// The listing keeps its last pushed stock when the allocation cannot be computed
+	update, err := s.inventoryUpdateFor(ctx, conn, mapping, quantity)
+	if err != nil {
+		s.logger.Error("Failed to allocate stock",
+			zap.String("product_id", mapping.InternalProductID.String()),
+			zap.Error(err),
+		)
+		s.publishSyncFailed(conn, mapping, err.Error())
+		return
+	}
+
 	// Update marketplace
 	switch conn.Platform {
 	case "shopee":
-		err = s.updateShopeeInventory(ctx, conn, mapping, accessToken, quantity)
+		err = s.updateShopeeInventory(ctx, conn, accessToken, update)
 	case "tiktok":
-		err = s.updateTikTokInventory(ctx, conn, mapping, accessToken, quantity)
+		err = s.updateTikTokInventory(ctx, conn, accessToken, update)
 	default:
 		s.logger.Error("Unknown platform", zap.String("platform", conn.Platform))
 		return
 	}
 
+	recordStockPushes(ctx, s.syncLogRepo, s.logger, conn.ID, stockPushLog(conn.ID, mapping, update, source, err))
+
 	if err != nil {
 		s.logger.Error("Failed to sync inventory",
 			zap.String("platform", conn.Platform),
@@ -171,13 +303,14 @@ func (s *InventorySyncService) syncInventoryForMapping(ctx context.Context, mapp
 	s.logger.Info("Inventory synced successfully",
 		zap.String("platform", conn.Platform),
 		zap.String("external_product_id", mapping.ExternalProductID),
-		zap.Int("quantity", quantity),
+		zap.Int("quantity", update.Quantity),
+		zap.Int("warehouses", len(update.Warehouses)),
 	)
 
 	s.publishSyncCompleted(conn, mapping)
 }
 
-func (s *InventorySyncService) updateShopeeInventory(ctx context.Context, conn *domain.Connection, mapping *domain.ProductMapping, accessToken string, quantity int) error {
+func (s *InventorySyncService) updateShopeeInventory(ctx context.Context, conn *domain.Connection, accessToken string, update providers.InventoryUpdate) error {
 	shopID, _ := strconv.ParseInt(conn.ShopID, 10, 64)
 
 	client, _ := shopee.NewClient(&shopee.ClientConfig{
@@ -189,10 +322,10 @@ func (s *InventorySyncService) updateShopeeInventory(ctx context.Context, conn *
 	client.SetTokens(accessToken, shopID)
 
 	provider := shopee.NewInventoryProvider(client)
-	return provider.UpdateStock(ctx, mapping.ExternalProductID, quantity)
+	return provider.UpdateStock(ctx, update)
 }
 
-func (s *InventorySyncService) updateTikTokInventory(ctx context.Context, conn *domain.Connection, mapping *domain.ProductMapping, accessToken string, quantity int) error {
+func (s *InventorySyncService) updateTikTokInventory(ctx context.Context, conn *domain.Connection, accessToken string, update providers.InventoryUpdate) error {
 	client := tiktok.NewClient(&tiktok.ClientConfig{
 		AppKey:    s.tiktokAppKey,
 		AppSecret: s.tiktokAppSecret,
@@ -201,7 +334,7 @@ func (s *InventorySyncService) updateTikTokInventory(ctx context.Context, conn *
 	client.SetTokens(accessToken, conn.ShopID)
 
 	provider := tiktok.NewInventoryProvider(client)
-	return provider.UpdateStock(ctx, mapping.ExternalProductID, mapping.ExternalSKU, quantity)
+	return provider.UpdateStock(ctx, update)
 }
 
 func (s *InventorySyncService) publishSyncCompleted(conn *domain.Connection, mapping *domain.ProductMapping) {
@@ -231,7 +364,8 @@ func (s *InventorySyncService) publishSyncFailed(conn *domain.Connection, mappin
 	})
 }
 
-// PushInventory manually pushes inventory for specific products
+// PushInventory manually pushes inventory for specific products. Quantities are available
+// stock and are limited by the connection's stock allocation rules.
 func (s *InventorySyncService) PushInventory(ctx context.Context, connectionID uuid.UUID, updates []providers.InventoryUpdate) ([]providers.InventoryUpdateResult, error) {
 	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
 	if err != nil {
@@ -246,32 +380,87 @@ func (s *InventorySyncService) PushInventory(ctx context.Context, connectionID u
 		}
 	}
 
-	switch conn.Platform {
-	case "shopee":
-		shopID, _ := strconv.ParseInt(conn.ShopID, 10, 64)
-		client, _ := shopee.NewClient(&shopee.ClientConfig{
-			PartnerID:  s.shopeePartnerID,
-			PartnerKey: s.shopeePartnerKey,
-			IsSandbox:  s.shopeeSandbox,
-			Logger:     s.logger,
-		})
-		client.SetTokens(accessToken, shopID)
-		provider := shopee.NewInventoryProvider(client)
-		return provider.UpdateBatchStock(ctx, updates)
+	access, err := s.stockAccessFor(conn, accessToken)
+	if err != nil {
+		return nil, err
+	}
 
-	case "tiktok":
-		client := tiktok.NewClient(&tiktok.ClientConfig{
-			AppKey:    s.tiktokAppKey,
-			AppSecret: s.tiktokAppSecret,
-			Logger:    s.logger,
-		})
-		client.SetTokens(accessToken, conn.ShopID)
-		provider := tiktok.NewInventoryProvider(client)
-		return provider.UpdateBatchStock(ctx, updates)
+	// Product mappings of the listings, for allocation rules and the sync log
+	externalIDs := make([]string, 0, len(updates))
+	for _, u := range updates {
+		externalIDs = append(externalIDs, u.ExternalProductID)
+	}
+	found, err := s.productMappingRepo.GetByConnectionAndExternalProducts(ctx, conn.ID, externalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product mappings: %w", err)
+	}
+	mappings := make(map[string]*domain.ProductMapping, len(found))
+	for i := range found {
+		mappings[found[i].ExternalProductID] = &found[i]
+	}
 
-	default:
-		return nil, ErrInvalidPlatform
+	if updates, err = s.allocateUpdates(ctx, conn, updates, mappings); err != nil {
+		return nil, err
+	}
+
+	results, err := access.updateBatchStock(ctx, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.InventorySyncLog, 0, len(results))
+	for i, result := range results {
+		var pushErr error
+		if !result.Success {
+			pushErr = errors.New(result.Error)
+		}
+		entries = append(entries, stockPushLog(conn.ID, mappings[updates[i].ExternalProductID], updates[i], domain.InventorySyncSourceManual, pushErr))
+	}
+	recordStockPushes(ctx, s.syncLogRepo, s.logger, conn.ID, entries...)
+
+	return results, nil
+}
+
+// allocateUpdates takes pending reservations off manual stock updates and applies the stock
+// allocation rules of a connection. Listings without a product mapping reserve nothing and get
+// the connection's default rule.
+func (s *InventorySyncService) allocateUpdates(ctx context.Context, conn *domain.Connection, updates []providers.InventoryUpdate, mappings map[string]*domain.ProductMapping) ([]providers.InventoryUpdate, error) {
+	rules, err := s.stockAllocation.rulesFor(ctx, conn.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	productIDs := make(map[string]uuid.UUID, len(mappings))
+	internalIDs := make([]string, 0, len(mappings))
+	reservedIDs := make([]uuid.UUID, 0, len(mappings))
+	for externalID, m := range mappings {
+		productIDs[externalID] = m.InternalProductID
+		internalIDs = append(internalIDs, m.InternalProductID.String())
+		reservedIDs = append(reservedIDs, m.InternalProductID)
+	}
+	categories, err := s.stockAllocation.productCategories(ctx, rules, internalIDs)
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := s.stockAllocation.reservedStocks(ctx, reservedIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	allocated := make([]providers.InventoryUpdate, len(updates))
+	for i, u := range updates {
+		categoryID := ""
+		if productID, ok := productIDs[u.ExternalProductID]; ok {
+			u.Warehouses, u.Quantity = unreservedStock(u.Warehouses, u.Quantity, reserved[productID])
+			categoryID = categories[productID.String()]
+		}
+		rule := rules.forCategory(categoryID)
+		var allocation *domain.StockAllocation
+		u.Warehouses, allocation = allocateStock(rule, u.Warehouses, u.Quantity)
+		u.Quantity = allocation.Quantity
+		allocated[i] = u
 	}
+	return allocated, nil
 }
 
 // GetInventoryStatus fetches current inventory from marketplace
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+// bufferedStockUpdate is a pending stock update of one marketplace item and the mapping it belongs to
+type bufferedStockUpdate struct {
+	mapping domain.ProductMapping
+	update  providers.InventoryUpdate
+}
+
+// pendingStockUpdates holds the buffered updates of one connection until its window closes
+type pendingStockUpdates struct {
+	updates map[string]*bufferedStockUpdate
+	order   []string
+	timer   *time.Timer // Nil while the connection's previous flush is in progress
+}
+
+// inventoryUpdateBuffer coalesces stock updates per connection and marketplace item over a
+// window, keeping only the latest quantity, and hands each connection's updates to flush at
+// once when its window closes. A connection's flushes never overlap: its next window starts
+// once the current flush returns, so quantities reach the marketplace in order.
+type inventoryUpdateBuffer struct {
+	window time.Duration
+	flush  func(connectionID uuid.UUID, updates []bufferedStockUpdate)
+
+	mu       sync.Mutex
+	pending  map[uuid.UUID]*pendingStockUpdates
+	flushing map[uuid.UUID]bool // Connections with a flush in progress
+	stopped  bool
+	wg       sync.WaitGroup
+}
+
+// newInventoryUpdateBuffer creates a buffer that flushes a connection's updates window after the first one
+func newInventoryUpdateBuffer(window time.Duration, flush func(connectionID uuid.UUID, updates []bufferedStockUpdate)) *inventoryUpdateBuffer {
+	return &inventoryUpdateBuffer{
+		window:   window,
+		flush:    flush,
+		pending:  make(map[uuid.UUID]*pendingStockUpdates),
+		flushing: make(map[uuid.UUID]bool),
+	}
+}
+
+// Add buffers a stock update, replacing any pending update of the same item.
+// Once the buffer is stopped, updates are flushed right away.
+func (b *inventoryUpdateBuffer) Add(connectionID uuid.UUID, mapping domain.ProductMapping, update providers.InventoryUpdate) {
+	item := &bufferedStockUpdate{mapping: mapping, update: update}
+
+	b.mu.Lock()
+	p, ok := b.pending[connectionID]
+	if !ok {
+		p = &pendingStockUpdates{updates: make(map[string]*bufferedStockUpdate)}
+		b.pending[connectionID] = p
+		if !b.stopped && !b.flushing[connectionID] {
+			b.startWindow(connectionID, p)
+		}
+	}
+
+	key := stockKey(update.ExternalProductID, update.ExternalVariantID)
+	if _, ok := p.updates[key]; !ok {
+		p.order = append(p.order, key)
+	}
+	p.updates[key] = item
+	flushNow := b.stopped && !b.flushing[connectionID]
+	b.mu.Unlock()
+
+	if flushNow {
+		b.flushConnection(connectionID)
+	}
+}
+
+// startWindow flushes the pending updates of a connection when the window closes.
+// The caller holds b.mu.
+func (b *inventoryUpdateBuffer) startWindow(connectionID uuid.UUID, p *pendingStockUpdates) {
+	b.wg.Add(1)
+	p.timer = time.AfterFunc(b.window, func() {
+		defer b.wg.Done()
+		b.flushConnection(connectionID)
+	})
+}
+
+// flushConnection flushes the pending updates of a connection, if any. Updates buffered
+// during the flush open the connection's next window when it returns, or are flushed right
+// away once the buffer is stopped.
+func (b *inventoryUpdateBuffer) flushConnection(connectionID uuid.UUID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.flushing[connectionID] {
+		// The flush in progress takes these updates when it returns
+		return
+	}
+
+	for {
+		p, ok := b.pending[connectionID]
+		if !ok {
+			return
+		}
+		delete(b.pending, connectionID)
+		b.flushing[connectionID] = true
+		b.mu.Unlock()
+
+		updates := make([]bufferedStockUpdate, 0, len(p.order))
+		for _, key := range p.order {
+			updates = append(updates, *p.updates[key])
+		}
+		b.flush(connectionID, updates)
+
+		b.mu.Lock()
+		delete(b.flushing, connectionID)
+		next, ok := b.pending[connectionID]
+		if !ok {
+			return
+		}
+		if !b.stopped {
+			b.startWindow(connectionID, next)
+			return
+		}
+	}
+}
+
+// Stop flushes all pending updates and waits for flushes in progress
+func (b *inventoryUpdateBuffer) Stop() {
+	b.mu.Lock()
+	b.stopped = true
+	var due []uuid.UUID
+	for connectionID, p := range b.pending {
+		// Windows whose timer already fired are flushed by the timer, and updates waiting
+		// for a flush in progress are flushed when it returns
+		if p.timer != nil && p.timer.Stop() {
+			b.wg.Done()
+			due = append(due, connectionID)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, connectionID := range due {
+		b.flushConnection(connectionID)
+	}
+	b.wg.Wait()
+}
+
+// flushStockUpdates pushes the buffered stock updates of a connection in batches and reports
+// the outcome of each update against its mapping
+func (s *InventorySyncService) flushStockUpdates(connectionID uuid.UUID, items []bufferedStockUpdate) {
+	ctx := context.Background()
+
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		s.logger.Error("Failed to get connection", zap.Error(err))
+		return
+	}
+
+	fail := func(msg string) {
+		for i := range items {
+			s.publishSyncFailed(conn, &items[i].mapping, msg)
+		}
+	}
+
+	accessToken := conn.AccessToken
+	if s.encryptor != nil {
+		accessToken, err = s.encryptor.Decrypt(conn.AccessToken)
+		if err != nil {
+			s.logger.Error("Failed to decrypt token", zap.Error(err))
+			fail("failed to decrypt token")
+			return
+		}
+	}
+
+	access, err := s.stockAccessFor(conn, accessToken)
+	if err != nil {
+		s.logger.Error("Failed to create inventory provider",
+			zap.String("platform", conn.Platform),
+			zap.Error(err),
+		)
+		fail(err.Error())
+		return
+	}
+
+	updates := make([]providers.InventoryUpdate, len(items))
+	for i, item := range items {
+		updates[i] = item.update
+	}
+
+	results, err := access.updateBatchStock(ctx, updates)
+	if err == nil && len(results) != len(updates) {
+		err = fmt.Errorf("expected %d stock update results, got %d", len(updates), len(results))
+	}
+	if err != nil {
+		s.logger.Error("Failed to sync inventory batch",
+			zap.String("platform", conn.Platform),
+			zap.Int("updates", len(updates)),
+			zap.Error(err),
+		)
+		entries := make([]domain.InventorySyncLog, 0, len(items))
+		for i := range items {
+			entries = append(entries, stockPushLog(conn.ID, &items[i].mapping, items[i].update, domain.InventorySyncSourceEvent, err))
+		}
+		recordStockPushes(ctx, s.syncLogRepo, s.logger, conn.ID, entries...)
+		fail(err.Error())
+		return
+	}
+
+	failed := 0
+	entries := make([]domain.InventorySyncLog, 0, len(results))
+	for i, result := range results {
+		mapping := &items[i].mapping
+		var pushErr error
+		if !result.Success {
+			pushErr = errors.New(result.Error)
+		}
+		entries = append(entries, stockPushLog(conn.ID, mapping, items[i].update, domain.InventorySyncSourceEvent, pushErr))
+		if !result.Success {
+			failed++
+			s.logger.Error("Failed to sync inventory",
+				zap.String("platform", conn.Platform),
+				zap.String("product_id", mapping.InternalProductID.String()),
+				zap.String("error", result.Error),
+			)
+			s.publishSyncFailed(conn, mapping, result.Error)
+			continue
+		}
+		s.publishSyncCompleted(conn, mapping)
+	}
+	recordStockPushes(ctx, s.syncLogRepo, s.logger, conn.ID, entries...)
+
+	s.logger.Info("Inventory batch synced",
+		zap.String("platform", conn.Platform),
+		zap.String("connection_id", conn.ID.String()),
+		zap.Int("updates", len(updates)),
+		zap.Int("failed", failed),
+	)
+}
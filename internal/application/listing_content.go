@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/clients"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/infrastructure/persistence"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers/shopee"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers/tiktok"
+)
+
+var (
+	ErrListingTemplateNotFound = errors.New("listing template not found")
+	ErrListingOverrideNotFound = errors.New("listing override not found")
+	ErrUnknownPlaceholder      = errors.New("unknown template placeholder")
+	ErrDescriptionBlockTooLong = errors.New("description header and footer are too long")
+)
+
+// maxDescriptionBlockLength caps the combined header and footer length, leaving most of the
+// shortest marketplace description limit (Shopee, 1200 characters) to the description itself
+const maxDescriptionBlockLength = 500
+
+// placeholderPattern matches template placeholders such as {{brand}}
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+// ListingContentService applies per-connection templates and per-product overrides
+// to the content published on marketplaces
+type ListingContentService struct {
+	connectionRepo     *persistence.ConnectionRepository
+	listingContentRepo *persistence.ListingContentRepository
+	catalogClient      *clients.CatalogClient
+	logger             *zap.Logger
+}
+
+// NewListingContentService creates a new ListingContentService
+func NewListingContentService(
+	connectionRepo *persistence.ConnectionRepository,
+	listingContentRepo *persistence.ListingContentRepository,
+	catalogClient *clients.CatalogClient,
+	logger *zap.Logger,
+) *ListingContentService {
+	return &ListingContentService{
+		connectionRepo:     connectionRepo,
+		listingContentRepo: listingContentRepo,
+		catalogClient:      catalogClient,
+		logger:             logger,
+	}
+}
+
+// GetTemplate retrieves the listing template of a connection
+func (s *ListingContentService) GetTemplate(ctx context.Context, connectionID uuid.UUID) (*domain.ListingTemplate, error) {
+	template, err := s.listingContentRepo.GetTemplate(ctx, connectionID)
+	if err != nil {
+		return nil, ErrListingTemplateNotFound
+	}
+	return template, nil
+}
+
+// SaveTemplate creates or replaces the listing template of a connection
+func (s *ListingContentService) SaveTemplate(ctx context.Context, connectionID uuid.UUID, req *domain.SaveListingTemplateRequest) (*domain.ListingTemplate, error) {
+	// Verify connection exists
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	titleFields := []string{domain.PlaceholderName, domain.PlaceholderBrand, domain.PlaceholderVariant, domain.PlaceholderSKU, domain.PlaceholderCategory}
+	if err := validateTemplate(req.TitleTemplate, titleFields); err != nil {
+		return nil, err
+	}
+	if err := validateTemplate(req.DescriptionTemplate, append(titleFields, domain.PlaceholderDescription)); err != nil {
+		return nil, err
+	}
+	for _, block := range []string{req.DescriptionHeader, req.DescriptionFooter} {
+		if err := validateTemplate(block, titleFields); err != nil {
+			return nil, err
+		}
+	}
+	header := strings.TrimSpace(req.DescriptionHeader)
+	footer := strings.TrimSpace(req.DescriptionFooter)
+	if blockLen := utf8.RuneCountInString(header) + utf8.RuneCountInString(footer); blockLen > maxDescriptionBlockLength {
+		return nil, fmt.Errorf("%w: %d characters, at most %d allowed", ErrDescriptionBlockTooLong, blockLen, maxDescriptionBlockLength)
+	}
+
+	template, _ := s.listingContentRepo.GetTemplate(ctx, connectionID)
+	if template == nil {
+		template = &domain.ListingTemplate{ConnectionID: connectionID}
+	}
+	template.TitleTemplate = strings.TrimSpace(req.TitleTemplate)
+	template.DescriptionTemplate = strings.TrimSpace(req.DescriptionTemplate)
+	template.MaxTitleLength = req.MaxTitleLength
+	template.DescriptionHeader = header
+	template.DescriptionFooter = footer
+	template.ExtendedDescription = req.ExtendedDescription
+
+	if err := s.listingContentRepo.SaveTemplate(ctx, template); err != nil {
+		return nil, fmt.Errorf("failed to save listing template: %w", err)
+	}
+	return template, nil
+}
+
+// DeleteTemplate deletes the listing template of a connection
+func (s *ListingContentService) DeleteTemplate(ctx context.Context, connectionID uuid.UUID) error {
+	if _, err := s.listingContentRepo.GetTemplate(ctx, connectionID); err != nil {
+		return ErrListingTemplateNotFound
+	}
+	return s.listingContentRepo.DeleteTemplate(ctx, connectionID)
+}
+
+// GetOverrides retrieves the listing overrides of a connection
+func (s *ListingContentService) GetOverrides(ctx context.Context, connectionID uuid.UUID, filter *domain.ListingOverrideFilter) ([]domain.ListingOverride, int64, error) {
+	return s.listingContentRepo.GetOverrides(ctx, connectionID, filter)
+}
+
+// GetOverride retrieves the listing override of a product
+func (s *ListingContentService) GetOverride(ctx context.Context, connectionID, productID uuid.UUID) (*domain.ListingOverride, error) {
+	override, err := s.listingContentRepo.GetOverride(ctx, connectionID, productID)
+	if err != nil {
+		return nil, ErrListingOverrideNotFound
+	}
+	return override, nil
+}
+
+// SaveOverride creates or replaces the listing override of a product
+func (s *ListingContentService) SaveOverride(ctx context.Context, connectionID, productID uuid.UUID, req *domain.SaveListingOverrideRequest) (*domain.ListingOverride, error) {
+	// Verify connection exists
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	override, _ := s.listingContentRepo.GetOverride(ctx, connectionID, productID)
+	if override == nil {
+		override = &domain.ListingOverride{
+			ConnectionID:      connectionID,
+			InternalProductID: productID,
+		}
+	}
+	override.Title = trimmedOrNil(req.Title)
+	override.Description = trimmedOrNil(req.Description)
+	override.ImageOrder = marshalJSON(req.ImageOrder, "[]")
+	override.Attributes = marshalJSON(req.Attributes, "{}")
+	override.Price = req.Price
+
+	if err := s.listingContentRepo.SaveOverride(ctx, override); err != nil {
+		return nil, fmt.Errorf("failed to save listing override: %w", err)
+	}
+	return override, nil
+}
+
+// UpdateOverride changes part of the listing override of a product, creating it if needed
+func (s *ListingContentService) UpdateOverride(ctx context.Context, connectionID, productID uuid.UUID, update func(override *domain.ListingOverride)) error {
+	override, _ := s.listingContentRepo.GetOverride(ctx, connectionID, productID)
+	if override == nil {
+		override = &domain.ListingOverride{
+			ConnectionID:      connectionID,
+			InternalProductID: productID,
+		}
+	}
+	update(override)
+
+	if err := s.listingContentRepo.SaveOverride(ctx, override); err != nil {
+		return fmt.Errorf("failed to save listing override: %w", err)
+	}
+	return nil
+}
+
+// DeleteOverride deletes the listing override of a product
+func (s *ListingContentService) DeleteOverride(ctx context.Context, connectionID, productID uuid.UUID) error {
+	if _, err := s.listingContentRepo.GetOverride(ctx, connectionID, productID); err != nil {
+		return ErrListingOverrideNotFound
+	}
+	return s.listingContentRepo.DeleteOverride(ctx, connectionID, productID)
+}
+
+// Preview shows the content that would be published for a catalog product on a connection,
+// including the description as formatted for the connection's marketplace
+func (s *ListingContentService) Preview(ctx context.Context, connectionID uuid.UUID, productID string) (*domain.ListingContent, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	product, err := s.catalogClient.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product from catalog: %w", err)
+	}
+
+	content := s.Resolve(ctx, connectionID, product)
+	blocks := descriptionBlocks(content)
+	switch conn.Platform {
+	case "shopee":
+		content.FormattedDescription = shopee.FormatDescription(blocks)
+	case "tiktok":
+		content.FormattedDescription = tiktok.FormatDescription(blocks)
+	}
+	return content, nil
+}
+
+// Resolve computes the content published for a catalog product on a connection.
+// Overrides win over the template, which wins over the catalog. Falls back to the
+// catalog content when the template or override cannot be loaded.
+func (s *ListingContentService) Resolve(ctx context.Context, connectionID uuid.UUID, product *clients.Product) *domain.ListingContent {
+	content := &domain.ListingContent{
+		Title:       product.Name,
+		Description: product.Description,
+		Images:      catalogImageURLs(product),
+	}
+	if s == nil {
+		return content
+	}
+
+	maxTitleLength := 0
+	if template, err := s.listingContentRepo.GetTemplate(ctx, connectionID); err == nil {
+		values := templateValues(product)
+		if template.TitleTemplate != "" {
+			content.Title = renderTemplate(template.TitleTemplate, values)
+		}
+		if template.DescriptionTemplate != "" {
+			content.Description = renderTemplate(template.DescriptionTemplate, values)
+		}
+		content.DescriptionHeader = renderTemplate(template.DescriptionHeader, values)
+		content.DescriptionFooter = renderTemplate(template.DescriptionFooter, values)
+		content.ExtendedDescription = template.ExtendedDescription
+		maxTitleLength = template.MaxTitleLength
+	}
+
+	if productID, err := uuid.Parse(product.ID); err == nil {
+		if override, err := s.listingContentRepo.GetOverride(ctx, connectionID, productID); err == nil {
+			content.Overridden = true
+			if override.Title != nil {
+				content.Title = *override.Title
+			}
+			if override.Description != nil {
+				content.Description = *override.Description
+			}
+			if order := override.GetImageOrder(); len(order) > 0 {
+				content.Images = orderImages(content.Images, order)
+				content.ImagesOverridden = true
+			}
+			if attrs := override.GetAttributes(); len(attrs) > 0 {
+				content.Attributes = attrs
+			}
+			content.Price = override.Price
+		}
+	}
+
+	// The marketplace title limit applies to overridden titles too
+	content.Title = truncateTitle(content.Title, maxTitleLength)
+	return content
+}
+
+// descriptionBlocks parses the description of listing content into the blocks formatted for
+// each marketplace, framed by the header and footer
+func descriptionBlocks(content *domain.ListingContent) []providers.DescriptionBlock {
+	return providers.BuildDescription(content.DescriptionHeader, content.Description, content.DescriptionFooter)
+}
+
+// templateValues returns the placeholder values of a catalog product
+func templateValues(product *clients.Product) map[string]string {
+	variant := ""
+	if len(product.Variants) == 1 {
+		variant = product.Variants[0].Name
+	}
+	return map[string]string{
+		domain.PlaceholderName:        product.Name,
+		domain.PlaceholderBrand:       product.Brand,
+		domain.PlaceholderVariant:     variant,
+		domain.PlaceholderSKU:         product.SKU,
+		domain.PlaceholderCategory:    product.CategoryName,
+		domain.PlaceholderDescription: product.Description,
+	}
+}
+
+// validateTemplate rejects placeholders that are not allowed in a template
+func validateTemplate(template string, allowed []string) error {
+	for _, match := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+		known := false
+		for _, name := range allowed {
+			if match[1] == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("%w: %s", ErrUnknownPlaceholder, match[0])
+		}
+	}
+	return nil
+}
+
+// renderTemplate substitutes placeholders and tidies the separators left around empty values,
+// so "{{brand}} {{name}} - {{variant}}" renders "Shirt" for a product without brand or variant
+func renderTemplate(template string, values map[string]string) string {
+	rendered := placeholderPattern.ReplaceAllStringFunc(template, func(match string) string {
+		return values[placeholderPattern.FindStringSubmatch(match)[1]]
+	})
+
+	lines := strings.Split(rendered, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Trim(strings.Join(strings.Fields(line), " "), " -|,:/")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// truncateTitle shortens a title to maxLength characters at a word boundary
+func truncateTitle(title string, maxLength int) string {
+	if maxLength <= 0 || utf8.RuneCountInString(title) <= maxLength {
+		return title
+	}
+	runes := []rune(title)
+	cut := string(runes[:maxLength])
+	// Avoid ending on half a word unless the title has no spaces to break at
+	if runes[maxLength] != ' ' {
+		if i := strings.LastIndex(cut, " "); i > 0 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRight(cut, " -|,:/")
+}
+
+// orderImages moves the preferred images to the front, keeping the rest in catalog order.
+// Preferred URLs that are no longer in the catalog are ignored.
+func orderImages(images, preferred []string) []string {
+	if len(preferred) == 0 {
+		return images
+	}
+	remaining := make(map[string]bool, len(images))
+	for _, url := range images {
+		remaining[url] = true
+	}
+
+	ordered := make([]string, 0, len(images))
+	for _, url := range preferred {
+		if remaining[url] {
+			ordered = append(ordered, url)
+			delete(remaining, url)
+		}
+	}
+	for _, url := range images {
+		if remaining[url] {
+			ordered = append(ordered, url)
+		}
+	}
+	return ordered
+}
+
+// trimmedOrNil returns nil for a missing or blank string
+func trimmedOrNil(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
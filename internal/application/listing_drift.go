@@ -0,0 +1,648 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/clients"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-marketplace/internal/infrastructure/persistence"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+var (
+	ErrDriftCheckInProgress = errors.New("a drift check is already running for this connection")
+	ErrDriftNotFound        = errors.New("drift report not found")
+	ErrFieldNotDrifted      = errors.New("field has no drift")
+	ErrDriftNotResolvable   = errors.New("drift cannot be resolved with this action")
+)
+
+const (
+	// driftCheckPageSize is the number of mappings compared per batch
+	driftCheckPageSize = 50
+
+	// driftPriceTolerance absorbs rounding differences between catalog and marketplace prices
+	driftPriceTolerance = 0.01
+)
+
+// listingEditor writes catalog values to live marketplace listings
+type listingEditor struct {
+	updateProduct func(ctx context.Context, externalID string, req *providers.ProductUpdateRequest) error
+	updatePrices  func(ctx context.Context, externalID string, prices map[string]float64) error
+	updateStock   func(ctx context.Context, update providers.InventoryUpdate) error
+}
+
+// listingEditorFor returns the listing editor for a connection's platform
+func (s *ProductSyncService) listingEditorFor(conn *domain.Connection, accessToken string) (*listingEditor, error) {
+	switch conn.Platform {
+	case "shopee":
+		shopID, _ := strconv.ParseInt(conn.ShopID, 10, 64)
+		_, productProvider := s.shopeeClientFactory(accessToken, shopID)
+		return &listingEditor{
+			updateProduct: productProvider.UpdateProduct,
+			updatePrices: func(ctx context.Context, externalID string, prices map[string]float64) error {
+				itemID, err := strconv.ParseInt(externalID, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid item id: %w", err)
+				}
+				modelPrices := make(map[int64]float64, len(prices))
+				for id, price := range prices {
+					modelID, _ := strconv.ParseInt(id, 10, 64)
+					modelPrices[modelID] = price
+				}
+				return productProvider.UpdatePrice(ctx, itemID, modelPrices)
+			},
+			updateStock: func(ctx context.Context, update providers.InventoryUpdate) error {
+				return productProvider.UpdateInventory(ctx, []providers.InventoryUpdate{update})
+			},
+		}, nil
+
+	case "tiktok":
+		_, productProvider := s.tiktokClientFactory(accessToken, conn.ShopID)
+		return &listingEditor{
+			updateProduct: productProvider.UpdateProduct,
+			updatePrices:  productProvider.UpdatePrices,
+			updateStock: func(ctx context.Context, update providers.InventoryUpdate) error {
+				return productProvider.UpdateInventory(ctx, []providers.InventoryUpdate{update})
+			},
+		}, nil
+
+	default:
+		return nil, ErrInvalidPlatform
+	}
+}
+
+// StartDriftCheck queues a background comparison of every mapped listing against its catalog product
+func (s *ProductSyncService) StartDriftCheck(ctx context.Context, connectionID uuid.UUID) (*domain.SyncJob, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+	if conn.Platform != "shopee" && conn.Platform != "tiktok" {
+		return nil, ErrInvalidPlatform
+	}
+
+	data, _ := json.Marshal(domain.DriftCheckPayload{})
+	job := &domain.SyncJob{
+		ConnectionID: connectionID,
+		JobType:      domain.JobTypeDriftCheck,
+		Payload:      data,
+		Status:       domain.JobStatusPending,
+		MaxAttempts:  1,
+	}
+
+	if active, err := s.syncJobRepo.CreateExclusive(ctx, job); err != nil {
+		if errors.Is(err, persistence.ErrActiveJobExists) {
+			return active, ErrDriftCheckInProgress
+		}
+		return nil, fmt.Errorf("failed to create sync job: %w", err)
+	}
+
+	go s.processDriftCheckJob(context.Background(), job, conn)
+
+	return job, nil
+}
+
+// FailInterruptedDriftChecks fails drift checks left unfinished by a restart of the service, so
+// they no longer block new checks of their connection. Checks are cheap to rerun, so they are
+// not resumed.
+func (s *ProductSyncService) FailInterruptedDriftChecks(ctx context.Context) {
+	failed, err := s.syncJobRepo.FailUnfinishedByType(ctx, domain.JobTypeDriftCheck, "interrupted by a service restart")
+	if err != nil {
+		s.logger.Error("Failed to fail interrupted drift checks", zap.Error(err))
+		return
+	}
+	if failed > 0 {
+		s.logger.Info("Failed interrupted drift checks", zap.Int64("jobs", failed))
+	}
+}
+
+// GetDriftCheckJob returns a drift check job of a connection, including its progress
+func (s *ProductSyncService) GetDriftCheckJob(ctx context.Context, connectionID, jobID uuid.UUID) (*domain.SyncJob, error) {
+	return s.getConnectionJob(ctx, connectionID, jobID, domain.JobTypeDriftCheck)
+}
+
+// processDriftCheckJob compares the mapped listings of a connection page by page
+func (s *ProductSyncService) processDriftCheckJob(ctx context.Context, job *domain.SyncJob, conn *domain.Connection) {
+	// Mark as processing
+	if err := s.syncJobRepo.MarkProcessing(ctx, job.ID); err != nil {
+		s.logger.Error("Failed to mark job as processing", zap.Error(err))
+		return
+	}
+
+	// Decrypt access token
+	accessToken := conn.AccessToken
+	if s.encryptor != nil {
+		var err error
+		accessToken, err = s.encryptor.Decrypt(conn.AccessToken)
+		if err != nil {
+			s.syncJobRepo.MarkFailed(ctx, job.ID, "failed to decrypt token")
+			return
+		}
+	}
+
+	importer, err := s.productImporterFor(conn, accessToken)
+	if err != nil {
+		s.syncJobRepo.MarkFailed(ctx, job.ID, err.Error())
+		return
+	}
+
+	var payload domain.DriftCheckPayload
+	filter := &domain.ProductMappingFilter{Page: 1, PageSize: driftCheckPageSize}
+	for {
+		mappings, total, err := s.productMappingRepo.GetByConnectionID(ctx, conn.ID, filter)
+		if err != nil {
+			s.syncJobRepo.MarkFailed(ctx, job.ID, fmt.Sprintf("failed to load product mappings: %v", err))
+			return
+		}
+		job.TotalItems = int(total)
+		if len(mappings) == 0 {
+			break
+		}
+
+		drifted, failed := s.checkListingDrift(ctx, conn, importer, mappings)
+		payload.Drifted += drifted
+		job.ProcessedItems += len(mappings)
+		job.FailedItems += failed
+		job.Payload, _ = json.Marshal(payload)
+		if err := s.syncJobRepo.UpdateProgress(ctx, job); err != nil {
+			s.logger.Warn("Failed to save drift check progress", zap.String("job_id", job.ID.String()), zap.Error(err))
+		}
+
+		if len(mappings) < filter.PageSize {
+			break
+		}
+		filter.Page++
+	}
+
+	s.syncJobRepo.MarkCompleted(ctx, job.ID)
+
+	s.logger.Info("Drift check completed",
+		zap.String("job_id", job.ID.String()),
+		zap.String("connection_id", conn.ID.String()),
+		zap.Int("checked", job.ProcessedItems),
+		zap.Int("drifted", payload.Drifted),
+		zap.Int("failed", job.FailedItems),
+	)
+}
+
+// checkListingDrift fetches the live listings of a batch of mappings and stores a drift report
+// for each. Returns the number of drifted and of uncomparable listings.
+func (s *ProductSyncService) checkListingDrift(ctx context.Context, conn *domain.Connection, importer *productImporter, mappings []domain.ProductMapping) (int, int) {
+	externalIDs := make([]string, 0, len(mappings))
+	internalIDs := make([]string, 0, len(mappings))
+	mappingIDs := make([]uuid.UUID, 0, len(mappings))
+	for _, m := range mappings {
+		externalIDs = append(externalIDs, m.ExternalProductID)
+		internalIDs = append(internalIDs, m.InternalProductID.String())
+		mappingIDs = append(mappingIDs, m.ID)
+	}
+
+	live := make(map[string]*domain.ImportedProduct, len(mappings))
+	liveErr := ""
+	if listings, err := importer.fetchDetails(ctx, externalIDs); err != nil {
+		liveErr = err.Error()
+	} else {
+		for i := range listings {
+			live[listings[i].ExternalProductID] = &listings[i]
+		}
+	}
+
+	catalog := make(map[string]*clients.Product, len(mappings))
+	catalogErr := ""
+	if products, err := s.catalogClient.GetProducts(ctx, internalIDs); err != nil {
+		catalogErr = fmt.Sprintf("failed to fetch catalog product: %v", err)
+	} else {
+		for i := range products {
+			catalog[products[i].ID] = &products[i]
+		}
+	}
+
+	// Image baselines of the previous reports
+	baselines := make(map[uuid.UUID]*domain.ListingImageBaseline, len(mappings))
+	if previous, err := s.listingDriftRepo.GetByMappingIDs(ctx, mappingIDs); err == nil {
+		for i := range previous {
+			baselines[previous[i].ProductMappingID] = previous[i].GetImageBaseline()
+		}
+	} else {
+		s.logger.Warn("Failed to load previous drift reports, images are compared by count", zap.Error(err))
+	}
+
+	drifted, failed := 0, 0
+	checkedAt := time.Now()
+	for i := range mappings {
+		mapping := &mappings[i]
+		report := &domain.ListingDrift{
+			ConnectionID:      conn.ID,
+			ProductMappingID:  mapping.ID,
+			InternalProductID: mapping.InternalProductID,
+			ExternalProductID: mapping.ExternalProductID,
+			CheckedAt:         checkedAt,
+		}
+
+		listing := live[mapping.ExternalProductID]
+		product := catalog[mapping.InternalProductID.String()]
+		switch {
+		case liveErr != "":
+			report.CheckError = liveErr
+		case listing == nil:
+			report.CheckError = "listing not returned by the marketplace"
+		case catalogErr != "":
+			report.CheckError = catalogErr
+		case product == nil:
+			report.CheckError = "catalog product not found"
+		}
+
+		var fields []domain.DriftField
+		baseline := baselines[mapping.ID]
+		if report.CheckError == "" {
+			var err error
+			if fields, baseline, err = s.compareListing(ctx, conn, mapping, product, listing, baseline); err != nil {
+				report.CheckError = err.Error()
+				baseline = baselines[mapping.ID]
+			}
+		}
+		report.SetImageBaseline(baseline)
+		if report.CheckError != "" {
+			failed++
+		}
+		report.SetFields(fields)
+		if report.HasDrift {
+			drifted++
+		}
+
+		if err := s.listingDriftRepo.Upsert(ctx, report); err != nil {
+			s.logger.Warn("Failed to save drift report",
+				zap.String("mapping_id", mapping.ID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return drifted, failed
+}
+
+// compareListing returns the fields where the live listing differs from what the catalog would
+// publish, and the image baseline to keep for the next check
+func (s *ProductSyncService) compareListing(ctx context.Context, conn *domain.Connection, mapping *domain.ProductMapping, product *clients.Product, listing *domain.ImportedProduct, baseline *domain.ListingImageBaseline) ([]domain.DriftField, *domain.ListingImageBaseline, error) {
+	var fields []domain.DriftField
+
+	// Titles are compared after the listing template and override are applied
+	content := s.listingContent.Resolve(ctx, conn.ID, product)
+	if strings.TrimSpace(content.Title) != strings.TrimSpace(listing.Name) {
+		fields = append(fields, domain.DriftField{Field: domain.DriftFieldName, CatalogValue: content.Title, MarketplaceValue: listing.Name})
+	}
+
+	// Listings are published at the original price computed by the connection's pricing rules
+	price, err := s.publishedPrice(ctx, conn.ID, product, content)
+	if err != nil {
+		return nil, nil, err
+	}
+	if math.Abs(price-listing.Price) > driftPriceTolerance {
+		fields = append(fields, domain.DriftField{Field: domain.DriftFieldPrice, CatalogValue: price, MarketplaceValue: listing.Price})
+	}
+
+	// Listings get the stock left by the connection's stock allocation rules
+	_, stock, err := s.publishedStock(ctx, conn, mapping.InternalProductID, product)
+	if err != nil {
+		return nil, nil, err
+	}
+	if stock != listing.Stock {
+		fields = append(fields, domain.DriftField{Field: domain.DriftFieldStock, CatalogValue: stock, MarketplaceValue: listing.Stock})
+	}
+
+	if status, ok := importedListingStatus(conn.Platform, listing); ok && status != mapping.ListingStatus {
+		fields = append(fields, domain.DriftField{Field: domain.DriftFieldStatus, CatalogValue: mapping.ListingStatus, MarketplaceValue: status})
+	}
+
+	catalogImages := content.Images
+	liveImages := listing.GetImages()
+	current := &domain.ListingImageBaseline{
+		Catalog:     normalizeImageURLs(catalogImages),
+		Marketplace: normalizeImageURLs(liveImages),
+	}
+	if imagesDrifted(baseline, current) {
+		fields = append(fields, domain.DriftField{Field: domain.DriftFieldImages, CatalogValue: catalogImages, MarketplaceValue: liveImages})
+		// Keep comparing against the images as they last matched
+		return fields, baseline, nil
+	}
+
+	return fields, current, nil
+}
+
+// imagesDrifted reports whether the images of a listing differ from the catalog's.
+// Marketplaces re-host images under their own URLs, so each side is compared with its state
+// when the images last matched: one side changing alone is drift, both changing is an image
+// update that was published. Without a baseline only the image counts are compared.
+func imagesDrifted(baseline, current *domain.ListingImageBaseline) bool {
+	if len(current.Catalog) != len(current.Marketplace) {
+		return true
+	}
+	if baseline == nil {
+		return false
+	}
+	catalogChanged := !slices.Equal(current.Catalog, baseline.Catalog)
+	marketplaceChanged := !slices.Equal(current.Marketplace, baseline.Marketplace)
+	return catalogChanged != marketplaceChanged
+}
+
+// normalizeImageURLs strips the parts of image URLs that change between fetches of the same
+// image: scheme, query (e.g. signed CDN parameters) and fragment
+func normalizeImageURLs(urls []string) []string {
+	normalized := make([]string, 0, len(urls))
+	for _, raw := range urls {
+		raw = strings.TrimSpace(raw)
+		u, err := url.Parse(raw)
+		if err != nil {
+			normalized = append(normalized, raw)
+			continue
+		}
+		normalized = append(normalized, strings.ToLower(u.Host)+strings.TrimSuffix(u.Path, "/"))
+	}
+	return normalized
+}
+
+// GetListingDrifts returns the drift reports of a connection
+func (s *ProductSyncService) GetListingDrifts(ctx context.Context, connectionID uuid.UUID, filter *domain.ListingDriftFilter) ([]domain.ListingDrift, int64, error) {
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return nil, 0, ErrConnectionNotFound
+	}
+	return s.listingDriftRepo.GetByConnectionID(ctx, connectionID, filter)
+}
+
+// GetListingDrift returns the drift report of a product mapping
+func (s *ProductSyncService) GetListingDrift(ctx context.Context, connectionID, mappingID uuid.UUID) (*domain.ListingDrift, error) {
+	drift, err := s.listingDriftRepo.GetByMappingID(ctx, mappingID)
+	if err != nil || drift.ConnectionID != connectionID {
+		return nil, ErrDriftNotFound
+	}
+	return drift, nil
+}
+
+// ResolveListingDrift resolves one drifted field of a mapping, either by overwriting the
+// marketplace listing from the catalog (push) or by taking the marketplace value into our
+// records (accept). Values are re-read from both sides so stale reports are not applied.
+func (s *ProductSyncService) ResolveListingDrift(ctx context.Context, connectionID, mappingID uuid.UUID, req *domain.ResolveDriftRequest) (*domain.ListingDrift, error) {
+	drift, err := s.GetListingDrift(ctx, connectionID, mappingID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := drift.GetFields()
+	idx := -1
+	for i := range fields {
+		if fields[i].Field == req.Field {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, ErrFieldNotDrifted
+	}
+
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+	mapping, err := s.productMappingRepo.GetByID(ctx, mappingID)
+	if err != nil {
+		return nil, ErrProductMappingNotFound
+	}
+
+	product, err := s.catalogClient.GetProduct(ctx, mapping.InternalProductID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product from catalog: %w", err)
+	}
+
+	// Decrypt access token
+	accessToken := conn.AccessToken
+	if s.encryptor != nil {
+		accessToken, err = s.encryptor.Decrypt(conn.AccessToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt token: %w", err)
+		}
+	}
+
+	importer, err := s.productImporterFor(conn, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	listings, err := importer.fetchDetails(ctx, []string{mapping.ExternalProductID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch marketplace listing: %w", err)
+	}
+	if len(listings) == 0 {
+		return nil, fmt.Errorf("listing %s not returned by the marketplace", mapping.ExternalProductID)
+	}
+	listing := &listings[0]
+
+	switch req.Action {
+	case domain.DriftActionPush:
+		err = s.pushDriftedField(ctx, conn, accessToken, mapping, product, listing, req.Field)
+	case domain.DriftActionAccept:
+		err = s.acceptDriftedField(ctx, conn, mapping, product, listing, req.Field)
+	default:
+		err = ErrDriftNotResolvable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	drift.SetFields(append(fields[:idx], fields[idx+1:]...))
+	if req.Field == domain.DriftFieldImages {
+		// Both sides are compared afresh on the next check
+		drift.SetImageBaseline(nil)
+	}
+	if err := s.listingDriftRepo.UpdateFields(ctx, drift); err != nil {
+		return nil, fmt.Errorf("failed to update drift report: %w", err)
+	}
+
+	s.logger.Info("Listing drift resolved",
+		zap.String("mapping_id", mappingID.String()),
+		zap.String("field", req.Field),
+		zap.String("action", req.Action),
+	)
+
+	return drift, nil
+}
+
+// pushDriftedField overwrites one field of the marketplace listing with the catalog value
+func (s *ProductSyncService) pushDriftedField(ctx context.Context, conn *domain.Connection, accessToken string, mapping *domain.ProductMapping, product *clients.Product, listing *domain.ImportedProduct, field string) error {
+	editor, err := s.listingEditorFor(conn, accessToken)
+	if err != nil {
+		return err
+	}
+
+	content := s.listingContent.Resolve(ctx, conn.ID, product)
+
+	switch field {
+	case domain.DriftFieldName:
+		return editor.updateProduct(ctx, mapping.ExternalProductID, &providers.ProductUpdateRequest{Name: content.Title})
+
+	case domain.DriftFieldImages:
+		images := content.Images
+		if len(images) == 0 {
+			return fmt.Errorf("%w: catalog product has no images", ErrDriftNotResolvable)
+		}
+		return editor.updateProduct(ctx, mapping.ExternalProductID, &providers.ProductUpdateRequest{Images: images})
+
+	case domain.DriftFieldPrice:
+		// Every model gets the catalog price; a Shopee item without models is model 0
+		price, err := s.publishedPrice(ctx, conn.ID, product, content)
+		if err != nil {
+			return err
+		}
+		prices := make(map[string]float64)
+		for _, sku := range listing.GetSKUs() {
+			prices[sku.ExternalSKUID] = price
+		}
+		if len(prices) == 0 {
+			prices["0"] = price
+		}
+		return editor.updatePrices(ctx, mapping.ExternalProductID, prices)
+
+	case domain.DriftFieldStock:
+		// A single stock figure cannot be split over several models
+		if len(listing.GetSKUs()) > 1 {
+			return fmt.Errorf("%w: listing has several models, sync stock per variant instead", ErrDriftNotResolvable)
+		}
+		update := providers.InventoryUpdate{
+			ExternalProductID: mapping.ExternalProductID,
+			ExternalSKU:       mapping.ExternalSKU,
+		}
+		var err error
+		if update.Warehouses, update.Quantity, err = s.publishedStock(ctx, conn, mapping.InternalProductID, product); err != nil {
+			return err
+		}
+		err = editor.updateStock(ctx, update)
+		recordStockPushes(ctx, s.syncLogRepo, s.logger, conn.ID, stockPushLog(conn.ID, mapping, update, domain.InventorySyncSourceDrift, err))
+		return err
+
+	case domain.DriftFieldStatus:
+		if mapping.ListingStatus != shared.ListingActive && mapping.ListingStatus != shared.ListingSoldOut && mapping.ListingStatus != shared.ListingPaused {
+			return fmt.Errorf("%w: only active, sold out and paused listings can be pushed", ErrDriftNotResolvable)
+		}
+		active := mapping.ListingStatus != shared.ListingPaused
+		reason := mapping.ListingReason
+		if !active && reason == "" {
+			reason = domain.ListingReasonUnlisted
+		}
+		result, err := s.setListingsActive(ctx, conn.ID, []string{mapping.InternalProductID.String()}, active, reason)
+		if err != nil {
+			return err
+		}
+		if result.Failed > 0 && len(result.Items) > 0 {
+			return fmt.Errorf("failed to update listing status: %s", result.Items[0].Error)
+		}
+		return nil
+
+	default:
+		return ErrDriftNotResolvable
+	}
+}
+
+// acceptDriftedField takes the marketplace value of one field into the catalog or the product mapping
+func (s *ProductSyncService) acceptDriftedField(ctx context.Context, conn *domain.Connection, mapping *domain.ProductMapping, product *clients.Product, listing *domain.ImportedProduct, field string) error {
+	var update map[string]interface{}
+	content := s.listingContent.Resolve(ctx, conn.ID, product)
+
+	switch field {
+	case domain.DriftFieldName:
+		// A title shaped by a template or override is kept channel-specific
+		if content.Title != product.Name {
+			return s.listingContent.UpdateOverride(ctx, conn.ID, mapping.InternalProductID, func(o *domain.ListingOverride) {
+				o.Title = &listing.Name
+			})
+		}
+		update = map[string]interface{}{"name": listing.Name}
+
+	case domain.DriftFieldPrice:
+		if content.Price != nil {
+			return s.listingContent.UpdateOverride(ctx, conn.ID, mapping.InternalProductID, func(o *domain.ListingOverride) {
+				o.Price = &listing.Price
+			})
+		}
+		// A price produced by a pricing rule cannot be mapped back to a catalog price
+		quote, err := s.pricingService.Quote(ctx, conn.ID, product)
+		if err != nil {
+			return err
+		}
+		if quote.RuleID != nil {
+			return fmt.Errorf("%w: the marketplace price is derived from a pricing rule, adjust the rule instead", ErrDriftNotResolvable)
+		}
+		update = map[string]interface{}{"base_price": listing.Price}
+
+	case domain.DriftFieldStock:
+		// Stock limited by an allocation rule cannot be mapped back to catalog stock
+		rule, err := s.stockAllocation.ruleFor(ctx, conn.ID, product.CategoryID)
+		if err != nil {
+			return err
+		}
+		if rule != nil {
+			return fmt.Errorf("%w: the marketplace stock is limited by a stock allocation rule, adjust the rule instead", ErrDriftNotResolvable)
+		}
+		update = map[string]interface{}{"stock_quantity": listing.Stock}
+
+	case domain.DriftFieldImages:
+		images := make([]map[string]interface{}, 0, len(listing.GetImages()))
+		for i, url := range listing.GetImages() {
+			images = append(images, map[string]interface{}{
+				"url":        url,
+				"sort_order": i,
+				"is_primary": i == 0,
+			})
+		}
+		update = map[string]interface{}{"images": images}
+
+	case domain.DriftFieldStatus:
+		// The listing status is our own record, not a catalog field
+		status, ok := importedListingStatus(conn.Platform, listing)
+		if !ok {
+			return fmt.Errorf("%w: unknown marketplace status %s", ErrDriftNotResolvable, listing.Status)
+		}
+		return s.productMappingRepo.UpdateListingStatus(ctx, mapping.ID, status, domain.ListingReasonMarketplaceChange)
+
+	default:
+		return ErrDriftNotResolvable
+	}
+
+	if err := s.catalogClient.UpdateProduct(ctx, product.ID, update); err != nil {
+		return fmt.Errorf("failed to update catalog product: %w", err)
+	}
+	return nil
+}
+
+// publishedPrice returns the price a listing is published at: the override price, or the
+// original price computed by the connection's pricing rules. Sales run as discount campaigns
+// and do not change the listed price.
+func (s *ProductSyncService) publishedPrice(ctx context.Context, connectionID uuid.UUID, product *clients.Product, content *domain.ListingContent) (float64, error) {
+	if content.Price != nil {
+		return *content.Price, nil
+	}
+	quote, err := s.pricingService.RegularQuote(ctx, connectionID, product)
+	if err != nil {
+		return 0, err
+	}
+	return quote.OriginalPrice, nil
+}
+
+// catalogImageURLs returns the image URLs of a catalog product in order
+func catalogImageURLs(product *clients.Product) []string {
+	images := make([]string, 0, len(product.Images))
+	for _, img := range product.Images {
+		images = append(images, img.URL)
+	}
+	return images
+}
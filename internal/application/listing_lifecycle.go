@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+var (
+	ErrProductNotListed = errors.New("product has not been pushed to this marketplace")
+	ErrListingDeleted   = errors.New("listing was deleted on the marketplace")
+)
+
+// ListingActionResult is the outcome of an unlist or relist request
+type ListingActionResult struct {
+	Requested int                 `json:"requested"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Items     []ListingActionItem `json:"items"`
+}
+
+// ListingActionItem is the outcome for a single product
+type ListingActionItem struct {
+	ProductID         string               `json:"product_id"`
+	MappingID         *uuid.UUID           `json:"mapping_id,omitempty"`
+	ExternalProductID string               `json:"external_product_id,omitempty"`
+	ListingStatus     shared.ListingStatus `json:"listing_status,omitempty"`
+	Error             string               `json:"error,omitempty"`
+}
+
+// listingToggler unlists (active=false) or relists (active=true) marketplace products
+type listingToggler func(ctx context.Context, externalIDs []string, active bool) ([]providers.ListingStatusFailure, error)
+
+// listingTogglerFor returns the unlist/relist function for a connection's platform
+func (s *ProductSyncService) listingTogglerFor(conn *domain.Connection, accessToken string) (listingToggler, error) {
+	switch conn.Platform {
+	case "shopee":
+		shopID, _ := strconv.ParseInt(conn.ShopID, 10, 64)
+		_, productProvider := s.shopeeClientFactory(accessToken, shopID)
+		return func(ctx context.Context, externalIDs []string, active bool) ([]providers.ListingStatusFailure, error) {
+			return productProvider.UnlistItems(ctx, externalIDs, !active)
+		}, nil
+
+	case "tiktok":
+		_, productProvider := s.tiktokClientFactory(accessToken, conn.ShopID)
+		return func(ctx context.Context, externalIDs []string, active bool) ([]providers.ListingStatusFailure, error) {
+			if active {
+				return productProvider.ActivateProducts(ctx, externalIDs)
+			}
+			return productProvider.DeactivateProducts(ctx, externalIDs)
+		}, nil
+
+	default:
+		return nil, ErrInvalidPlatform
+	}
+}
+
+// UnlistProducts pauses the marketplace listings of catalog products without deleting them
+func (s *ProductSyncService) UnlistProducts(ctx context.Context, connectionID uuid.UUID, productIDs []string) (*ListingActionResult, error) {
+	return s.setListingsActive(ctx, connectionID, productIDs, false, domain.ListingReasonUnlisted)
+}
+
+// RelistProducts reactivates paused marketplace listings of catalog products
+func (s *ProductSyncService) RelistProducts(ctx context.Context, connectionID uuid.UUID, productIDs []string) (*ListingActionResult, error) {
+	return s.setListingsActive(ctx, connectionID, productIDs, true, "")
+}
+
+// UnlistMapping pauses the marketplace listing of a single product mapping
+func (s *ProductSyncService) UnlistMapping(ctx context.Context, connectionID, mappingID uuid.UUID) (*ListingActionResult, error) {
+	mapping, err := s.productMappingRepo.GetByID(ctx, mappingID)
+	if err != nil || mapping.ConnectionID != connectionID {
+		return nil, ErrProductMappingNotFound
+	}
+	return s.setListingsActive(ctx, connectionID, []string{mapping.InternalProductID.String()}, false, domain.ListingReasonUnlisted)
+}
+
+// RelistMapping reactivates the marketplace listing of a single product mapping
+func (s *ProductSyncService) RelistMapping(ctx context.Context, connectionID, mappingID uuid.UUID) (*ListingActionResult, error) {
+	mapping, err := s.productMappingRepo.GetByID(ctx, mappingID)
+	if err != nil || mapping.ConnectionID != connectionID {
+		return nil, ErrProductMappingNotFound
+	}
+	return s.setListingsActive(ctx, connectionID, []string{mapping.InternalProductID.String()}, true, "")
+}
+
+// PauseInactiveProduct pauses a product's listings after it was deactivated in the catalog
+func (s *ProductSyncService) PauseInactiveProduct(ctx context.Context, connectionID uuid.UUID, productID string) (*ListingActionResult, error) {
+	return s.setListingsActive(ctx, connectionID, []string{productID}, false, domain.ListingReasonCatalogInactive)
+}
+
+// setListingsActive unlists or relists the listings of catalog products and records the new listing status.
+// Listings already in the requested state are reported as succeeded without calling the marketplace.
+func (s *ProductSyncService) setListingsActive(ctx context.Context, connectionID uuid.UUID, productIDs []string, active bool, reason string) (*ListingActionResult, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	// Decrypt access token
+	accessToken := conn.AccessToken
+	if s.encryptor != nil {
+		accessToken, err = s.encryptor.Decrypt(conn.AccessToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt token: %w", err)
+		}
+	}
+
+	toggle, err := s.listingTogglerFor(conn, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	target := shared.ListingPaused
+	if active {
+		target = shared.ListingActive
+	}
+
+	result := &ListingActionResult{
+		Requested: len(productIDs),
+		Items:     make([]ListingActionItem, 0, len(productIDs)),
+	}
+
+	// Resolve mappings and collect the listings that need to change
+	mappings := make(map[string]*domain.ProductMapping)
+	var externalIDs []string
+	for _, productID := range productIDs {
+		item := ListingActionItem{ProductID: productID}
+
+		internalID, err := uuid.Parse(productID)
+		if err != nil {
+			item.Error = "invalid product ID"
+			result.Items = append(result.Items, item)
+			continue
+		}
+
+		mapping, err := s.productMappingRepo.GetByConnectionAndInternalProduct(ctx, connectionID, internalID)
+		if err != nil || mapping.ExternalProductID == "" {
+			item.Error = ErrProductNotListed.Error()
+			result.Items = append(result.Items, item)
+			continue
+		}
+
+		item.MappingID = &mapping.ID
+		item.ExternalProductID = mapping.ExternalProductID
+		item.ListingStatus = mapping.ListingStatus
+		if mapping.ListingStatus == shared.ListingDeleted {
+			item.Error = ErrListingDeleted.Error()
+			result.Items = append(result.Items, item)
+			continue
+		}
+		result.Items = append(result.Items, item)
+
+		// Sold out listings are live, only without stock
+		if mapping.ListingStatus == target || (active && mapping.ListingStatus == shared.ListingSoldOut) {
+			continue
+		}
+		if _, seen := mappings[mapping.ExternalProductID]; !seen {
+			mappings[mapping.ExternalProductID] = mapping
+			externalIDs = append(externalIDs, mapping.ExternalProductID)
+		}
+	}
+
+	failed := make(map[string]string)
+	if len(externalIDs) > 0 {
+		failures, err := toggle(ctx, externalIDs, active)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update listings: %w", err)
+		}
+		for _, f := range failures {
+			failed[f.ExternalProductID] = f.Reason
+		}
+	}
+
+	for i := range result.Items {
+		item := &result.Items[i]
+		if item.Error != "" {
+			result.Failed++
+			continue
+		}
+
+		mapping, changed := mappings[item.ExternalProductID]
+		if !changed {
+			result.Succeeded++
+			continue
+		}
+
+		if failReason, ok := failed[item.ExternalProductID]; ok {
+			item.Error = failReason
+			result.Failed++
+			continue
+		}
+
+		if err := s.productMappingRepo.UpdateListingStatus(ctx, mapping.ID, target, reason); err != nil {
+			s.logger.Warn("Failed to record listing status",
+				zap.String("mapping_id", mapping.ID.String()),
+				zap.Error(err),
+			)
+		}
+		item.ListingStatus = target
+		result.Succeeded++
+	}
+
+	return result, nil
+}
+
+// HandleShopeeItemStatusEvent records listing status changes pushed by Shopee webhooks
+func (s *ProductSyncService) HandleShopeeItemStatusEvent(shopID, itemID int64, itemStatus, reason string) {
+	status, ok := shopeeListingStatus(itemStatus)
+	if !ok {
+		s.logger.Debug("Ignoring unknown Shopee item status", zap.String("item_status", itemStatus))
+		return
+	}
+	if reason == "" && status != shared.ListingActive {
+		reason = itemStatus
+	}
+	s.recordListingStatus("shopee", fmt.Sprintf("%d", shopID), fmt.Sprintf("%d", itemID), status, reason)
+}
+
+// HandleTikTokProductStatusEvent records listing status changes pushed by TikTok webhooks
+func (s *ProductSyncService) HandleTikTokProductStatusEvent(shopID, productID, productStatus, reason string) {
+	status, ok := tiktokListingStatus(productStatus)
+	if !ok {
+		s.logger.Debug("Ignoring unknown TikTok product status", zap.String("status", productStatus))
+		return
+	}
+	if reason == "" && status != shared.ListingActive {
+		reason = productStatus
+	}
+	s.recordListingStatus("tiktok", shopID, productID, status, reason)
+}
+
+// recordListingStatus updates the listing status of the mapping for an external product
+func (s *ProductSyncService) recordListingStatus(platform, shopID, externalProductID string, status shared.ListingStatus, reason string) {
+	ctx := context.Background()
+
+	conn, err := s.connectionRepo.GetByPlatformAndShopID(ctx, platform, shopID)
+	if err != nil {
+		s.logger.Error("Connection not found for shop", zap.String("platform", platform), zap.String("shop_id", shopID))
+		return
+	}
+
+	mapping, err := s.productMappingRepo.GetByConnectionAndExternalProduct(ctx, conn.ID, externalProductID)
+	if err != nil {
+		s.logger.Debug("No product mapping for listing status event",
+			zap.String("platform", platform),
+			zap.String("external_product_id", externalProductID),
+		)
+		return
+	}
+
+	if err := s.productMappingRepo.UpdateListingStatus(ctx, mapping.ID, status, reason); err != nil {
+		s.logger.Error("Failed to update listing status", zap.String("mapping_id", mapping.ID.String()), zap.Error(err))
+		return
+	}
+
+	s.logger.Info("Listing status updated from webhook",
+		zap.String("platform", platform),
+		zap.String("external_product_id", externalProductID),
+		zap.String("listing_status", status.String()),
+	)
+}
+
+// initialListingStatus is the listing status of a freshly pushed product.
+// TikTok Shop reviews new products before they go live.
+func initialListingStatus(platform string) shared.ListingStatus {
+	if platform == "tiktok" {
+		return shared.ListingPending
+	}
+	return shared.ListingActive
+}
+
+// draftListingStatus returns the status and reason of a listing created as a draft.
+// Shopee has no drafts, so its draft listings are created unlisted and recorded as paused.
+func draftListingStatus(platform string) (shared.ListingStatus, string) {
+	if platform == "tiktok" {
+		return shared.ListingDraft, ""
+	}
+	return shared.ListingPaused, domain.ListingReasonDraft
+}
+
+// importedListingStatus maps the status of an imported product to a listing status.
+// Live listings without stock are sold out.
+func importedListingStatus(platform string, listing *domain.ImportedProduct) (shared.ListingStatus, bool) {
+	var status shared.ListingStatus
+	var ok bool
+	switch platform {
+	case "shopee":
+		status, ok = shopeeListingStatus(listing.Status)
+	case "tiktok":
+		status, ok = tiktokListingStatus(listing.Status)
+	}
+	if status == shared.ListingActive && listing.Stock <= 0 {
+		return shared.ListingSoldOut, true
+	}
+	return status, ok
+}
+
+// shopeeListingStatus maps a Shopee item_status to a listing status
+func shopeeListingStatus(itemStatus string) (shared.ListingStatus, bool) {
+	switch itemStatus {
+	case "NORMAL":
+		return shared.ListingActive, true
+	case "UNLIST":
+		return shared.ListingPaused, true
+	case "SELLER_DELETE":
+		return shared.ListingDeleted, true
+	case "REVIEWING":
+		return shared.ListingPending, true
+	case "BANNED", "SHOPEE_DELETE":
+		return shared.ListingRejected, true
+	default:
+		return "", false
+	}
+}
+
+// tiktokListingStatus maps a TikTok product status to a listing status
+func tiktokListingStatus(productStatus string) (shared.ListingStatus, bool) {
+	switch productStatus {
+	case "DRAFT":
+		return shared.ListingDraft, true
+	case "PENDING":
+		return shared.ListingPending, true
+	case "ACTIVATE", "LIVE":
+		return shared.ListingActive, true
+	case "SELLER_DEACTIVATED":
+		return shared.ListingPaused, true
+	case "DELETED":
+		return shared.ListingDeleted, true
+	case "FAILED", "PLATFORM_DEACTIVATED", "FREEZE":
+		return shared.ListingRejected, true
+	default:
+		return "", false
+	}
+}
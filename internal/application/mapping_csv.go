@@ -0,0 +1,871 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/clients"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/infrastructure/persistence"
+)
+
+var (
+	ErrInvalidMappingCSV       = errors.New("invalid mapping CSV")
+	ErrMappingImportInProgress = errors.New("a product mapping import is already running for this connection")
+)
+
+const (
+	mappingExportBatchSize = 500
+	maxMappingImportRows   = 5000 // Every product row costs a catalog lookup
+	mappingImportBatchSize = 100  // Catalog products fetched between progress updates
+)
+
+// ExportProductMappings writes the product and variant mappings of a connection as CSV,
+// one row per product followed by one row per variant
+func (s *ProductSyncService) ExportProductMappings(ctx context.Context, connectionID uuid.UUID, w io.Writer) error {
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return ErrConnectionNotFound
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(domain.ProductMappingCSVHeader); err != nil {
+		return err
+	}
+
+	err := s.productMappingRepo.FindInBatchesByConnectionID(ctx, connectionID, mappingExportBatchSize, func(mappings []domain.ProductMapping) error {
+		for _, m := range mappings {
+			productID := m.InternalProductID.String()
+			if err := writer.Write([]string{productID, m.ExternalProductID, m.ExternalSKU, "", "", ""}); err != nil {
+				return err
+			}
+			for _, v := range m.VariantMappings {
+				if err := writer.Write([]string{productID, m.ExternalProductID, "", v.InternalVariantID.String(), v.ExternalVariantID, v.ExternalSKU}); err != nil {
+					return err
+				}
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export product mappings: %w", err)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportCategoryMappings writes the category mappings of a connection as CSV
+func (s *ProductSyncService) ExportCategoryMappings(ctx context.Context, connectionID uuid.UUID, w io.Writer) error {
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return ErrConnectionNotFound
+	}
+
+	mappings, err := s.categoryMappingRepo.GetByConnectionID(ctx, connectionID)
+	if err != nil {
+		return fmt.Errorf("failed to get category mappings: %w", err)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(domain.CategoryMappingCSVHeader); err != nil {
+		return err
+	}
+	for _, m := range mappings {
+		if err := writer.Write([]string{
+			m.InternalCategoryID.String(),
+			m.ExternalCategoryID,
+			m.ExternalCategoryName,
+			strconv.FormatBool(m.Inherit),
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// productMappingImport is a product mapping read from a CSV, with its variant rows
+type productMappingImport struct {
+	row               int // Row product-level errors are reported on
+	hasProductRow     bool
+	internalProductID uuid.UUID
+	externalProductID string
+	externalSKU       string
+	variants          []variantMappingImport
+}
+
+// variantMappingImport is a variant mapping read from a CSV
+type variantMappingImport struct {
+	row               int
+	internalVariantID uuid.UUID
+	externalVariantID string
+	externalSKU       string
+}
+
+// ValidateProductMappings validates a product mapping CSV without applying it
+func (s *ProductSyncService) ValidateProductMappings(ctx context.Context, connectionID uuid.UUID, r io.Reader) (*domain.MappingImportReport, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	rows, err := readMappingCSV(r, domain.ProductMappingCSVHeader)
+	if err != nil {
+		return nil, err
+	}
+	return s.importProductMappings(ctx, conn, rows, true, nil)
+}
+
+// StartProductMappingImport queues a background import of a product mapping CSV. The file is
+// checked for well-formed columns and its size right away; rows are validated by the job, whose
+// payload holds the import report once it has run.
+func (s *ProductSyncService) StartProductMappingImport(ctx context.Context, connectionID uuid.UUID, r io.Reader) (*domain.SyncJob, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMappingCSV, err)
+	}
+	rows, err := readMappingCSV(bytes.NewReader(data), domain.ProductMappingCSVHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, _ := json.Marshal(domain.MappingImportPayload{CSV: string(data)})
+	job := &domain.SyncJob{
+		ConnectionID: connectionID,
+		JobType:      domain.JobTypeMappingImport,
+		Payload:      payload,
+		Status:       domain.JobStatusPending,
+		MaxAttempts:  1,
+		TotalItems:   len(rows),
+	}
+
+	if active, err := s.syncJobRepo.CreateExclusive(ctx, job); err != nil {
+		if errors.Is(err, persistence.ErrActiveJobExists) {
+			return active, ErrMappingImportInProgress
+		}
+		return nil, fmt.Errorf("failed to create sync job: %w", err)
+	}
+
+	go s.processMappingImportJob(context.Background(), job, conn, rows)
+
+	return job, nil
+}
+
+// GetMappingImportJob returns a product mapping import job of a connection, including its
+// progress and, once it has run, its report
+func (s *ProductSyncService) GetMappingImportJob(ctx context.Context, connectionID, jobID uuid.UUID) (*domain.SyncJob, error) {
+	return s.getConnectionJob(ctx, connectionID, jobID, domain.JobTypeMappingImport)
+}
+
+// FailInterruptedMappingImports fails mapping imports left unfinished by a restart of the
+// service, so they no longer block new imports of their connection
+func (s *ProductSyncService) FailInterruptedMappingImports(ctx context.Context) {
+	failed, err := s.syncJobRepo.FailUnfinishedByType(ctx, domain.JobTypeMappingImport, "interrupted by a service restart")
+	if err != nil {
+		s.logger.Error("Failed to fail interrupted mapping imports", zap.Error(err))
+		return
+	}
+	if failed > 0 {
+		s.logger.Info("Failed interrupted mapping imports", zap.Int64("jobs", failed))
+	}
+}
+
+// processMappingImportJob validates and applies the rows of a product mapping CSV. Progress
+// counts the products checked against the catalog; once done, it counts the rows.
+func (s *ProductSyncService) processMappingImportJob(ctx context.Context, job *domain.SyncJob, conn *domain.Connection, rows []mappingCSVRow) {
+	if err := s.syncJobRepo.MarkProcessing(ctx, job.ID); err != nil {
+		s.logger.Error("Failed to mark job as processing", zap.Error(err))
+		return
+	}
+
+	report, err := s.importProductMappings(ctx, conn, rows, false, func(checked, total int) {
+		job.ProcessedItems, job.TotalItems = checked, total
+		if err := s.syncJobRepo.UpdateProgress(ctx, job); err != nil {
+			s.logger.Warn("Failed to save mapping import progress", zap.String("job_id", job.ID.String()), zap.Error(err))
+		}
+	})
+	if err != nil {
+		s.logger.Error("Product mapping import failed", zap.String("job_id", job.ID.String()), zap.Error(err))
+		s.syncJobRepo.MarkFailed(ctx, job.ID, err.Error())
+		return
+	}
+
+	job.Payload, _ = json.Marshal(domain.MappingImportPayload{Report: report})
+	job.TotalItems = report.TotalRows
+	job.ProcessedItems = report.TotalRows
+	job.FailedItems = report.InvalidRows
+	if err := s.syncJobRepo.UpdateProgress(ctx, job); err != nil {
+		s.logger.Error("Failed to save mapping import report", zap.String("job_id", job.ID.String()), zap.Error(err))
+	}
+	s.syncJobRepo.MarkCompleted(ctx, job.ID)
+}
+
+// importProductMappings validates product mapping CSV rows and applies them in one transaction.
+// Every row is checked against the catalog, the marketplace listings and the existing mappings;
+// nothing is applied on a dry run or when any row is invalid. progress, if set, is called as
+// the products of valid rows are checked against the catalog.
+//
+// Products without variant rows keep their variant mappings, unless they are mapped to a new
+// listing, in which case variants are paired automatically as for a manual mapping.
+func (s *ProductSyncService) importProductMappings(ctx context.Context, conn *domain.Connection, rows []mappingCSVRow, dryRun bool, progress func(checked, total int)) (*domain.MappingImportReport, error) {
+	report := &domain.MappingImportReport{
+		DryRun:    dryRun,
+		TotalRows: len(rows),
+		Errors:    []domain.MappingImportRowError{},
+	}
+	errs := newMappingImportErrors(report)
+	imports := parseProductMappingRows(rows, errs)
+
+	internalIDs := make([]uuid.UUID, 0, len(imports))
+	productIDs := make([]string, 0, len(imports))
+	externalIDs := make([]string, 0, len(imports))
+	for _, imp := range imports {
+		internalIDs = append(internalIDs, imp.internalProductID)
+		productIDs = append(productIDs, imp.internalProductID.String())
+		externalIDs = append(externalIDs, imp.externalProductID)
+	}
+
+	// Catalog products; missing ones are left out
+	catalog := make(map[string]*clients.Product, len(productIDs))
+	for start := 0; start < len(productIDs); start += mappingImportBatchSize {
+		products, err := s.catalogClient.GetProducts(ctx, productIDs[start:min(start+mappingImportBatchSize, len(productIDs))])
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch products from catalog: %w", err)
+		}
+		for i := range products {
+			catalog[products[i].ID] = &products[i]
+		}
+		if progress != nil {
+			progress(min(start+mappingImportBatchSize, len(productIDs)), len(productIDs))
+		}
+	}
+
+	// Marketplace listings, from imported products or else from the marketplace itself
+	listings := make(map[string]*domain.ImportedProduct, len(externalIDs))
+	imported, err := s.importedProductRepo.GetByExternalProductIDs(ctx, conn.ID, externalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get imported products: %w", err)
+	}
+	for i := range imported {
+		listings[imported[i].ExternalProductID] = &imported[i]
+	}
+	var missing []string
+	for _, id := range externalIDs {
+		if listings[id] == nil {
+			missing = append(missing, id)
+		}
+	}
+	fetched, err := s.fetchMarketplaceListings(ctx, conn, missing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch listings from marketplace: %w", err)
+	}
+	for i := range fetched {
+		listings[fetched[i].ExternalProductID] = &fetched[i]
+	}
+
+	// Existing mappings of the products and of the listings
+	current, err := s.productMappingRepo.GetByConnectionAndInternalProducts(ctx, conn.ID, internalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product mappings: %w", err)
+	}
+	byInternal := make(map[uuid.UUID]*domain.ProductMapping, len(current))
+	for i := range current {
+		byInternal[current[i].InternalProductID] = &current[i]
+	}
+	holders, err := s.productMappingRepo.GetByConnectionAndExternalProducts(ctx, conn.ID, externalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product mappings: %w", err)
+	}
+	byExternal := make(map[string]*domain.ProductMapping, len(holders))
+	for i := range holders {
+		byExternal[holders[i].ExternalProductID] = &holders[i]
+	}
+
+	// Products the file moves to another listing release their current one
+	released := make(map[uuid.UUID]string)
+	for _, imp := range imports {
+		if m := byInternal[imp.internalProductID]; m != nil && m.ExternalProductID != imp.externalProductID {
+			released[imp.internalProductID] = m.ExternalProductID
+		}
+	}
+
+	var mappings []*domain.ProductMapping
+	for _, imp := range imports {
+		before := errs.count()
+
+		product := catalog[imp.internalProductID.String()]
+		if product == nil {
+			errs.add(imp.row, "internal_product_id", "product not found in catalog")
+		}
+		listing := listings[imp.externalProductID]
+		switch {
+		case listing == nil:
+			errs.add(imp.row, "external_product_id", "listing not found in imported products or on the marketplace")
+		case listing.IsRemoved:
+			errs.add(imp.row, "external_product_id", "listing was removed from the marketplace")
+		}
+		if holder := byExternal[imp.externalProductID]; holder != nil && holder.InternalProductID != imp.internalProductID {
+			if _, ok := released[holder.InternalProductID]; !ok {
+				errs.add(imp.row, "external_product_id", "listing is already mapped to internal product %s", holder.InternalProductID)
+			}
+		}
+
+		variantMappings := validateVariantImports(imp, product, listing, errs)
+		if errs.count() > before {
+			continue
+		}
+
+		existing := byInternal[imp.internalProductID]
+		externalSKU := imp.externalSKU
+		newListing := existing == nil || existing.ExternalProductID != imp.externalProductID
+		if newListing && len(imp.variants) == 0 {
+			variantMappings = autoVariantMappings(listing.GetSKUs(), product)
+			// Stock of a multi-model listing can only be pushed per model
+			if len(listing.GetSKUs()) > 1 && len(variantMappings) == 0 {
+				errs.add(imp.row, "external_product_id", "listing has several models and none could be paired automatically, add variant rows")
+				continue
+			}
+		}
+		switch {
+		case existing == nil:
+			if externalSKU == "" {
+				externalSKU = importedExternalSKU(conn.Platform, listing)
+			}
+			mapping := &domain.ProductMapping{
+				ConnectionID:      conn.ID,
+				InternalProductID: imp.internalProductID,
+				ExternalProductID: imp.externalProductID,
+				ExternalSKU:       externalSKU,
+				SyncStatus:        domain.SyncStatusSynced,
+				VariantMappings:   variantMappings,
+			}
+			if status, ok := importedListingStatus(conn.Platform, listing); ok {
+				mapping.ListingStatus = status
+			}
+			mappings = append(mappings, mapping)
+			report.Created++
+
+		case existing.ExternalProductID != imp.externalProductID:
+			if externalSKU == "" {
+				externalSKU = importedExternalSKU(conn.Platform, listing)
+			}
+			mappings = append(mappings, &domain.ProductMapping{
+				ID:                existing.ID,
+				InternalProductID: imp.internalProductID,
+				ExternalProductID: imp.externalProductID,
+				ExternalSKU:       externalSKU,
+				VariantMappings:   variantMappings,
+			})
+			report.Updated++
+
+		default:
+			if externalSKU == "" {
+				externalSKU = existing.ExternalSKU
+			}
+			if len(imp.variants) == 0 {
+				variantMappings = nil
+			}
+			if externalSKU == existing.ExternalSKU && (variantMappings == nil || sameVariantMappings(existing.VariantMappings, variantMappings)) {
+				report.Unchanged++
+				continue
+			}
+			mappings = append(mappings, &domain.ProductMapping{
+				ID:                existing.ID,
+				InternalProductID: imp.internalProductID,
+				ExternalProductID: imp.externalProductID,
+				ExternalSKU:       externalSKU,
+				VariantMappings:   variantMappings,
+			})
+			report.Updated++
+		}
+	}
+
+	report.InvalidRows = errs.rows()
+	if report.InvalidRows > 0 || dryRun {
+		return report, nil
+	}
+
+	if len(mappings) > 0 {
+		if err := s.productMappingRepo.ImportMappings(ctx, mappings); err != nil {
+			return nil, fmt.Errorf("failed to import product mappings: %w", err)
+		}
+	}
+	report.Applied = true
+
+	s.syncImportedMappingFlags(ctx, conn, mappings, listings, fetched, released)
+
+	s.logger.Info("Product mappings imported",
+		zap.String("connection_id", conn.ID.String()),
+		zap.Int("created", report.Created),
+		zap.Int("updated", report.Updated),
+		zap.Int("unchanged", report.Unchanged),
+	)
+
+	return report, nil
+}
+
+// parseProductMappingRows groups product mapping CSV rows by internal product, reporting
+// malformed rows and rows that contradict each other
+func parseProductMappingRows(rows []mappingCSVRow, errs *mappingImportErrors) []*productMappingImport {
+	byProduct := make(map[uuid.UUID]*productMappingImport)
+	byExternal := make(map[string]*productMappingImport)
+	var imports []*productMappingImport
+
+	for _, row := range rows {
+		f := row.fields
+		productID, err := uuid.Parse(f[0])
+		if err != nil {
+			errs.add(row.line, "internal_product_id", "must be a UUID")
+			continue
+		}
+		externalID := f[1]
+		if externalID == "" {
+			errs.add(row.line, "external_product_id", "is required")
+			continue
+		}
+		isVariant := f[3] != "" || f[4] != ""
+		var variantID uuid.UUID
+		if isVariant {
+			if variantID, err = uuid.Parse(f[3]); err != nil {
+				errs.add(row.line, "internal_variant_id", "must be a UUID")
+				continue
+			}
+			if f[4] == "" {
+				errs.add(row.line, "external_variant_id", "is required on a variant row")
+				continue
+			}
+		}
+
+		imp := byProduct[productID]
+		if imp == nil {
+			if other := byExternal[externalID]; other != nil {
+				errs.add(row.line, "external_product_id", "listing is also mapped to internal product %s on row %d", other.internalProductID, other.row)
+				continue
+			}
+			imp = &productMappingImport{row: row.line, internalProductID: productID, externalProductID: externalID}
+			byProduct[productID] = imp
+			byExternal[externalID] = imp
+			imports = append(imports, imp)
+		} else if imp.externalProductID != externalID {
+			errs.add(row.line, "external_product_id", "differs from %s on row %d for the same product", imp.externalProductID, imp.row)
+			continue
+		}
+
+		if !isVariant {
+			if imp.hasProductRow {
+				errs.add(row.line, "internal_product_id", "product is already mapped on row %d", imp.row)
+				continue
+			}
+			imp.hasProductRow = true
+			imp.row = row.line
+			imp.externalSKU = f[2]
+			continue
+		}
+
+		duplicate := false
+		for _, v := range imp.variants {
+			if v.internalVariantID == variantID {
+				errs.add(row.line, "internal_variant_id", "variant is already mapped on row %d", v.row)
+				duplicate = true
+			} else if v.externalVariantID == f[4] {
+				errs.add(row.line, "external_variant_id", "model is already mapped on row %d", v.row)
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			imp.variants = append(imp.variants, variantMappingImport{
+				row:               row.line,
+				internalVariantID: variantID,
+				externalVariantID: f[4],
+				externalSKU:       f[5],
+			})
+		}
+	}
+
+	return imports
+}
+
+// validateVariantImports checks the variant rows of a product against the catalog variants
+// and the listing's models and returns the variant mappings they describe
+func validateVariantImports(imp *productMappingImport, product *clients.Product, listing *domain.ImportedProduct, errs *mappingImportErrors) []domain.VariantMapping {
+	if len(imp.variants) == 0 {
+		return nil
+	}
+
+	variantIDs := make(map[uuid.UUID]bool)
+	if product != nil {
+		for _, v := range product.Variants {
+			if id, err := uuid.Parse(v.ID); err == nil {
+				variantIDs[id] = true
+			}
+		}
+	}
+	skus := make(map[string]domain.ImportedSKU)
+	if listing != nil {
+		for _, sku := range listing.GetSKUs() {
+			skus[sku.ExternalSKUID] = sku
+		}
+	}
+
+	mappings := make([]domain.VariantMapping, 0, len(imp.variants))
+	for _, v := range imp.variants {
+		if product != nil && !variantIDs[v.internalVariantID] {
+			errs.add(v.row, "internal_variant_id", "variant not found on catalog product")
+		}
+		sku, ok := skus[v.externalVariantID]
+		if listing != nil && !ok {
+			errs.add(v.row, "external_variant_id", "model not found on the listing")
+		}
+
+		externalSKU := v.externalSKU
+		if externalSKU == "" {
+			externalSKU = sku.SellerSKU
+		}
+		mappings = append(mappings, domain.VariantMapping{
+			InternalVariantID: v.internalVariantID,
+			ExternalVariantID: v.externalVariantID,
+			ExternalSKU:       externalSKU,
+		})
+	}
+	return mappings
+}
+
+// sameVariantMappings reports whether two sets of variant mappings pair the same variants and models
+func sameVariantMappings(a, b []domain.VariantMapping) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	pairs := make(map[uuid.UUID]domain.VariantMapping, len(a))
+	for _, v := range a {
+		pairs[v.InternalVariantID] = v
+	}
+	for _, v := range b {
+		other, ok := pairs[v.InternalVariantID]
+		if !ok || other.ExternalVariantID != v.ExternalVariantID || other.ExternalSKU != v.ExternalSKU {
+			return false
+		}
+	}
+	return true
+}
+
+// importedExternalSKU is the external SKU recorded on a mapping to an imported listing.
+// TikTok inventory updates address SKUs by ID; the models of multi-model listings are
+// addressed through their variant mappings instead.
+func importedExternalSKU(platform string, listing *domain.ImportedProduct) string {
+	if platform == "tiktok" {
+		if skus := listing.GetSKUs(); len(skus) == 1 {
+			return skus[0].ExternalSKUID
+		}
+	}
+	return listing.ExternalSKU
+}
+
+// fetchMarketplaceListings fetches listings directly from the marketplace.
+// Listings that do not exist or cannot be fetched are left out.
+func (s *ProductSyncService) fetchMarketplaceListings(ctx context.Context, conn *domain.Connection, externalIDs []string) ([]domain.ImportedProduct, error) {
+	if len(externalIDs) == 0 {
+		return nil, nil
+	}
+
+	// Decrypt access token
+	accessToken := conn.AccessToken
+	if s.encryptor != nil {
+		var err error
+		accessToken, err = s.encryptor.Decrypt(conn.AccessToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt token: %w", err)
+		}
+	}
+
+	importer, err := s.productImporterFor(conn, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var listings []domain.ImportedProduct
+	for start := 0; start < len(externalIDs); start += shopeeImportPageSize {
+		batch := externalIDs[start:min(start+shopeeImportPageSize, len(externalIDs))]
+		products, err := importer.fetchDetails(ctx, batch)
+		if err == nil {
+			listings = append(listings, products...)
+			continue
+		}
+
+		// Retry one by one so a single unknown listing does not hide the others
+		for _, id := range batch {
+			products, err := importer.fetchDetails(ctx, []string{id})
+			if err != nil {
+				s.logger.Debug("Listing not found on marketplace",
+					zap.String("external_product_id", id),
+					zap.Error(err),
+				)
+				continue
+			}
+			listings = append(listings, products...)
+		}
+	}
+	return listings, nil
+}
+
+// syncImportedMappingFlags keeps the is_mapped flag of imported products in line with imported
+// mappings. Listings fetched from the marketplace are saved as imported products.
+func (s *ProductSyncService) syncImportedMappingFlags(ctx context.Context, conn *domain.Connection, mappings []*domain.ProductMapping, listings map[string]*domain.ImportedProduct, fetched []domain.ImportedProduct, released map[uuid.UUID]string) {
+	// Saving marks the fetched listings that now have a mapping
+	if _, err := s.saveImportedProducts(ctx, conn, fetched); err != nil {
+		s.logger.Warn("Failed to save listings fetched for mapping import", zap.Error(err))
+	}
+
+	mapped := make(map[string]bool, len(mappings))
+	for _, m := range mappings {
+		mapped[m.ExternalProductID] = true
+		if listing := listings[m.ExternalProductID]; listing != nil && listing.ID != uuid.Nil {
+			if err := s.importedProductRepo.SetMapped(ctx, listing.ID, m.InternalProductID); err != nil {
+				s.logger.Warn("Failed to mark imported product as mapped", zap.Error(err))
+			}
+		}
+	}
+
+	for _, externalID := range released {
+		if mapped[externalID] {
+			continue
+		}
+		if imported, _ := s.importedProductRepo.GetByExternalProductID(ctx, conn.ID, externalID); imported != nil {
+			if err := s.importedProductRepo.SetUnmapped(ctx, imported.ID); err != nil {
+				s.logger.Warn("Failed to mark imported product as unmapped", zap.Error(err))
+			}
+		}
+	}
+}
+
+// ImportCategoryMappings validates a category mapping CSV and applies it in one transaction.
+// Internal categories must exist in the catalog and external ones in the marketplace category
+// tree; nothing is applied on a dry run or when any row is invalid.
+func (s *ProductSyncService) ImportCategoryMappings(ctx context.Context, connectionID uuid.UUID, r io.Reader, dryRun bool) (*domain.MappingImportReport, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	rows, err := readMappingCSV(r, domain.CategoryMappingCSVHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.catalogClient.GetCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch categories from catalog: %w", err)
+	}
+	internal := make(map[string]bool, len(categories))
+	for _, cat := range categories {
+		internal[cat.ID] = true
+	}
+
+	externalCategories, err := s.cachedExternalCategories(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	external := make(map[string]*domain.ExternalCategory, len(externalCategories))
+	for i := range externalCategories {
+		external[externalCategories[i].CategoryID] = &externalCategories[i]
+	}
+
+	existing, err := s.categoryMappingRepo.GetByConnectionID(ctx, conn.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category mappings: %w", err)
+	}
+	byInternal := make(map[uuid.UUID]*domain.CategoryMapping, len(existing))
+	for i := range existing {
+		byInternal[existing[i].InternalCategoryID] = &existing[i]
+	}
+
+	report := &domain.MappingImportReport{
+		DryRun:    dryRun,
+		TotalRows: len(rows),
+		Errors:    []domain.MappingImportRowError{},
+	}
+	errs := newMappingImportErrors(report)
+
+	seen := make(map[uuid.UUID]int)
+	var mappings []*domain.CategoryMapping
+	for _, row := range rows {
+		f := row.fields
+		before := errs.count()
+
+		categoryID, err := uuid.Parse(f[0])
+		if err != nil {
+			errs.add(row.line, "internal_category_id", "must be a UUID")
+			continue
+		}
+		if prev, ok := seen[categoryID]; ok {
+			errs.add(row.line, "internal_category_id", "category is already mapped on row %d", prev)
+			continue
+		}
+		seen[categoryID] = row.line
+		if !internal[categoryID.String()] {
+			errs.add(row.line, "internal_category_id", "category not found in catalog")
+		}
+
+		category := external[f[1]]
+		switch {
+		case f[1] == "":
+			errs.add(row.line, "external_category_id", "is required")
+		case category == nil:
+			errs.add(row.line, "external_category_id", "category not found on the marketplace")
+		case !category.IsActive:
+			errs.add(row.line, "external_category_id", "category no longer exists on the marketplace")
+		}
+
+		inherit := true
+		if f[3] != "" {
+			if inherit, err = strconv.ParseBool(f[3]); err != nil {
+				errs.add(row.line, "inherit", "must be true or false")
+			}
+		}
+		if errs.count() > before {
+			continue
+		}
+
+		name := f[2]
+		if name == "" {
+			name = category.Name
+		}
+
+		current := byInternal[categoryID]
+		switch {
+		case current == nil:
+			mappings = append(mappings, &domain.CategoryMapping{
+				ConnectionID:         conn.ID,
+				InternalCategoryID:   categoryID,
+				ExternalCategoryID:   f[1],
+				ExternalCategoryName: name,
+				Inherit:              inherit,
+			})
+			report.Created++
+		case current.ExternalCategoryID == f[1] && current.ExternalCategoryName == name && current.Inherit == inherit && !current.IsStale:
+			report.Unchanged++
+		default:
+			updated := *current
+			updated.ExternalCategoryID = f[1]
+			updated.ExternalCategoryName = name
+			updated.Inherit = inherit
+			updated.IsStale = false
+			mappings = append(mappings, &updated)
+			report.Updated++
+		}
+	}
+
+	report.InvalidRows = errs.rows()
+	if report.InvalidRows > 0 || dryRun {
+		return report, nil
+	}
+
+	if len(mappings) > 0 {
+		if err := s.categoryMappingRepo.ImportMappings(ctx, mappings); err != nil {
+			return nil, fmt.Errorf("failed to import category mappings: %w", err)
+		}
+	}
+	report.Applied = true
+
+	s.logger.Info("Category mappings imported",
+		zap.String("connection_id", conn.ID.String()),
+		zap.Int("created", report.Created),
+		zap.Int("updated", report.Updated),
+		zap.Int("unchanged", report.Unchanged),
+	)
+
+	return report, nil
+}
+
+// mappingCSVRow is a data row of a mapping CSV with its fields trimmed
+type mappingCSVRow struct {
+	line   int
+	fields []string
+}
+
+// readMappingCSV checks the header of a mapping CSV and reads its data rows
+func readMappingCSV(r io.Reader, header []string) ([]mappingCSVRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(header)
+
+	columns, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidMappingCSV)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMappingCSV, err)
+	}
+	for i, column := range header {
+		// Spreadsheet exports may start with a byte order mark
+		name := strings.TrimPrefix(columns[i], "\ufeff")
+		if !strings.EqualFold(strings.TrimSpace(name), column) {
+			return nil, fmt.Errorf("%w: expected column %d to be %q, got %q", ErrInvalidMappingCSV, i+1, column, columns[i])
+		}
+	}
+
+	var rows []mappingCSVRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidMappingCSV, err)
+		}
+		if len(rows) == maxMappingImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidMappingCSV, maxMappingImportRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+		rows = append(rows, mappingCSVRow{line: line, fields: record})
+	}
+	return rows, nil
+}
+
+// mappingImportErrors collects the row errors of a mapping import into its report
+type mappingImportErrors struct {
+	report  *domain.MappingImportReport
+	invalid map[int]bool
+}
+
+func newMappingImportErrors(report *domain.MappingImportReport) *mappingImportErrors {
+	return &mappingImportErrors{report: report, invalid: make(map[int]bool)}
+}
+
+// add records an error on a row
+func (e *mappingImportErrors) add(row int, column, format string, args ...interface{}) {
+	e.invalid[row] = true
+	e.report.Errors = append(e.report.Errors, domain.MappingImportRowError{
+		Row:    row,
+		Column: column,
+		Error:  fmt.Sprintf(format, args...),
+	})
+}
+
+// count returns the number of errors recorded so far
+func (e *mappingImportErrors) count() int {
+	return len(e.report.Errors)
+}
+
+// rows returns the number of rows with at least one error
+func (e *mappingImportErrors) rows() int {
+	return len(e.invalid)
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+)
+
+func TestReadMappingCSV(t *testing.T) {
+	header := "internal_product_id,external_product_id,external_sku,internal_variant_id,external_variant_id,external_variant_sku\n"
+
+	tests := []struct {
+		name    string
+		csv     string
+		want    []mappingCSVRow
+		wantErr string
+	}{
+		{
+			name:    "empty file",
+			csv:     "",
+			wantErr: "file is empty",
+		},
+		{
+			name: "header only",
+			csv:  header,
+			want: nil,
+		},
+		{
+			name: "rows are trimmed and numbered by line",
+			csv:  header + " p1 , 100 ,SKU-1,,,\np1,100,,v1,200, SKU-1-RED \n",
+			want: []mappingCSVRow{
+				{line: 2, fields: []string{"p1", "100", "SKU-1", "", "", ""}},
+				{line: 3, fields: []string{"p1", "100", "", "v1", "200", "SKU-1-RED"}},
+			},
+		},
+		{
+			name: "header with byte order mark, other case and spaces",
+			csv:  "\ufeffInternal_Product_ID, external_product_id ,EXTERNAL_SKU,internal_variant_id,external_variant_id,external_variant_sku\r\np1,100,,,,\r\n",
+			want: []mappingCSVRow{
+				{line: 2, fields: []string{"p1", "100", "", "", "", ""}},
+			},
+		},
+		{
+			name: "quoted fields spanning lines keep the starting line",
+			csv:  header + "p1,\"100\",\"multi\nline\",,,\np2,101,,,,\n",
+			want: []mappingCSVRow{
+				{line: 2, fields: []string{"p1", "100", "multi\nline", "", "", ""}},
+				{line: 4, fields: []string{"p2", "101", "", "", "", ""}},
+			},
+		},
+		{
+			name:    "wrong column",
+			csv:     "internal_product_id,external_sku,external_product_id,internal_variant_id,external_variant_id,external_variant_sku\n",
+			wantErr: `expected column 2 to be "external_product_id"`,
+		},
+		{
+			name:    "missing columns",
+			csv:     "internal_product_id,external_product_id\n",
+			wantErr: "wrong number of fields",
+		},
+		{
+			name:    "row with missing fields",
+			csv:     header + "p1,100\n",
+			wantErr: "wrong number of fields",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readMappingCSV(strings.NewReader(tt.csv), domain.ProductMappingCSVHeader)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidMappingCSV) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readMappingCSV() error = %v, want ErrInvalidMappingCSV containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readMappingCSV() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readMappingCSV() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMappingCSVRowLimit(t *testing.T) {
+	var b strings.Builder
+	b.WriteString(strings.Join(domain.ProductMappingCSVHeader, ",") + "\n")
+	for i := 0; i <= maxMappingImportRows; i++ {
+		b.WriteString("p,1,,,,\n")
+	}
+
+	_, err := readMappingCSV(strings.NewReader(b.String()), domain.ProductMappingCSVHeader)
+	if !errors.Is(err, ErrInvalidMappingCSV) {
+		t.Fatalf("readMappingCSV() error = %v, want ErrInvalidMappingCSV", err)
+	}
+}
+
+func TestParseProductMappingRows(t *testing.T) {
+	p1 := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	p2 := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	v1 := uuid.MustParse("aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa")
+	v2 := uuid.MustParse("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb")
+
+	row := func(line int, fields ...string) mappingCSVRow {
+		return mappingCSVRow{line: line, fields: fields}
+	}
+
+	tests := []struct {
+		name       string
+		rows       []mappingCSVRow
+		want       []*productMappingImport
+		wantErrors []domain.MappingImportRowError
+	}{
+		{
+			name: "product and variant rows are grouped",
+			rows: []mappingCSVRow{
+				row(2, p1.String(), "100", "SKU", "", "", ""),
+				row(3, p1.String(), "100", "", v1.String(), "m1", "SKU-RED"),
+				row(4, p2.String(), "101", "", "", "", ""),
+				row(5, p1.String(), "100", "", v2.String(), "m2", ""),
+			},
+			want: []*productMappingImport{
+				{
+					row:               2,
+					hasProductRow:     true,
+					internalProductID: p1,
+					externalProductID: "100",
+					externalSKU:       "SKU",
+					variants: []variantMappingImport{
+						{row: 3, internalVariantID: v1, externalVariantID: "m1", externalSKU: "SKU-RED"},
+						{row: 5, internalVariantID: v2, externalVariantID: "m2"},
+					},
+				},
+				{row: 4, hasProductRow: true, internalProductID: p2, externalProductID: "101"},
+			},
+		},
+		{
+			name: "variant rows before the product row",
+			rows: []mappingCSVRow{
+				row(2, p1.String(), "100", "", v1.String(), "m1", "SKU-RED"),
+				row(3, p1.String(), "100", "", v2.String(), "m2", ""),
+				row(4, p1.String(), "100", "SKU", "", "", ""),
+				row(5, p2.String(), "101", "", "", "", ""),
+			},
+			want: []*productMappingImport{
+				{
+					row:               4,
+					hasProductRow:     true,
+					internalProductID: p1,
+					externalProductID: "100",
+					externalSKU:       "SKU",
+					variants: []variantMappingImport{
+						{row: 2, internalVariantID: v1, externalVariantID: "m1", externalSKU: "SKU-RED"},
+						{row: 3, internalVariantID: v2, externalVariantID: "m2"},
+					},
+				},
+				{row: 5, hasProductRow: true, internalProductID: p2, externalProductID: "101"},
+			},
+		},
+		{
+			name: "malformed rows",
+			rows: []mappingCSVRow{
+				row(2, "not-a-uuid", "100", "", "", "", ""),
+				row(3, p1.String(), "", "", "", "", ""),
+				row(4, p1.String(), "100", "", "bad", "m1", ""),
+				row(5, p1.String(), "100", "", v1.String(), "", ""),
+			},
+			want: nil,
+			wantErrors: []domain.MappingImportRowError{
+				{Row: 2, Column: "internal_product_id", Error: "must be a UUID"},
+				{Row: 3, Column: "external_product_id", Error: "is required"},
+				{Row: 4, Column: "internal_variant_id", Error: "must be a UUID"},
+				{Row: 5, Column: "external_variant_id", Error: "is required on a variant row"},
+			},
+		},
+		{
+			name: "contradicting rows",
+			rows: []mappingCSVRow{
+				row(2, p1.String(), "100", "", "", "", ""),
+				row(3, p1.String(), "999", "", "", "", ""),
+				row(4, p1.String(), "100", "", "", "", ""),
+				row(5, p2.String(), "100", "", "", "", ""),
+				row(6, p1.String(), "100", "", v1.String(), "m1", ""),
+				row(7, p1.String(), "100", "", v1.String(), "m2", ""),
+				row(8, p1.String(), "100", "", v2.String(), "m1", ""),
+			},
+			want: []*productMappingImport{
+				{
+					row:               2,
+					hasProductRow:     true,
+					internalProductID: p1,
+					externalProductID: "100",
+					variants: []variantMappingImport{
+						{row: 6, internalVariantID: v1, externalVariantID: "m1"},
+					},
+				},
+			},
+			wantErrors: []domain.MappingImportRowError{
+				{Row: 3, Column: "external_product_id", Error: "differs from 100 on row 2 for the same product"},
+				{Row: 4, Column: "internal_product_id", Error: "product is already mapped on row 2"},
+				{Row: 5, Column: "external_product_id", Error: "listing is also mapped to internal product " + p1.String() + " on row 2"},
+				{Row: 7, Column: "internal_variant_id", Error: "variant is already mapped on row 6"},
+				{Row: 8, Column: "external_variant_id", Error: "model is already mapped on row 6"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &domain.MappingImportReport{}
+			got := parseProductMappingRows(tt.rows, newMappingImportErrors(report))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseProductMappingRows() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(report.Errors, tt.wantErrors) {
+				t.Errorf("parseProductMappingRows() errors = %+v, want %+v", report.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
@@ -12,8 +12,10 @@ import (
 	"github.com/Ecom-micro-template/service-marketplace/internal/clients"
 	"github.com/Ecom-micro-template/service-marketplace/internal/events"
 	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain/shared"
 	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
 	"github.com/Ecom-micro-template/service-marketplace/internal/providers/shopee"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers/tiktok"
 	"github.com/Ecom-micro-template/service-marketplace/internal/infrastructure/persistence"
 	"github.com/Ecom-micro-template/service-marketplace/internal/utils"
 )
@@ -24,6 +26,10 @@ type MarketplaceSyncHandler struct {
 	connectionRepo      *persistence.ConnectionRepository
 	productMappingRepo  *persistence.ProductMappingRepository
 	categoryMappingRepo *persistence.CategoryMappingRepository
+	pricingService      *PricingService
+	listingContent      *ListingContentService
+	productSyncService  *ProductSyncService
+	inventorySync       *InventorySyncService
 	catalogClient       *clients.CatalogClient
 	eventPublisher      *events.Publisher
 	encryptor           *utils.Encryptor
@@ -34,8 +40,13 @@ type MarketplaceSyncHandler struct {
 	shopeePartnerKey string
 	shopeeSandbox    bool
 
+	// TikTok configuration
+	tiktokAppKey    string
+	tiktokAppSecret string
+
 	// Auto-sync settings
-	autoSyncEnabled bool
+	autoSyncEnabled       bool
+	pauseInactiveProducts bool
 }
 
 // MarketplaceSyncHandlerConfig holds configuration for the sync handler
@@ -43,8 +54,14 @@ type MarketplaceSyncHandlerConfig struct {
 	ShopeePartnerID  string
 	ShopeePartnerKey string
 	ShopeeSandbox    bool
+	TikTokAppKey     string
+	TikTokAppSecret  string
 	EncryptionKey    string
 	AutoSyncEnabled  bool
+
+	// PauseInactiveProducts unlists marketplace listings when the catalog product is
+	// deactivated, and relists them when it is reactivated
+	PauseInactiveProducts bool
 }
 
 // NewMarketplaceSyncHandler creates a new marketplace sync handler
@@ -52,6 +69,10 @@ func NewMarketplaceSyncHandler(
 	connectionRepo *persistence.ConnectionRepository,
 	productMappingRepo *persistence.ProductMappingRepository,
 	categoryMappingRepo *persistence.CategoryMappingRepository,
+	pricingService *PricingService,
+	listingContent *ListingContentService,
+	productSyncService *ProductSyncService,
+	inventorySync *InventorySyncService,
 	catalogClient *clients.CatalogClient,
 	eventPublisher *events.Publisher,
 	cfg *MarketplaceSyncHandlerConfig,
@@ -70,6 +91,10 @@ func NewMarketplaceSyncHandler(
 		connectionRepo:      connectionRepo,
 		productMappingRepo:  productMappingRepo,
 		categoryMappingRepo: categoryMappingRepo,
+		pricingService:      pricingService,
+		listingContent:      listingContent,
+		productSyncService:  productSyncService,
+		inventorySync:       inventorySync,
 		catalogClient:       catalogClient,
 		eventPublisher:      eventPublisher,
 		encryptor:           encryptor,
@@ -77,7 +102,11 @@ func NewMarketplaceSyncHandler(
 		shopeePartnerID:     cfg.ShopeePartnerID,
 		shopeePartnerKey:    cfg.ShopeePartnerKey,
 		shopeeSandbox:       cfg.ShopeeSandbox,
+		tiktokAppKey:        cfg.TikTokAppKey,
+		tiktokAppSecret:     cfg.TikTokAppSecret,
 		autoSyncEnabled:     cfg.AutoSyncEnabled,
+
+		pauseInactiveProducts: cfg.PauseInactiveProducts,
 	}, nil
 }
 
@@ -88,29 +117,37 @@ func (h *MarketplaceSyncHandler) HandleStockChanged(event *events.StockChangedEv
 		return nil
 	}
 
-	ctx := context.Background()
+	// Stock is pushed per marketplace warehouse by the inventory sync service
+	return h.inventorySync.SyncStockChange(context.Background(), event)
+}
 
-	// Find all product mappings for this product
-	mappings, err := h.productMappingRepo.GetByInternalProductID(ctx, event.ProductID)
-	if err != nil {
-		return fmt.Errorf("failed to get product mappings: %w", err)
+// HandleProductCreated handles product creation events from catalog service by
+// auto-listing the product on connections whose auto-listing rule it matches
+func (h *MarketplaceSyncHandler) HandleProductCreated(event *events.ProductCreatedEvent) error {
+	if !h.autoSyncEnabled || h.productSyncService == nil {
+		h.logger.Debug("Auto-sync disabled, skipping product created event")
+		return nil
 	}
 
-	if len(mappings) == 0 {
-		h.logger.Debug("No marketplace mappings found for product", zap.String("product_id", event.ProductID.String()))
+	if !event.IsActive {
+		h.logger.Debug("Product is inactive, skipping auto-listing", zap.String("product_id", event.ProductID))
 		return nil
 	}
 
-	// Sync inventory to each connected marketplace
-	for _, mapping := range mappings {
-		if err := h.syncInventoryToMarketplace(ctx, &mapping, event.NewQuantity); err != nil {
-			h.logger.Error("Failed to sync inventory to marketplace",
-				zap.String("connection_id", mapping.ConnectionID.String()),
-				zap.String("product_id", event.ProductID.String()),
-				zap.Error(err),
-			)
-			continue
-		}
+	if _, err := uuid.Parse(event.ProductID); err != nil {
+		return fmt.Errorf("invalid product ID: %w", err)
+	}
+
+	jobs, err := h.productSyncService.AutoListProduct(context.Background(), event.ProductID)
+	if err != nil {
+		return fmt.Errorf("failed to auto-list product: %w", err)
+	}
+
+	if len(jobs) > 0 {
+		h.logger.Info("Auto-listing product on marketplaces",
+			zap.String("product_id", event.ProductID),
+			zap.Int("marketplace_count", len(jobs)),
+		)
 	}
 
 	return nil
@@ -141,6 +178,26 @@ func (h *MarketplaceSyncHandler) HandleProductUpdated(event *events.ProductUpdat
 		return nil
 	}
 
+	// Pause listings instead of pushing updates for deactivated products
+	if h.pauseInactiveProducts && h.productSyncService != nil {
+		if !event.IsActive {
+			h.logger.Info("Pausing marketplace listings for inactive product",
+				zap.String("product_id", event.ProductID),
+				zap.Int("marketplace_count", len(mappings)),
+			)
+			for _, mapping := range mappings {
+				go h.setListingActive(ctx, &mapping, false)
+			}
+			return nil
+		}
+
+		for _, mapping := range mappings {
+			if mapping.ListingStatus == shared.ListingPaused && mapping.ListingReason == domain.ListingReasonCatalogInactive {
+				go h.setListingActive(ctx, &mapping, true)
+			}
+		}
+	}
+
 	h.logger.Info("Syncing product update to marketplaces",
 		zap.String("product_id", event.ProductID),
 		zap.Int("marketplace_count", len(mappings)),
@@ -198,6 +255,31 @@ func (h *MarketplaceSyncHandler) HandleProductDeleted(event *events.ProductDelet
 	return nil
 }
 
+// setListingActive pauses or relists a listing after the catalog product was (de)activated
+func (h *MarketplaceSyncHandler) setListingActive(ctx context.Context, mapping *domain.ProductMapping, active bool) {
+	productID := mapping.InternalProductID.String()
+
+	var result *ListingActionResult
+	var err error
+	if active {
+		result, err = h.productSyncService.RelistProducts(ctx, mapping.ConnectionID, []string{productID})
+	} else {
+		result, err = h.productSyncService.PauseInactiveProduct(ctx, mapping.ConnectionID, productID)
+	}
+	if err == nil && result.Failed > 0 {
+		err = fmt.Errorf("%s", result.Items[0].Error)
+	}
+
+	if err != nil {
+		h.logger.Error("Failed to update marketplace listing status",
+			zap.String("connection_id", mapping.ConnectionID.String()),
+			zap.String("external_product_id", mapping.ExternalProductID),
+			zap.Bool("active", active),
+			zap.Error(err),
+		)
+	}
+}
+
 // syncProductUpdateToMarketplace syncs a product update to a specific marketplace
 func (h *MarketplaceSyncHandler) syncProductUpdateToMarketplace(ctx context.Context, mapping *domain.ProductMapping, product *clients.Product) {
 	// Get connection details
@@ -243,6 +325,8 @@ func (h *MarketplaceSyncHandler) syncProductUpdateToMarketplace(ctx context.Cont
 	switch conn.Platform {
 	case "shopee":
 		syncErr = h.updateProductOnShopee(ctx, conn, mapping, product)
+	case "tiktok":
+		syncErr = h.updateProductOnTikTok(ctx, conn, mapping, product)
 	default:
 		syncErr = fmt.Errorf("unsupported platform: %s", conn.Platform)
 	}
@@ -276,23 +360,22 @@ func (h *MarketplaceSyncHandler) updateProductOnShopee(ctx context.Context, conn
 
 	productProvider := shopee.NewProductProvider(client)
 
-	// Build update request
-	price := product.BasePrice
-	if product.SalePrice != nil {
-		price = *product.SalePrice
-	}
-
-	updateReq := &providers.ProductUpdateRequest{
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       &price,
-	}
-
 	// Update on Shopee
+	updateReq, err := h.productUpdateRequest(ctx, conn, product)
+	if err != nil {
+		return err
+	}
 	if err := productProvider.UpdateProduct(ctx, mapping.ExternalProductID, updateReq); err != nil {
 		return fmt.Errorf("failed to update product on Shopee: %w", err)
 	}
 
+	// Start, replace or end the discount campaign when the catalog sale changed
+	if h.productSyncService != nil {
+		if err := h.productSyncService.SyncSaleCampaign(ctx, conn, mapping, product); err != nil {
+			return fmt.Errorf("failed to sync sale campaign: %w", err)
+		}
+	}
+
 	h.logger.Info("Successfully synced product update to Shopee",
 		zap.String("internal_product_id", mapping.InternalProductID.String()),
 		zap.String("external_product_id", mapping.ExternalProductID),
@@ -302,23 +385,9 @@ func (h *MarketplaceSyncHandler) updateProductOnShopee(ctx context.Context, conn
 	return nil
 }
 
-// syncInventoryToMarketplace syncs inventory to a specific marketplace
-func (h *MarketplaceSyncHandler) syncInventoryToMarketplace(ctx context.Context, mapping *domain.ProductMapping, quantity int) error {
-	conn, err := h.connectionRepo.GetByID(ctx, mapping.ConnectionID)
-	if err != nil || !conn.IsActive {
-		return fmt.Errorf("connection not found or inactive")
-	}
-
-	switch conn.Platform {
-	case "shopee":
-		return h.updateInventoryOnShopee(ctx, conn, mapping, quantity)
-	default:
-		return fmt.Errorf("unsupported platform: %s", conn.Platform)
-	}
-}
-
-// updateInventoryOnShopee updates inventory on Shopee
-func (h *MarketplaceSyncHandler) updateInventoryOnShopee(ctx context.Context, conn *domain.Connection, mapping *domain.ProductMapping, quantity int) error {
+// updateProductOnTikTok updates a product on TikTok Shop
+func (h *MarketplaceSyncHandler) updateProductOnTikTok(ctx context.Context, conn *domain.Connection, mapping *domain.ProductMapping, product *clients.Product) error {
+	// Decrypt access token
 	accessToken := conn.AccessToken
 	if h.encryptor != nil {
 		var err error
@@ -328,40 +397,76 @@ func (h *MarketplaceSyncHandler) updateInventoryOnShopee(ctx context.Context, co
 		}
 	}
 
-	client, err := shopee.NewClient(&shopee.ClientConfig{
-		PartnerID:  h.shopeePartnerID,
-		PartnerKey: h.shopeePartnerKey,
-		IsSandbox:  h.shopeeSandbox,
-		Logger:     h.logger,
+	client := tiktok.NewClient(&tiktok.ClientConfig{
+		AppKey:    h.tiktokAppKey,
+		AppSecret: h.tiktokAppSecret,
+		Logger:    h.logger,
 	})
+	client.SetTokens(accessToken, conn.ShopID)
+
+	productProvider := tiktok.NewProductProvider(client)
+
+	// Update on TikTok Shop; prices are set per SKU
+	updateReq, err := h.productUpdateRequest(ctx, conn, product)
 	if err != nil {
 		return err
 	}
-
-	shopID, _ := strconv.ParseInt(conn.ShopID, 10, 64)
-	client.SetTokens(accessToken, shopID)
-
-	productProvider := shopee.NewProductProvider(client)
-
-	updates := []providers.InventoryUpdate{
-		{
-			ExternalProductID: mapping.ExternalProductID,
-			Quantity:          quantity,
-		},
+	if err := productProvider.UpdateProduct(ctx, mapping.ExternalProductID, updateReq); err != nil {
+		return fmt.Errorf("failed to update product on TikTok: %w", err)
 	}
+	if h.productSyncService != nil {
+		prices := h.productSyncService.listingSKUPrices(ctx, conn, mapping, *updateReq.OriginalPrice)
+		if err := productProvider.UpdatePrices(ctx, mapping.ExternalProductID, prices); err != nil {
+			return fmt.Errorf("failed to update prices on TikTok: %w", err)
+		}
 
-	if err := productProvider.UpdateInventory(ctx, updates); err != nil {
-		return fmt.Errorf("failed to update inventory on Shopee: %w", err)
+		// Start, replace or end the promotion when the catalog sale changed
+		if err := h.productSyncService.SyncSaleCampaign(ctx, conn, mapping, product); err != nil {
+			return fmt.Errorf("failed to sync sale campaign: %w", err)
+		}
 	}
 
-	h.logger.Info("Successfully synced inventory to Shopee",
+	h.logger.Info("Successfully synced product update to TikTok",
+		zap.String("internal_product_id", mapping.InternalProductID.String()),
 		zap.String("external_product_id", mapping.ExternalProductID),
-		zap.Int("quantity", quantity),
+		zap.String("shop_id", conn.ShopID),
 	)
 
 	return nil
 }
 
+// productUpdateRequest builds the update of a listing with the connection's pricing rules,
+// listing template and the product's override applied, so catalog changes never overwrite
+// channel-specific content. The listing keeps its regular price; a sale runs as a discount
+// campaign.
+func (h *MarketplaceSyncHandler) productUpdateRequest(ctx context.Context, conn *domain.Connection, product *clients.Product) (*providers.ProductUpdateRequest, error) {
+	quote, err := h.pricingService.RegularQuote(ctx, conn.ID, product)
+	if err != nil {
+		return nil, err
+	}
+	content := h.listingContent.Resolve(ctx, conn.ID, product)
+
+	blocks := descriptionBlocks(content)
+	updateReq := &providers.ProductUpdateRequest{
+		Name:                content.Title,
+		Description:         providers.RenderPlainText(blocks),
+		DescriptionBlocks:   blocks,
+		ExtendedDescription: content.ExtendedDescription,
+		Price:               &quote.Price,
+		OriginalPrice:       &quote.OriginalPrice,
+		Attributes:          content.Attributes,
+	}
+	if content.Price != nil {
+		updateReq.Price = content.Price
+		updateReq.OriginalPrice = content.Price
+	}
+	// Images are only re-sent when the override reorders them
+	if content.ImagesOverridden {
+		updateReq.Images = content.Images
+	}
+	return updateReq, nil
+}
+
 // deleteProductFromMarketplace deletes a product from a specific marketplace
 func (h *MarketplaceSyncHandler) deleteProductFromMarketplace(ctx context.Context, mapping *domain.ProductMapping) {
 	conn, err := h.connectionRepo.GetByID(ctx, mapping.ConnectionID)
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/infrastructure/persistence"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+var (
+	ErrBackfillInProgress   = errors.New("an order backfill is already running for this connection")
+	ErrInvalidBackfillRange = errors.New("time_from must be before time_to")
+)
+
+// StartOrderBackfill queues a background import of the orders created in a date range. The
+// range is split into the longest windows the platform accepts, which are imported one after
+// another with the job's checkpoint saved after every page.
+func (s *OrderSyncService) StartOrderBackfill(ctx context.Context, connectionID uuid.UUID, req *domain.StartOrderBackfillRequest) (*domain.SyncJob, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+	if conn.Platform != "shopee" && conn.Platform != "tiktok" {
+		return nil, ErrInvalidPlatform
+	}
+
+	timeTo := time.Now()
+	if req.TimeTo != nil && req.TimeTo.Before(timeTo) {
+		timeTo = *req.TimeTo
+	}
+	if !req.TimeFrom.Before(timeTo) {
+		return nil, ErrInvalidBackfillRange
+	}
+
+	payload := domain.OrderBackfillPayload{
+		TimeFrom:         req.TimeFrom,
+		TimeTo:           timeTo,
+		SkipOrderService: req.SkipOrderService,
+		WindowFrom:       req.TimeFrom,
+	}
+	data, _ := json.Marshal(payload)
+	job := &domain.SyncJob{
+		ConnectionID: connectionID,
+		JobType:      domain.JobTypeOrderBackfill,
+		Payload:      data,
+		Status:       domain.JobStatusPending,
+		TotalItems:   len(orderTimeWindows(payload.TimeFrom, payload.TimeTo, orderListWindow(conn.Platform))),
+		MaxAttempts:  3,
+	}
+
+	if active, err := s.syncJobRepo.CreateExclusive(ctx, job); err != nil {
+		if errors.Is(err, persistence.ErrActiveJobExists) {
+			return active, ErrBackfillInProgress
+		}
+		return nil, fmt.Errorf("failed to create sync job: %w", err)
+	}
+
+	go s.processOrderBackfillJob(context.Background(), job, conn)
+
+	return job, nil
+}
+
+// GetBackfillJob returns an order backfill job of a connection, including its progress
+func (s *OrderSyncService) GetBackfillJob(ctx context.Context, connectionID, jobID uuid.UUID) (*domain.SyncJob, error) {
+	job, err := s.syncJobRepo.GetByID(ctx, jobID)
+	if err != nil || job.ConnectionID != connectionID || job.JobType != domain.JobTypeOrderBackfill {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// ResumeOrderBackfill restarts a failed backfill job from its checkpoint
+func (s *OrderSyncService) ResumeOrderBackfill(ctx context.Context, connectionID, jobID uuid.UUID) (*domain.SyncJob, error) {
+	job, err := s.GetBackfillJob(ctx, connectionID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != domain.JobStatusFailed {
+		return nil, ErrJobNotResumable
+	}
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+	if active, err := s.syncJobRepo.Requeue(ctx, job); err != nil {
+		if errors.Is(err, persistence.ErrActiveJobExists) {
+			return active, ErrBackfillInProgress
+		}
+		return nil, fmt.Errorf("failed to requeue sync job: %w", err)
+	}
+
+	go s.processOrderBackfillJob(context.Background(), job, conn)
+
+	return job, nil
+}
+
+// ResumeBackfillJobs restarts backfill jobs that were interrupted, e.g. by a restart of the service
+func (s *OrderSyncService) ResumeBackfillJobs(ctx context.Context) {
+	jobs, err := s.syncJobRepo.GetUnfinishedByType(ctx, domain.JobTypeOrderBackfill)
+	if err != nil {
+		s.logger.Error("Failed to load unfinished backfill jobs", zap.Error(err))
+		return
+	}
+
+	for i := range jobs {
+		job := &jobs[i]
+		conn, err := s.connectionRepo.GetByID(ctx, job.ConnectionID)
+		if err != nil {
+			s.syncJobRepo.MarkFailed(ctx, job.ID, "connection not found")
+			continue
+		}
+
+		s.logger.Info("Resuming order backfill",
+			zap.String("job_id", job.ID.String()),
+			zap.String("connection_id", job.ConnectionID.String()),
+			zap.Int("processed_windows", job.ProcessedItems),
+		)
+		go s.processOrderBackfillJob(context.Background(), job, conn)
+	}
+}
+
+// processOrderBackfillJob imports the job's windows in order, starting at its checkpoint, and
+// persists the checkpoint and progress after every page so the job can be resumed.
+// ProcessedItems counts completed windows and FailedItems orders that could not be imported.
+func (s *OrderSyncService) processOrderBackfillJob(ctx context.Context, job *domain.SyncJob, conn *domain.Connection) {
+	// Mark as processing
+	if err := s.syncJobRepo.MarkProcessing(ctx, job.ID); err != nil {
+		s.logger.Error("Failed to mark job as processing", zap.Error(err))
+		return
+	}
+
+	var payload domain.OrderBackfillPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		s.syncJobRepo.MarkFailed(ctx, job.ID, "invalid payload")
+		return
+	}
+
+	// Decrypt access token
+	accessToken := conn.AccessToken
+	if s.encryptor != nil {
+		var err error
+		accessToken, err = s.encryptor.Decrypt(conn.AccessToken)
+		if err != nil {
+			s.syncJobRepo.MarkFailed(ctx, job.ID, "failed to decrypt token")
+			return
+		}
+	}
+
+	windows := orderTimeWindows(payload.TimeFrom, payload.TimeTo, orderListWindow(conn.Platform))
+	job.TotalItems = len(windows)
+
+	var lastRequest time.Time
+	for i, window := range windows {
+		// Windows before the checkpoint are already imported
+		if !window.to.After(payload.WindowFrom) {
+			continue
+		}
+		job.ProcessedItems = i
+
+		for {
+			if err := s.waitForBackfillRequest(ctx, &lastRequest); err != nil {
+				s.failBackfillJob(ctx, job, &payload, err)
+				return
+			}
+
+			orders, nextCursor, err := s.fetchOrderPage(ctx, conn, accessToken, &providers.OrderListParams{
+				TimeFrom: window.from,
+				TimeTo:   window.to,
+			}, payload.Cursor)
+			if err != nil {
+				s.failBackfillJob(ctx, job, &payload, err)
+				return
+			}
+
+			// Backfilled orders are history: their stock is long accounted for by inventory
+			imported, failed := s.importOrders(ctx, conn, orders, orderImportOptions{
+				skipReservations: true,
+				skipOrderService: payload.SkipOrderService,
+			})
+			payload.Imported += imported
+			job.FailedItems += failed
+
+			// Persist the checkpoint only after the page has been imported
+			payload.Cursor = nextCursor
+			if nextCursor == "" {
+				payload.WindowFrom = window.to
+				job.ProcessedItems = i + 1
+			}
+			job.Payload, _ = json.Marshal(payload)
+			if err := s.syncJobRepo.UpdateProgress(ctx, job); err != nil {
+				s.logger.Warn("Failed to save backfill progress", zap.String("job_id", job.ID.String()), zap.Error(err))
+			}
+
+			if nextCursor == "" {
+				break
+			}
+		}
+	}
+
+	s.syncJobRepo.MarkCompleted(ctx, job.ID)
+
+	s.logger.Info("Order backfill completed",
+		zap.String("job_id", job.ID.String()),
+		zap.String("connection_id", conn.ID.String()),
+		zap.Int("windows", job.TotalItems),
+		zap.Int("imported", payload.Imported),
+		zap.Int("failed", job.FailedItems),
+	)
+}
+
+// waitForBackfillRequest spaces backfill requests at least the backfill interval apart
+func (s *OrderSyncService) waitForBackfillRequest(ctx context.Context, lastRequest *time.Time) error {
+	if !lastRequest.IsZero() {
+		if wait := s.backfillInterval - time.Since(*lastRequest); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+	}
+	*lastRequest = time.Now()
+	return nil
+}
+
+// failBackfillJob stores the checkpoint of a backfill job and marks it failed
+func (s *OrderSyncService) failBackfillJob(ctx context.Context, job *domain.SyncJob, payload *domain.OrderBackfillPayload, err error) {
+	s.logger.Error("Order backfill failed",
+		zap.String("job_id", job.ID.String()),
+		zap.Time("window_from", payload.WindowFrom),
+		zap.Error(err),
+	)
+	job.Payload, _ = json.Marshal(payload)
+	s.syncJobRepo.UpdateProgress(ctx, job)
+	s.syncJobRepo.MarkFailed(ctx, job.ID, err.Error())
+}
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/infrastructure/persistence"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers/shopee"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers/tiktok"
+)
+
+const (
+	// DefaultOrderPollLookback is how far back the first order poll of a connection looks
+	DefaultOrderPollLookback = 24 * time.Hour
+
+	// maxOrderPollRange caps the update-time range one poll covers, so a connection that fell
+	// behind catches up over several polls instead of in one ever-growing query
+	maxOrderPollRange = 24 * time.Hour
+
+	// maxFailedPollOrders caps how many failed orders a connection keeps for retry; the oldest
+	// are given up beyond it
+	maxFailedPollOrders = 100
+)
+
+// orderTimeWindow is a [from, to) range of one order list query
+type orderTimeWindow struct {
+	from time.Time
+	to   time.Time
+}
+
+// orderTimeWindows splits a time range into consecutive windows no longer than size.
+// A size of zero keeps the range whole.
+func orderTimeWindows(from, to time.Time, size time.Duration) []orderTimeWindow {
+	if !from.Before(to) {
+		return nil
+	}
+	if size <= 0 {
+		return []orderTimeWindow{{from: from, to: to}}
+	}
+	var windows []orderTimeWindow
+	for start := from; start.Before(to); start = start.Add(size) {
+		end := start.Add(size)
+		if end.After(to) {
+			end = to
+		}
+		windows = append(windows, orderTimeWindow{from: start, to: end})
+	}
+	return windows
+}
+
+// orderListWindow returns the longest time range a platform accepts in one order list query;
+// zero means unlimited
+func orderListWindow(platform string) time.Duration {
+	switch platform {
+	case "shopee":
+		return shopee.MaxOrderListWindow
+	default:
+		return 0
+	}
+}
+
+// PollOrders syncs the orders of a connection updated since its watermark, less the poll
+// overlap, so status changes missed by webhooks are picked up. A poll covers at most
+// maxOrderPollRange past the watermark, which advances after each window that was fetched.
+// Orders that fail to import are kept on the watermark and retried by ID on later polls.
+// Returns how many orders were imported.
+func (s *OrderSyncService) PollOrders(ctx context.Context, connectionID uuid.UUID) (int, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return 0, ErrConnectionNotFound
+	}
+
+	watermark, err := s.watermarkRepo.GetByConnectionID(ctx, conn.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get order sync watermark: %w", err)
+	}
+	now := time.Now()
+	if watermark == nil {
+		watermark = &domain.OrderSyncWatermark{ConnectionID: conn.ID, SyncedTo: now.Add(-DefaultOrderPollLookback)}
+	}
+	watermark.LastPolledAt = &now
+	watermark.LastError = ""
+
+	var failed []string
+	if len(watermark.FailedOrderIDs) > 0 {
+		if err := json.Unmarshal(watermark.FailedOrderIDs, &failed); err != nil {
+			s.logger.Warn("Failed to parse failed order IDs of watermark", zap.String("connection_id", conn.ID.String()), zap.Error(err))
+		}
+	}
+
+	imported, failed, pollErr := s.pollOrderWindows(ctx, conn, watermark, failed, now)
+	if pollErr == nil && len(failed) > 0 {
+		pollErr = fmt.Errorf("failed to import %d orders, retried on the next poll", len(failed))
+	}
+	if pollErr != nil {
+		watermark.LastError = pollErr.Error()
+	}
+	if len(failed) > maxFailedPollOrders {
+		s.logger.Error("Giving up retrying failed orders",
+			zap.String("connection_id", conn.ID.String()),
+			zap.Strings("order_ids", failed[:len(failed)-maxFailedPollOrders]),
+		)
+		failed = failed[len(failed)-maxFailedPollOrders:]
+	}
+	if failed == nil {
+		failed = []string{}
+	}
+	watermark.FailedOrderIDs, _ = json.Marshal(failed)
+
+	if err := s.watermarkRepo.Upsert(ctx, watermark); err != nil {
+		return imported, fmt.Errorf("failed to save order sync watermark: %w", err)
+	}
+	return imported, pollErr
+}
+
+// pollOrderWindows retries the orders that failed on earlier polls, then imports orders
+// updated from the watermark, less the overlap, in the longest windows the platform accepts,
+// advancing the watermark window by window. Returns how many orders were imported and the
+// IDs of those still failing, oldest first.
+func (s *OrderSyncService) pollOrderWindows(ctx context.Context, conn *domain.Connection, watermark *domain.OrderSyncWatermark, failed []string, now time.Time) (int, []string, error) {
+	accessToken := conn.AccessToken
+	if s.encryptor != nil {
+		var err error
+		accessToken, err = s.encryptor.Decrypt(conn.AccessToken)
+		if err != nil {
+			return 0, failed, fmt.Errorf("failed to decrypt token: %w", err)
+		}
+	}
+
+	imported := 0
+	retried := failed
+	failed = nil
+	for _, orderID := range retried {
+		order, err := s.fetchOrder(ctx, conn, accessToken, orderID)
+		if err == nil {
+			err = s.importOrder(ctx, conn, order, orderImportOptions{})
+		}
+		if err != nil {
+			s.logger.Warn("Failed to retry order import", zap.String("order_id", orderID), zap.Error(err))
+			failed = append(failed, orderID)
+			continue
+		}
+		imported++
+	}
+
+	to := watermark.SyncedTo.Add(maxOrderPollRange)
+	if to.After(now) {
+		to = now
+	}
+	for _, window := range orderTimeWindows(watermark.SyncedTo.Add(-s.pollOverlap), to, orderListWindow(conn.Platform)) {
+		orders, err := s.fetchOrders(ctx, conn, accessToken, &providers.OrderListParams{
+			TimeFrom:  window.from,
+			TimeTo:    window.to,
+			TimeField: providers.OrderTimeFieldUpdate,
+		})
+		if err != nil {
+			return imported, failed, err
+		}
+
+		for i := range orders {
+			orderID := orders[i].ExternalOrderID
+			if err := s.importOrder(ctx, conn, &orders[i], orderImportOptions{}); err != nil {
+				s.logger.Error("Failed to import order", zap.String("order_id", orderID), zap.Error(err))
+				if !slices.Contains(failed, orderID) {
+					failed = append(failed, orderID)
+				}
+				continue
+			}
+			imported++
+			failed = slices.DeleteFunc(failed, func(id string) bool { return id == orderID })
+		}
+		if window.to.After(watermark.SyncedTo) {
+			watermark.SyncedTo = window.to
+		}
+	}
+	return imported, failed, nil
+}
+
+// fetchOrder fetches one order of a connection by its external ID
+func (s *OrderSyncService) fetchOrder(ctx context.Context, conn *domain.Connection, accessToken, orderID string) (*providers.ExternalOrder, error) {
+	switch conn.Platform {
+	case "shopee":
+		shopID, _ := strconv.ParseInt(conn.ShopID, 10, 64)
+		client, _ := shopee.NewClient(&shopee.ClientConfig{
+			PartnerID:  s.shopeePartnerID,
+			PartnerKey: s.shopeePartnerKey,
+			IsSandbox:  s.shopeeSandbox,
+			Logger:     s.logger,
+		})
+		client.SetTokens(accessToken, shopID)
+		return shopee.NewOrderProvider(client).GetOrder(ctx, orderID)
+
+	case "tiktok":
+		client := tiktok.NewClient(&tiktok.ClientConfig{
+			AppKey:    s.tiktokAppKey,
+			AppSecret: s.tiktokAppSecret,
+			Logger:    s.logger,
+		})
+		client.SetTokens(accessToken, conn.ShopID)
+		return tiktok.NewOrderProvider(client).GetOrder(ctx, orderID)
+
+	default:
+		return nil, ErrInvalidPlatform
+	}
+}
+
+// OrderPollerConfig holds configuration for the order poller.
+type OrderPollerConfig struct {
+	PollInterval time.Duration // How often every connection's updated orders are polled
+}
+
+// OrderPoller periodically polls updated orders for all active connections, as a safety net
+// for dropped webhooks.
+type OrderPoller struct {
+	connectionRepo   *persistence.ConnectionRepository
+	orderSyncService *OrderSyncService
+	config           OrderPollerConfig
+	logger           *zap.Logger
+
+	// Lifecycle management
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	running  bool
+	mu       sync.Mutex
+}
+
+// NewOrderPoller creates a new order poller.
+func NewOrderPoller(
+	connectionRepo *persistence.ConnectionRepository,
+	orderSyncService *OrderSyncService,
+	cfg OrderPollerConfig,
+	logger *zap.Logger,
+) *OrderPoller {
+	// Set defaults
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = 5 * time.Minute
+	}
+
+	return &OrderPoller{
+		connectionRepo:   connectionRepo,
+		orderSyncService: orderSyncService,
+		config:           cfg,
+		logger:           logger,
+		stopChan:         make(chan struct{}),
+	}
+}
+
+// Start begins the background order polling process.
+func (op *OrderPoller) Start(ctx context.Context) error {
+	op.mu.Lock()
+	if op.running {
+		op.mu.Unlock()
+		return fmt.Errorf("order poller already running")
+	}
+	op.running = true
+	op.mu.Unlock()
+
+	op.wg.Add(1)
+	go op.run(ctx)
+
+	op.logger.Info("order poller started",
+		zap.Duration("poll_interval", op.config.PollInterval),
+	)
+
+	return nil
+}
+
+// Stop gracefully stops the order poller.
+func (op *OrderPoller) Stop() {
+	op.mu.Lock()
+	if !op.running {
+		op.mu.Unlock()
+		return
+	}
+	op.running = false
+	op.mu.Unlock()
+
+	close(op.stopChan)
+	op.wg.Wait()
+
+	op.logger.Info("order poller stopped")
+}
+
+// run is the main background loop. Polls run one after another, so a slow poll
+// delays the next one instead of overlapping it.
+func (op *OrderPoller) run(ctx context.Context) {
+	defer op.wg.Done()
+
+	ticker := time.NewTicker(op.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-op.stopChan:
+			return
+		case <-ticker.C:
+			op.pollConnections(ctx)
+		}
+	}
+}
+
+// pollConnections polls updated orders for every active connection.
+func (op *OrderPoller) pollConnections(ctx context.Context) {
+	connections, err := op.connectionRepo.GetActiveConnections(ctx)
+	if err != nil {
+		op.logger.Error("failed to get active connections", zap.Error(err))
+		return
+	}
+
+	for i := range connections {
+		conn := &connections[i]
+		if conn.Platform != "shopee" && conn.Platform != "tiktok" {
+			continue
+		}
+		imported, err := op.orderSyncService.PollOrders(ctx, conn.ID)
+		if err != nil {
+			op.logger.Error("failed to poll orders",
+				zap.String("connection_id", conn.ID.String()),
+				zap.String("platform", conn.Platform),
+				zap.Error(err),
+			)
+			continue
+		}
+		if imported > 0 {
+			op.logger.Info("orders polled",
+				zap.String("connection_id", conn.ID.String()),
+				zap.String("platform", conn.Platform),
+				zap.Int("orders", imported),
+			)
+		}
+	}
+}
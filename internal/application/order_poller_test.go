@@ -0,0 +1,82 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOrderTimeWindows(t *testing.T) {
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return base.Add(time.Duration(hours) * time.Hour) }
+	window := func(from, to int) orderTimeWindow { return orderTimeWindow{from: at(from), to: at(to)} }
+
+	tests := []struct {
+		name string
+		from time.Time
+		to   time.Time
+		size time.Duration
+		want []orderTimeWindow
+	}{
+		{
+			name: "empty range",
+			from: at(5),
+			to:   at(5),
+			size: time.Hour,
+			want: nil,
+		},
+		{
+			name: "reversed range",
+			from: at(5),
+			to:   at(2),
+			size: time.Hour,
+			want: nil,
+		},
+		{
+			name: "no size keeps the range whole",
+			from: at(0),
+			to:   at(100),
+			want: []orderTimeWindow{window(0, 100)},
+		},
+		{
+			name: "range shorter than the size",
+			from: at(0),
+			to:   at(3),
+			size: 24 * time.Hour,
+			want: []orderTimeWindow{window(0, 3)},
+		},
+		{
+			name: "range split exactly",
+			from: at(0),
+			to:   at(48),
+			size: 24 * time.Hour,
+			want: []orderTimeWindow{window(0, 24), window(24, 48)},
+		},
+		{
+			name: "last window is shortened",
+			from: at(0),
+			to:   at(50),
+			size: 24 * time.Hour,
+			want: []orderTimeWindow{window(0, 24), window(24, 48), window(48, 50)},
+		},
+		{
+			name: "sub-second ends are kept",
+			from: base,
+			to:   base.Add(time.Hour + time.Millisecond),
+			size: time.Hour,
+			want: []orderTimeWindow{
+				{from: base, to: base.Add(time.Hour)},
+				{from: base.Add(time.Hour), to: base.Add(time.Hour + time.Millisecond)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := orderTimeWindows(tt.from, tt.to, tt.size)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("orderTimeWindows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -24,7 +24,10 @@ import (
 type OrderSyncService struct {
 	connectionRepo *persistence.ConnectionRepository
 	orderRepo      *persistence.MarketplaceOrderRepository
+	watermarkRepo  *persistence.OrderSyncWatermarkRepository
+	syncJobRepo    *persistence.SyncJobRepository
 	orderClient    *clients.OrderClient
+	inventorySync  *InventorySyncService
 	encryptor      *utils.Encryptor
 	logger         *zap.Logger
 
@@ -33,6 +36,12 @@ type OrderSyncService struct {
 	shopeeSandbox    bool
 	tiktokAppKey     string
 	tiktokAppSecret  string
+
+	// How far before the watermark order polls start
+	pollOverlap time.Duration
+
+	// Minimum time between order list requests of a backfill
+	backfillInterval time.Duration
 }
 
 // OrderSyncServiceConfig holds configuration
@@ -43,13 +52,24 @@ type OrderSyncServiceConfig struct {
 	TikTokAppKey     string
 	TikTokAppSecret  string
 	EncryptionKey    string
+
+	// PollOverlap is how far before its watermark each order poll starts, so orders updated
+	// while the previous poll ran are not missed
+	PollOverlap time.Duration
+
+	// BackfillRequestInterval is the minimum time between order list requests of a backfill job,
+	// keeping long imports under the platform's rate limits
+	BackfillRequestInterval time.Duration
 }
 
 // NewOrderSyncService creates a new OrderSyncService
 func NewOrderSyncService(
 	connectionRepo *persistence.ConnectionRepository,
 	orderRepo *persistence.MarketplaceOrderRepository,
+	watermarkRepo *persistence.OrderSyncWatermarkRepository,
+	syncJobRepo *persistence.SyncJobRepository,
 	orderClient *clients.OrderClient,
+	inventorySync *InventorySyncService,
 	cfg *OrderSyncServiceConfig,
 	logger *zap.Logger,
 ) (*OrderSyncService, error) {
@@ -65,7 +85,10 @@ func NewOrderSyncService(
 	return &OrderSyncService{
 		connectionRepo:   connectionRepo,
 		orderRepo:        orderRepo,
+		watermarkRepo:    watermarkRepo,
+		syncJobRepo:      syncJobRepo,
 		orderClient:      orderClient,
+		inventorySync:    inventorySync,
 		encryptor:        encryptor,
 		logger:           logger,
 		shopeePartnerID:  cfg.ShopeePartnerID,
@@ -73,6 +96,8 @@ func NewOrderSyncService(
 		shopeeSandbox:    cfg.ShopeeSandbox,
 		tiktokAppKey:     cfg.TikTokAppKey,
 		tiktokAppSecret:  cfg.TikTokAppSecret,
+		pollOverlap:      cfg.PollOverlap,
+		backfillInterval: cfg.BackfillRequestInterval,
 	}, nil
 }
 
@@ -81,7 +106,7 @@ func (s *OrderSyncService) GetOrders(ctx context.Context, connectionID uuid.UUID
 	return s.orderRepo.GetByConnectionID(ctx, connectionID, filter)
 }
 
-// SyncOrders manually syncs orders from marketplace
+// SyncOrders manually syncs orders created in a time range from marketplace
 func (s *OrderSyncService) SyncOrders(ctx context.Context, connectionID uuid.UUID, timeFrom, timeTo time.Time) (int, error) {
 	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
 	if err != nil {
@@ -96,52 +121,53 @@ func (s *OrderSyncService) SyncOrders(ctx context.Context, connectionID uuid.UUI
 		}
 	}
 
+	// Query the range in the longest windows the platform accepts, importing each window's
+	// orders before the next is fetched, so the count covers what was actually imported
+	importedCount := 0
+	for _, window := range orderTimeWindows(timeFrom, timeTo, orderListWindow(conn.Platform)) {
+		orders, err := s.fetchOrders(ctx, conn, accessToken, &providers.OrderListParams{
+			TimeFrom: window.from,
+			TimeTo:   window.to,
+		})
+		if err != nil {
+			return importedCount, err
+		}
+		count, _ := s.importOrders(ctx, conn, orders, orderImportOptions{})
+		importedCount += count
+	}
+	return importedCount, nil
+}
+
+// orderImportOptions limits the side effects of importing orders
+type orderImportOptions struct {
+	skipReservations bool // Orders neither reserve nor release stock
+	skipOrderService bool // New orders are not pushed to service-order
+}
+
+// importOrders imports fetched orders, logging failures. Returns how many were imported and
+// how many failed.
+func (s *OrderSyncService) importOrders(ctx context.Context, conn *domain.Connection, orders []providers.ExternalOrder, opts orderImportOptions) (int, int) {
+	importedCount, failedCount := 0, 0
+	for _, order := range orders {
+		if err := s.importOrder(ctx, conn, &order, opts); err != nil {
+			s.logger.Error("Failed to import order", zap.String("order_id", order.ExternalOrderID), zap.Error(err))
+			failedCount++
+			continue
+		}
+		importedCount++
+	}
+	return importedCount, failedCount
+}
+
+// fetchOrders fetches every page of an order list query
+func (s *OrderSyncService) fetchOrders(ctx context.Context, conn *domain.Connection, accessToken string, params *providers.OrderListParams) ([]providers.ExternalOrder, error) {
 	var orders []providers.ExternalOrder
 	cursor := ""
 
 	for {
-		var fetchedOrders []providers.ExternalOrder
-		var nextCursor string
-
-		switch conn.Platform {
-		case "shopee":
-			shopID, _ := strconv.ParseInt(conn.ShopID, 10, 64)
-			client, _ := shopee.NewClient(&shopee.ClientConfig{
-				PartnerID:  s.shopeePartnerID,
-				PartnerKey: s.shopeePartnerKey,
-				IsSandbox:  s.shopeeSandbox,
-				Logger:     s.logger,
-			})
-			client.SetTokens(accessToken, shopID)
-			provider := shopee.NewOrderProvider(client)
-			fetchedOrders, nextCursor, err = provider.GetOrders(ctx, &providers.OrderListParams{
-				TimeFrom: timeFrom,
-				TimeTo:   timeTo,
-				PageSize: 50,
-				Cursor:   cursor,
-			})
-
-		case "tiktok":
-			client := tiktok.NewClient(&tiktok.ClientConfig{
-				AppKey:    s.tiktokAppKey,
-				AppSecret: s.tiktokAppSecret,
-				Logger:    s.logger,
-			})
-			client.SetTokens(accessToken, conn.ShopID)
-			provider := tiktok.NewOrderProvider(client)
-			fetchedOrders, nextCursor, err = provider.GetOrders(ctx, &providers.OrderListParams{
-				TimeFrom: timeFrom,
-				TimeTo:   timeTo,
-				PageSize: 50,
-				Cursor:   cursor,
-			})
-
-		default:
-			return 0, ErrInvalidPlatform
-		}
-
+		fetchedOrders, nextCursor, err := s.fetchOrderPage(ctx, conn, accessToken, params, cursor)
 		if err != nil {
-			return len(orders), fmt.Errorf("failed to fetch orders: %w", err)
+			return nil, err
 		}
 
 		orders = append(orders, fetchedOrders...)
@@ -152,23 +178,76 @@ func (s *OrderSyncService) SyncOrders(ctx context.Context, connectionID uuid.UUI
 		cursor = nextCursor
 	}
 
-	// Import orders
-	importedCount := 0
-	for _, order := range orders {
-		if err := s.importOrder(ctx, conn, &order); err != nil {
-			s.logger.Error("Failed to import order", zap.String("order_id", order.ExternalOrderID), zap.Error(err))
-			continue
-		}
-		importedCount++
+	return orders, nil
+}
+
+// fetchOrderPage fetches one page of an order list query. The next cursor is empty on the last page.
+func (s *OrderSyncService) fetchOrderPage(ctx context.Context, conn *domain.Connection, accessToken string, params *providers.OrderListParams, cursor string) ([]providers.ExternalOrder, string, error) {
+	var fetchedOrders []providers.ExternalOrder
+	var nextCursor string
+	var err error
+
+	switch conn.Platform {
+	case "shopee":
+		shopID, _ := strconv.ParseInt(conn.ShopID, 10, 64)
+		client, _ := shopee.NewClient(&shopee.ClientConfig{
+			PartnerID:  s.shopeePartnerID,
+			PartnerKey: s.shopeePartnerKey,
+			IsSandbox:  s.shopeeSandbox,
+			Logger:     s.logger,
+		})
+		client.SetTokens(accessToken, shopID)
+		provider := shopee.NewOrderProvider(client)
+		fetchedOrders, nextCursor, err = provider.GetOrders(ctx, &providers.OrderListParams{
+			TimeFrom:  params.TimeFrom,
+			TimeTo:    params.TimeTo,
+			TimeField: params.TimeField,
+			PageSize:  50,
+			Cursor:    cursor,
+		})
+
+	case "tiktok":
+		client := tiktok.NewClient(&tiktok.ClientConfig{
+			AppKey:    s.tiktokAppKey,
+			AppSecret: s.tiktokAppSecret,
+			Logger:    s.logger,
+		})
+		client.SetTokens(accessToken, conn.ShopID)
+		provider := tiktok.NewOrderProvider(client)
+		fetchedOrders, nextCursor, err = provider.GetOrders(ctx, &providers.OrderListParams{
+			TimeFrom:  params.TimeFrom,
+			TimeTo:    params.TimeTo,
+			TimeField: params.TimeField,
+			PageSize:  50,
+			Cursor:    cursor,
+		})
+
+	default:
+		return nil, "", ErrInvalidPlatform
 	}
 
-	return importedCount, nil
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch orders: %w", err)
+	}
+	return fetchedOrders, nextCursor, nil
 }
 
-func (s *OrderSyncService) importOrder(ctx context.Context, conn *domain.Connection, order *providers.ExternalOrder) error {
+// importOrder creates or updates a marketplace order, reserving its stock and pushing it to
+// service-order unless the options skip it
+func (s *OrderSyncService) importOrder(ctx context.Context, conn *domain.Connection, order *providers.ExternalOrder, opts orderImportOptions) error {
 	// Check if order already exists
 	existing, _ := s.orderRepo.GetByExternalOrderID(ctx, conn.ID, order.ExternalOrderID)
 	if existing != nil {
+		// Hold back stock of orders paid since the last import, release it for cancelled ones
+		if s.inventorySync != nil && !opts.skipReservations && existing.Status != order.Status {
+			switch {
+			case reservesStock(order.Status) && !reservesStock(existing.Status):
+				s.inventorySync.ReserveOrderStock(ctx, conn, order)
+			case order.Status == domain.OrderStatusCancelled:
+				s.inventorySync.ReleaseOrderStock(ctx, conn, order.ExternalOrderID)
+			}
+		}
+
 		// Update existing order status
 		existing.Status = order.Status
 		// Update shipping info if tracking available
@@ -248,8 +327,13 @@ func (s *OrderSyncService) importOrder(ctx context.Context, conn *domain.Connect
 		return fmt.Errorf("failed to create marketplace order: %w", err)
 	}
 
+	// Hold back the stock of a paid order on other channels until inventory accounts for it
+	if s.inventorySync != nil && !opts.skipReservations && reservesStock(order.Status) {
+		s.inventorySync.ReserveOrderStock(ctx, conn, order)
+	}
+
 	// Push to service-order if client configured
-	if s.orderClient != nil {
+	if s.orderClient != nil && !opts.skipOrderService {
 		items := make([]clients.OrderItemRequest, len(order.Items))
 		for i, item := range order.Items {
 			items[i] = clients.OrderItemRequest{
@@ -326,7 +410,7 @@ func (s *OrderSyncService) HandleShopeeOrderEvent(shopID int64, orderSN, status
 		return
 	}
 
-	if err := s.importOrder(ctx, conn, order); err != nil {
+	if err := s.importOrder(ctx, conn, order, orderImportOptions{}); err != nil {
 		s.logger.Error("Failed to import order from webhook", zap.Error(err))
 	}
 }
@@ -362,7 +446,7 @@ func (s *OrderSyncService) HandleTikTokOrderEvent(shopID, orderID string, status
 		return
 	}
 
-	if err := s.importOrder(ctx, conn, order); err != nil {
+	if err := s.importOrder(ctx, conn, order, orderImportOptions{}); err != nil {
 		s.logger.Error("Failed to import order from webhook", zap.Error(err))
 	}
 }
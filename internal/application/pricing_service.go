@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/clients"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/infrastructure/persistence"
+)
+
+var ErrPricingRuleNotFound = errors.New("pricing rule not found")
+
+// PricingService evaluates per-connection pricing rules
+type PricingService struct {
+	connectionRepo  *persistence.ConnectionRepository
+	pricingRuleRepo *persistence.PricingRuleRepository
+	catalogClient   *clients.CatalogClient
+	logger          *zap.Logger
+}
+
+// NewPricingService creates a new PricingService
+func NewPricingService(
+	connectionRepo *persistence.ConnectionRepository,
+	pricingRuleRepo *persistence.PricingRuleRepository,
+	catalogClient *clients.CatalogClient,
+	logger *zap.Logger,
+) *PricingService {
+	return &PricingService{
+		connectionRepo:  connectionRepo,
+		pricingRuleRepo: pricingRuleRepo,
+		catalogClient:   catalogClient,
+		logger:          logger,
+	}
+}
+
+// PricePreview shows the computed marketplace prices for a catalog product
+type PricePreview struct {
+	ProductID    string                `json:"product_id"`
+	Name         string                `json:"name"`
+	CategoryID   string                `json:"category_id"`
+	CategoryName string                `json:"category_name"`
+	Quote        *domain.PriceQuote    `json:"quote"`
+	Variants     []VariantPricePreview `json:"variants,omitempty"`
+}
+
+// VariantPricePreview shows the computed marketplace price for a catalog variant
+type VariantPricePreview struct {
+	VariantID string             `json:"variant_id"`
+	SKU       string             `json:"sku"`
+	Name      string             `json:"name"`
+	Quote     *domain.PriceQuote `json:"quote"`
+}
+
+// GetRules retrieves pricing rules for a connection
+func (s *PricingService) GetRules(ctx context.Context, connectionID uuid.UUID) ([]domain.PricingRule, error) {
+	return s.pricingRuleRepo.GetByConnectionID(ctx, connectionID)
+}
+
+// SaveRule creates or replaces the default rule (no category) or a category override
+func (s *PricingService) SaveRule(ctx context.Context, connectionID uuid.UUID, req *domain.CreatePricingRuleRequest) (*domain.PricingRule, error) {
+	// Verify connection exists
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	rule, _ := s.pricingRuleRepo.GetByConnectionAndCategory(ctx, connectionID, req.InternalCategoryID)
+	isNew := rule == nil
+	if isNew {
+		rule = &domain.PricingRule{
+			ConnectionID:       connectionID,
+			InternalCategoryID: req.InternalCategoryID,
+		}
+	}
+
+	rule.MarkupType = req.MarkupType
+	if rule.MarkupType == "" {
+		rule.MarkupType = domain.MarkupTypePercent
+	}
+	rule.MarkupValue = req.MarkupValue
+	rule.Rounding = req.Rounding
+	if rule.Rounding == "" {
+		rule.Rounding = domain.RoundingNone
+	}
+	rule.MinMarginPercent = req.MinMarginPercent
+	rule.StrikeThrough = req.StrikeThrough
+	if rule.StrikeThrough == "" {
+		rule.StrikeThrough = domain.StrikeThroughCatalog
+	}
+	rule.StrikeThroughPercent = req.StrikeThroughPercent
+	rule.IsActive = true
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if isNew {
+		if err := s.pricingRuleRepo.Create(ctx, rule); err != nil {
+			return nil, fmt.Errorf("failed to create pricing rule: %w", err)
+		}
+		return rule, nil
+	}
+
+	if err := s.pricingRuleRepo.Update(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to update pricing rule: %w", err)
+	}
+	return rule, nil
+}
+
+// DeleteRule deletes a pricing rule
+func (s *PricingService) DeleteRule(ctx context.Context, connectionID, ruleID uuid.UUID) error {
+	rule, err := s.pricingRuleRepo.GetByID(ctx, ruleID)
+	if err != nil || rule.ConnectionID != connectionID {
+		return ErrPricingRuleNotFound
+	}
+	return s.pricingRuleRepo.Delete(ctx, ruleID)
+}
+
+// Quote computes the marketplace price for a catalog product on a connection.
+// Falls back to catalog prices when no rule applies. Rules that cannot be loaded are an
+// error, so a product is never published at an unintended price.
+func (s *PricingService) Quote(ctx context.Context, connectionID uuid.UUID, product *clients.Product) (*domain.PriceQuote, error) {
+	rule, err := s.ruleFor(ctx, connectionID, product.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	return rule.QuotePrice(product.BasePrice, product.SalePrice), nil
+}
+
+// RegularQuote computes the marketplace price of a catalog product without its sale price.
+// Listings are published at this price; sales run as marketplace discount campaigns.
+func (s *PricingService) RegularQuote(ctx context.Context, connectionID uuid.UUID, product *clients.Product) (*domain.PriceQuote, error) {
+	rule, err := s.ruleFor(ctx, connectionID, product.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	return rule.QuotePrice(product.BasePrice, nil), nil
+}
+
+// ruleFor returns the effective rule for a category: the override of the category or of its
+// nearest ancestor, else the connection default. Returns nil if none applies.
+func (s *PricingService) ruleFor(ctx context.Context, connectionID uuid.UUID, categoryID string) (*domain.PricingRule, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	rules, err := s.pricingRuleRepo.GetByConnectionID(ctx, connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pricing rules: %w", err)
+	}
+
+	var defaultRule *domain.PricingRule
+	overrides := make(map[string]*domain.PricingRule)
+	for i := range rules {
+		switch {
+		case !rules[i].IsActive:
+		case rules[i].InternalCategoryID == nil:
+			defaultRule = &rules[i]
+		default:
+			overrides[rules[i].InternalCategoryID.String()] = &rules[i]
+		}
+	}
+	if categoryID == "" || len(overrides) == 0 {
+		return defaultRule, nil
+	}
+	if rule, ok := overrides[categoryID]; ok {
+		return rule, nil
+	}
+
+	// Overrides of a category apply to its subcategories
+	categories, err := s.catalogClient.GetCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch categories from catalog: %w", err)
+	}
+	for _, id := range categoryAncestry(categories, categoryID) {
+		if rule, ok := overrides[id]; ok {
+			return rule, nil
+		}
+	}
+	return defaultRule, nil
+}
+
+// PreviewPrice shows the computed price for any catalog product on a connection
+func (s *PricingService) PreviewPrice(ctx context.Context, connectionID uuid.UUID, productID string) (*PricePreview, error) {
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	product, err := s.catalogClient.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product from catalog: %w", err)
+	}
+
+	rule, err := s.ruleFor(ctx, connectionID, product.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	preview := &PricePreview{
+		ProductID:    product.ID,
+		Name:         product.Name,
+		CategoryID:   product.CategoryID,
+		CategoryName: product.CategoryName,
+		Quote:        rule.QuotePrice(product.BasePrice, product.SalePrice),
+	}
+
+	for _, v := range product.Variants {
+		if v.Price <= 0 {
+			continue
+		}
+		preview.Variants = append(preview.Variants, VariantPricePreview{
+			VariantID: v.ID,
+			SKU:       v.SKU,
+			Name:      v.Name,
+			Quote:     rule.QuotePrice(v.Price, nil),
+		})
+	}
+
+	return preview, nil
+}
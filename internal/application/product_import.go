@@ -0,0 +1,586 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/infrastructure/persistence"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers/shopee"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers/tiktok"
+)
+
+var (
+	ErrImportInProgress = errors.New("a product import is already running for this connection")
+	ErrJobNotFound      = errors.New("sync job not found")
+	ErrJobNotResumable  = errors.New("only failed import jobs can be resumed")
+)
+
+// Listing page sizes used by product imports
+const (
+	shopeeImportPageSize = 50 // Also the get_item_base_info batch limit
+	tiktokImportPageSize = tiktok.MaxSearchPageSize
+)
+
+// importListing is a listing returned by a marketplace list call
+type importListing struct {
+	ExternalProductID string
+	UpdateTime        int64 // Unix seconds
+}
+
+// importPage is one page of marketplace listings
+type importPage struct {
+	Listings   []importListing
+	Total      int
+	NextCursor int
+	HasMore    bool
+}
+
+// productImporter pages through a shop's listings and fetches the details of selected ones
+type productImporter struct {
+	listPage     func(ctx context.Context, cursor int, status string) (*importPage, error)
+	fetchDetails func(ctx context.Context, externalIDs []string) ([]domain.ImportedProduct, error)
+}
+
+// productImporterFor returns the importer for a connection's platform
+func (s *ProductSyncService) productImporterFor(conn *domain.Connection, accessToken string) (*productImporter, error) {
+	switch conn.Platform {
+	case "shopee":
+		shopID, _ := strconv.ParseInt(conn.ShopID, 10, 64)
+		_, productProvider := s.shopeeClientFactory(accessToken, shopID)
+		return &productImporter{
+			listPage: func(ctx context.Context, cursor int, status string) (*importPage, error) {
+				items, total, hasMore, err := productProvider.GetItemList(ctx, cursor, shopeeImportPageSize, status)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get item list: %w", err)
+				}
+				page := &importPage{Total: total, NextCursor: cursor + len(items), HasMore: hasMore}
+				for _, item := range items {
+					page.Listings = append(page.Listings, importListing{
+						ExternalProductID: strconv.FormatInt(item.ItemID, 10),
+						UpdateTime:        item.UpdateTime,
+					})
+				}
+				return page, nil
+			},
+			fetchDetails: func(ctx context.Context, externalIDs []string) ([]domain.ImportedProduct, error) {
+				itemIDs := make([]int64, 0, len(externalIDs))
+				for _, id := range externalIDs {
+					if itemID, err := strconv.ParseInt(id, 10, 64); err == nil {
+						itemIDs = append(itemIDs, itemID)
+					}
+				}
+				details, err := productProvider.GetItemBaseInfo(ctx, itemIDs)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get item details: %w", err)
+				}
+				products := make([]domain.ImportedProduct, 0, len(details))
+				for i := range details {
+					// Models (variations) are only available through a separate call per item
+					var models *shopee.ShopeeModelList
+					if details[i].HasModel {
+						models, err = productProvider.GetModelList(ctx, details[i].ItemID)
+						if err != nil {
+							s.logger.Warn("Failed to get model list, importing item without models",
+								zap.Int64("item_id", details[i].ItemID),
+								zap.Error(err),
+							)
+							models = nil
+						}
+					}
+					products = append(products, importedShopeeProduct(conn.ID, &details[i], models))
+				}
+				return products, nil
+			},
+		}, nil
+
+	case "tiktok":
+		_, productProvider := s.tiktokClientFactory(accessToken, conn.ShopID)
+		// Search results of the current page, used when a detail call fails
+		summaries := make(map[string]tiktok.TikTokProduct)
+		return &productImporter{
+			listPage: func(ctx context.Context, cursor int, status string) (*importPage, error) {
+				pageNumber := max(cursor, 1)
+				products, total, err := productProvider.SearchProducts(ctx, pageNumber, tiktokImportPageSize, status)
+				if err != nil {
+					return nil, fmt.Errorf("failed to search products: %w", err)
+				}
+				page := &importPage{
+					Total:      total,
+					NextCursor: pageNumber + 1,
+					HasMore:    len(products) == tiktokImportPageSize && pageNumber*tiktokImportPageSize < total,
+				}
+				clear(summaries)
+				for _, product := range products {
+					summaries[product.ProductID] = product
+					page.Listings = append(page.Listings, importListing{
+						ExternalProductID: product.ProductID,
+						UpdateTime:        product.UpdateTime,
+					})
+				}
+				return page, nil
+			},
+			fetchDetails: func(ctx context.Context, externalIDs []string) ([]domain.ImportedProduct, error) {
+				products := make([]domain.ImportedProduct, 0, len(externalIDs))
+				for _, id := range externalIDs {
+					detail, err := productProvider.GetProductDetail(ctx, id)
+					if err != nil {
+						summary, ok := summaries[id]
+						if !ok {
+							return nil, fmt.Errorf("failed to get product detail: %w", err)
+						}
+						s.logger.Warn("Failed to get product detail, importing search summary",
+							zap.String("product_id", id),
+							zap.Error(err),
+						)
+						detail = &tiktok.TikTokProductDetail{
+							ProductID:  summary.ProductID,
+							Name:       summary.Name,
+							Status:     summary.Status,
+							SKUs:       summary.SKUs,
+							UpdateTime: summary.UpdateTime,
+						}
+					}
+					products = append(products, importedTikTokProduct(conn.ID, detail))
+				}
+				return products, nil
+			},
+		}, nil
+
+	default:
+		return nil, ErrInvalidPlatform
+	}
+}
+
+// defaultImportStatus is the listing status imported when none is requested
+func defaultImportStatus(platform string) string {
+	if platform == "tiktok" {
+		return "LIVE"
+	}
+	return "NORMAL"
+}
+
+// StartProductImport queues a background import of the shop's existing listings.
+// Unless a full import is requested, listings unchanged since the last completed
+// import are only marked as seen instead of being fetched again.
+func (s *ProductSyncService) StartProductImport(ctx context.Context, connectionID uuid.UUID, req *domain.StartProductImportRequest) (*domain.SyncJob, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+	if conn.Platform != "shopee" && conn.Platform != "tiktok" {
+		return nil, ErrInvalidPlatform
+	}
+
+	payload := domain.ProductImportPayload{
+		Status: req.Status,
+	}
+	if payload.Status == "" {
+		payload.Status = defaultImportStatus(conn.Platform)
+	}
+
+	// Incremental imports continue from the previous completed import with the same status filter
+	if !req.Full {
+		if last, err := s.syncJobRepo.GetLastCompletedByType(ctx, connectionID, domain.JobTypeProductImport); err == nil {
+			var previous domain.ProductImportPayload
+			if json.Unmarshal(last.Payload, &previous) == nil && previous.Status == payload.Status && !previous.RunStartedAt.IsZero() {
+				payload.Incremental = true
+				payload.UpdatedSince = previous.RunStartedAt.Unix()
+			}
+		}
+	}
+
+	data, _ := json.Marshal(payload)
+	job := &domain.SyncJob{
+		ConnectionID: connectionID,
+		JobType:      domain.JobTypeProductImport,
+		Payload:      data,
+		Status:       domain.JobStatusPending,
+		MaxAttempts:  3,
+	}
+
+	if active, err := s.syncJobRepo.CreateExclusive(ctx, job); err != nil {
+		if errors.Is(err, persistence.ErrActiveJobExists) {
+			return active, ErrImportInProgress
+		}
+		return nil, fmt.Errorf("failed to create sync job: %w", err)
+	}
+
+	go s.processProductImportJob(context.Background(), job, conn)
+
+	return job, nil
+}
+
+// GetImportJob returns an import job of a connection, including its progress
+func (s *ProductSyncService) GetImportJob(ctx context.Context, connectionID, jobID uuid.UUID) (*domain.SyncJob, error) {
+	return s.getConnectionJob(ctx, connectionID, jobID, domain.JobTypeProductImport)
+}
+
+// getConnectionJob returns a job of the given type that belongs to a connection
+func (s *ProductSyncService) getConnectionJob(ctx context.Context, connectionID, jobID uuid.UUID, jobType string) (*domain.SyncJob, error) {
+	job, err := s.syncJobRepo.GetByID(ctx, jobID)
+	if err != nil || job.ConnectionID != connectionID || job.JobType != jobType {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// ResumeProductImport restarts a failed import job from its stored cursor
+func (s *ProductSyncService) ResumeProductImport(ctx context.Context, connectionID, jobID uuid.UUID) (*domain.SyncJob, error) {
+	job, err := s.GetImportJob(ctx, connectionID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != domain.JobStatusFailed {
+		return nil, ErrJobNotResumable
+	}
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+	if active, err := s.syncJobRepo.Requeue(ctx, job); err != nil {
+		if errors.Is(err, persistence.ErrActiveJobExists) {
+			return active, ErrImportInProgress
+		}
+		return nil, fmt.Errorf("failed to requeue sync job: %w", err)
+	}
+
+	go s.processProductImportJob(context.Background(), job, conn)
+
+	return job, nil
+}
+
+// ResumeImportJobs restarts import jobs that were interrupted, e.g. by a restart of the service
+func (s *ProductSyncService) ResumeImportJobs(ctx context.Context) {
+	jobs, err := s.syncJobRepo.GetUnfinishedByType(ctx, domain.JobTypeProductImport)
+	if err != nil {
+		s.logger.Error("Failed to load unfinished import jobs", zap.Error(err))
+		return
+	}
+
+	for i := range jobs {
+		job := &jobs[i]
+		conn, err := s.connectionRepo.GetByID(ctx, job.ConnectionID)
+		if err != nil {
+			s.syncJobRepo.MarkFailed(ctx, job.ID, "connection not found")
+			continue
+		}
+
+		s.logger.Info("Resuming product import",
+			zap.String("job_id", job.ID.String()),
+			zap.String("connection_id", job.ConnectionID.String()),
+			zap.Int("processed_items", job.ProcessedItems),
+		)
+		go s.processProductImportJob(context.Background(), job, conn)
+	}
+}
+
+// processProductImportJob pages through the shop's listings starting at the job's cursor,
+// persisting the cursor and progress after every page so the job can be resumed
+func (s *ProductSyncService) processProductImportJob(ctx context.Context, job *domain.SyncJob, conn *domain.Connection) {
+	// Mark as processing
+	if err := s.syncJobRepo.MarkProcessing(ctx, job.ID); err != nil {
+		s.logger.Error("Failed to mark job as processing", zap.Error(err))
+		return
+	}
+
+	var payload domain.ProductImportPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		s.syncJobRepo.MarkFailed(ctx, job.ID, "invalid payload")
+		return
+	}
+	if payload.RunStartedAt.IsZero() {
+		payload.RunStartedAt = time.Now()
+	}
+
+	// Decrypt access token
+	accessToken := conn.AccessToken
+	if s.encryptor != nil {
+		var err error
+		accessToken, err = s.encryptor.Decrypt(conn.AccessToken)
+		if err != nil {
+			s.syncJobRepo.MarkFailed(ctx, job.ID, "failed to decrypt token")
+			return
+		}
+	}
+
+	importer, err := s.productImporterFor(conn, accessToken)
+	if err != nil {
+		s.syncJobRepo.MarkFailed(ctx, job.ID, err.Error())
+		return
+	}
+
+	for {
+		page, err := importer.listPage(ctx, payload.Cursor, payload.Status)
+		if err != nil {
+			s.failImportJob(ctx, job, &payload, err)
+			return
+		}
+		job.TotalItems = page.Total
+
+		if err := s.importListingPage(ctx, conn, job, &payload, importer, page.Listings); err != nil {
+			s.failImportJob(ctx, job, &payload, err)
+			return
+		}
+
+		// Persist the cursor only after the page has been stored
+		payload.Cursor = page.NextCursor
+		job.Payload, _ = json.Marshal(payload)
+		if err := s.syncJobRepo.UpdateProgress(ctx, job); err != nil {
+			s.logger.Warn("Failed to save import progress", zap.String("job_id", job.ID.String()), zap.Error(err))
+		}
+
+		if !page.HasMore || len(page.Listings) == 0 {
+			break
+		}
+	}
+
+	// Listings with the imported status that were not seen in this run are gone
+	removed, err := s.importedProductRepo.MarkRemovedNotSeenSince(ctx, conn.ID, payload.Status, payload.RunStartedAt)
+	if err != nil {
+		s.logger.Warn("Failed to mark removed listings", zap.String("job_id", job.ID.String()), zap.Error(err))
+	}
+	payload.Removed = int(removed)
+	job.Payload, _ = json.Marshal(payload)
+	s.syncJobRepo.UpdateProgress(ctx, job)
+	s.syncJobRepo.MarkCompleted(ctx, job.ID)
+
+	s.logger.Info("Product import completed",
+		zap.String("job_id", job.ID.String()),
+		zap.String("connection_id", conn.ID.String()),
+		zap.Int("listed", job.ProcessedItems),
+		zap.Int("fetched", payload.Fetched),
+		zap.Int("unchanged", payload.Unchanged),
+		zap.Int("removed", payload.Removed),
+		zap.Int("failed", job.FailedItems),
+	)
+}
+
+// importListingPage marks a page of listings as seen and (re)imports the ones that changed
+func (s *ProductSyncService) importListingPage(ctx context.Context, conn *domain.Connection, job *domain.SyncJob, payload *domain.ProductImportPayload, importer *productImporter, listings []importListing) error {
+	if len(listings) == 0 {
+		return nil
+	}
+
+	seenAt := time.Now()
+	ids := make([]string, 0, len(listings))
+	for _, l := range listings {
+		ids = append(ids, l.ExternalProductID)
+	}
+
+	changed := ids
+	if payload.Incremental {
+		existing, err := s.importedProductRepo.GetExistingExternalIDs(ctx, conn.ID, ids)
+		if err != nil {
+			return fmt.Errorf("failed to load imported products: %w", err)
+		}
+
+		changed = make([]string, 0, len(ids))
+		var unchanged []string
+		for _, l := range listings {
+			if existing[l.ExternalProductID] && l.UpdateTime > 0 && l.UpdateTime < payload.UpdatedSince {
+				unchanged = append(unchanged, l.ExternalProductID)
+				continue
+			}
+			changed = append(changed, l.ExternalProductID)
+		}
+
+		if err := s.importedProductRepo.TouchSeen(ctx, conn.ID, unchanged, seenAt); err != nil {
+			return fmt.Errorf("failed to update imported products: %w", err)
+		}
+		payload.Unchanged += len(unchanged)
+	}
+
+	if len(changed) > 0 {
+		products, err := importer.fetchDetails(ctx, changed)
+		if err != nil {
+			return err
+		}
+		for i := range products {
+			products[i].LastSeenAt = &seenAt
+		}
+		if _, err := s.saveImportedProducts(ctx, conn, products); err != nil {
+			return err
+		}
+		payload.Fetched += len(products)
+		job.FailedItems += len(changed) - len(products)
+	}
+
+	job.ProcessedItems += len(listings)
+	return nil
+}
+
+// failImportJob stores the resume state of an import job and marks it failed
+func (s *ProductSyncService) failImportJob(ctx context.Context, job *domain.SyncJob, payload *domain.ProductImportPayload, err error) {
+	s.logger.Error("Product import failed",
+		zap.String("job_id", job.ID.String()),
+		zap.Int("cursor", payload.Cursor),
+		zap.Error(err),
+	)
+	job.Payload, _ = json.Marshal(payload)
+	s.syncJobRepo.UpdateProgress(ctx, job)
+	s.syncJobRepo.MarkFailed(ctx, job.ID, err.Error())
+}
+
+// importedShopeeProduct converts a Shopee item and its models into an ImportedProduct.
+// With models, price is the lowest model price and stock the total over all models.
+func importedShopeeProduct(connectionID uuid.UUID, detail *shopee.ShopeeItemDetail, models *shopee.ShopeeModelList) domain.ImportedProduct {
+	imported := domain.ImportedProduct{
+		ConnectionID:      connectionID,
+		ExternalProductID: strconv.FormatInt(detail.ItemID, 10),
+		ExternalSKU:       detail.ItemSKU,
+		Name:              detail.ItemName,
+		Description:       detail.Description,
+		Price:             detail.OriginalPrice,
+		Stock:             detail.Stock,
+		CategoryID:        strconv.FormatInt(detail.CategoryID, 10),
+		Status:            detail.ItemStatus,
+		Brand:             detail.Brand,
+		Weight:            detail.Weight * 1000, // kg to g
+		Length:            detail.Dimension.PackageLength,
+		Width:             detail.Dimension.PackageWidth,
+		Height:            detail.Dimension.PackageHeight,
+	}
+	if len(detail.Images) > 0 {
+		imported.ImageURL = detail.Images[0]
+	}
+	if detail.UpdateTime > 0 {
+		updatedAt := time.Unix(detail.UpdateTime, 0)
+		imported.ExternalUpdatedAt = &updatedAt
+	}
+
+	skus := []domain.ImportedSKU{}
+	raw := map[string]json.RawMessage{"item": detail.Raw}
+	if models != nil && len(models.Models) > 0 {
+		raw["model_list"] = models.Raw
+		imported.Price, imported.Stock = 0, 0
+		for i, model := range models.Models {
+			if i == 0 || model.Price < imported.Price {
+				imported.Price = model.Price
+			}
+			imported.Stock += model.Stock
+			skus = append(skus, domain.ImportedSKU{
+				ExternalSKUID: strconv.FormatInt(model.ModelID, 10),
+				Name:          model.Name,
+				SellerSKU:     model.ModelSKU,
+				Price:         model.Price,
+				Stock:         model.Stock,
+				Attributes:    model.Options,
+			})
+		}
+	}
+
+	imported.Images = marshalJSON(detail.Images, "[]")
+	imported.SKUs = marshalJSON(skus, "[]")
+	imported.Attributes = marshalJSON(detail.Attributes, "{}")
+	if len(detail.Raw) > 0 {
+		imported.RawPayload = marshalJSON(raw, "null")
+	}
+	return imported
+}
+
+// importedTikTokProduct converts a TikTok product into an ImportedProduct.
+// Price is the lowest SKU price and stock the total over all SKUs.
+func importedTikTokProduct(connectionID uuid.UUID, detail *tiktok.TikTokProductDetail) domain.ImportedProduct {
+	imported := domain.ImportedProduct{
+		ConnectionID:      connectionID,
+		ExternalProductID: detail.ProductID,
+		Name:              detail.Name,
+		Description:       detail.Description,
+		CategoryID:        detail.CategoryID,
+		Status:            detail.Status,
+		Brand:             detail.Brand,
+		Weight:            detail.Weight * 1000, // kg to g
+		Length:            detail.Length,
+		Width:             detail.Width,
+		Height:            detail.Height,
+	}
+	if len(detail.Images) > 0 {
+		imported.ImageURL = detail.Images[0]
+	}
+	if detail.UpdateTime > 0 {
+		updatedAt := time.Unix(detail.UpdateTime, 0)
+		imported.ExternalUpdatedAt = &updatedAt
+	}
+
+	skus := make([]domain.ImportedSKU, 0, len(detail.SKUs))
+	for i, sku := range detail.SKUs {
+		if i == 0 || sku.Price < imported.Price {
+			imported.Price = sku.Price
+		}
+		imported.Stock += sku.Stock
+
+		importedSKU := domain.ImportedSKU{
+			ExternalSKUID: sku.ID,
+			SellerSKU:     sku.SellerSKU,
+			Price:         sku.Price,
+			Stock:         sku.Stock,
+		}
+		if len(sku.SalesAttributes) > 0 {
+			importedSKU.Attributes = make(map[string]string, len(sku.SalesAttributes))
+			values := make([]string, 0, len(sku.SalesAttributes))
+			for _, attr := range sku.SalesAttributes {
+				importedSKU.Attributes[attr.Name] = attr.Value
+				values = append(values, attr.Value)
+			}
+			importedSKU.Name = strings.Join(values, ",")
+		}
+		skus = append(skus, importedSKU)
+	}
+	if len(skus) > 0 {
+		imported.ExternalSKU = skus[0].SellerSKU
+	}
+
+	imported.Images = marshalJSON(detail.Images, "[]")
+	imported.SKUs = marshalJSON(skus, "[]")
+	imported.Attributes = marshalJSON(detail.Attributes, "{}")
+	if len(detail.Raw) > 0 {
+		imported.RawPayload = datatypes.JSON(detail.Raw)
+	}
+	return imported
+}
+
+// marshalJSON encodes v for a JSONB column, using fallback for nil values
+func marshalJSON(v interface{}, fallback string) datatypes.JSON {
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return datatypes.JSON(fallback)
+	}
+	return data
+}
+
+// saveImportedProducts upserts imported products and marks those that already have a product mapping
+func (s *ProductSyncService) saveImportedProducts(ctx context.Context, conn *domain.Connection, importedProducts []domain.ImportedProduct) (int, error) {
+	if len(importedProducts) == 0 {
+		return 0, nil
+	}
+
+	// Upsert imported products
+	if err := s.importedProductRepo.UpsertBatch(ctx, importedProducts); err != nil {
+		return 0, fmt.Errorf("failed to save imported products: %w", err)
+	}
+
+	// Sync is_mapped status with existing product_mappings
+	// This handles products that were previously pushed/mapped
+	for _, product := range importedProducts {
+		existingMapping, _ := s.productMappingRepo.GetByConnectionAndExternalProduct(ctx, conn.ID, product.ExternalProductID)
+		if existingMapping != nil {
+			// Product already has a mapping, update imported_product status
+			importedProduct, _ := s.importedProductRepo.GetByExternalProductID(ctx, conn.ID, product.ExternalProductID)
+			if importedProduct != nil && !importedProduct.IsMapped {
+				s.importedProductRepo.SetMapped(ctx, importedProduct.ID, existingMapping.InternalProductID)
+			}
+		}
+	}
+
+	return len(importedProducts), nil
+}
@@ -0,0 +1,350 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/clients"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/utils"
+)
+
+// Product match scoring weights (sum to 1)
+const (
+	productMatchSKUWeight   = 0.40 // Exact (case-insensitive) SKU match, including variant SKUs
+	productMatchNameWeight  = 0.35 // Normalized name similarity
+	productMatchPriceWeight = 0.10 // Price proximity
+	productMatchImageWeight = 0.15 // Same image URL or image file (CDN content hash)
+
+	// productMatchSKUFloor is the minimum confidence of an exact SKU match
+	productMatchSKUFloor = 0.9
+
+	// productMatchPriceTolerance is the relative price difference at which the price score reaches 0
+	productMatchPriceTolerance = 0.5
+
+	defaultProductMatchMinConfidence = 0.5
+	defaultProductMatchLimit         = 3
+	maxProductMatchLimit             = 10
+)
+
+// ProductMatchOptions controls which match candidates are proposed
+type ProductMatchOptions struct {
+	MinConfidence float64 // Minimum confidence of a candidate
+	Limit         int     // Candidates per imported product
+}
+
+// ProductMatchSuggestions holds the catalog candidates for one imported product
+type ProductMatchSuggestions struct {
+	ImportedProduct domain.ImportedProduct  `json:"imported_product"`
+	Candidates      []ProductMatchCandidate `json:"candidates"`
+}
+
+// ProductMatchCandidate is a catalog product that may be the same as an imported product
+type ProductMatchCandidate struct {
+	InternalProductID string   `json:"internal_product_id"`
+	Name              string   `json:"name"`
+	SKU               string   `json:"sku"`
+	Price             float64  `json:"price"`
+	ImageURL          string   `json:"image_url,omitempty"`
+	Confidence        float64  `json:"confidence"`
+	Reasons           []string `json:"reasons"`
+}
+
+// ConfirmProductMatchesResult is the outcome of a bulk confirm
+type ConfirmProductMatchesResult struct {
+	Created []domain.ProductMapping `json:"created"`
+	Failed  []ProductMatchFailure   `json:"failed"`
+}
+
+// ProductMatchFailure reports a match that could not be confirmed
+type ProductMatchFailure struct {
+	ImportedProductID uuid.UUID `json:"imported_product_id"`
+	InternalProductID uuid.UUID `json:"internal_product_id"`
+	Error             string    `json:"error"`
+}
+
+// matchableProduct is a catalog product prepared for matching
+type matchableProduct struct {
+	product *clients.Product
+	skus    map[string]bool
+	images  map[string]bool
+	price   float64
+}
+
+// SuggestProductMatches proposes catalog products for every unmapped imported product
+func (s *ProductSyncService) SuggestProductMatches(ctx context.Context, connectionID uuid.UUID, opts ProductMatchOptions) ([]ProductMatchSuggestions, error) {
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	if opts.MinConfidence <= 0 {
+		opts.MinConfidence = defaultProductMatchMinConfidence
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = defaultProductMatchLimit
+	}
+	opts.Limit = min(opts.Limit, maxProductMatchLimit)
+
+	imported, err := s.importedProductRepo.GetUnmapped(ctx, connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load imported products: %w", err)
+	}
+	if len(imported) == 0 {
+		return []ProductMatchSuggestions{}, nil
+	}
+
+	products, err := s.catalogClient.GetAllProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch products from catalog: %w", err)
+	}
+
+	// Catalog products already mapped on this connection cannot be matched again
+	mapped := make(map[string]bool)
+	if ids, err := s.productMappingRepo.GetMappedInternalProductIDs(ctx, connectionID); err == nil {
+		for _, id := range ids {
+			mapped[id.String()] = true
+		}
+	} else {
+		s.logger.Warn("Failed to load mapped products", zap.Error(err))
+	}
+
+	// Index catalog products by SKU, image and name token so each import is only
+	// scored against plausible candidates
+	candidates := make([]matchableProduct, 0, len(products))
+	bySKU := make(map[string][]int)
+	byImage := make(map[string][]int)
+	byToken := make(map[string][]int)
+	for i := range products {
+		if mapped[products[i].ID] {
+			continue
+		}
+		m := newMatchableProduct(&products[i])
+		idx := len(candidates)
+		candidates = append(candidates, m)
+		for sku := range m.skus {
+			bySKU[sku] = append(bySKU[sku], idx)
+		}
+		for image := range m.images {
+			byImage[image] = append(byImage[image], idx)
+		}
+		for _, t := range uniqueTokens(products[i].Name) {
+			byToken[t] = append(byToken[t], idx)
+		}
+	}
+
+	results := make([]ProductMatchSuggestions, 0, len(imported))
+	for _, imp := range imported {
+		pool := make(map[int]bool)
+		if sku := normalizeSKU(imp.ExternalSKU); sku != "" {
+			for _, idx := range bySKU[sku] {
+				pool[idx] = true
+			}
+		}
+		for _, key := range imageKeys(imp.ImageURL) {
+			for _, idx := range byImage[key] {
+				pool[idx] = true
+			}
+		}
+		for _, t := range uniqueTokens(imp.Name) {
+			for _, idx := range byToken[t] {
+				pool[idx] = true
+			}
+		}
+
+		suggestion := ProductMatchSuggestions{
+			ImportedProduct: imp,
+			Candidates:      []ProductMatchCandidate{},
+		}
+		for idx := range pool {
+			candidate := scoreProductMatch(&imp, &candidates[idx])
+			if candidate.Confidence >= opts.MinConfidence {
+				suggestion.Candidates = append(suggestion.Candidates, candidate)
+			}
+		}
+
+		sort.SliceStable(suggestion.Candidates, func(i, j int) bool {
+			if suggestion.Candidates[i].Confidence != suggestion.Candidates[j].Confidence {
+				return suggestion.Candidates[i].Confidence > suggestion.Candidates[j].Confidence
+			}
+			return suggestion.Candidates[i].InternalProductID < suggestion.Candidates[j].InternalProductID
+		})
+		if len(suggestion.Candidates) > opts.Limit {
+			suggestion.Candidates = suggestion.Candidates[:opts.Limit]
+		}
+
+		results = append(results, suggestion)
+	}
+
+	// Most confident matches first so reviewers can confirm them quickly
+	sort.SliceStable(results, func(i, j int) bool {
+		return bestConfidence(results[i]) > bestConfidence(results[j])
+	})
+
+	return results, nil
+}
+
+// ConfirmProductMatches creates product mappings from reviewed matches and marks the imports as mapped
+func (s *ProductSyncService) ConfirmProductMatches(ctx context.Context, connectionID uuid.UUID, req *domain.ConfirmProductMatchesRequest) (*ConfirmProductMatchesResult, error) {
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	result := &ConfirmProductMatchesResult{
+		Created: make([]domain.ProductMapping, 0, len(req.Matches)),
+		Failed:  []ProductMatchFailure{},
+	}
+
+	for _, match := range req.Matches {
+		mapping, err := s.CreateManualMapping(ctx, connectionID, match.ImportedProductID, match.InternalProductID, match.Variants)
+		if err != nil {
+			result.Failed = append(result.Failed, ProductMatchFailure{
+				ImportedProductID: match.ImportedProductID,
+				InternalProductID: match.InternalProductID,
+				Error:             err.Error(),
+			})
+			continue
+		}
+		result.Created = append(result.Created, *mapping)
+	}
+
+	return result, nil
+}
+
+// scoreProductMatch combines every signal into a single match confidence
+func scoreProductMatch(imp *domain.ImportedProduct, m *matchableProduct) ProductMatchCandidate {
+	candidate := ProductMatchCandidate{
+		InternalProductID: m.product.ID,
+		Name:              m.product.Name,
+		SKU:               m.product.SKU,
+		Price:             m.price,
+		Reasons:           []string{},
+	}
+	if len(m.product.Images) > 0 {
+		candidate.ImageURL = m.product.Images[0].URL
+	}
+
+	skuScore := 0.0
+	if sku := normalizeSKU(imp.ExternalSKU); sku != "" && m.skus[sku] {
+		skuScore = 1
+		candidate.Reasons = append(candidate.Reasons, "exact SKU match")
+	}
+
+	nameScore := utils.NameSimilarity(imp.Name, m.product.Name)
+	if nameScore > 0 {
+		candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("name similarity %.2f", nameScore))
+	}
+
+	priceScore := 0.0
+	if imp.Price > 0 && m.price > 0 {
+		diff := math.Abs(imp.Price-m.price) / max(imp.Price, m.price)
+		priceScore = max(0, 1-diff/productMatchPriceTolerance)
+		if priceScore > 0 {
+			candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("price within %.0f%%", diff*100))
+		}
+	}
+
+	imageScore := 0.0
+	for _, key := range imageKeys(imp.ImageURL) {
+		if m.images[key] {
+			imageScore = 1
+			candidate.Reasons = append(candidate.Reasons, "same image")
+			break
+		}
+	}
+
+	confidence := productMatchSKUWeight*skuScore +
+		productMatchNameWeight*nameScore +
+		productMatchPriceWeight*priceScore +
+		productMatchImageWeight*imageScore
+	if skuScore == 1 {
+		confidence = max(confidence, productMatchSKUFloor)
+	}
+	candidate.Confidence = math.Round(confidence*1000) / 1000
+	return candidate
+}
+
+// newMatchableProduct collects the SKUs, image keys and effective price of a catalog product
+func newMatchableProduct(product *clients.Product) matchableProduct {
+	m := matchableProduct{
+		product: product,
+		skus:    make(map[string]bool),
+		images:  make(map[string]bool),
+		price:   product.BasePrice,
+	}
+	if product.SalePrice != nil && *product.SalePrice > 0 {
+		m.price = *product.SalePrice
+	}
+	if sku := normalizeSKU(product.SKU); sku != "" {
+		m.skus[sku] = true
+	}
+	for _, v := range product.Variants {
+		if sku := normalizeSKU(v.SKU); sku != "" {
+			m.skus[sku] = true
+		}
+	}
+	for _, img := range product.Images {
+		for _, key := range imageKeys(img.URL) {
+			m.images[key] = true
+		}
+	}
+	return m
+}
+
+// normalizeSKU trims and lowercases a SKU for comparison
+func normalizeSKU(sku string) string {
+	return strings.ToLower(strings.TrimSpace(sku))
+}
+
+// imageKeys returns the comparison keys of an image URL: the URL without scheme and query,
+// and its file name, which marketplace and catalog CDNs usually derive from the content hash
+func imageKeys(raw string) []string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return []string{strings.ToLower(raw)}
+	}
+
+	keys := []string{strings.ToLower(u.Host + u.Path)}
+	name := strings.TrimSuffix(path.Base(u.Path), path.Ext(u.Path))
+	// Strip size suffixes such as "_tn" used for thumbnails
+	if i := strings.LastIndex(name, "_"); i > 0 {
+		name = name[:i]
+	}
+	if len(name) >= 16 {
+		keys = append(keys, "file:"+strings.ToLower(name))
+	}
+	return keys
+}
+
+// uniqueTokens returns the distinct name tokens that are long enough to be meaningful
+func uniqueTokens(name string) []string {
+	seen := make(map[string]bool)
+	var tokens []string
+	for _, t := range utils.Tokenize(name) {
+		if len(t) < 3 || seen[t] {
+			continue
+		}
+		seen[t] = true
+		tokens = append(tokens, t)
+	}
+	return tokens
+}
+
+// bestConfidence returns the confidence of the top candidate, or 0 without candidates
+func bestConfidence(s ProductMatchSuggestions) float64 {
+	if len(s.Candidates) == 0 {
+		return 0
+	}
+	return s.Candidates[0].Confidence
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/Ecom-micro-template/service-marketplace/internal/clients"
 	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain/shared"
 	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
 	"github.com/Ecom-micro-template/service-marketplace/internal/providers/shopee"
 	"github.com/Ecom-micro-template/service-marketplace/internal/providers/tiktok"
@@ -22,7 +23,9 @@ import (
 var (
 	ErrCategoryMappingNotFound = errors.New("category mapping not found")
 	ErrProductMappingNotFound  = errors.New("product mapping not found")
+	ErrImportedProductNotFound = errors.New("imported product not found")
 	ErrNoProductsToSync        = errors.New("no products to sync")
+	ErrNoValidProducts         = errors.New("no products passed validation")
 )
 
 // ProductSyncService handles product synchronization
@@ -32,10 +35,26 @@ type ProductSyncService struct {
 	categoryMappingRepo   *persistence.CategoryMappingRepository
 	syncJobRepo           *persistence.SyncJobRepository
 	importedProductRepo   *persistence.ImportedProductRepository
+	brandMappingRepo      *persistence.BrandMappingRepository
+	externalCategoryRepo  *persistence.ExternalCategoryRepository
+	listingDriftRepo      *persistence.ListingDriftRepository
+	autoListingRuleRepo   *persistence.AutoListingRuleRepository
+	saleCampaignRepo      *persistence.SaleCampaignRepository
+	warehouseRepo         *persistence.WarehouseRepository
+	syncLogRepo           *persistence.InventorySyncLogRepository
+	pricingService        *PricingService
+	stockAllocation       *StockAllocationService
+	listingContent        *ListingContentService
 	catalogClient         *clients.CatalogClient
 	encryptor             *utils.Encryptor
 	logger                *zap.Logger
 
+	// Serializes sale campaign syncs per connection and product
+	saleCampaignLocks saleCampaignLocks
+
+	// Leaf categories indexed for suggestions, per connection and category tree refresh
+	leafIndexes externalLeafIndexCache
+
 	// Provider factories
 	shopeeClientFactory func(accessToken string, shopID int64) (*shopee.Client, *shopee.ProductProvider)
 	tiktokClientFactory func(accessToken, shopID string) (*tiktok.Client, *tiktok.ProductProvider)
@@ -58,6 +77,16 @@ func NewProductSyncService(
 	categoryMappingRepo *persistence.CategoryMappingRepository,
 	syncJobRepo *persistence.SyncJobRepository,
 	importedProductRepo *persistence.ImportedProductRepository,
+	brandMappingRepo *persistence.BrandMappingRepository,
+	externalCategoryRepo *persistence.ExternalCategoryRepository,
+	listingDriftRepo *persistence.ListingDriftRepository,
+	autoListingRuleRepo *persistence.AutoListingRuleRepository,
+	saleCampaignRepo *persistence.SaleCampaignRepository,
+	warehouseRepo *persistence.WarehouseRepository,
+	syncLogRepo *persistence.InventorySyncLogRepository,
+	pricingService *PricingService,
+	stockAllocation *StockAllocationService,
+	listingContent *ListingContentService,
 	catalogClient *clients.CatalogClient,
 	cfg *ProductSyncServiceConfig,
 	logger *zap.Logger,
@@ -77,6 +106,16 @@ func NewProductSyncService(
 		categoryMappingRepo:   categoryMappingRepo,
 		syncJobRepo:           syncJobRepo,
 		importedProductRepo:   importedProductRepo,
+		brandMappingRepo:      brandMappingRepo,
+		externalCategoryRepo:  externalCategoryRepo,
+		listingDriftRepo:      listingDriftRepo,
+		autoListingRuleRepo:   autoListingRuleRepo,
+		saleCampaignRepo:      saleCampaignRepo,
+		warehouseRepo:         warehouseRepo,
+		syncLogRepo:           syncLogRepo,
+		pricingService:        pricingService,
+		stockAllocation:       stockAllocation,
+		listingContent:        listingContent,
 		catalogClient:         catalogClient,
 		encryptor:             encryptor,
 		logger:                logger,
@@ -117,16 +156,12 @@ func (s *ProductSyncService) GetProductMapping(ctx context.Context, mappingID uu
 	return s.productMappingRepo.GetByID(ctx, mappingID)
 }
 
-// GetExternalCategories fetches categories from the marketplace
-func (s *ProductSyncService) GetExternalCategories(ctx context.Context, connectionID uuid.UUID) ([]providers.ExternalCategory, error) {
-	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
-	if err != nil {
-		return nil, ErrConnectionNotFound
-	}
-
+// fetchExternalCategories fetches categories directly from the marketplace
+func (s *ProductSyncService) fetchExternalCategories(ctx context.Context, conn *domain.Connection) ([]providers.ExternalCategory, error) {
 	// Decrypt access token
 	accessToken := conn.AccessToken
 	if s.encryptor != nil {
+		var err error
 		accessToken, err = s.encryptor.Decrypt(conn.AccessToken)
 		if err != nil {
 			return nil, fmt.Errorf("failed to decrypt token: %w", err)
@@ -167,6 +202,10 @@ func (s *ProductSyncService) CreateCategoryMapping(ctx context.Context, connecti
 		// Update existing
 		existing.ExternalCategoryID = req.ExternalCategoryID
 		existing.ExternalCategoryName = req.ExternalCategoryName
+		existing.IsStale = false
+		if req.Inherit != nil {
+			existing.Inherit = *req.Inherit
+		}
 		if err := s.categoryMappingRepo.Update(ctx, existing); err != nil {
 			return nil, fmt.Errorf("failed to update mapping: %w", err)
 		}
@@ -178,6 +217,10 @@ func (s *ProductSyncService) CreateCategoryMapping(ctx context.Context, connecti
 		InternalCategoryID:   req.InternalCategoryID,
 		ExternalCategoryID:   req.ExternalCategoryID,
 		ExternalCategoryName: req.ExternalCategoryName,
+		Inherit:              true,
+	}
+	if req.Inherit != nil {
+		mapping.Inherit = *req.Inherit
 	}
 
 	if err := s.categoryMappingRepo.Create(ctx, mapping); err != nil {
@@ -193,43 +236,71 @@ func (s *ProductSyncService) DeleteCategoryMapping(ctx context.Context, mappingI
 }
 
 // PushProducts pushes products to a marketplace
-// If productIDs is empty, fetches all active products from catalog
-func (s *ProductSyncService) PushProducts(ctx context.Context, connectionID uuid.UUID, productIDs []string) (*domain.SyncJob, error) {
+// If productIDs is empty, fetches all active products from catalog.
+// Products are validated up front; only valid products are queued and the
+// validation report is returned alongside the job.
+func (s *ProductSyncService) PushProducts(ctx context.Context, connectionID uuid.UUID, productIDs []string) (*domain.SyncJob, *ProductValidationReport, error) {
 	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
 	if err != nil {
-		return nil, ErrConnectionNotFound
+		return nil, nil, ErrConnectionNotFound
 	}
 
-	// If no specific products, fetch all from catalog
 	pushAll := len(productIDs) == 0
-	if pushAll {
-		s.logger.Info("Push all products requested, fetching from catalog")
-		products, err := s.catalogClient.GetAllProducts(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch products from catalog: %w", err)
-		}
-		if len(products) == 0 {
-			return nil, ErrNoProductsToSync
-		}
-		productIDs = make([]string, len(products))
-		for i, p := range products {
-			productIDs[i] = p.ID
-		}
-		s.logger.Info("Fetched products for push all", zap.Int("count", len(productIDs)))
+	products, err := s.fetchCatalogProducts(ctx, productIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(products) == 0 {
+		return nil, nil, ErrNoProductsToSync
+	}
+
+	// Validate before queueing so unpushable products are reported immediately
+	report, err := s.validateCatalogProducts(ctx, conn, productIDs, products)
+	if err != nil {
+		return nil, nil, err
+	}
+	productIDs = report.ValidProductIDs()
+	if len(productIDs) == 0 {
+		return nil, report, ErrNoValidProducts
 	}
 
-	// Create sync job
-	payload, _ := json.Marshal(map[string]interface{}{
-		"product_ids": productIDs,
-		"push_all":    pushAll,
+	job, err := s.queueProductPush(ctx, conn, &domain.ProductPushPayload{
+		ProductIDs: productIDs,
+		PushAll:    pushAll,
+		Trigger:    domain.PushTriggerManual,
 	})
+	if err != nil {
+		return nil, report, err
+	}
+
+	return job, report, nil
+}
+
+// GetPushJobs lists the product push jobs of a connection, including auto-listing pushes
+func (s *ProductSyncService) GetPushJobs(ctx context.Context, connectionID uuid.UUID, filter *domain.SyncJobFilter) ([]domain.SyncJob, int64, error) {
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return nil, 0, ErrConnectionNotFound
+	}
+	filter.JobType = domain.JobTypeProductPush
+	return s.syncJobRepo.GetByConnectionID(ctx, connectionID, filter)
+}
+
+// GetPushJob returns a product push job of a connection with its per-product results
+func (s *ProductSyncService) GetPushJob(ctx context.Context, connectionID, jobID uuid.UUID) (*domain.SyncJob, error) {
+	return s.getConnectionJob(ctx, connectionID, jobID, domain.JobTypeProductPush)
+}
+
+// queueProductPush creates a product push job and starts processing it
+func (s *ProductSyncService) queueProductPush(ctx context.Context, conn *domain.Connection, payload *domain.ProductPushPayload) (*domain.SyncJob, error) {
+	data, _ := json.Marshal(payload)
 
 	job := &domain.SyncJob{
-		ConnectionID: connectionID,
+		ConnectionID: conn.ID,
 		JobType:      domain.JobTypeProductPush,
-		Payload:      payload,
+		Payload:      data,
 		Status:       domain.JobStatusPending,
 		MaxAttempts:  3,
+		TotalItems:   len(payload.ProductIDs),
 	}
 
 	if err := s.syncJobRepo.Create(ctx, job); err != nil {
@@ -251,9 +322,7 @@ func (s *ProductSyncService) processProductPushJob(ctx context.Context, job *dom
 	}
 
 	// Parse payload
-	var payload struct {
-		ProductIDs []string `json:"product_ids"`
-	}
+	var payload domain.ProductPushPayload
 	if err := json.Unmarshal(job.Payload, &payload); err != nil {
 		s.syncJobRepo.MarkFailed(ctx, job.ID, "invalid payload")
 		return
@@ -288,6 +357,10 @@ func (s *ProductSyncService) processProductPushJob(ctx context.Context, job *dom
 		return
 	}
 
+	// Resolve brands against the marketplace brand list
+	listBrands, _ := s.brandListerFor(conn, accessToken)
+	brands := s.newBrandResolver(job.ConnectionID, listBrands)
+
 	// Fetch products from catalog
 	products, err := s.catalogClient.GetProducts(ctx, payload.ProductIDs)
 	if err != nil {
@@ -295,57 +368,52 @@ func (s *ProductSyncService) processProductPushJob(ctx context.Context, job *dom
 		return
 	}
 
+	// Resolve category mappings through the internal category hierarchy
+	categoryMappings, err := s.newCategoryMappingResolver(ctx, job.ConnectionID)
+	if err != nil {
+		s.syncJobRepo.MarkFailed(ctx, job.ID, err.Error())
+		return
+	}
+
 	// Push each product
 	successCount := 0
+	payload.Results = make([]domain.ProductPushResult, 0, len(products))
 	for _, product := range products {
+		result := domain.ProductPushResult{ProductID: product.ID}
+
 		// Get category mapping
-		internalCatID, _ := uuid.Parse(product.CategoryID)
-		catMapping, err := s.categoryMappingRepo.GetByConnectionAndInternalCategory(ctx, job.ConnectionID, internalCatID)
-		if err != nil {
+		catMapping, _ := categoryMappings.Resolve(product.CategoryID)
+		if catMapping == nil {
 			s.logger.Warn("No category mapping for product", zap.String("product", product.ID))
+			result.Error = "no category mapping"
+			payload.Results = append(payload.Results, result)
 			continue
 		}
 
 		// Build push request
-		images := make([]string, len(product.Images))
-		for i, img := range product.Images {
-			images[i] = img.URL
-		}
-
-		price := product.BasePrice
-		if product.SalePrice != nil {
-			price = *product.SalePrice
-		}
-
-		// Map dimensions if available from catalog
-		var dimensions *providers.Dimensions
-		if product.Dimensions != nil {
-			dimensions = &providers.Dimensions{
-				Length: product.Dimensions.Length,
-				Width:  product.Dimensions.Width,
-				Height: product.Dimensions.Height,
-			}
+		pushReq := buildPushRequest(&product, catMapping.ExternalCategoryID)
+		if err := s.applyPricing(ctx, job.ConnectionID, &product, pushReq); err != nil {
+			s.logger.Error("Failed to price product", zap.String("product", product.ID), zap.Error(err))
+			result.Error = err.Error()
+			payload.Results = append(payload.Results, result)
+			continue
 		}
-
-		pushReq := &providers.ProductPushRequest{
-			InternalID:    product.ID,
-			Name:          product.Name,
-			Description:   product.Description,
-			Price:         price,
-			OriginalPrice: product.BasePrice,
-			Stock:         product.StockQuantity,
-			SKU:           product.SKU,
-			CategoryID:    catMapping.ExternalCategoryID,
-			Images:        images,
-			Weight:        product.Weight,
-			Brand:         product.Brand,
-			Dimensions:    dimensions,
+		s.applyListingContent(ctx, job.ConnectionID, &product, pushReq)
+		if err := s.applyWarehouseStock(ctx, conn, &product, pushReq); err != nil {
+			s.logger.Error("Failed to allocate product stock", zap.String("product", product.ID), zap.Error(err))
+			result.Error = err.Error()
+			payload.Results = append(payload.Results, result)
+			continue
 		}
+		pushReq.BrandID, pushReq.BrandName = brands.Resolve(ctx, product.Brand, catMapping.ExternalCategoryID)
+		pushReq.AsDraft = payload.AsDraft
 
 		// Push to marketplace
 		resp, err := pushProduct(ctx, pushReq)
 		if err != nil {
 			s.logger.Error("Failed to push product", zap.String("product", product.ID), zap.Error(err))
+			result.Error = err.Error()
+			payload.Results = append(payload.Results, result)
 
 			// Create/update mapping with error
 			productID, _ := uuid.Parse(product.ID)
@@ -354,12 +422,21 @@ func (s *ProductSyncService) processProductPushJob(ctx context.Context, job *dom
 				InternalProductID: productID,
 				SyncStatus:        domain.SyncStatusError,
 				SyncError:         err.Error(),
+				ListingStatus:     shared.ListingDraft,
 			}
 			s.productMappingRepo.Create(ctx, mapping)
+			s.recordPushedStock(ctx, conn, mapping, pushReq, err)
 			continue
 		}
+		result.ExternalProductID = resp.ExternalProductID
+		payload.Results = append(payload.Results, result)
 
 		// Create/update product mapping
+		listingStatus, listingReason := initialListingStatus(conn.Platform), ""
+		if payload.AsDraft {
+			listingStatus, listingReason = draftListingStatus(conn.Platform)
+		}
+
 		productID, _ := uuid.Parse(product.ID)
 		mapping := &domain.ProductMapping{
 			ConnectionID:      job.ConnectionID,
@@ -367,6 +444,8 @@ func (s *ProductSyncService) processProductPushJob(ctx context.Context, job *dom
 			ExternalProductID: resp.ExternalProductID,
 			ExternalSKU:       resp.ExternalSKU,
 			SyncStatus:        domain.SyncStatusSynced,
+			ListingStatus:     listingStatus,
+			ListingReason:     listingReason,
 		}
 
 		existing, _ := s.productMappingRepo.GetByConnectionAndInternalProduct(ctx, job.ConnectionID, productID)
@@ -375,14 +454,34 @@ func (s *ProductSyncService) processProductPushJob(ctx context.Context, job *dom
 			existing.ExternalSKU = resp.ExternalSKU
 			existing.SyncStatus = domain.SyncStatusSynced
 			existing.SyncError = ""
+			if existing.ListingStatus == shared.ListingDraft || existing.ListingStatus == shared.ListingRejected {
+				existing.ListingStatus = mapping.ListingStatus
+				existing.ListingReason = mapping.ListingReason
+			}
 			s.productMappingRepo.Update(ctx, existing)
+			mapping = existing
 		} else {
 			s.productMappingRepo.Create(ctx, mapping)
 		}
+		s.recordPushedStock(ctx, conn, mapping, pushReq, nil)
+
+		// The listing is live at its regular price even if the sale cannot be published
+		if err := s.SyncSaleCampaign(ctx, conn, mapping, &product); err != nil {
+			s.logger.Warn("Failed to publish sale campaign", zap.String("product", product.ID), zap.Error(err))
+		}
 
 		successCount++
 	}
 
+	// Record per-product results so they are visible in the job API
+	job.TotalItems = len(products)
+	job.ProcessedItems = len(products)
+	job.FailedItems = len(products) - successCount
+	job.Payload, _ = json.Marshal(payload)
+	if err := s.syncJobRepo.UpdateProgress(ctx, job); err != nil {
+		s.logger.Warn("Failed to save push results", zap.String("job_id", job.ID.String()), zap.Error(err))
+	}
+
 	// Mark job complete
 	if successCount == len(products) {
 		s.syncJobRepo.MarkCompleted(ctx, job.ID)
@@ -393,138 +492,116 @@ func (s *ProductSyncService) processProductPushJob(ctx context.Context, job *dom
 	}
 }
 
-// UpdateProductMapping updates a product mapping
-func (s *ProductSyncService) UpdateProductMapping(ctx context.Context, mappingID uuid.UUID, status string) error {
-	mapping, err := s.productMappingRepo.GetByID(ctx, mappingID)
+// applyPricing replaces the catalog prices of a push request with the connection's pricing rules.
+// The listing gets the regular price; a sale is published as a discount campaign after the push.
+func (s *ProductSyncService) applyPricing(ctx context.Context, connectionID uuid.UUID, product *clients.Product, pushReq *providers.ProductPushRequest) error {
+	quote, err := s.pricingService.RegularQuote(ctx, connectionID, product)
 	if err != nil {
-		return ErrProductMappingNotFound
+		return err
 	}
-
-	mapping.SyncStatus = status
-	return s.productMappingRepo.Update(ctx, mapping)
+	pushReq.Price = quote.Price
+	pushReq.OriginalPrice = quote.OriginalPrice
+	return nil
 }
 
-// DeleteProductMapping deletes a product mapping
-func (s *ProductSyncService) DeleteProductMapping(ctx context.Context, mappingID uuid.UUID) error {
-	return s.productMappingRepo.Delete(ctx, mappingID)
+// applyListingContent replaces the catalog content of a push request with the connection's
+// listing template and the product's override
+func (s *ProductSyncService) applyListingContent(ctx context.Context, connectionID uuid.UUID, product *clients.Product, pushReq *providers.ProductPushRequest) {
+	content := s.listingContent.Resolve(ctx, connectionID, product)
+	pushReq.Name = content.Title
+	pushReq.DescriptionBlocks = descriptionBlocks(content)
+	pushReq.Description = providers.RenderPlainText(pushReq.DescriptionBlocks)
+	pushReq.ExtendedDescription = content.ExtendedDescription
+	pushReq.Images = content.Images
+	if len(content.Attributes) > 0 {
+		pushReq.Attributes = content.Attributes
+	}
+	// An overridden price is published as is, without strike-through
+	if content.Price != nil {
+		pushReq.Price = *content.Price
+		pushReq.OriginalPrice = *content.Price
+	}
 }
 
-// ImportProducts imports products from a marketplace and stores them locally
-func (s *ProductSyncService) ImportProducts(ctx context.Context, connectionID uuid.UUID) (int, error) {
-	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+// applyWarehouseStock sets the stock of a push request to what the connection publishes,
+// split over its marketplace warehouses
+func (s *ProductSyncService) applyWarehouseStock(ctx context.Context, conn *domain.Connection, product *clients.Product, pushReq *providers.ProductPushRequest) error {
+	productID, err := uuid.Parse(product.ID)
 	if err != nil {
-		return 0, ErrConnectionNotFound
-	}
-
-	// Decrypt access token
-	accessToken := conn.AccessToken
-	if s.encryptor != nil {
-		accessToken, err = s.encryptor.Decrypt(conn.AccessToken)
-		if err != nil {
-			return 0, fmt.Errorf("failed to decrypt token: %w", err)
-		}
+		return nil
 	}
+	pushReq.Warehouses, pushReq.Stock, err = s.publishedStock(ctx, conn, productID, product)
+	return err
+}
 
-	switch conn.Platform {
-	case "shopee":
-		return s.importShopeeProducts(ctx, conn, accessToken)
-	case "tiktok":
-		// TODO: Implement TikTok import
-		return 0, fmt.Errorf("tiktok import not yet implemented")
-	default:
-		return 0, ErrInvalidPlatform
+// recordPushedStock logs the initial stock of a pushed product
+func (s *ProductSyncService) recordPushedStock(ctx context.Context, conn *domain.Connection, mapping *domain.ProductMapping, pushReq *providers.ProductPushRequest, pushErr error) {
+	update := providers.InventoryUpdate{
+		ExternalProductID: mapping.ExternalProductID,
+		Quantity:          pushReq.Stock,
 	}
+	recordStockPushes(ctx, s.syncLogRepo, s.logger, conn.ID, stockPushLog(conn.ID, mapping, update, domain.InventorySyncSourceProductPush, pushErr))
 }
 
-// importShopeeProducts imports products from Shopee
-func (s *ProductSyncService) importShopeeProducts(ctx context.Context, conn *domain.Connection, accessToken string) (int, error) {
-	shopID, _ := strconv.ParseInt(conn.ShopID, 10, 64)
-	_, productProvider := s.shopeeClientFactory(accessToken, shopID)
-
-	var allItems []shopee.ShopeeItem
-	offset := 0
-	pageSize := 50
-
-	// Fetch all items with pagination
-	for {
-		items, _, hasMore, err := productProvider.GetItemList(ctx, offset, pageSize, "NORMAL")
-		if err != nil {
-			return 0, fmt.Errorf("failed to get item list: %w", err)
-		}
-		allItems = append(allItems, items...)
+// publishedStock computes the stock published for a product on a connection: split over the
+// connection's marketplace warehouses and limited by its stock allocation rules
+func (s *ProductSyncService) publishedStock(ctx context.Context, conn *domain.Connection, productID uuid.UUID, product *clients.Product) ([]providers.WarehouseStock, int, error) {
+	warehouses, total := warehouseStock(ctx, s.warehouseRepo, conn, productID, product.StockQuantity)
+	return s.stockAllocation.Allocate(ctx, conn.ID, productID, product.CategoryID, warehouses, total)
+}
 
-		if !hasMore {
-			break
-		}
-		offset += pageSize
+// buildPushRequest converts a catalog product into a marketplace push request
+func buildPushRequest(product *clients.Product, externalCategoryID string) *providers.ProductPushRequest {
+	images := make([]string, len(product.Images))
+	for i, img := range product.Images {
+		images[i] = img.URL
 	}
 
-	if len(allItems) == 0 {
-		return 0, nil
+	price := product.BasePrice
+	if product.SalePrice != nil {
+		price = *product.SalePrice
 	}
 
-	// Get item details in batches of 50
-	var importedProducts []domain.ImportedProduct
-	for i := 0; i < len(allItems); i += 50 {
-		end := i + 50
-		if end > len(allItems) {
-			end = len(allItems)
-		}
-
-		// Extract item IDs
-		itemIDs := make([]int64, end-i)
-		for j, item := range allItems[i:end] {
-			itemIDs[j] = item.ItemID
-		}
-
-		// Get details
-		details, err := productProvider.GetItemBaseInfo(ctx, itemIDs)
-		if err != nil {
-			s.logger.Warn("Failed to get item details", zap.Error(err))
-			continue
-		}
-
-		// Convert to ImportedProduct
-		for _, detail := range details {
-			imageURL := ""
-			if len(detail.Images) > 0 {
-				imageURL = detail.Images[0]
-			}
-
-			importedProducts = append(importedProducts, domain.ImportedProduct{
-				ConnectionID:      conn.ID,
-				ExternalProductID: strconv.FormatInt(detail.ItemID, 10),
-				ExternalSKU:       detail.ItemSKU,
-				Name:              detail.ItemName,
-				Description:       detail.Description,
-				Price:             detail.OriginalPrice,
-				Stock:             detail.Stock,
-				CategoryID:        strconv.FormatInt(detail.CategoryID, 10),
-				Status:            detail.ItemStatus,
-				ImageURL:          imageURL,
-			})
+	// Map dimensions if available from catalog
+	var dimensions *providers.Dimensions
+	if product.Dimensions != nil {
+		dimensions = &providers.Dimensions{
+			Length: product.Dimensions.Length,
+			Width:  product.Dimensions.Width,
+			Height: product.Dimensions.Height,
 		}
 	}
 
-	// Upsert imported products
-	if err := s.importedProductRepo.UpsertBatch(ctx, importedProducts); err != nil {
-		return 0, fmt.Errorf("failed to save imported products: %w", err)
+	return &providers.ProductPushRequest{
+		InternalID:    product.ID,
+		Name:          product.Name,
+		Description:   product.Description,
+		Price:         price,
+		OriginalPrice: product.BasePrice,
+		Stock:         product.StockQuantity,
+		SKU:           product.SKU,
+		CategoryID:    externalCategoryID,
+		Images:        images,
+		Weight:        product.Weight,
+		Brand:         product.Brand,
+		Dimensions:    dimensions,
 	}
+}
 
-	// Sync is_mapped status with existing product_mappings
-	// This handles products that were previously pushed/mapped
-	for _, product := range importedProducts {
-		existingMapping, _ := s.productMappingRepo.GetByConnectionAndExternalProduct(ctx, conn.ID, product.ExternalProductID)
-		if existingMapping != nil {
-			// Product already has a mapping, update imported_product status
-			importedProduct, _ := s.importedProductRepo.GetByExternalProductID(ctx, conn.ID, product.ExternalProductID)
-			if importedProduct != nil && !importedProduct.IsMapped {
-				s.importedProductRepo.SetMapped(ctx, importedProduct.ID, existingMapping.InternalProductID)
-			}
-		}
+// UpdateProductMapping updates a product mapping
+func (s *ProductSyncService) UpdateProductMapping(ctx context.Context, mappingID uuid.UUID, status string) error {
+	mapping, err := s.productMappingRepo.GetByID(ctx, mappingID)
+	if err != nil {
+		return ErrProductMappingNotFound
 	}
 
-	return len(importedProducts), nil
+	mapping.SyncStatus = status
+	return s.productMappingRepo.Update(ctx, mapping)
+}
+
+// DeleteProductMapping deletes a product mapping
+func (s *ProductSyncService) DeleteProductMapping(ctx context.Context, mappingID uuid.UUID) error {
+	return s.productMappingRepo.Delete(ctx, mappingID)
 }
 
 // GetImportedProducts retrieves imported products for a connection
@@ -532,10 +609,19 @@ func (s *ProductSyncService) GetImportedProducts(ctx context.Context, connection
 	return s.importedProductRepo.GetByConnectionID(ctx, connectionID, filter)
 }
 
+// GetImportedProduct retrieves a single imported product, including its raw platform payload
+func (s *ProductSyncService) GetImportedProduct(ctx context.Context, connectionID, importedProductID uuid.UUID) (*domain.ImportedProduct, error) {
+	product, err := s.importedProductRepo.GetByID(ctx, importedProductID)
+	if err != nil || product.ConnectionID != connectionID {
+		return nil, ErrImportedProductNotFound
+	}
+	return product, nil
+}
+
 // CreateManualMapping creates a manual mapping between an imported product and an internal product
-func (s *ProductSyncService) CreateManualMapping(ctx context.Context, connectionID uuid.UUID, importedProductID uuid.UUID, internalProductID uuid.UUID) (*domain.ProductMapping, error) {
+func (s *ProductSyncService) CreateManualMapping(ctx context.Context, connectionID uuid.UUID, importedProductID uuid.UUID, internalProductID uuid.UUID, variants []domain.VariantMatch) (*domain.ProductMapping, error) {
 	// Verify connection exists
-	_, err := s.connectionRepo.GetByID(ctx, connectionID)
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
 	if err != nil {
 		return nil, ErrConnectionNotFound
 	}
@@ -558,13 +644,27 @@ func (s *ProductSyncService) CreateManualMapping(ctx context.Context, connection
 		return nil, fmt.Errorf("external product is already mapped to internal product %s", existingByExternal.InternalProductID)
 	}
 
-	// Create the mapping
+	// Pair the imported models with catalog variants
+	variantMappings, err := s.pairImportedVariants(ctx, importedProduct, internalProductID, variants)
+	if err != nil {
+		return nil, err
+	}
+	// Stock of a multi-model listing can only be pushed per model
+	if len(importedProduct.GetSKUs()) > 1 && len(variantMappings) == 0 {
+		return nil, ErrVariantPairsRequired
+	}
+
+	// Create the mapping; variant mappings are created with it
 	mapping := &domain.ProductMapping{
 		ConnectionID:      connectionID,
 		InternalProductID: internalProductID,
 		ExternalProductID: importedProduct.ExternalProductID,
-		ExternalSKU:       importedProduct.ExternalSKU,
+		ExternalSKU:       importedExternalSKU(conn.Platform, importedProduct),
 		SyncStatus:        domain.SyncStatusSynced,
+		VariantMappings:   variantMappings,
+	}
+	if status, ok := importedListingStatus(conn.Platform, importedProduct); ok {
+		mapping.ListingStatus = status
 	}
 
 	if err := s.productMappingRepo.Create(ctx, mapping); err != nil {
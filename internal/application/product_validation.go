@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/clients"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+// issueProductNotFound is reported for requested products missing from the catalog
+const issueProductNotFound = "product_not_found"
+
+// ProductValidation is the dry-run result for a single product
+type ProductValidation struct {
+	ProductID string                      `json:"product_id"`
+	Name      string                      `json:"name"`
+	SKU       string                      `json:"sku"`
+	Valid     bool                        `json:"valid"`
+	Errors    []providers.ValidationIssue `json:"errors"`
+	Warnings  []providers.ValidationIssue `json:"warnings"`
+}
+
+// ProductValidationReport is the dry-run result for a set of products
+type ProductValidationReport struct {
+	ConnectionID uuid.UUID                   `json:"connection_id"`
+	Platform     string                      `json:"platform"`
+	Total        int                         `json:"total"`
+	ValidCount   int                         `json:"valid_count"`
+	InvalidCount int                         `json:"invalid_count"`
+	ShopErrors   []providers.ValidationIssue `json:"shop_errors"` // Block every product when present
+	Products     []ProductValidation         `json:"products"`
+}
+
+// ValidProductIDs returns the IDs of products that passed validation
+func (r *ProductValidationReport) ValidProductIDs() []string {
+	ids := make([]string, 0, r.ValidCount)
+	for _, p := range r.Products {
+		if p.Valid {
+			ids = append(ids, p.ProductID)
+		}
+	}
+	return ids
+}
+
+// productRules holds the platform validation rules for a connection
+type productRules struct {
+	validateProduct func(product *providers.ProductPushRequest) *providers.ValidationResult
+	validateShop    func(ctx context.Context) *providers.ValidationResult
+}
+
+// productRulesFor returns the validation rules for a connection's platform
+func (s *ProductSyncService) productRulesFor(conn *domain.Connection, accessToken string) (*productRules, error) {
+	switch conn.Platform {
+	case "shopee":
+		shopID, _ := strconv.ParseInt(conn.ShopID, 10, 64)
+		_, productProvider := s.shopeeClientFactory(accessToken, shopID)
+		return &productRules{
+			validateProduct: productProvider.ValidateProduct,
+			validateShop:    productProvider.ValidateShop,
+		}, nil
+
+	case "tiktok":
+		_, productProvider := s.tiktokClientFactory(accessToken, conn.ShopID)
+		return &productRules{
+			validateProduct: productProvider.ValidateProduct,
+		}, nil
+
+	default:
+		return nil, ErrInvalidPlatform
+	}
+}
+
+// ValidateProducts runs every platform rule against products without calling write APIs
+// If productIDs is empty, validates all products from catalog
+func (s *ProductSyncService) ValidateProducts(ctx context.Context, connectionID uuid.UUID, productIDs []string) (*ProductValidationReport, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	products, err := s.fetchCatalogProducts(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.validateCatalogProducts(ctx, conn, productIDs, products)
+}
+
+// fetchCatalogProducts fetches the given products, or all products if productIDs is empty
+func (s *ProductSyncService) fetchCatalogProducts(ctx context.Context, productIDs []string) ([]clients.Product, error) {
+	if len(productIDs) == 0 {
+		s.logger.Info("All products requested, fetching from catalog")
+		products, err := s.catalogClient.GetAllProducts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch products from catalog: %w", err)
+		}
+		s.logger.Info("Fetched all products from catalog", zap.Int("count", len(products)))
+		return products, nil
+	}
+
+	products, err := s.catalogClient.GetProducts(ctx, productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch products from catalog: %w", err)
+	}
+	return products, nil
+}
+
+// validateCatalogProducts builds the push request for each product and checks it against
+// category mappings, brand requirements and the platform listing rules
+func (s *ProductSyncService) validateCatalogProducts(ctx context.Context, conn *domain.Connection, requestedIDs []string, products []clients.Product) (*ProductValidationReport, error) {
+	// Decrypt access token
+	accessToken := conn.AccessToken
+	if s.encryptor != nil {
+		var err error
+		accessToken, err = s.encryptor.Decrypt(conn.AccessToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt token: %w", err)
+		}
+	}
+
+	rules, err := s.productRulesFor(conn, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	listBrands, _ := s.brandListerFor(conn, accessToken)
+	brands := s.newBrandResolver(conn.ID, listBrands)
+	categoryMappings, err := s.newCategoryMappingResolver(ctx, conn.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ProductValidationReport{
+		ConnectionID: conn.ID,
+		Platform:     conn.Platform,
+		ShopErrors:   []providers.ValidationIssue{},
+		Products:     make([]ProductValidation, 0, len(products)),
+	}
+
+	// Shop-level prerequisites apply to every product
+	if rules.validateShop != nil {
+		report.ShopErrors = rules.validateShop(ctx).Errors
+	}
+
+	found := make(map[string]bool, len(products))
+	for i := range products {
+		product := &products[i]
+		found[product.ID] = true
+
+		result := providers.NewValidationResult()
+
+		// Category mapping
+		externalCategoryID := ""
+		if catMapping, _ := categoryMappings.Resolve(product.CategoryID); catMapping != nil {
+			externalCategoryID = catMapping.ExternalCategoryID
+		}
+
+		pushReq := buildPushRequest(product, externalCategoryID)
+		if err := s.applyPricing(ctx, conn.ID, product, pushReq); err != nil {
+			return nil, err
+		}
+		s.applyListingContent(ctx, conn.ID, product, pushReq)
+
+		// Brand requirements can only be checked once the category is known
+		if externalCategoryID != "" {
+			lookup := brands.Lookup(ctx, product.Brand, externalCategoryID)
+			pushReq.BrandID, pushReq.BrandName = lookup.BrandID, lookup.BrandName
+			s.validateBrand(result, product.Brand, lookup)
+		}
+
+		result.Merge(rules.validateProduct(pushReq))
+		if externalCategoryID == "" && product.CategoryName != "" {
+			// Make the unmapped-category hint point at the actual category
+			for j := range result.Errors {
+				if result.Errors[j].Code == providers.IssueCategoryUnmapped {
+					result.Errors[j].Hint = fmt.Sprintf("Create a category mapping for %q", product.CategoryName)
+				}
+			}
+		}
+
+		report.Products = append(report.Products, ProductValidation{
+			ProductID: product.ID,
+			Name:      product.Name,
+			SKU:       product.SKU,
+			Valid:     result.IsValid() && len(report.ShopErrors) == 0,
+			Errors:    result.Errors,
+			Warnings:  result.Warnings,
+		})
+	}
+
+	// Requested products the catalog did not return
+	for _, id := range requestedIDs {
+		if found[id] {
+			continue
+		}
+		found[id] = true
+		report.Products = append(report.Products, ProductValidation{
+			ProductID: id,
+			Valid:     false,
+			Errors: []providers.ValidationIssue{{
+				Code:    issueProductNotFound,
+				Field:   "product_id",
+				Message: "product was not found in the catalog",
+				Hint:    "Check the product exists and is active",
+			}},
+			Warnings: []providers.ValidationIssue{},
+		})
+	}
+
+	report.Total = len(report.Products)
+	for _, p := range report.Products {
+		if p.Valid {
+			report.ValidCount++
+		} else {
+			report.InvalidCount++
+		}
+	}
+
+	return report, nil
+}
+
+// validateBrand reports mandatory and unresolved brands
+func (s *ProductSyncService) validateBrand(result *providers.ValidationResult, brand string, lookup *brandLookup) {
+	if lookup.BrandID != "" {
+		return
+	}
+
+	hint := "Set a brand in the catalog and confirm a brand mapping"
+	if brand != "" {
+		hint = fmt.Sprintf("Confirm a brand mapping for %q", brand)
+		if lookup.Match != nil {
+			hint = fmt.Sprintf("Confirm the suggested brand %q (ID %s) for %q", lookup.Match.BrandName, lookup.Match.BrandID, brand)
+		}
+	}
+
+	if lookup.Mandatory {
+		result.AddError(providers.IssueBrandRequired, "brand", "the mapped category requires a brand", hint)
+		return
+	}
+	if brand != "" {
+		result.AddWarning(providers.IssueBrandUnresolved, "brand",
+			fmt.Sprintf("brand %q has no marketplace brand mapping and will be listed as No Brand", brand), hint)
+	}
+}
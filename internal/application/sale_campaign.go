@@ -0,0 +1,411 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/clients"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+// SaleCampaignRenewalLead is how long before a campaign capped by the marketplace's maximum
+// duration ends its successor is created
+const SaleCampaignRenewalLead = 3 * 24 * time.Hour
+
+// discountEditor creates and ends marketplace discounts
+type discountEditor struct {
+	create func(ctx context.Context, discount *providers.DiscountRequest) (*providers.DiscountResponse, error)
+	end    func(ctx context.Context, discountID string, started bool) error
+}
+
+// discountEditorFor returns the discount editor for a connection's platform
+func (s *ProductSyncService) discountEditorFor(conn *domain.Connection, accessToken string) (*discountEditor, error) {
+	switch conn.Platform {
+	case "shopee":
+		shopID, _ := strconv.ParseInt(conn.ShopID, 10, 64)
+		_, productProvider := s.shopeeClientFactory(accessToken, shopID)
+		return &discountEditor{create: productProvider.CreateDiscount, end: productProvider.EndDiscount}, nil
+
+	case "tiktok":
+		_, productProvider := s.tiktokClientFactory(accessToken, conn.ShopID)
+		return &discountEditor{create: productProvider.CreateDiscount, end: productProvider.EndDiscount}, nil
+
+	default:
+		return nil, ErrInvalidPlatform
+	}
+}
+
+// saleCampaignKey identifies the campaigns of one product on one connection
+type saleCampaignKey struct {
+	connectionID      uuid.UUID
+	internalProductID uuid.UUID
+}
+
+// saleCampaignLocks hands out one lock per connection and product, dropped once unused.
+// The zero value is ready to use.
+type saleCampaignLocks struct {
+	mu    sync.Mutex
+	locks map[saleCampaignKey]*saleCampaignLock
+}
+
+type saleCampaignLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lock blocks until no other sync of the product's campaigns runs and returns the unlock function
+func (l *saleCampaignLocks) lock(connectionID, internalProductID uuid.UUID) func() {
+	key := saleCampaignKey{connectionID: connectionID, internalProductID: internalProductID}
+
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[saleCampaignKey]*saleCampaignLock)
+	}
+	lock := l.locks[key]
+	if lock == nil {
+		lock = &saleCampaignLock{}
+		l.locks[key] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+
+		l.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// catalogSale is the sale of a catalog product as published on a connection
+type catalogSale struct {
+	regularPrice float64
+	salePrice    float64
+	startsAt     *time.Time
+	endsAt       *time.Time
+}
+
+// matches reports whether a campaign was created for this sale
+func (sale *catalogSale) matches(campaign *domain.SaleCampaign) bool {
+	return math.Abs(campaign.SalePrice-sale.salePrice) <= driftPriceTolerance &&
+		sameTime(campaign.CatalogStartsAt, sale.startsAt) &&
+		sameTime(campaign.CatalogEndsAt, sale.endsAt)
+}
+
+// outlasts reports whether the sale continues past a campaign that ends within the renewal
+// lead time, because the marketplace capped the campaign's duration
+func (sale *catalogSale) outlasts(campaign *domain.SaleCampaign, now time.Time) bool {
+	if campaign.EndsAt == nil || !campaign.EndsAt.Before(now.Add(SaleCampaignRenewalLead)) {
+		return false
+	}
+	return sale.endsAt == nil || sale.endsAt.After(*campaign.EndsAt)
+}
+
+// GetSaleCampaigns retrieves the sale campaigns of a connection
+func (s *ProductSyncService) GetSaleCampaigns(ctx context.Context, connectionID uuid.UUID, filter *domain.SaleCampaignFilter) ([]domain.SaleCampaign, int64, error) {
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return nil, 0, ErrConnectionNotFound
+	}
+	return s.saleCampaignRepo.GetByConnectionID(ctx, connectionID, filter)
+}
+
+// SyncSaleCampaign publishes the catalog sale of a mapped product as a marketplace discount.
+// The current campaign is kept while the sale price and window are unchanged, and ended when
+// they change or the sale is removed. A campaign the marketplace capped is renewed shortly
+// before it ends. Overridden prices are published as is, without campaign.
+//
+// Syncs of the same product on a connection run one at a time, so catalog events and the
+// renewer racing each other never both create a discount.
+func (s *ProductSyncService) SyncSaleCampaign(ctx context.Context, conn *domain.Connection, mapping *domain.ProductMapping, product *clients.Product) error {
+	if mapping.ExternalProductID == "" {
+		return nil
+	}
+	defer s.saleCampaignLocks.lock(conn.ID, mapping.InternalProductID)()
+
+	now := time.Now()
+	sale, err := s.catalogSaleFor(ctx, conn.ID, product, now)
+	if err != nil {
+		return err
+	}
+
+	latest, _ := s.saleCampaignRepo.GetLatest(ctx, conn.ID, mapping.InternalProductID)
+	current := latest
+	if current != nil && current.Status == domain.SaleCampaignActive && current.EndsAt != nil && !current.EndsAt.After(now) {
+		// The marketplace ended it at its end time
+		current.Status = domain.SaleCampaignEnded
+		if err := s.saleCampaignRepo.Update(ctx, current); err != nil {
+			return fmt.Errorf("failed to update sale campaign: %w", err)
+		}
+	}
+	if current != nil && current.Status != domain.SaleCampaignActive {
+		current = nil
+	}
+
+	// Renewed campaigns run until their successor starts
+	running, err := s.saleCampaignRepo.GetRenewedRunning(ctx, conn.ID, mapping.InternalProductID, now)
+	if err != nil {
+		return fmt.Errorf("failed to get renewed sale campaigns: %w", err)
+	}
+	if current == nil && len(running) > 0 {
+		// Its successor could not be created; renew it again
+		current = &running[0]
+		running = running[1:]
+	}
+
+	renew := false
+	if current != nil && sale != nil && sale.matches(current) {
+		if !sale.outlasts(current, now) {
+			return nil
+		}
+		renew = true
+	}
+	if current == nil && sale == nil {
+		return nil
+	}
+
+	// Decrypt access token
+	accessToken := conn.AccessToken
+	if s.encryptor != nil {
+		var err error
+		accessToken, err = s.encryptor.Decrypt(conn.AccessToken)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt token: %w", err)
+		}
+	}
+
+	editor, err := s.discountEditorFor(conn, accessToken)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case renew:
+		// The campaign keeps running on the marketplace until its successor starts
+		current.Status = domain.SaleCampaignRenewed
+		if err := s.saleCampaignRepo.Update(ctx, current); err != nil {
+			return fmt.Errorf("failed to update sale campaign: %w", err)
+		}
+	case current != nil:
+		for _, c := range append([]*domain.SaleCampaign{current}, campaignRefs(running)...) {
+			if err := s.endSaleCampaign(ctx, editor, conn, product, c, now); err != nil {
+				return err
+			}
+		}
+	}
+	if sale == nil {
+		return nil
+	}
+
+	// A failed attempt is retried in place rather than piling up failed campaigns
+	campaign := &domain.SaleCampaign{
+		ConnectionID:      conn.ID,
+		InternalProductID: mapping.InternalProductID,
+	}
+	if latest != nil && latest.Status == domain.SaleCampaignFailed {
+		campaign = latest
+	}
+	campaign.ExternalProductID = mapping.ExternalProductID
+	campaign.RegularPrice = sale.regularPrice
+	campaign.SalePrice = sale.salePrice
+	campaign.CatalogStartsAt = sale.startsAt
+	campaign.CatalogEndsAt = sale.endsAt
+
+	start := now
+	if sale.startsAt != nil && sale.startsAt.After(now) {
+		start = *sale.startsAt
+	}
+	if renew && current.EndsAt.After(start) {
+		start = *current.EndsAt
+	}
+	var end time.Time
+	if sale.endsAt != nil {
+		end = *sale.endsAt
+	}
+
+	resp, err := editor.create(ctx, &providers.DiscountRequest{
+		Name:              fmt.Sprintf("Sale %s", product.SKU),
+		ExternalProductID: mapping.ExternalProductID,
+		Prices:            s.listingSKUPrices(ctx, conn, mapping, sale.salePrice),
+		StartTime:         start,
+		EndTime:           end,
+	})
+	if err != nil {
+		campaign.Status = domain.SaleCampaignFailed
+		campaign.Error = err.Error()
+	} else {
+		campaign.ExternalDiscountID = resp.DiscountID
+		campaign.StartsAt = &resp.StartTime
+		campaign.EndsAt = &resp.EndTime
+		campaign.Status = domain.SaleCampaignActive
+		campaign.Error = ""
+	}
+
+	var saveErr error
+	if campaign.ID == uuid.Nil {
+		saveErr = s.saleCampaignRepo.Create(ctx, campaign)
+	} else {
+		saveErr = s.saleCampaignRepo.Update(ctx, campaign)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create sale campaign: %w", err)
+	}
+	if saveErr != nil {
+		return fmt.Errorf("failed to save sale campaign: %w", saveErr)
+	}
+
+	s.logger.Info("Created sale campaign",
+		zap.String("connection_id", conn.ID.String()),
+		zap.String("product_id", product.ID),
+		zap.String("discount_id", campaign.ExternalDiscountID),
+		zap.Float64("sale_price", campaign.SalePrice),
+	)
+	return nil
+}
+
+// endSaleCampaign ends a campaign on the marketplace and records it as ended
+func (s *ProductSyncService) endSaleCampaign(ctx context.Context, editor *discountEditor, conn *domain.Connection, product *clients.Product, campaign *domain.SaleCampaign, now time.Time) error {
+	started := campaign.StartsAt == nil || !campaign.StartsAt.After(now)
+	if err := editor.end(ctx, campaign.ExternalDiscountID, started); err != nil {
+		return fmt.Errorf("failed to end sale campaign: %w", err)
+	}
+	campaign.Status = domain.SaleCampaignEnded
+	if err := s.saleCampaignRepo.Update(ctx, campaign); err != nil {
+		return fmt.Errorf("failed to update sale campaign: %w", err)
+	}
+	s.logger.Info("Ended sale campaign",
+		zap.String("connection_id", conn.ID.String()),
+		zap.String("product_id", product.ID),
+		zap.String("discount_id", campaign.ExternalDiscountID),
+	)
+	return nil
+}
+
+// RenewSaleCampaigns renews the campaigns of all connections that end within the renewal
+// lead time while their catalog sale goes on. Campaigns whose sale changed meanwhile are
+// replaced or ended instead. Returns how many campaigns were synced.
+func (s *ProductSyncService) RenewSaleCampaigns(ctx context.Context) (int, error) {
+	campaigns, err := s.saleCampaignRepo.GetActiveEndingBefore(ctx, time.Now().Add(SaleCampaignRenewalLead))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get ending sale campaigns: %w", err)
+	}
+
+	synced := 0
+	for i := range campaigns {
+		campaign := &campaigns[i]
+		// The campaign covers the rest of the sale
+		if campaign.CatalogEndsAt != nil && !campaign.CatalogEndsAt.After(*campaign.EndsAt) {
+			continue
+		}
+
+		conn, err := s.connectionRepo.GetByID(ctx, campaign.ConnectionID)
+		if err != nil || !conn.IsActive {
+			continue
+		}
+		mapping, err := s.productMappingRepo.GetByConnectionAndInternalProduct(ctx, conn.ID, campaign.InternalProductID)
+		if err != nil {
+			continue
+		}
+		product, err := s.catalogClient.GetProduct(ctx, campaign.InternalProductID.String())
+		if err != nil {
+			s.logger.Warn("Failed to fetch product for sale campaign renewal",
+				zap.String("campaign_id", campaign.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		// The current catalog sale decides whether the campaign is renewed, replaced or ended
+		if err := s.SyncSaleCampaign(ctx, conn, mapping, product); err != nil {
+			s.logger.Error("Failed to renew sale campaign",
+				zap.String("campaign_id", campaign.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		synced++
+	}
+	return synced, nil
+}
+
+// campaignRefs returns pointers to the elements of a campaign slice
+func campaignRefs(campaigns []domain.SaleCampaign) []*domain.SaleCampaign {
+	refs := make([]*domain.SaleCampaign, len(campaigns))
+	for i := range campaigns {
+		refs[i] = &campaigns[i]
+	}
+	return refs
+}
+
+// catalogSaleFor returns the sale of a catalog product on a connection, or nil when the
+// product is not on sale, its sale is over or its price is overridden on the connection
+func (s *ProductSyncService) catalogSaleFor(ctx context.Context, connectionID uuid.UUID, product *clients.Product, now time.Time) (*catalogSale, error) {
+	if product.SalePrice == nil || (product.SaleEndsAt != nil && !product.SaleEndsAt.After(now)) {
+		return nil, nil
+	}
+	if content := s.listingContent.Resolve(ctx, connectionID, product); content.Price != nil {
+		return nil, nil
+	}
+
+	regular, err := s.pricingService.RegularQuote(ctx, connectionID, product)
+	if err != nil {
+		return nil, err
+	}
+	discounted, err := s.pricingService.Quote(ctx, connectionID, product)
+	if err != nil {
+		return nil, err
+	}
+	if discounted.Price >= regular.Price {
+		return nil, nil
+	}
+	return &catalogSale{
+		regularPrice: regular.Price,
+		salePrice:    discounted.Price,
+		startsAt:     product.SaleStartsAt,
+		endsAt:       product.SaleEndsAt,
+	}, nil
+}
+
+// listingSKUPrices gives every model / SKU of a listing the sale price. The models are taken
+// from the imported listing; without one a Shopee item is assumed to have no models and a
+// TikTok product the single SKU recorded at push.
+func (s *ProductSyncService) listingSKUPrices(ctx context.Context, conn *domain.Connection, mapping *domain.ProductMapping, price float64) map[string]float64 {
+	prices := make(map[string]float64)
+	if listing, err := s.importedProductRepo.GetByExternalProductID(ctx, conn.ID, mapping.ExternalProductID); err == nil {
+		for _, sku := range listing.GetSKUs() {
+			if sku.ExternalSKUID != "" {
+				prices[sku.ExternalSKUID] = price
+			}
+		}
+	}
+	if len(prices) > 0 {
+		return prices
+	}
+
+	if conn.Platform == "tiktok" && mapping.ExternalSKU != "" {
+		prices[mapping.ExternalSKU] = price
+	} else {
+		prices["0"] = price
+	}
+	return prices
+}
+
+// sameTime compares optional times
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SaleCampaignRenewerConfig holds configuration for the sale campaign renewer.
+type SaleCampaignRenewerConfig struct {
+	RenewInterval time.Duration // How often campaigns ending within the renewal lead time are renewed
+}
+
+// SaleCampaignRenewer periodically renews sale campaigns capped by the marketplace's maximum
+// discount duration, so open-ended and long catalog sales stay published.
+type SaleCampaignRenewer struct {
+	productSyncService *ProductSyncService
+	config             SaleCampaignRenewerConfig
+	logger             *zap.Logger
+
+	// Lifecycle management
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	running  bool
+	mu       sync.Mutex
+}
+
+// NewSaleCampaignRenewer creates a new sale campaign renewer.
+func NewSaleCampaignRenewer(
+	productSyncService *ProductSyncService,
+	cfg SaleCampaignRenewerConfig,
+	logger *zap.Logger,
+) *SaleCampaignRenewer {
+	// Set defaults
+	if cfg.RenewInterval == 0 {
+		cfg.RenewInterval = time.Hour
+	}
+
+	return &SaleCampaignRenewer{
+		productSyncService: productSyncService,
+		config:             cfg,
+		logger:             logger,
+		stopChan:           make(chan struct{}),
+	}
+}
+
+// Start begins the background renewal process.
+func (sr *SaleCampaignRenewer) Start(ctx context.Context) error {
+	sr.mu.Lock()
+	if sr.running {
+		sr.mu.Unlock()
+		return fmt.Errorf("sale campaign renewer already running")
+	}
+	sr.running = true
+	sr.mu.Unlock()
+
+	sr.wg.Add(1)
+	go sr.run(ctx)
+
+	sr.logger.Info("sale campaign renewer started",
+		zap.Duration("renew_interval", sr.config.RenewInterval),
+	)
+
+	return nil
+}
+
+// Stop gracefully stops the sale campaign renewer.
+func (sr *SaleCampaignRenewer) Stop() {
+	sr.mu.Lock()
+	if !sr.running {
+		sr.mu.Unlock()
+		return
+	}
+	sr.running = false
+	sr.mu.Unlock()
+
+	close(sr.stopChan)
+	sr.wg.Wait()
+
+	sr.logger.Info("sale campaign renewer stopped")
+}
+
+// run is the main background loop. The renewal lead time spans many intervals, so a
+// campaign is renewed well before it ends even if some runs fail.
+func (sr *SaleCampaignRenewer) run(ctx context.Context) {
+	defer sr.wg.Done()
+
+	ticker := time.NewTicker(sr.config.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sr.stopChan:
+			return
+		case <-ticker.C:
+			sr.renewCampaigns(ctx)
+		}
+	}
+}
+
+// renewCampaigns renews the campaigns ending soon.
+func (sr *SaleCampaignRenewer) renewCampaigns(ctx context.Context) {
+	synced, err := sr.productSyncService.RenewSaleCampaigns(ctx)
+	if err != nil {
+		sr.logger.Error("failed to renew sale campaigns", zap.Error(err))
+		return
+	}
+	if synced > 0 {
+		sr.logger.Info("sale campaigns renewed", zap.Int("campaigns", synced))
+	}
+}
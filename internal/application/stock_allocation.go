@@ -0,0 +1,412 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/clients"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/infrastructure/persistence"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+var ErrStockAllocationRuleNotFound = errors.New("stock allocation rule not found")
+
+// stockCategoryCacheTTL is how long product categories and the category tree are cached for
+// category overrides; stock events arrive far more often than categories change
+const stockCategoryCacheTTL = 10 * time.Minute
+
+// StockAllocationService evaluates per-connection stock allocation rules
+type StockAllocationService struct {
+	connectionRepo  *persistence.ConnectionRepository
+	ruleRepo        *persistence.StockAllocationRuleRepository
+	warehouseRepo   *persistence.WarehouseRepository
+	reservationRepo *persistence.StockReservationRepository
+	catalogClient   *clients.CatalogClient
+	logger          *zap.Logger
+
+	// How long stock reservations of orders hold stock back
+	reservationTTL time.Duration
+
+	// Catalog categories of products and the category tree, for category overrides
+	categoryMu      sync.Mutex
+	productCategory map[string]cachedCategory
+	categoryParents map[string]string
+	categoryTreeAt  time.Time
+}
+
+// cachedCategory is the catalog category of a product as of a time
+type cachedCategory struct {
+	categoryID string
+	fetchedAt  time.Time
+}
+
+// StockAllocationServiceConfig holds configuration for the stock allocation service.
+type StockAllocationServiceConfig struct {
+	// ReservationTTL is how long the stock of a paid marketplace order is held back from
+	// other channels when no stock event accounts for it
+	ReservationTTL time.Duration
+}
+
+// NewStockAllocationService creates a new StockAllocationService
+func NewStockAllocationService(
+	connectionRepo *persistence.ConnectionRepository,
+	ruleRepo *persistence.StockAllocationRuleRepository,
+	warehouseRepo *persistence.WarehouseRepository,
+	reservationRepo *persistence.StockReservationRepository,
+	catalogClient *clients.CatalogClient,
+	cfg StockAllocationServiceConfig,
+	logger *zap.Logger,
+) *StockAllocationService {
+	// Set defaults
+	if cfg.ReservationTTL <= 0 {
+		cfg.ReservationTTL = DefaultStockReservationTTL
+	}
+
+	return &StockAllocationService{
+		connectionRepo:  connectionRepo,
+		ruleRepo:        ruleRepo,
+		warehouseRepo:   warehouseRepo,
+		reservationRepo: reservationRepo,
+		catalogClient:   catalogClient,
+		logger:          logger,
+		reservationTTL:  cfg.ReservationTTL,
+		productCategory: make(map[string]cachedCategory),
+	}
+}
+
+// StockPreview shows the stock published to each active connection for a catalog product
+type StockPreview struct {
+	ProductID    string                `json:"product_id"`
+	Name         string                `json:"name"`
+	CategoryID   string                `json:"category_id"`
+	CategoryName string                `json:"category_name"`
+	Available    int                   `json:"available"` // Catalog stock quantity
+	Reserved     int                   `json:"reserved"`  // Held back by pending order reservations
+	Channels     []ChannelStockPreview `json:"channels"`
+}
+
+// ChannelStockPreview shows the stock published to one connection
+type ChannelStockPreview struct {
+	ConnectionID uuid.UUID                  `json:"connection_id"`
+	Platform     string                     `json:"platform"`
+	ShopName     string                     `json:"shop_name"`
+	Allocation   *domain.StockAllocation    `json:"allocation"`
+	Warehouses   []providers.WarehouseStock `json:"warehouses,omitempty"`
+}
+
+// stockRules holds the active allocation rules of a connection
+type stockRules struct {
+	defaultRule *domain.StockAllocationRule
+	byCategory  map[string]*domain.StockAllocationRule
+	parents     map[string]string // Parent of each catalog category, when there are category overrides
+}
+
+// forCategory returns the rule of a category or of its nearest ancestor, falling back to the
+// connection default
+func (r *stockRules) forCategory(categoryID string) *domain.StockAllocationRule {
+	if r == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for id := categoryID; id != "" && !seen[id]; id = r.parents[id] {
+		seen[id] = true
+		if rule, ok := r.byCategory[id]; ok {
+			return rule
+		}
+	}
+	return r.defaultRule
+}
+
+// GetRules retrieves stock allocation rules for a connection
+func (s *StockAllocationService) GetRules(ctx context.Context, connectionID uuid.UUID) ([]domain.StockAllocationRule, error) {
+	return s.ruleRepo.GetByConnectionID(ctx, connectionID)
+}
+
+// SaveRule creates or replaces the default rule (no category) or a category override
+func (s *StockAllocationService) SaveRule(ctx context.Context, connectionID uuid.UUID, req *domain.SaveStockAllocationRuleRequest) (*domain.StockAllocationRule, error) {
+	// Verify connection exists
+	if _, err := s.connectionRepo.GetByID(ctx, connectionID); err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	rule, err := s.ruleRepo.GetByConnectionAndCategory(ctx, connectionID, req.InternalCategoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock allocation rule: %w", err)
+	}
+	isNew := rule == nil
+	if isNew {
+		rule = &domain.StockAllocationRule{
+			ConnectionID:       connectionID,
+			InternalCategoryID: req.InternalCategoryID,
+		}
+	}
+
+	rule.BufferQuantity = req.BufferQuantity
+	rule.AllocationPercent = 100
+	if req.AllocationPercent != nil {
+		rule.AllocationPercent = *req.AllocationPercent
+	}
+	rule.MaxQuantity = req.MaxQuantity
+	rule.SoldOutThreshold = req.SoldOutThreshold
+	rule.IsActive = true
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if isNew {
+		if err := s.ruleRepo.Create(ctx, rule); err != nil {
+			return nil, fmt.Errorf("failed to create stock allocation rule: %w", err)
+		}
+		return rule, nil
+	}
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to update stock allocation rule: %w", err)
+	}
+	return rule, nil
+}
+
+// DeleteRule deletes a stock allocation rule
+func (s *StockAllocationService) DeleteRule(ctx context.Context, connectionID, ruleID uuid.UUID) error {
+	rule, err := s.ruleRepo.GetByID(ctx, ruleID)
+	if err != nil || rule.ConnectionID != connectionID {
+		return ErrStockAllocationRuleNotFound
+	}
+	return s.ruleRepo.Delete(ctx, ruleID)
+}
+
+// Allocate computes the stock of a product published to a connection: its stock less pending
+// reservations, limited by the product's allocation rule. The category is looked up in the
+// catalog when it is not known and the connection has category overrides. All unreserved stock
+// is published when no rule applies. Reservations or rules that cannot be loaded are an error,
+// so more stock than intended is never published.
+func (s *StockAllocationService) Allocate(ctx context.Context, connectionID, productID uuid.UUID, categoryID string, warehouses []providers.WarehouseStock, total int) ([]providers.WarehouseStock, int, error) {
+	reserved, err := s.reservedStock(ctx, productID)
+	if err != nil {
+		return nil, 0, err
+	}
+	warehouses, total = unreservedStock(warehouses, total, reserved)
+
+	rules, err := s.rulesFor(ctx, connectionID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if categoryID == "" {
+		categories, err := s.productCategories(ctx, rules, []string{productID.String()})
+		if err != nil {
+			return nil, 0, err
+		}
+		categoryID = categories[productID.String()]
+	}
+	warehouses, allocation := allocateStock(rules.forCategory(categoryID), warehouses, total)
+	return warehouses, allocation.Quantity, nil
+}
+
+// reservedStock returns the stock of a product held back by pending reservations
+func (s *StockAllocationService) reservedStock(ctx context.Context, productID uuid.UUID) (int, error) {
+	if s == nil || s.reservationRepo == nil {
+		return 0, nil
+	}
+	reserved, err := s.reservationRepo.GetPendingQuantity(ctx, productID, time.Now().Add(-s.reservationTTL))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get reserved stock: %w", err)
+	}
+	return reserved, nil
+}
+
+// reservedStocks returns the stock held back by pending reservations of products, keyed by
+// product ID. Products without reservations are left out.
+func (s *StockAllocationService) reservedStocks(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	if s == nil || s.reservationRepo == nil || len(productIDs) == 0 {
+		return nil, nil
+	}
+	reserved, err := s.reservationRepo.GetPendingQuantities(ctx, productIDs, time.Now().Add(-s.reservationTTL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reserved stock: %w", err)
+	}
+	return reserved, nil
+}
+
+// ruleFor returns the effective rule for a category, or nil if none applies
+func (s *StockAllocationService) ruleFor(ctx context.Context, connectionID uuid.UUID, categoryID string) (*domain.StockAllocationRule, error) {
+	rules, err := s.rulesFor(ctx, connectionID)
+	if err != nil {
+		return nil, err
+	}
+	return rules.forCategory(categoryID), nil
+}
+
+// rulesFor loads the active rules of a connection, or nil if it has none. Category overrides
+// come with the category tree, so they apply to subcategories.
+func (s *StockAllocationService) rulesFor(ctx context.Context, connectionID uuid.UUID) (*stockRules, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	rules, err := s.ruleRepo.GetActiveByConnectionID(ctx, connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stock allocation rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	set := &stockRules{byCategory: make(map[string]*domain.StockAllocationRule)}
+	for i := range rules {
+		if rules[i].InternalCategoryID == nil {
+			set.defaultRule = &rules[i]
+		} else {
+			set.byCategory[rules[i].InternalCategoryID.String()] = &rules[i]
+		}
+	}
+	if len(set.byCategory) > 0 {
+		if set.parents, err = s.categoryTree(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// categoryTree returns the parent of each catalog category, cached for stockCategoryCacheTTL
+func (s *StockAllocationService) categoryTree(ctx context.Context) (map[string]string, error) {
+	s.categoryMu.Lock()
+	defer s.categoryMu.Unlock()
+
+	if s.categoryParents != nil && time.Since(s.categoryTreeAt) < stockCategoryCacheTTL {
+		return s.categoryParents, nil
+	}
+
+	categories, err := s.catalogClient.GetCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch categories from catalog: %w", err)
+	}
+	parents := make(map[string]string, len(categories))
+	for _, cat := range categories {
+		parents[cat.ID] = cat.ParentID
+	}
+	s.categoryParents = parents
+	s.categoryTreeAt = time.Now()
+	return parents, nil
+}
+
+// productCategories returns the categories of catalog products, keyed by product ID.
+// Categories are cached for stockCategoryCacheTTL; nothing is fetched when the rules have no
+// category overrides.
+func (s *StockAllocationService) productCategories(ctx context.Context, rules *stockRules, productIDs []string) (map[string]string, error) {
+	if rules == nil || len(rules.byCategory) == 0 || len(productIDs) == 0 {
+		return nil, nil
+	}
+
+	categories := make(map[string]string, len(productIDs))
+	var missing []string
+	s.categoryMu.Lock()
+	for _, id := range productIDs {
+		if cached, ok := s.productCategory[id]; ok && time.Since(cached.fetchedAt) < stockCategoryCacheTTL {
+			categories[id] = cached.categoryID
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	s.categoryMu.Unlock()
+	if len(missing) == 0 {
+		return categories, nil
+	}
+
+	products, err := s.catalogClient.GetProducts(ctx, missing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product categories from catalog: %w", err)
+	}
+
+	now := time.Now()
+	s.categoryMu.Lock()
+	defer s.categoryMu.Unlock()
+	for _, p := range products {
+		categories[p.ID] = p.CategoryID
+		s.productCategory[p.ID] = cachedCategory{categoryID: p.CategoryID, fetchedAt: now}
+	}
+	return categories, nil
+}
+
+// PreviewStock shows the stock each active connection would receive for a catalog product
+func (s *StockAllocationService) PreviewStock(ctx context.Context, productID string) (*StockPreview, error) {
+	product, err := s.catalogClient.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product from catalog: %w", err)
+	}
+
+	connections, err := s.connectionRepo.GetActiveConnections(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connections: %w", err)
+	}
+
+	preview := &StockPreview{
+		ProductID:    product.ID,
+		Name:         product.Name,
+		CategoryID:   product.CategoryID,
+		CategoryName: product.CategoryName,
+		Available:    product.StockQuantity,
+		Channels:     make([]ChannelStockPreview, 0, len(connections)),
+	}
+
+	id, _ := uuid.Parse(product.ID)
+	if preview.Reserved, err = s.reservedStock(ctx, id); err != nil {
+		return nil, err
+	}
+	for i := range connections {
+		conn := &connections[i]
+		rule, err := s.ruleFor(ctx, conn.ID, product.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		warehouses, total := warehouseStock(ctx, s.warehouseRepo, conn, id, product.StockQuantity)
+		warehouses, total = unreservedStock(warehouses, total, preview.Reserved)
+		warehouses, allocation := allocateStock(rule, warehouses, total)
+		preview.Channels = append(preview.Channels, ChannelStockPreview{
+			ConnectionID: conn.ID,
+			Platform:     conn.Platform,
+			ShopName:     conn.ShopName,
+			Allocation:   allocation,
+			Warehouses:   warehouses,
+		})
+	}
+
+	return preview, nil
+}
+
+// allocateStock applies a rule to the stock of a listing and trims its warehouse split to
+// the allocated quantity, filling the warehouses in order so the default one comes first
+func allocateStock(rule *domain.StockAllocationRule, warehouses []providers.WarehouseStock, total int) ([]providers.WarehouseStock, *domain.StockAllocation) {
+	allocation := rule.Allocate(total)
+	if allocation.Quantity >= total {
+		return warehouses, allocation
+	}
+	return trimWarehouseStock(warehouses, allocation.Quantity), allocation
+}
+
+// unreservedStock takes reserved stock off the stock of a listing and its warehouse split
+func unreservedStock(warehouses []providers.WarehouseStock, total, reserved int) ([]providers.WarehouseStock, int) {
+	if reserved <= 0 {
+		return warehouses, total
+	}
+	total = max(total-reserved, 0)
+	return trimWarehouseStock(warehouses, total), total
+}
+
+// trimWarehouseStock limits warehouse stock to a total, taking it from warehouses in order
+func trimWarehouseStock(warehouses []providers.WarehouseStock, total int) []providers.WarehouseStock {
+	remaining := total
+	trimmed := make([]providers.WarehouseStock, 0, len(warehouses))
+	for _, w := range warehouses {
+		w.Quantity = min(max(w.Quantity, 0), remaining)
+		remaining -= w.Quantity
+		trimmed = append(trimmed, w)
+	}
+	return trimmed
+}
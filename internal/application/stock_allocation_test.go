@@ -0,0 +1,92 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+func TestTrimWarehouseStock(t *testing.T) {
+	warehouses := []providers.WarehouseStock{
+		{WarehouseID: "WH-A", Quantity: 4},
+		{WarehouseID: "WH-B", Quantity: 6},
+	}
+
+	tests := []struct {
+		name       string
+		warehouses []providers.WarehouseStock
+		total      int
+		want       []providers.WarehouseStock
+	}{
+		{
+			name:       "total covers all stock",
+			warehouses: warehouses,
+			total:      10,
+			want:       warehouses,
+		},
+		{
+			name:       "total above the stock does not add any",
+			warehouses: warehouses,
+			total:      15,
+			want:       warehouses,
+		},
+		{
+			name:       "stock is taken from the first warehouses",
+			warehouses: warehouses,
+			total:      7,
+			want: []providers.WarehouseStock{
+				{WarehouseID: "WH-A", Quantity: 4},
+				{WarehouseID: "WH-B", Quantity: 3},
+			},
+		},
+		{
+			name:       "later warehouses are kept at zero",
+			warehouses: warehouses,
+			total:      3,
+			want: []providers.WarehouseStock{
+				{WarehouseID: "WH-A", Quantity: 3},
+				{WarehouseID: "WH-B", Quantity: 0},
+			},
+		},
+		{
+			name:       "zero total",
+			warehouses: warehouses,
+			total:      0,
+			want: []providers.WarehouseStock{
+				{WarehouseID: "WH-A", Quantity: 0},
+				{WarehouseID: "WH-B", Quantity: 0},
+			},
+		},
+		{
+			name: "negative warehouse stock counts as zero",
+			warehouses: []providers.WarehouseStock{
+				{WarehouseID: "WH-A", Quantity: -2},
+				{WarehouseID: "WH-B", Quantity: 6},
+			},
+			total: 5,
+			want: []providers.WarehouseStock{
+				{WarehouseID: "WH-A", Quantity: 0},
+				{WarehouseID: "WH-B", Quantity: 5},
+			},
+		},
+		{
+			name:  "no warehouses",
+			total: 5,
+			want:  []providers.WarehouseStock{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := append([]providers.WarehouseStock(nil), tt.warehouses...)
+			got := trimWarehouseStock(tt.warehouses, tt.total)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("trimWarehouseStock() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.warehouses, original) {
+				t.Errorf("trimWarehouseStock() modified its input")
+			}
+		})
+	}
+}
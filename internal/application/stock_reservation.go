@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/events"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+// DefaultStockReservationTTL is how long a reservation holds stock back when no stock event
+// accounts for its order
+const DefaultStockReservationTTL = 30 * time.Minute
+
+// reservesStock reports whether an order in the given status is paid but not yet fulfilled,
+// so its stock is sold but may not be reflected by the inventory service yet
+func reservesStock(status string) bool {
+	switch status {
+	case "pending_shipment", "processing":
+		return true
+	default:
+		return false
+	}
+}
+
+// ReserveOrderStock holds back the stock of a paid marketplace order until the inventory
+// service reports it. The reservation is published for the inventory service, and the reduced
+// stock is pushed right away to every other connection listing the same products, so they
+// cannot sell stock the order already took. Reserving an order again is a no-op.
+func (s *InventorySyncService) ReserveOrderStock(ctx context.Context, conn *domain.Connection, order *providers.ExternalOrder) {
+	if s.reservationRepo == nil || len(order.Items) == 0 {
+		return
+	}
+
+	externalIDs := make([]string, 0, len(order.Items))
+	for _, item := range order.Items {
+		externalIDs = append(externalIDs, item.ExternalProductID)
+	}
+	mappings, err := s.productMappingRepo.GetByConnectionAndExternalProducts(ctx, conn.ID, externalIDs)
+	if err != nil {
+		s.logger.Error("Failed to get mappings for order", zap.String("order_id", order.ExternalOrderID), zap.Error(err))
+		return
+	}
+	byExternalID := make(map[string]*domain.ProductMapping, len(mappings))
+	for i := range mappings {
+		byExternalID[mappings[i].ExternalProductID] = &mappings[i]
+	}
+
+	// One reservation per product, summing lines of the same product
+	var reservations []*domain.StockReservation
+	byProduct := make(map[uuid.UUID]*domain.StockReservation)
+	skus := make(map[uuid.UUID]string)
+	for _, item := range order.Items {
+		mapping, ok := byExternalID[item.ExternalProductID]
+		if !ok || item.Quantity <= 0 {
+			continue
+		}
+		if r, ok := byProduct[mapping.InternalProductID]; ok {
+			r.Quantity += item.Quantity
+			continue
+		}
+		r := &domain.StockReservation{
+			ConnectionID:      conn.ID,
+			ExternalOrderID:   order.ExternalOrderID,
+			InternalProductID: mapping.InternalProductID,
+			ProductMappingID:  mapping.ID,
+			Quantity:          item.Quantity,
+			Status:            domain.StockReservationPending,
+		}
+		byProduct[mapping.InternalProductID] = r
+		skus[mapping.InternalProductID] = item.ExternalSKU
+		reservations = append(reservations, r)
+	}
+
+	var reserved []*domain.StockReservation
+	for _, r := range reservations {
+		created, err := s.reservationRepo.CreateIfAbsent(ctx, r)
+		if err != nil {
+			s.logger.Error("Failed to reserve stock",
+				zap.String("order_id", order.ExternalOrderID),
+				zap.String("product_id", r.InternalProductID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		if created {
+			reserved = append(reserved, r)
+		}
+	}
+	if len(reserved) == 0 {
+		return
+	}
+
+	s.publishStockReserved(conn, order, reserved, skus)
+
+	productIDs := make([]uuid.UUID, len(reserved))
+	for i, r := range reserved {
+		productIDs[i] = r.InternalProductID
+	}
+	s.pushReservedStock(context.WithoutCancel(ctx), conn.ID, productIDs)
+
+	s.logger.Info("Order stock reserved",
+		zap.String("platform", conn.Platform),
+		zap.String("order_id", order.ExternalOrderID),
+		zap.Int("products", len(reserved)),
+	)
+}
+
+// ReleaseOrderStock releases the reservations of a cancelled order and pushes the restored
+// stock to the other connections listing its products
+func (s *InventorySyncService) ReleaseOrderStock(ctx context.Context, conn *domain.Connection, externalOrderID string) {
+	if s.reservationRepo == nil {
+		return
+	}
+
+	released, err := s.reservationRepo.ReleaseByOrder(ctx, conn.ID, externalOrderID, domain.ReservationReleaseCancelled)
+	if err != nil {
+		s.logger.Error("Failed to release order stock", zap.String("order_id", externalOrderID), zap.Error(err))
+		return
+	}
+	if len(released) == 0 {
+		return
+	}
+
+	productIDs := make([]uuid.UUID, len(released))
+	for i, r := range released {
+		productIDs[i] = r.InternalProductID
+	}
+	s.pushReservedStock(context.WithoutCancel(ctx), conn.ID, productIDs)
+}
+
+// releaseReservations releases the reservations a stock event accounts for: those it echoes,
+// or for a sale, the oldest reservations of the product made before it that the sold quantity
+// covers. Other reservations are left to expire after the reservation TTL.
+func (s *InventorySyncService) releaseReservations(ctx context.Context, event *events.StockChangedEvent) {
+	if s.reservationRepo == nil {
+		return
+	}
+
+	var (
+		released int64
+		err      error
+	)
+	switch {
+	case len(event.ReservationIDs) > 0:
+		released, err = s.reservationRepo.ReleaseByIDs(ctx, event.ProductID, event.ReservationIDs, domain.ReservationReleaseStockEvent)
+	case event.Reason == events.StockReasonSale && event.OldQuantity > event.NewQuantity:
+		before := event.Timestamp
+		if before.IsZero() {
+			before = time.Now()
+		}
+		sold := event.OldQuantity - event.NewQuantity
+		released, err = s.reservationRepo.ReleaseCovered(ctx, event.ProductID, before, sold, domain.ReservationReleaseStockEvent)
+	default:
+		return
+	}
+	if err != nil {
+		s.logger.Warn("Failed to release stock reservations",
+			zap.String("product_id", event.ProductID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+	if released > 0 {
+		s.logger.Debug("Stock reservations released",
+			zap.String("product_id", event.ProductID.String()),
+			zap.Int64("reservations", released),
+		)
+	}
+}
+
+// pushReservedStock refreshes the stock of products from the inventory service and pushes it,
+// less reservations, to every connection listing them other than the one the order came from
+func (s *InventorySyncService) pushReservedStock(ctx context.Context, originID uuid.UUID, productIDs []uuid.UUID) {
+	if s.inventoryClient == nil {
+		return
+	}
+
+	ids := make([]string, len(productIDs))
+	for i, id := range productIDs {
+		ids[i] = id.String()
+	}
+	levels, err := s.inventoryClient.GetStockLevels(ctx, ids)
+	if err != nil {
+		s.logger.Error("Failed to get stock levels", zap.Error(err))
+		return
+	}
+	productLevels, _, _ := groupStockLevels(levels)
+	s.recordStockLevels(ctx, productLevels)
+
+	for _, productID := range productIDs {
+		productStock, ok := productLevels[productID.String()]
+		if !ok {
+			s.logger.Debug("No stock known for reserved product", zap.String("product_id", productID.String()))
+			continue
+		}
+		total := sumStockLevels(productStock)
+
+		mappings, err := s.productMappingRepo.GetByInternalProductID(ctx, productID)
+		if err != nil {
+			s.logger.Error("Failed to get mappings", zap.Error(err))
+			continue
+		}
+		for _, mapping := range mappings {
+			if mapping.ConnectionID == originID {
+				continue
+			}
+			go s.syncInventoryForMapping(ctx, &mapping, total, domain.InventorySyncSourceReservation)
+		}
+	}
+}
+
+func (s *InventorySyncService) publishStockReserved(conn *domain.Connection, order *providers.ExternalOrder, reserved []*domain.StockReservation, skus map[uuid.UUID]string) {
+	if s.publisher == nil {
+		return
+	}
+	items := make([]events.StockReservationItem, len(reserved))
+	for i, r := range reserved {
+		items[i] = events.StockReservationItem{
+			ReservationID: r.ID,
+			ProductID:     r.InternalProductID,
+			SKU:           skus[r.InternalProductID],
+			Quantity:      r.Quantity,
+		}
+	}
+	if err := s.publisher.PublishStockReserved(&events.StockReservedEvent{
+		ConnectionID:    conn.ID,
+		Platform:        conn.Platform,
+		ExternalOrderID: order.ExternalOrderID,
+		Items:           items,
+		Timestamp:       time.Now(),
+	}); err != nil {
+		s.logger.Warn("Failed to publish stock reservation", zap.String("order_id", order.ExternalOrderID), zap.Error(err))
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/clients"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+)
+
+var (
+	ErrUnknownImportedModel  = errors.New("imported product has no such model")
+	ErrUnknownCatalogVariant = errors.New("catalog product has no such variant")
+	ErrDuplicateVariantPair  = errors.New("a model or variant is paired more than once")
+	ErrVariantPairsRequired  = errors.New("listing has several models, pair them with catalog variants")
+)
+
+// pairImportedVariants builds the variant mappings between the models of an imported product
+// and the variants of a catalog product. Explicit pairs are validated; without them models
+// are paired automatically by SKU, then by identical options.
+func (s *ProductSyncService) pairImportedVariants(ctx context.Context, imported *domain.ImportedProduct, internalProductID uuid.UUID, pairs []domain.VariantMatch) ([]domain.VariantMapping, error) {
+	skus := imported.GetSKUs()
+	if len(skus) == 0 && len(pairs) == 0 {
+		return nil, nil
+	}
+
+	product, err := s.catalogClient.GetProduct(ctx, internalProductID.String())
+	if err != nil {
+		if len(pairs) > 0 {
+			return nil, fmt.Errorf("failed to fetch product from catalog: %w", err)
+		}
+		s.logger.Warn("Failed to fetch product, variants will not be paired",
+			zap.String("product_id", internalProductID.String()),
+			zap.Error(err),
+		)
+		return nil, nil
+	}
+
+	if len(pairs) > 0 {
+		return explicitVariantMappings(skus, product, pairs)
+	}
+	return autoVariantMappings(skus, product), nil
+}
+
+// explicitVariantMappings validates admin-provided model/variant pairs
+func explicitVariantMappings(skus []domain.ImportedSKU, product *clients.Product, pairs []domain.VariantMatch) ([]domain.VariantMapping, error) {
+	skuByID := make(map[string]*domain.ImportedSKU, len(skus))
+	for i := range skus {
+		skuByID[skus[i].ExternalSKUID] = &skus[i]
+	}
+	variantIDs := make(map[uuid.UUID]bool, len(product.Variants))
+	for _, v := range product.Variants {
+		if id, err := uuid.Parse(v.ID); err == nil {
+			variantIDs[id] = true
+		}
+	}
+
+	usedSKUs := make(map[string]bool, len(pairs))
+	usedVariants := make(map[uuid.UUID]bool, len(pairs))
+	mappings := make([]domain.VariantMapping, 0, len(pairs))
+	for _, pair := range pairs {
+		sku, ok := skuByID[pair.ExternalSKUID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownImportedModel, pair.ExternalSKUID)
+		}
+		if !variantIDs[pair.InternalVariantID] {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownCatalogVariant, pair.InternalVariantID)
+		}
+		if usedSKUs[pair.ExternalSKUID] || usedVariants[pair.InternalVariantID] {
+			return nil, ErrDuplicateVariantPair
+		}
+		usedSKUs[pair.ExternalSKUID] = true
+		usedVariants[pair.InternalVariantID] = true
+
+		mappings = append(mappings, domain.VariantMapping{
+			InternalVariantID: pair.InternalVariantID,
+			ExternalVariantID: sku.ExternalSKUID,
+			ExternalSKU:       sku.SellerSKU,
+		})
+	}
+	return mappings, nil
+}
+
+// autoVariantMappings pairs each model with the catalog variant of the same SKU or, failing
+// that, the variant with exactly the same options. Ambiguous models are left unpaired.
+func autoVariantMappings(skus []domain.ImportedSKU, product *clients.Product) []domain.VariantMapping {
+	type candidate struct {
+		id      uuid.UUID
+		sku     string
+		options string
+	}
+	candidates := make([]candidate, 0, len(product.Variants))
+	for _, v := range product.Variants {
+		id, err := uuid.Parse(v.ID)
+		if err != nil {
+			continue
+		}
+		options := make(map[string]string, len(v.Options))
+		for _, opt := range v.Options {
+			options[opt.Name] = opt.Value
+		}
+		candidates = append(candidates, candidate{id: id, sku: normalizeSKU(v.SKU), options: optionKey(options)})
+	}
+
+	used := make(map[uuid.UUID]bool)
+	var mappings []domain.VariantMapping
+	pair := func(sku *domain.ImportedSKU, match func(c *candidate) bool) bool {
+		found := -1
+		for i := range candidates {
+			if used[candidates[i].id] || !match(&candidates[i]) {
+				continue
+			}
+			if found >= 0 {
+				return false
+			}
+			found = i
+		}
+		if found < 0 {
+			return false
+		}
+		used[candidates[found].id] = true
+		mappings = append(mappings, domain.VariantMapping{
+			InternalVariantID: candidates[found].id,
+			ExternalVariantID: sku.ExternalSKUID,
+			ExternalSKU:       sku.SellerSKU,
+		})
+		return true
+	}
+
+	// SKU matches are paired before option matches so they cannot be taken by a weaker match
+	var unpaired []*domain.ImportedSKU
+	for i := range skus {
+		sku := normalizeSKU(skus[i].SellerSKU)
+		if sku == "" || !pair(&skus[i], func(c *candidate) bool { return c.sku == sku }) {
+			unpaired = append(unpaired, &skus[i])
+		}
+	}
+	for _, sku := range unpaired {
+		key := optionKey(sku.Attributes)
+		if key != "" {
+			pair(sku, func(c *candidate) bool { return c.options == key })
+		}
+	}
+	return mappings
+}
+
+// optionKey returns a case-insensitive, order-independent key of a set of variant options
+func optionKey(options map[string]string) string {
+	parts := make([]string, 0, len(options))
+	for name, value := range options {
+		parts = append(parts, strings.ToLower(strings.TrimSpace(name))+"="+strings.ToLower(strings.TrimSpace(value)))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ";")
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/infrastructure/persistence"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+var ErrInvalidWarehouseMapping = errors.New("invalid warehouse mapping")
+
+// GetWarehouseMappings retrieves the warehouse mappings of a connection and its policy for unmapped warehouses
+func (s *InventorySyncService) GetWarehouseMappings(ctx context.Context, connectionID uuid.UUID) (*domain.WarehouseMappingsResponse, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	mappings, err := s.warehouseRepo.GetMappings(ctx, connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouse mappings: %w", err)
+	}
+	if mappings == nil {
+		mappings = []domain.WarehouseMapping{}
+	}
+
+	return &domain.WarehouseMappingsResponse{
+		UnmappedPolicy: conn.GetSettings().UnmappedWarehouses,
+		Mappings:       mappings,
+	}, nil
+}
+
+// SaveWarehouseMappings replaces the warehouse mappings of a connection and its policy for
+// unmapped warehouses. Sending no mappings turns per-warehouse stock off.
+func (s *InventorySyncService) SaveWarehouseMappings(ctx context.Context, connectionID uuid.UUID, req *domain.SaveWarehouseMappingsRequest) (*domain.WarehouseMappingsResponse, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, connectionID)
+	if err != nil {
+		return nil, ErrConnectionNotFound
+	}
+
+	policy := req.UnmappedPolicy
+	if policy == "" {
+		policy = domain.UnmappedWarehousesDefault
+	}
+
+	mappings := make([]domain.WarehouseMapping, 0, len(req.Mappings))
+	seen := make(map[string]bool, len(req.Mappings))
+	defaults := 0
+	for _, m := range req.Mappings {
+		if seen[m.InternalWarehouseID] {
+			return nil, fmt.Errorf("%w: warehouse %s is mapped twice", ErrInvalidWarehouseMapping, m.InternalWarehouseID)
+		}
+		seen[m.InternalWarehouseID] = true
+		if m.IsDefault {
+			defaults++
+		}
+		mappings = append(mappings, domain.WarehouseMapping{
+			ConnectionID:        connectionID,
+			InternalWarehouseID: m.InternalWarehouseID,
+			ExternalWarehouseID: m.ExternalWarehouseID,
+			IsDefault:           m.IsDefault,
+		})
+	}
+	if defaults > 1 {
+		return nil, fmt.Errorf("%w: only one warehouse can be the default", ErrInvalidWarehouseMapping)
+	}
+	if len(mappings) > 0 && defaults == 0 && policy == domain.UnmappedWarehousesDefault {
+		return nil, fmt.Errorf("%w: a default warehouse is required to sum unmapped warehouses into", ErrInvalidWarehouseMapping)
+	}
+
+	if err := s.warehouseRepo.ReplaceMappings(ctx, connectionID, mappings); err != nil {
+		return nil, fmt.Errorf("failed to save warehouse mappings: %w", err)
+	}
+
+	settings := conn.GetSettings()
+	settings.UnmappedWarehouses = policy
+	if err := s.connectionRepo.UpdateSettings(ctx, connectionID, settings); err != nil {
+		return nil, fmt.Errorf("failed to save connection settings: %w", err)
+	}
+
+	s.logger.Info("Warehouse mappings saved",
+		zap.String("connection_id", connectionID.String()),
+		zap.Int("mappings", len(mappings)),
+		zap.String("unmapped_policy", policy),
+	)
+
+	return &domain.WarehouseMappingsResponse{UnmappedPolicy: policy, Mappings: mappings}, nil
+}
+
+// warehouseStock splits the stock of a product over the marketplace warehouses of a
+// connection. Returns the stock per warehouse, nil when the connection has no warehouse
+// mappings, and the total to publish. The catalog total is used when no warehouse stock
+// of the product is known yet.
+func warehouseStock(ctx context.Context, repo *persistence.WarehouseRepository, conn *domain.Connection, productID uuid.UUID, total int) ([]providers.WarehouseStock, int) {
+	if repo == nil {
+		return nil, total
+	}
+	mappings, err := repo.GetMappings(ctx, conn.ID)
+	if err != nil {
+		return nil, total
+	}
+	levels, err := repo.GetStockLevels(ctx, productID)
+	if err != nil {
+		return nil, total
+	}
+	return allocateWarehouseStock(mappings, conn.GetSettings().UnmappedWarehouses, levels, total)
+}
+
+// allocateWarehouseStock maps warehouse stock levels onto marketplace warehouses. Stock of
+// unmapped warehouses goes to the default warehouse or is left out, depending on the policy.
+// Without known levels the whole total goes to the default warehouse, if there is one.
+// Levels only split stock over mapped warehouses; without mappings the total is published.
+func allocateWarehouseStock(mappings []domain.WarehouseMapping, policy string, levels []domain.WarehouseStockLevel, total int) ([]providers.WarehouseStock, int) {
+	if len(mappings) == 0 {
+		return nil, total
+	}
+
+	external := make(map[string]string, len(mappings))
+	defaultWarehouse := ""
+	for _, m := range mappings {
+		external[m.InternalWarehouseID] = m.ExternalWarehouseID
+		if m.IsDefault {
+			defaultWarehouse = m.ExternalWarehouseID
+		}
+	}
+
+	if len(levels) == 0 {
+		// Nothing is known per warehouse yet: publish the total as before
+		if defaultWarehouse == "" {
+			return nil, total
+		}
+		return []providers.WarehouseStock{{WarehouseID: defaultWarehouse, Quantity: total}}, total
+	}
+
+	quantities := make(map[string]int)
+	for _, level := range levels {
+		if id, ok := external[level.WarehouseID]; ok {
+			quantities[id] += level.Quantity
+		} else if policy == domain.UnmappedWarehousesDefault && defaultWarehouse != "" {
+			quantities[defaultWarehouse] += level.Quantity
+		}
+	}
+
+	// Keep the order of the mappings, the default warehouse first
+	stock := make([]providers.WarehouseStock, 0, len(quantities))
+	sum := 0
+	for _, m := range mappings {
+		quantity, ok := quantities[m.ExternalWarehouseID]
+		if !ok {
+			continue
+		}
+		delete(quantities, m.ExternalWarehouseID)
+		stock = append(stock, providers.WarehouseStock{WarehouseID: m.ExternalWarehouseID, Quantity: quantity})
+		sum += quantity
+	}
+	return stock, sum
+}
+
+// sumStockLevels returns the total stock of warehouse stock levels
+func sumStockLevels(levels []domain.WarehouseStockLevel) int {
+	total := 0
+	for _, level := range levels {
+		total += level.Quantity
+	}
+	return total
+}
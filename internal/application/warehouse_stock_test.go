@@ -0,0 +1,141 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+func TestAllocateWarehouseStock(t *testing.T) {
+	mappings := []domain.WarehouseMapping{
+		{InternalWarehouseID: "jkt", ExternalWarehouseID: "WH-A", IsDefault: true},
+		{InternalWarehouseID: "sby", ExternalWarehouseID: "WH-B"},
+		{InternalWarehouseID: "sby-2", ExternalWarehouseID: "WH-B"},
+	}
+	withoutDefault := []domain.WarehouseMapping{
+		{InternalWarehouseID: "jkt", ExternalWarehouseID: "WH-A"},
+		{InternalWarehouseID: "sby", ExternalWarehouseID: "WH-B"},
+	}
+	level := func(warehouseID string, quantity int) domain.WarehouseStockLevel {
+		return domain.WarehouseStockLevel{WarehouseID: warehouseID, Quantity: quantity}
+	}
+
+	tests := []struct {
+		name      string
+		mappings  []domain.WarehouseMapping
+		policy    string
+		levels    []domain.WarehouseStockLevel
+		total     int
+		wantStock []providers.WarehouseStock
+		wantTotal int
+	}{
+		{
+			name:      "no mappings publishes the total",
+			levels:    []domain.WarehouseStockLevel{level("jkt", 3)},
+			policy:    domain.UnmappedWarehousesDefault,
+			total:     10,
+			wantTotal: 10,
+		},
+		{
+			name:      "no levels puts the total in the default warehouse",
+			mappings:  mappings,
+			policy:    domain.UnmappedWarehousesDefault,
+			total:     10,
+			wantStock: []providers.WarehouseStock{{WarehouseID: "WH-A", Quantity: 10}},
+			wantTotal: 10,
+		},
+		{
+			name:      "no levels and no default warehouse publishes the total",
+			mappings:  withoutDefault,
+			policy:    domain.UnmappedWarehousesIgnore,
+			total:     10,
+			wantTotal: 10,
+		},
+		{
+			name:     "levels are split over mapped warehouses in mapping order",
+			mappings: mappings,
+			policy:   domain.UnmappedWarehousesDefault,
+			levels:   []domain.WarehouseStockLevel{level("sby", 4), level("jkt", 2)},
+			total:    99,
+			wantStock: []providers.WarehouseStock{
+				{WarehouseID: "WH-A", Quantity: 2},
+				{WarehouseID: "WH-B", Quantity: 4},
+			},
+			wantTotal: 6,
+		},
+		{
+			name:      "internal warehouses mapped to the same warehouse are summed",
+			mappings:  mappings,
+			policy:    domain.UnmappedWarehousesDefault,
+			levels:    []domain.WarehouseStockLevel{level("sby", 4), level("sby-2", 5)},
+			total:     9,
+			wantStock: []providers.WarehouseStock{{WarehouseID: "WH-B", Quantity: 9}},
+			wantTotal: 9,
+		},
+		{
+			name:     "unmapped warehouses go to the default warehouse",
+			mappings: mappings,
+			policy:   domain.UnmappedWarehousesDefault,
+			levels:   []domain.WarehouseStockLevel{level("sby", 4), level("bdg", 7), level("mdn", 1)},
+			total:    12,
+			wantStock: []providers.WarehouseStock{
+				{WarehouseID: "WH-A", Quantity: 8},
+				{WarehouseID: "WH-B", Quantity: 4},
+			},
+			wantTotal: 12,
+		},
+		{
+			name:      "unmapped warehouses are left out when ignored",
+			mappings:  mappings,
+			policy:    domain.UnmappedWarehousesIgnore,
+			levels:    []domain.WarehouseStockLevel{level("sby", 4), level("bdg", 7)},
+			total:     11,
+			wantStock: []providers.WarehouseStock{{WarehouseID: "WH-B", Quantity: 4}},
+			wantTotal: 4,
+		},
+		{
+			name:      "unmapped warehouses are left out without a default warehouse",
+			mappings:  withoutDefault,
+			policy:    domain.UnmappedWarehousesDefault,
+			levels:    []domain.WarehouseStockLevel{level("jkt", 1), level("bdg", 7)},
+			total:     8,
+			wantStock: []providers.WarehouseStock{{WarehouseID: "WH-A", Quantity: 1}},
+			wantTotal: 1,
+		},
+		{
+			name:      "only unmapped stock when ignored publishes nothing",
+			mappings:  mappings,
+			policy:    domain.UnmappedWarehousesIgnore,
+			levels:    []domain.WarehouseStockLevel{level("bdg", 7)},
+			total:     7,
+			wantStock: []providers.WarehouseStock{},
+			wantTotal: 0,
+		},
+		{
+			name:     "empty warehouses are kept at zero",
+			mappings: mappings,
+			policy:   domain.UnmappedWarehousesDefault,
+			levels:   []domain.WarehouseStockLevel{level("jkt", 0), level("sby", 3)},
+			total:    3,
+			wantStock: []providers.WarehouseStock{
+				{WarehouseID: "WH-A", Quantity: 0},
+				{WarehouseID: "WH-B", Quantity: 3},
+			},
+			wantTotal: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stock, total := allocateWarehouseStock(tt.mappings, tt.policy, tt.levels, tt.total)
+			if !reflect.DeepEqual(stock, tt.wantStock) {
+				t.Errorf("allocateWarehouseStock() stock = %+v, want %+v", stock, tt.wantStock)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("allocateWarehouseStock() total = %d, want %d", total, tt.wantTotal)
+			}
+		})
+	}
+}
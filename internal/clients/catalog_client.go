@@ -1,8 +1,10 @@
 package clients
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,6 +13,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// ErrProductNotFound is returned when service-catalog has no product with the given ID
+var ErrProductNotFound = errors.New("product not found in catalog")
+
 // CatalogClient handles communication with service-catalog
 type CatalogClient struct {
 	baseURL    string
@@ -37,6 +42,8 @@ type Product struct {
 	Description   string            `json:"description"`
 	BasePrice     float64           `json:"base_price"`
 	SalePrice     *float64          `json:"sale_price"`
+	SaleStartsAt  *time.Time        `json:"sale_starts_at"` // Sale window, open-ended when unset
+	SaleEndsAt    *time.Time        `json:"sale_ends_at"`
 	SKU           string            `json:"sku"`
 	Weight        float64           `json:"weight"`
 	Dimensions    *ProductDimension `json:"dimensions"`
@@ -107,6 +114,9 @@ func (c *CatalogClient) GetProduct(ctx context.Context, productID string) (*Prod
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrProductNotFound, productID)
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
@@ -125,22 +135,53 @@ func (c *CatalogClient) GetProduct(ctx context.Context, productID string) (*Prod
 	return &result.Data, nil
 }
 
-// GetProducts fetches multiple products by IDs
+// GetProducts fetches multiple products by IDs. Products missing from the catalog are left
+// out; any other failure, such as the catalog being unreachable, fails the whole call.
 func (c *CatalogClient) GetProducts(ctx context.Context, productIDs []string) ([]Product, error) {
 	products := make([]Product, 0, len(productIDs))
 
 	for _, id := range productIDs {
 		product, err := c.GetProduct(ctx, id)
-		if err != nil {
-			c.logger.Warn("Failed to fetch product", zap.String("id", id), zap.Error(err))
+		if errors.Is(err, ErrProductNotFound) {
+			c.logger.Warn("Product not found in catalog", zap.String("id", id))
 			continue
 		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch product %s: %w", id, err)
+		}
 		products = append(products, *product)
 	}
 
 	return products, nil
 }
 
+// UpdateProduct partially updates a product; only the given fields are changed
+func (c *CatalogClient) UpdateProduct(ctx context.Context, productID string, fields map[string]interface{}) error {
+	url := fmt.Sprintf("%s/api/v1/catalog/products/%s", c.baseURL, productID)
+
+	body, _ := json.Marshal(fields)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("product update failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
 // GetAllProducts fetches all active products from catalog (for Push All feature)
 // Uses public catalog endpoint for service-to-service communication
 func (c *CatalogClient) GetAllProducts(ctx context.Context) ([]Product, error) {
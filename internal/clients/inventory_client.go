@@ -0,0 +1,78 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// InventoryClient handles communication with service-inventory
+type InventoryClient struct {
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewInventoryClient creates a new InventoryClient
+func NewInventoryClient(baseURL string, logger *zap.Logger) *InventoryClient {
+	return &InventoryClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// StockLevel is the available stock of a product or variant in one warehouse
+type StockLevel struct {
+	ProductID   string `json:"product_id"`
+	VariantID   string `json:"variant_id,omitempty"` // Empty for products without variants
+	WarehouseID string `json:"warehouse_id"`
+	SKU         string `json:"sku"`
+	Available   int    `json:"available"` // On hand minus reserved
+}
+
+// GetStockLevels fetches the stock levels of products in every warehouse, variants included
+func (c *InventoryClient) GetStockLevels(ctx context.Context, productIDs []string) ([]StockLevel, error) {
+	url := fmt.Sprintf("%s/api/v1/inventory/stock/batch", c.baseURL)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"product_ids": productIDs,
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result struct {
+		Success bool         `json:"success"`
+		Message string       `json:"message"`
+		Data    []StockLevel `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -17,6 +18,7 @@ type Config struct {
 	TikTok   TikTokConfig   `mapstructure:"tiktok"`
 	Security SecurityConfig `mapstructure:"security"`
 	Services ServicesConfig `mapstructure:"services"`
+	Sync     SyncConfig     `mapstructure:"sync"`
 }
 
 // AppConfig holds application configuration
@@ -80,6 +82,21 @@ type ServicesConfig struct {
 	OrderURL     string `mapstructure:"order_url"`
 }
 
+// SyncConfig holds marketplace auto-sync behaviour
+type SyncConfig struct {
+	PauseInactiveProducts      bool          `mapstructure:"pause_inactive_products"`      // Unlist instead of update when a catalog product is deactivated
+	CategoryRefreshInterval    time.Duration `mapstructure:"category_refresh_interval"`    // How often cached marketplace category trees are refreshed
+	DriftCheckInterval         time.Duration `mapstructure:"drift_check_interval"`         // How often mapped listings are compared with the catalog
+	InventoryReconcileInterval time.Duration `mapstructure:"inventory_reconcile_interval"` // How often marketplace stock is compared with the inventory service
+	InventoryAutoCorrect       bool          `mapstructure:"inventory_auto_correct"`       // Push the expected stock where reconciliation finds a difference
+	StockUpdateWindow          time.Duration `mapstructure:"stock_update_window"`          // How long stock changes are coalesced before a batched push; 0 pushes each change
+	StockReservationTTL        time.Duration `mapstructure:"stock_reservation_ttl"`        // How long paid marketplace orders hold stock back from other channels without a stock event
+	OrderPollInterval          time.Duration `mapstructure:"order_poll_interval"`          // How often orders updated since the last poll are fetched from every connection
+	OrderPollOverlap           time.Duration `mapstructure:"order_poll_overlap"`           // How far before the last poll's watermark each order poll starts
+	OrderBackfillInterval      time.Duration `mapstructure:"order_backfill_interval"`      // Minimum time between order list requests of a backfill job
+	SaleRenewalInterval        time.Duration `mapstructure:"sale_renewal_interval"`        // How often sale campaigns about to hit the marketplace's maximum duration are renewed
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	v := viper.New()
@@ -127,6 +144,19 @@ func Load() (*Config, error) {
 	_ = v.BindEnv("services.inventory_url", "SERVICE_INVENTORY_URL")
 	_ = v.BindEnv("services.order_url", "SERVICE_ORDER_URL")
 
+	// Sync
+	_ = v.BindEnv("sync.pause_inactive_products", "MARKETPLACE_PAUSE_INACTIVE_PRODUCTS")
+	_ = v.BindEnv("sync.category_refresh_interval", "MARKETPLACE_CATEGORY_REFRESH_INTERVAL")
+	_ = v.BindEnv("sync.drift_check_interval", "MARKETPLACE_DRIFT_CHECK_INTERVAL")
+	_ = v.BindEnv("sync.inventory_reconcile_interval", "MARKETPLACE_INVENTORY_RECONCILE_INTERVAL")
+	_ = v.BindEnv("sync.inventory_auto_correct", "MARKETPLACE_INVENTORY_AUTO_CORRECT")
+	_ = v.BindEnv("sync.stock_update_window", "MARKETPLACE_STOCK_UPDATE_WINDOW")
+	_ = v.BindEnv("sync.stock_reservation_ttl", "MARKETPLACE_STOCK_RESERVATION_TTL")
+	_ = v.BindEnv("sync.order_poll_interval", "MARKETPLACE_ORDER_POLL_INTERVAL")
+	_ = v.BindEnv("sync.order_poll_overlap", "MARKETPLACE_ORDER_POLL_OVERLAP")
+	_ = v.BindEnv("sync.order_backfill_interval", "MARKETPLACE_ORDER_BACKFILL_INTERVAL")
+	_ = v.BindEnv("sync.sale_renewal_interval", "MARKETPLACE_SALE_RENEWAL_INTERVAL")
+
 	// Set defaults
 	setDefaults(v)
 
@@ -164,6 +194,19 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("services.inventory_url", "http://localhost:8083")
 	v.SetDefault("services.order_url", "http://localhost:8005")
 
+	// Sync
+	v.SetDefault("sync.pause_inactive_products", false)
+	v.SetDefault("sync.category_refresh_interval", "24h")
+	v.SetDefault("sync.drift_check_interval", "6h")
+	v.SetDefault("sync.inventory_reconcile_interval", "12h")
+	v.SetDefault("sync.inventory_auto_correct", false)
+	v.SetDefault("sync.stock_update_window", "2s")
+	v.SetDefault("sync.stock_reservation_ttl", "30m")
+	v.SetDefault("sync.order_poll_interval", "5m")
+	v.SetDefault("sync.order_poll_overlap", "10m")
+	v.SetDefault("sync.order_backfill_interval", "1s")
+	v.SetDefault("sync.sale_renewal_interval", "1h")
+
 	// Sentry
 	v.SetDefault("sentry.dsn", "")
 	v.SetDefault("sentry.environment", "development")
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// AutoListingRule decides which newly created catalog products are pushed to a connection
+// automatically. Connections without an active rule never auto-list.
+type AutoListingRule struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConnectionID  uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex" json:"connection_id"`
+	CategoryIDs   datatypes.JSON `gorm:"type:jsonb;default:'[]'" json:"category_ids"` // []string internal categories, subcategories included; empty for all
+	MinStock      int            `gorm:"default:0" json:"min_stock"`
+	RequireImages bool           `gorm:"not null" json:"require_images"`
+	InitialState  string         `gorm:"type:varchar(20);default:'draft'" json:"initial_state"` // draft, active
+	IsActive      bool           `gorm:"not null" json:"is_active"`
+	CreatedAt     time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for AutoListingRule
+func (AutoListingRule) TableName() string {
+	return "marketplace.auto_listing_rules"
+}
+
+// GetCategoryIDs decodes the categories the rule is limited to
+func (r *AutoListingRule) GetCategoryIDs() []string {
+	var ids []string
+	if len(r.CategoryIDs) > 0 {
+		_ = json.Unmarshal(r.CategoryIDs, &ids)
+	}
+	return ids
+}
+
+// Initial listing state constants
+const (
+	AutoListDraft  = "draft"
+	AutoListActive = "active"
+)
+
+// SaveAutoListingRuleRequest represents a request to create or replace a connection's auto-listing rule
+type SaveAutoListingRuleRequest struct {
+	CategoryIDs   []uuid.UUID `json:"category_ids"`
+	MinStock      int         `json:"min_stock" binding:"gte=0"`
+	RequireImages *bool       `json:"require_images"`
+	InitialState  string      `json:"initial_state" binding:"omitempty,oneof=draft active"`
+	IsActive      *bool       `json:"is_active"`
+}
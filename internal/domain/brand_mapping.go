@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BrandMapping represents the mapping between an internal brand name and a marketplace brand ID
+type BrandMapping struct {
+	ID                uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConnectionID      uuid.UUID `gorm:"type:uuid;not null" json:"connection_id"`
+	InternalBrand     string    `gorm:"type:varchar(255);not null" json:"internal_brand"`
+	ExternalBrandID   string    `gorm:"type:varchar(100);not null" json:"external_brand_id"`
+	ExternalBrandName string    `gorm:"type:varchar(255)" json:"external_brand_name"`
+	Status            string    `gorm:"type:varchar(50);default:'suggested'" json:"status"` // suggested, confirmed
+	Confidence        float64   `gorm:"type:decimal(5,4);default:0" json:"confidence"`
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	Connection *Connection `gorm:"foreignKey:ConnectionID" json:"connection,omitempty"`
+}
+
+// TableName specifies the table name for BrandMapping
+func (BrandMapping) TableName() string {
+	return "marketplace.brand_mappings"
+}
+
+// BrandMappingStatus constants
+const (
+	BrandMappingStatusSuggested = "suggested"
+	BrandMappingStatusConfirmed = "confirmed"
+)
+
+// IsConfirmed returns true if the mapping was confirmed by an admin
+func (m *BrandMapping) IsConfirmed() bool {
+	return m.Status == BrandMappingStatusConfirmed
+}
+
+// SetBrandMappingRequest represents a request to set (override) a brand mapping
+type SetBrandMappingRequest struct {
+	InternalBrand     string `json:"internal_brand" binding:"required"`
+	ExternalBrandID   string `json:"external_brand_id" binding:"required"`
+	ExternalBrandName string `json:"external_brand_name"`
+}
+
+// SuggestBrandMappingsRequest represents a request to generate brand mapping suggestions
+type SuggestBrandMappingsRequest struct {
+	CategoryID string   `json:"category_id"` // External category to fetch brands for (required for Shopee)
+	Brands     []string `json:"brands"`      // Internal brands to match, defaults to all catalog brands
+}
+
+// BrandMappingFilter represents filter options for brand mappings
+type BrandMappingFilter struct {
+	Status   string `json:"status"`
+	Search   string `json:"search"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
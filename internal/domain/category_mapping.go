@@ -13,6 +13,8 @@ type CategoryMapping struct {
 	InternalCategoryID   uuid.UUID `gorm:"type:uuid;not null" json:"internal_category_id"`
 	ExternalCategoryID   string    `gorm:"type:varchar(100);not null" json:"external_category_id"`
 	ExternalCategoryName string    `gorm:"type:varchar(255)" json:"external_category_name"`
+	Inherit              bool      `json:"inherit"`                       // Also applies to descendant categories without their own mapping
+	IsStale              bool      `gorm:"default:false" json:"is_stale"` // External category disappeared from the marketplace
 	CreatedAt            time.Time `gorm:"autoCreateTime" json:"created_at"`
 
 	// Relations
@@ -29,6 +31,12 @@ type CreateCategoryMappingRequest struct {
 	InternalCategoryID   uuid.UUID `json:"internal_category_id" binding:"required"`
 	ExternalCategoryID   string    `json:"external_category_id" binding:"required"`
 	ExternalCategoryName string    `json:"external_category_name"`
+	Inherit              *bool     `json:"inherit"` // Defaults to true
+}
+
+// AcceptCategorySuggestionsRequest represents a request to create mappings from chosen suggestions
+type AcceptCategorySuggestionsRequest struct {
+	Mappings []CreateCategoryMappingRequest `json:"mappings" binding:"required,min=1,dive"`
 }
 
 // CategoryMappingFilter represents filter options for category mappings
@@ -42,6 +50,7 @@ type ExternalCategoryResponse struct {
 	CategoryID   string                     `json:"category_id"`
 	CategoryName string                     `json:"category_name"`
 	ParentID     string                     `json:"parent_id,omitempty"`
+	Breadcrumb   string                     `json:"breadcrumb,omitempty"`
 	HasChildren  bool                       `json:"has_children"`
 	Children     []ExternalCategoryResponse `json:"children,omitempty"`
 }
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExternalCategory is a cached marketplace category for a connection.
+// Categories missing from the latest refresh are kept with IsActive=false so
+// mappings pointing at them can be flagged.
+type ExternalCategory struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConnectionID uuid.UUID `gorm:"type:uuid;not null" json:"connection_id"`
+	CategoryID   string    `gorm:"type:varchar(100);not null" json:"category_id"`
+	ParentID     string    `gorm:"type:varchar(100)" json:"parent_id,omitempty"`
+	Name         string    `gorm:"type:varchar(255);not null" json:"name"`
+	Breadcrumb   string    `gorm:"type:text" json:"breadcrumb"` // "Parent > Child > Leaf"
+	Depth        int       `gorm:"default:0" json:"depth"`
+	IsLeaf       bool      `gorm:"default:false" json:"is_leaf"`
+	IsActive     bool      `gorm:"default:true" json:"is_active"`
+	LastSeenAt   time.Time `gorm:"type:timestamptz" json:"last_seen_at"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for ExternalCategory
+func (ExternalCategory) TableName() string {
+	return "marketplace.external_categories"
+}
+
+// ToResponse converts a cached category into a tree node without children
+func (c *ExternalCategory) ToResponse() ExternalCategoryResponse {
+	return ExternalCategoryResponse{
+		CategoryID:   c.CategoryID,
+		CategoryName: c.Name,
+		ParentID:     c.ParentID,
+		Breadcrumb:   c.Breadcrumb,
+		HasChildren:  !c.IsLeaf,
+	}
+}
+
+// ExternalCategoryFilter represents filter options for cached marketplace categories
+type ExternalCategoryFilter struct {
+	Query    string `json:"query"`
+	LeafOnly bool   `json:"leaf_only"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
@@ -1,28 +1,42 @@
 package domain
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/datatypes"
 )
 
 // ImportedProduct represents a product imported from a marketplace (not yet mapped to internal product)
 type ImportedProduct struct {
-	ID                uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	ConnectionID      uuid.UUID  `gorm:"type:uuid;not null" json:"connection_id"`
-	ExternalProductID string     `gorm:"type:varchar(100);not null" json:"external_product_id"`
-	ExternalSKU       string     `gorm:"type:varchar(100)" json:"external_sku"`
-	Name              string     `gorm:"type:varchar(500);not null" json:"name"`
-	Description       string     `gorm:"type:text" json:"description"`
-	Price             float64    `gorm:"type:decimal(12,2)" json:"price"`
-	Stock             int        `gorm:"default:0" json:"stock"`
-	CategoryID        string     `gorm:"type:varchar(100)" json:"category_id"`
-	Status            string     `gorm:"type:varchar(50)" json:"status"` // NORMAL, BANNED, etc.
-	ImageURL          string     `gorm:"type:text" json:"image_url"`     // First image URL
-	IsMapped          bool       `gorm:"default:false" json:"is_mapped"` // Whether this product is mapped to an internal product
-	MappedToProductID *uuid.UUID `gorm:"type:uuid" json:"mapped_to_product_id,omitempty"`
-	ImportedAt        time.Time  `gorm:"autoCreateTime" json:"imported_at"`
-	UpdatedAt         time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	ID                uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConnectionID      uuid.UUID      `gorm:"type:uuid;not null" json:"connection_id"`
+	ExternalProductID string         `gorm:"type:varchar(100);not null" json:"external_product_id"`
+	ExternalSKU       string         `gorm:"type:varchar(100)" json:"external_sku"`
+	Name              string         `gorm:"type:varchar(500);not null" json:"name"`
+	Description       string         `gorm:"type:text" json:"description"`
+	Price             float64        `gorm:"type:decimal(12,2)" json:"price"`
+	Stock             int            `gorm:"default:0" json:"stock"`
+	CategoryID        string         `gorm:"type:varchar(100)" json:"category_id"`
+	Status            string         `gorm:"type:varchar(50)" json:"status"`            // NORMAL, BANNED, etc.
+	ImageURL          string         `gorm:"type:text" json:"image_url"`                // First image URL
+	Images            datatypes.JSON `gorm:"type:jsonb;default:'[]'" json:"images"`     // []string, all image URLs
+	SKUs              datatypes.JSON `gorm:"type:jsonb;default:'[]'" json:"skus"`       // []ImportedSKU, one per model
+	Attributes        datatypes.JSON `gorm:"type:jsonb;default:'{}'" json:"attributes"` // map[string]string, e.g. {"Material": "Cotton"}
+	Brand             string         `gorm:"type:varchar(255)" json:"brand"`            // Marketplace brand name
+	Weight            float64        `gorm:"type:decimal(10,2)" json:"weight"`          // Package weight in grams
+	Length            float64        `gorm:"type:decimal(10,2)" json:"length"`          // Package length in cm
+	Width             float64        `gorm:"type:decimal(10,2)" json:"width"`           // Package width in cm
+	Height            float64        `gorm:"type:decimal(10,2)" json:"height"`          // Package height in cm
+	RawPayload        datatypes.JSON `gorm:"type:jsonb" json:"raw_payload,omitempty"`   // Unmodified platform response
+	IsMapped          bool           `gorm:"default:false" json:"is_mapped"`            // Whether this product is mapped to an internal product
+	IsRemoved         bool           `gorm:"default:false" json:"is_removed"`           // No longer returned by the marketplace
+	ExternalUpdatedAt *time.Time     `gorm:"type:timestamptz" json:"external_updated_at,omitempty"`
+	LastSeenAt        *time.Time     `gorm:"type:timestamptz" json:"last_seen_at,omitempty"`
+	MappedToProductID *uuid.UUID     `gorm:"type:uuid" json:"mapped_to_product_id,omitempty"`
+	ImportedAt        time.Time      `gorm:"autoCreateTime" json:"imported_at"`
+	UpdatedAt         time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 
 	// Relations
 	Connection *Connection `gorm:"foreignKey:ConnectionID" json:"connection,omitempty"`
@@ -33,12 +47,69 @@ func (ImportedProduct) TableName() string {
 	return "marketplace.imported_products"
 }
 
+// GetSKUs decodes the SKU list of an imported product
+func (p *ImportedProduct) GetSKUs() []ImportedSKU {
+	var skus []ImportedSKU
+	if len(p.SKUs) > 0 {
+		_ = json.Unmarshal(p.SKUs, &skus)
+	}
+	return skus
+}
+
+// GetImages decodes the image URLs of an imported product
+func (p *ImportedProduct) GetImages() []string {
+	var images []string
+	if len(p.Images) > 0 {
+		_ = json.Unmarshal(p.Images, &images)
+	}
+	return images
+}
+
+// GetAttributes decodes the product attributes of an imported product
+func (p *ImportedProduct) GetAttributes() map[string]string {
+	var attributes map[string]string
+	if len(p.Attributes) > 0 {
+		_ = json.Unmarshal(p.Attributes, &attributes)
+	}
+	return attributes
+}
+
+// ImportedSKU is a single SKU (model) of an imported product
+type ImportedSKU struct {
+	ExternalSKUID string            `json:"external_sku_id"` // Shopee model ID or TikTok SKU ID
+	Name          string            `json:"name,omitempty"`  // Model name, e.g. "Red,M"
+	SellerSKU     string            `json:"seller_sku"`
+	Price         float64           `json:"price"`
+	Stock         int               `json:"stock"`
+	Attributes    map[string]string `json:"attributes,omitempty"` // e.g. {"Size": "M"}
+}
+
 // ImportedProductFilter represents filter options for imported products
 type ImportedProductFilter struct {
 	ConnectionID *uuid.UUID `json:"connection_id"`
 	IsMapped     *bool      `json:"is_mapped"`
+	IsRemoved    *bool      `json:"is_removed"`
 	Status       string     `json:"status"`
 	Search       string     `json:"search"`
 	Page         int        `json:"page"`
 	PageSize     int        `json:"page_size"`
 }
+
+// ProductMatch pairs an imported marketplace product with a catalog product.
+// Variants pairs the imported models with catalog variants; when empty they are paired by SKU and options.
+type ProductMatch struct {
+	ImportedProductID uuid.UUID      `json:"imported_product_id" binding:"required"`
+	InternalProductID uuid.UUID      `json:"internal_product_id" binding:"required"`
+	Variants          []VariantMatch `json:"variants,omitempty" binding:"omitempty,dive"`
+}
+
+// VariantMatch pairs an imported model (SKU) with a catalog variant
+type VariantMatch struct {
+	ExternalSKUID     string    `json:"external_sku_id" binding:"required"`
+	InternalVariantID uuid.UUID `json:"internal_variant_id" binding:"required"`
+}
+
+// ConfirmProductMatchesRequest represents a request to create mappings from reviewed matches
+type ConfirmProductMatchesRequest struct {
+	Matches []ProductMatch `json:"matches" binding:"required,min=1,dive"`
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InventoryDiscrepancy is the latest comparison of the marketplace stock of a listing, or of
+// one variant of it, against the inventory service. There is one report per listed SKU.
+type InventoryDiscrepancy struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConnectionID      uuid.UUID  `gorm:"type:uuid;not null" json:"connection_id"`
+	ProductMappingID  uuid.UUID  `gorm:"type:uuid;not null" json:"product_mapping_id"`
+	InternalProductID uuid.UUID  `gorm:"type:uuid;not null" json:"internal_product_id"`
+	InternalVariantID *uuid.UUID `gorm:"type:uuid" json:"internal_variant_id,omitempty"`
+	ExternalProductID string     `gorm:"type:varchar(100);not null" json:"external_product_id"`
+	ExternalVariantID string     `gorm:"type:varchar(100);not null;default:''" json:"external_variant_id"` // Shopee model or TikTok SKU; empty for the whole listing
+	SKU               string     `gorm:"type:varchar(100)" json:"sku"`
+	ExpectedQuantity  int        `gorm:"not null" json:"expected_quantity"` // What we would publish
+	ActualQuantity    int        `gorm:"not null" json:"actual_quantity"`   // What the marketplace shows
+	HasDiscrepancy    bool       `gorm:"not null" json:"has_discrepancy"`
+	CheckError        string     `gorm:"type:text" json:"check_error,omitempty"` // Why the stock could not be compared
+	CheckedAt         time.Time  `gorm:"type:timestamptz;not null" json:"checked_at"`
+	Uncorrectable     string     `gorm:"type:text" json:"uncorrectable,omitempty"` // Why auto-correct cannot push the expected stock
+
+	// Last correction pushed to the marketplace
+	LastCorrectedAt        *time.Time `gorm:"type:timestamptz" json:"last_corrected_at"`
+	LastCorrectionQuantity *int       `json:"last_correction_quantity"`
+	LastCorrectionError    string     `gorm:"type:text" json:"last_correction_error,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for InventoryDiscrepancy
+func (InventoryDiscrepancy) TableName() string {
+	return "marketplace.inventory_discrepancies"
+}
+
+// InventoryReconcilePayload is the payload of an inventory reconciliation job
+type InventoryReconcilePayload struct {
+	AutoCorrect   bool `json:"auto_correct"`  // Push the expected stock where it differs
+	Discrepancies int  `json:"discrepancies"` // SKUs whose marketplace stock differs
+	Corrected     int  `json:"corrected"`
+}
+
+// StartInventoryReconcileRequest represents a request to reconcile the stock of a connection
+type StartInventoryReconcileRequest struct {
+	AutoCorrect *bool `json:"auto_correct"` // Defaults to the service configuration
+}
+
+// InventoryDiscrepancyFilter represents filter options for inventory discrepancy reports
+type InventoryDiscrepancyFilter struct {
+	HasDiscrepancy *bool `json:"has_discrepancy"`
+	Page           int   `json:"page"`
+	PageSize       int   `json:"page_size"`
+}
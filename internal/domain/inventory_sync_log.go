@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InventorySyncLog records one stock push to a marketplace listing, or one of its variants
+type InventorySyncLog struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConnectionID      uuid.UUID  `gorm:"type:uuid;not null" json:"connection_id"`
+	ProductMappingID  *uuid.UUID `gorm:"type:uuid" json:"product_mapping_id,omitempty"`
+	InternalProductID *uuid.UUID `gorm:"type:uuid" json:"internal_product_id,omitempty"` // Unknown for manual pushes of unmapped listings
+	ExternalProductID string     `gorm:"type:varchar(100)" json:"external_product_id"`
+	ExternalVariantID string     `gorm:"type:varchar(100);not null;default:''" json:"external_variant_id,omitempty"`
+	Source            string     `gorm:"type:varchar(30);not null" json:"source"`
+	PreviousQuantity  *int       `json:"previous_quantity,omitempty"` // Last quantity pushed, or the marketplace stock seen by reconciliation
+	NewQuantity       int        `gorm:"not null" json:"new_quantity"`
+	SyncStatus        string     `gorm:"type:varchar(50);default:'pending'" json:"sync_status"`
+	ErrorMessage      string     `gorm:"type:text" json:"error_message,omitempty"`
+	CreatedAt         time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for InventorySyncLog
+func (InventorySyncLog) TableName() string {
+	return "marketplace.inventory_sync_logs"
+}
+
+// Inventory sync log source constants: what triggered the stock push
+const (
+	InventorySyncSourceEvent          = "event"          // inventory.stock.changed event
+	InventorySyncSourceManual         = "manual"         // Inventory push API
+	InventorySyncSourceReconciliation = "reconciliation" // Reconciliation auto-correct
+	InventorySyncSourceProductPush    = "product_push"   // Initial stock of a pushed product
+	InventorySyncSourceDrift          = "drift"          // Stock drift resolved by pushing the catalog value
+	InventorySyncSourceReservation    = "reservation"    // Stock reserved by an order on another connection
+)
+
+// Inventory sync log status constants
+const (
+	InventorySyncStatusSuccess = "success"
+	InventorySyncStatusFailed  = "failed"
+)
+
+// InventorySyncLogFilter represents filter options for inventory sync logs
+type InventorySyncLogFilter struct {
+	ConnectionID      *uuid.UUID `json:"connection_id"`
+	ProductMappingID  *uuid.UUID `json:"product_mapping_id"`
+	InternalProductID *uuid.UUID `json:"internal_product_id"`
+	ExternalProductID string     `json:"external_product_id"`
+	Source            string     `json:"source"`
+	Status            string     `json:"status"`
+	StartDate         *time.Time `json:"start_date"`
+	EndDate           *time.Time `json:"end_date"`
+	Page              int        `json:"page"`
+	PageSize          int        `json:"page_size"`
+}
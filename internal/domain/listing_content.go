@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// ListingTemplate shapes the title and description of every listing published on a connection,
+// e.g. "{{brand}} {{name}} - {{variant}}". Empty templates publish the catalog values. The
+// description header and footer, e.g. shipping or warranty notes, frame every description.
+type ListingTemplate struct {
+	ID                  uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConnectionID        uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"connection_id"`
+	TitleTemplate       string    `gorm:"type:text" json:"title_template"`
+	DescriptionTemplate string    `gorm:"type:text" json:"description_template"`
+	MaxTitleLength      int       `gorm:"default:0" json:"max_title_length"`    // 0 means no limit
+	DescriptionHeader   string    `gorm:"type:text" json:"description_header"`  // Kept when the description is shortened
+	DescriptionFooter   string    `gorm:"type:text" json:"description_footer"`  // Kept when the description is shortened
+	ExtendedDescription bool      `gorm:"not null" json:"extended_description"` // Shopee: publish description images where the shop is eligible
+	CreatedAt           time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for ListingTemplate
+func (ListingTemplate) TableName() string {
+	return "marketplace.listing_templates"
+}
+
+// Template placeholders
+const (
+	PlaceholderName        = "name"
+	PlaceholderBrand       = "brand"
+	PlaceholderVariant     = "variant" // Variant name of single-variant products, empty otherwise
+	PlaceholderSKU         = "sku"
+	PlaceholderCategory    = "category"
+	PlaceholderDescription = "description" // Only available in description templates
+)
+
+// ListingOverride replaces catalog content of one product on one connection. Unset fields
+// fall back to the connection template and the catalog. Overrides are keyed by product rather
+// than by mapping so they can be prepared before the first push.
+type ListingOverride struct {
+	ID                uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConnectionID      uuid.UUID      `gorm:"type:uuid;not null" json:"connection_id"`
+	InternalProductID uuid.UUID      `gorm:"type:uuid;not null" json:"internal_product_id"`
+	Title             *string        `gorm:"type:text" json:"title,omitempty"`
+	Description       *string        `gorm:"type:text" json:"description,omitempty"`
+	ImageOrder        datatypes.JSON `gorm:"type:jsonb;default:'[]'" json:"image_order"` // []string of catalog image URLs, listed first
+	Attributes        datatypes.JSON `gorm:"type:jsonb;default:'{}'" json:"attributes"`  // map[string]string
+	Price             *float64       `gorm:"type:decimal(12,2)" json:"price,omitempty"`  // Replaces the pricing rule result
+	CreatedAt         time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for ListingOverride
+func (ListingOverride) TableName() string {
+	return "marketplace.listing_overrides"
+}
+
+// GetImageOrder decodes the preferred image order
+func (o *ListingOverride) GetImageOrder() []string {
+	var images []string
+	if len(o.ImageOrder) > 0 {
+		_ = json.Unmarshal(o.ImageOrder, &images)
+	}
+	return images
+}
+
+// GetAttributes decodes the attribute overrides
+func (o *ListingOverride) GetAttributes() map[string]string {
+	var attrs map[string]string
+	if len(o.Attributes) > 0 {
+		_ = json.Unmarshal(o.Attributes, &attrs)
+	}
+	return attrs
+}
+
+// ListingContent is the content published for a product on a connection after the
+// template and override are applied
+type ListingContent struct {
+	Title                string            `json:"title"`
+	Description          string            `json:"description"`
+	DescriptionHeader    string            `json:"description_header,omitempty"`
+	DescriptionFooter    string            `json:"description_footer,omitempty"`
+	ExtendedDescription  bool              `json:"extended_description"`
+	FormattedDescription string            `json:"formatted_description,omitempty"` // As sent to the marketplace; set by previews
+	Images               []string          `json:"images"`
+	Attributes           map[string]string `json:"attributes,omitempty"`
+	Price                *float64          `json:"price,omitempty"`   // Set when overridden
+	Overridden           bool              `json:"overridden"`        // Whether an override exists for the product
+	ImagesOverridden     bool              `json:"images_overridden"` // Whether the override reorders the images
+}
+
+// SaveListingTemplateRequest represents a request to create or replace a connection's template
+type SaveListingTemplateRequest struct {
+	TitleTemplate       string `json:"title_template"`
+	DescriptionTemplate string `json:"description_template"`
+	MaxTitleLength      int    `json:"max_title_length" binding:"gte=0"`
+	DescriptionHeader   string `json:"description_header"`
+	DescriptionFooter   string `json:"description_footer"`
+	ExtendedDescription bool   `json:"extended_description"`
+}
+
+// SaveListingOverrideRequest represents a request to create or replace a product's override.
+// Omitted fields are not overridden.
+type SaveListingOverrideRequest struct {
+	Title       *string           `json:"title"`
+	Description *string           `json:"description"`
+	ImageOrder  []string          `json:"image_order"`
+	Attributes  map[string]string `json:"attributes"`
+	Price       *float64          `json:"price" binding:"omitempty,gt=0"`
+}
+
+// ListingOverrideFilter represents filter options for listing overrides
+type ListingOverrideFilter struct {
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+}
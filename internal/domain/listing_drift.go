@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// ListingDrift is the latest comparison of a mapped product's live marketplace listing
+// against its catalog product. There is one report per product mapping.
+type ListingDrift struct {
+	ID                uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConnectionID      uuid.UUID      `gorm:"type:uuid;not null" json:"connection_id"`
+	ProductMappingID  uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex" json:"product_mapping_id"`
+	InternalProductID uuid.UUID      `gorm:"type:uuid;not null" json:"internal_product_id"`
+	ExternalProductID string         `gorm:"type:varchar(100);not null" json:"external_product_id"`
+	Fields            datatypes.JSON `gorm:"type:jsonb;default:'[]'" json:"fields"` // []DriftField, only fields that differ
+	HasDrift          bool           `gorm:"default:false" json:"has_drift"`
+	CheckError        string         `gorm:"type:text" json:"check_error,omitempty"` // Why the listing could not be compared
+	ImageBaseline     datatypes.JSON `gorm:"type:jsonb" json:"-"`                    // ListingImageBaseline, nil until images are first found matching
+	CheckedAt         time.Time      `gorm:"type:timestamptz;not null" json:"checked_at"`
+	CreatedAt         time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for ListingDrift
+func (ListingDrift) TableName() string {
+	return "marketplace.listing_drifts"
+}
+
+// GetFields decodes the drifted fields of a report
+func (d *ListingDrift) GetFields() []DriftField {
+	var fields []DriftField
+	if len(d.Fields) > 0 {
+		_ = json.Unmarshal(d.Fields, &fields)
+	}
+	return fields
+}
+
+// SetFields encodes the drifted fields of a report and updates HasDrift
+func (d *ListingDrift) SetFields(fields []DriftField) {
+	if fields == nil {
+		fields = []DriftField{}
+	}
+	d.Fields, _ = json.Marshal(fields)
+	d.HasDrift = len(fields) > 0
+}
+
+// GetImageBaseline decodes the image baseline of a report; nil when none was recorded
+func (d *ListingDrift) GetImageBaseline() *ListingImageBaseline {
+	if len(d.ImageBaseline) == 0 {
+		return nil
+	}
+	var baseline ListingImageBaseline
+	if err := json.Unmarshal(d.ImageBaseline, &baseline); err != nil {
+		return nil
+	}
+	return &baseline
+}
+
+// SetImageBaseline encodes the image baseline of a report; nil clears it
+func (d *ListingDrift) SetImageBaseline(baseline *ListingImageBaseline) {
+	if baseline == nil {
+		d.ImageBaseline = nil
+		return
+	}
+	d.ImageBaseline, _ = json.Marshal(baseline)
+}
+
+// ListingImageBaseline holds the normalized image URLs of both sides as of the last drift
+// check that found them matching. Marketplaces re-host images under their own URLs, so each
+// side is compared with its own earlier state rather than with the other side.
+type ListingImageBaseline struct {
+	Catalog     []string `json:"catalog"`
+	Marketplace []string `json:"marketplace"`
+}
+
+// DriftField is a listing field whose marketplace value differs from the catalog
+type DriftField struct {
+	Field            string      `json:"field"` // name, price, stock, status, images
+	CatalogValue     interface{} `json:"catalog_value"`
+	MarketplaceValue interface{} `json:"marketplace_value"`
+}
+
+// Drift fields
+const (
+	DriftFieldName   = "name"
+	DriftFieldPrice  = "price"
+	DriftFieldStock  = "stock"
+	DriftFieldStatus = "status"
+	DriftFieldImages = "images"
+)
+
+// Drift resolution actions
+const (
+	DriftActionPush   = "push"   // Overwrite the marketplace listing from the catalog
+	DriftActionAccept = "accept" // Take the marketplace value into our records
+)
+
+// ResolveDriftRequest represents a request to resolve one drifted field
+type ResolveDriftRequest struct {
+	Field  string `json:"field" binding:"required,oneof=name price stock status images"`
+	Action string `json:"action" binding:"required,oneof=push accept"`
+}
+
+// DriftCheckPayload is the payload of a drift check job
+type DriftCheckPayload struct {
+	Drifted int `json:"drifted"` // Listings with at least one drifted field
+}
+
+// ListingDriftFilter represents filter options for drift reports
+type ListingDriftFilter struct {
+	HasDrift *bool  `json:"has_drift"`
+	Field    string `json:"field"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
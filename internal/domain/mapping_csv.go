@@ -0,0 +1,41 @@
+package domain
+
+// ProductMappingCSVHeader is the header of product mapping CSV files. A row with empty
+// variant columns maps a product; a row with them maps one variant of the product.
+var ProductMappingCSVHeader = []string{
+	"internal_product_id",
+	"external_product_id",
+	"external_sku",
+	"internal_variant_id",
+	"external_variant_id",
+	"external_variant_sku",
+}
+
+// CategoryMappingCSVHeader is the header of category mapping CSV files
+var CategoryMappingCSVHeader = []string{
+	"internal_category_id",
+	"external_category_id",
+	"external_category_name",
+	"inherit",
+}
+
+// MappingImportReport is the outcome of a mapping CSV import. Nothing is applied unless
+// every row is valid.
+type MappingImportReport struct {
+	DryRun      bool                    `json:"dry_run"`
+	Applied     bool                    `json:"applied"`
+	TotalRows   int                     `json:"total_rows"`
+	InvalidRows int                     `json:"invalid_rows"`
+	Created     int                     `json:"created"` // Mappings created, or that would be on a dry run
+	Updated     int                     `json:"updated"`
+	Unchanged   int                     `json:"unchanged"`
+	Errors      []MappingImportRowError `json:"errors"`
+}
+
+// MappingImportRowError is a validation error of one CSV row.
+// Rows are numbered as in a spreadsheet, the header being row 1.
+type MappingImportRowError struct {
+	Row    int    `json:"row"`
+	Column string `json:"column,omitempty"`
+	Error  string `json:"error"`
+}
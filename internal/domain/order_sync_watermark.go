@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// OrderSyncWatermark tracks how far the order poller has synced a connection's orders
+type OrderSyncWatermark struct {
+	ConnectionID   uuid.UUID      `gorm:"type:uuid;primaryKey" json:"connection_id"`
+	SyncedTo       time.Time      `gorm:"not null" json:"synced_to"` // Orders updated before this time are synced, apart from FailedOrderIDs
+	LastPolledAt   *time.Time     `json:"last_polled_at,omitempty"`
+	LastError      string         `gorm:"type:text" json:"last_error,omitempty"`
+	FailedOrderIDs datatypes.JSON `gorm:"type:jsonb;default:'[]'" json:"failed_order_ids"` // []string external IDs of orders whose import failed, retried by ID
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for OrderSyncWatermark
+func (OrderSyncWatermark) TableName() string {
+	return "marketplace.order_sync_watermarks"
+}
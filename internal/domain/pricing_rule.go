@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PricingRule defines how catalog prices are converted to marketplace prices for a connection.
+// A rule without InternalCategoryID is the connection default; category rules override it.
+type PricingRule struct {
+	ID                   uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConnectionID         uuid.UUID  `gorm:"type:uuid;not null" json:"connection_id"`
+	InternalCategoryID   *uuid.UUID `gorm:"type:uuid" json:"internal_category_id,omitempty"`
+	MarkupType           string     `gorm:"type:varchar(20);default:'percent'" json:"markup_type"` // percent, fixed
+	MarkupValue          float64    `gorm:"type:decimal(12,4);default:0" json:"markup_value"`
+	Rounding             string     `gorm:"type:varchar(20);default:'none'" json:"rounding"` // none, integer, end_90, end_99
+	MinMarginPercent     float64    `gorm:"type:decimal(8,4);default:0" json:"min_margin_percent"`
+	StrikeThrough        string     `gorm:"type:varchar(20);default:'catalog'" json:"strike_through"` // catalog, none, percent
+	StrikeThroughPercent float64    `gorm:"type:decimal(8,4);default:0" json:"strike_through_percent"`
+	IsActive             bool       `gorm:"not null" json:"is_active"`
+	CreatedAt            time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt            time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	Connection *Connection `gorm:"foreignKey:ConnectionID" json:"connection,omitempty"`
+}
+
+// TableName specifies the table name for PricingRule
+func (PricingRule) TableName() string {
+	return "marketplace.pricing_rules"
+}
+
+// Markup type constants
+const (
+	MarkupTypePercent = "percent"
+	MarkupTypeFixed   = "fixed"
+)
+
+// Rounding strategy constants
+const (
+	RoundingNone    = "none"
+	RoundingInteger = "integer" // Round up to the next whole unit
+	RoundingEnd90   = "end_90"  // Round up to the next price ending in .90
+	RoundingEnd99   = "end_99"  // Round up to the next price ending in .99
+)
+
+// Strike-through (original price) strategy constants
+const (
+	StrikeThroughCatalog = "catalog" // Original price is the rule applied to the catalog base price
+	StrikeThroughNone    = "none"    // Original price equals the selling price
+	StrikeThroughPercent = "percent" // Original price is the selling price inflated by StrikeThroughPercent
+)
+
+// PriceQuote is the result of applying a pricing rule to a catalog price
+type PriceQuote struct {
+	BasePrice     float64    `json:"base_price"`
+	SalePrice     *float64   `json:"sale_price,omitempty"`
+	Price         float64    `json:"price"`          // Selling price on the marketplace
+	OriginalPrice float64    `json:"original_price"` // List / strike-through price on the marketplace
+	RuleID        *uuid.UUID `json:"rule_id,omitempty"`
+	MarginFloored bool       `json:"margin_floored"` // Whether the minimum margin raised the price
+}
+
+// CreatePricingRuleRequest represents a request to create or replace a pricing rule
+type CreatePricingRuleRequest struct {
+	InternalCategoryID   *uuid.UUID `json:"internal_category_id"`
+	MarkupType           string     `json:"markup_type" binding:"omitempty,oneof=percent fixed"`
+	MarkupValue          float64    `json:"markup_value"`
+	Rounding             string     `json:"rounding" binding:"omitempty,oneof=none integer end_90 end_99"`
+	MinMarginPercent     float64    `json:"min_margin_percent" binding:"gte=0"`
+	StrikeThrough        string     `json:"strike_through" binding:"omitempty,oneof=catalog none percent"`
+	StrikeThroughPercent float64    `json:"strike_through_percent" binding:"gte=0"`
+	IsActive             *bool      `json:"is_active"`
+}
+
+// QuotePrice computes the marketplace price for a catalog price.
+// A nil rule keeps the catalog behaviour: sale (or base) price with the base price as original price.
+func (r *PricingRule) QuotePrice(basePrice float64, salePrice *float64) *PriceQuote {
+	selling := basePrice
+	if salePrice != nil {
+		selling = *salePrice
+	}
+
+	quote := &PriceQuote{
+		BasePrice:     basePrice,
+		SalePrice:     salePrice,
+		Price:         selling,
+		OriginalPrice: basePrice,
+	}
+	if r == nil || !r.IsActive {
+		return quote
+	}
+
+	ruleID := r.ID
+	quote.RuleID = &ruleID
+
+	var floored bool
+	quote.Price, floored = r.apply(selling)
+	quote.MarginFloored = floored
+
+	switch r.StrikeThrough {
+	case StrikeThroughNone:
+		quote.OriginalPrice = quote.Price
+	case StrikeThroughPercent:
+		quote.OriginalPrice = r.round(quote.Price * (1 + r.StrikeThroughPercent/100))
+	default:
+		quote.OriginalPrice, _ = r.apply(basePrice)
+	}
+
+	// The strike-through price can never be below the selling price
+	if quote.OriginalPrice < quote.Price {
+		quote.OriginalPrice = quote.Price
+	}
+
+	return quote
+}
+
+// apply adds the markup, enforces the minimum margin and rounds
+func (r *PricingRule) apply(price float64) (float64, bool) {
+	result := price
+	switch r.MarkupType {
+	case MarkupTypeFixed:
+		result += r.MarkupValue
+	default:
+		result *= 1 + r.MarkupValue/100
+	}
+
+	floored := false
+	if minPrice := price * (1 + r.MinMarginPercent/100); result < minPrice {
+		result = minPrice
+		floored = true
+	}
+
+	return r.round(result), floored
+}
+
+// round applies the rounding strategy, always rounding up so margins are preserved
+func (r *PricingRule) round(price float64) float64 {
+	// Normalize to cents first to avoid float noise such as 12.900000001
+	price = math.Ceil(math.Round(price*10000)/100) / 100
+
+	switch r.Rounding {
+	case RoundingInteger:
+		return math.Ceil(price)
+	case RoundingEnd90:
+		return roundUpToEnding(price, 0.90)
+	case RoundingEnd99:
+		return roundUpToEnding(price, 0.99)
+	default:
+		return price
+	}
+}
+
+// roundUpToEnding returns the smallest price >= price whose fractional part is ending
+func roundUpToEnding(price, ending float64) float64 {
+	candidate := math.Floor(price) + ending
+	if candidate+1e-9 < price {
+		candidate++
+	}
+	return math.Round(candidate*100) / 100
+}
@@ -0,0 +1,196 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestPricingRuleQuotePrice(t *testing.T) {
+	ruleID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	price := func(p float64) *float64 { return &p }
+	rule := func(r PricingRule) *PricingRule {
+		r.ID = ruleID
+		r.IsActive = true
+		return &r
+	}
+
+	tests := []struct {
+		name          string
+		rule          *PricingRule
+		basePrice     float64
+		salePrice     *float64
+		wantPrice     float64
+		wantOriginal  float64
+		wantFloored   bool
+		wantRuleApply bool
+	}{
+		{
+			name:         "no rule keeps the catalog price",
+			basePrice:    100,
+			wantPrice:    100,
+			wantOriginal: 100,
+		},
+		{
+			name:         "no rule sells at the sale price",
+			basePrice:    100,
+			salePrice:    price(80),
+			wantPrice:    80,
+			wantOriginal: 100,
+		},
+		{
+			name:         "inactive rule is ignored",
+			rule:         &PricingRule{ID: ruleID, MarkupValue: 50},
+			basePrice:    100,
+			wantPrice:    100,
+			wantOriginal: 100,
+		},
+		{
+			name:          "percent markup",
+			rule:          rule(PricingRule{MarkupType: MarkupTypePercent, MarkupValue: 10}),
+			basePrice:     100,
+			wantPrice:     110,
+			wantOriginal:  110,
+			wantRuleApply: true,
+		},
+		{
+			name:          "percent markup on a sale price",
+			rule:          rule(PricingRule{MarkupType: MarkupTypePercent, MarkupValue: 10}),
+			basePrice:     100,
+			salePrice:     price(80),
+			wantPrice:     88,
+			wantOriginal:  110,
+			wantRuleApply: true,
+		},
+		{
+			name:          "fixed markup",
+			rule:          rule(PricingRule{MarkupType: MarkupTypeFixed, MarkupValue: 5}),
+			basePrice:     19.99,
+			wantPrice:     24.99,
+			wantOriginal:  24.99,
+			wantRuleApply: true,
+		},
+		{
+			name:          "float noise does not add a cent",
+			rule:          rule(PricingRule{MarkupValue: 29}),
+			basePrice:     10,
+			wantPrice:     12.9,
+			wantOriginal:  12.9,
+			wantRuleApply: true,
+		},
+		{
+			name:          "fractions of a cent round up",
+			rule:          rule(PricingRule{MarkupValue: 10}),
+			basePrice:     12.34,
+			wantPrice:     13.58,
+			wantOriginal:  13.58,
+			wantRuleApply: true,
+		},
+		{
+			name:          "integer rounding",
+			rule:          rule(PricingRule{MarkupValue: 10, Rounding: RoundingInteger}),
+			basePrice:     12.34,
+			wantPrice:     14,
+			wantOriginal:  14,
+			wantRuleApply: true,
+		},
+		{
+			name:          "end 90 rounding",
+			rule:          rule(PricingRule{MarkupValue: 10, Rounding: RoundingEnd90}),
+			basePrice:     12,
+			wantPrice:     13.9,
+			wantOriginal:  13.9,
+			wantRuleApply: true,
+		},
+		{
+			name:          "end 90 rounding past the ending",
+			rule:          rule(PricingRule{Rounding: RoundingEnd90}),
+			basePrice:     13.95,
+			wantPrice:     14.9,
+			wantOriginal:  14.9,
+			wantRuleApply: true,
+		},
+		{
+			name:          "end 99 rounding keeps an exact ending",
+			rule:          rule(PricingRule{Rounding: RoundingEnd99}),
+			basePrice:     13.99,
+			wantPrice:     13.99,
+			wantOriginal:  13.99,
+			wantRuleApply: true,
+		},
+		{
+			name:          "minimum margin raises the price",
+			rule:          rule(PricingRule{MarkupValue: 5, MinMarginPercent: 20}),
+			basePrice:     100,
+			salePrice:     price(50),
+			wantPrice:     60,
+			wantOriginal:  120,
+			wantFloored:   true,
+			wantRuleApply: true,
+		},
+		{
+			name:          "negative markup is floored at the minimum margin",
+			rule:          rule(PricingRule{MarkupType: MarkupTypeFixed, MarkupValue: -10}),
+			basePrice:     100,
+			wantPrice:     100,
+			wantOriginal:  100,
+			wantFloored:   true,
+			wantRuleApply: true,
+		},
+		{
+			name:          "no strike-through",
+			rule:          rule(PricingRule{MarkupValue: 10, StrikeThrough: StrikeThroughNone}),
+			basePrice:     100,
+			salePrice:     price(80),
+			wantPrice:     88,
+			wantOriginal:  88,
+			wantRuleApply: true,
+		},
+		{
+			name:          "percent strike-through",
+			rule:          rule(PricingRule{MarkupValue: 10, StrikeThrough: StrikeThroughPercent, StrikeThroughPercent: 25}),
+			basePrice:     100,
+			wantPrice:     110,
+			wantOriginal:  137.5,
+			wantRuleApply: true,
+		},
+		{
+			name:          "percent strike-through is rounded",
+			rule:          rule(PricingRule{MarkupValue: 10, Rounding: RoundingEnd99, StrikeThrough: StrikeThroughPercent, StrikeThroughPercent: 25}),
+			basePrice:     100,
+			wantPrice:     110.99,
+			wantOriginal:  138.99,
+			wantRuleApply: true,
+		},
+		{
+			name:          "original price is never below the selling price",
+			rule:          rule(PricingRule{}),
+			basePrice:     100,
+			salePrice:     price(120),
+			wantPrice:     120,
+			wantOriginal:  120,
+			wantRuleApply: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := tt.rule.QuotePrice(tt.basePrice, tt.salePrice)
+			if quote.Price != tt.wantPrice {
+				t.Errorf("Price = %v, want %v", quote.Price, tt.wantPrice)
+			}
+			if quote.OriginalPrice != tt.wantOriginal {
+				t.Errorf("OriginalPrice = %v, want %v", quote.OriginalPrice, tt.wantOriginal)
+			}
+			if quote.MarginFloored != tt.wantFloored {
+				t.Errorf("MarginFloored = %v, want %v", quote.MarginFloored, tt.wantFloored)
+			}
+			if applied := quote.RuleID != nil && *quote.RuleID == ruleID; applied != tt.wantRuleApply {
+				t.Errorf("RuleID = %v, want rule applied %v", quote.RuleID, tt.wantRuleApply)
+			}
+			if quote.BasePrice != tt.basePrice || quote.SalePrice != tt.salePrice {
+				t.Errorf("quote catalog prices = %v, %v, want %v, %v", quote.BasePrice, quote.SalePrice, tt.basePrice, tt.salePrice)
+			}
+		})
+	}
+}
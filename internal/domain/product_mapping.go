@@ -4,20 +4,25 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain/shared"
 )
 
 // ProductMapping represents the mapping between internal and external product IDs
 type ProductMapping struct {
-	ID                uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	ConnectionID      uuid.UUID  `gorm:"type:uuid;not null" json:"connection_id"`
-	InternalProductID uuid.UUID  `gorm:"type:uuid;not null" json:"internal_product_id"`
-	ExternalProductID string     `gorm:"type:varchar(100);not null" json:"external_product_id"`
-	ExternalSKU       string     `gorm:"type:varchar(100)" json:"external_sku"`
-	SyncStatus        string     `gorm:"type:varchar(50);default:'synced'" json:"sync_status"` // synced, pending, error
-	LastSyncedAt      *time.Time `gorm:"type:timestamptz" json:"last_synced_at"`
-	SyncError         string     `gorm:"type:text" json:"sync_error,omitempty"`
-	CreatedAt         time.Time  `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt         time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	ID                uuid.UUID            `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConnectionID      uuid.UUID            `gorm:"type:uuid;not null" json:"connection_id"`
+	InternalProductID uuid.UUID            `gorm:"type:uuid;not null" json:"internal_product_id"`
+	ExternalProductID string               `gorm:"type:varchar(100);not null" json:"external_product_id"`
+	ExternalSKU       string               `gorm:"type:varchar(100)" json:"external_sku"`
+	SyncStatus        string               `gorm:"type:varchar(50);default:'synced'" json:"sync_status"` // synced, pending, error
+	LastSyncedAt      *time.Time           `gorm:"type:timestamptz" json:"last_synced_at"`
+	SyncError         string               `gorm:"type:text" json:"sync_error,omitempty"`
+	ListingStatus     shared.ListingStatus `gorm:"type:varchar(50);default:'active'" json:"listing_status"`
+	ListingReason     string               `gorm:"type:text" json:"listing_reason,omitempty"` // Why the listing is paused or rejected
+	ListingUpdatedAt  *time.Time           `gorm:"type:timestamptz" json:"listing_updated_at"`
+	CreatedAt         time.Time            `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time            `gorm:"autoUpdateTime" json:"updated_at"`
 
 	// Relations
 	Connection      *Connection      `gorm:"foreignKey:ConnectionID" json:"connection,omitempty"`
@@ -56,10 +61,24 @@ type ProductMappingFilter struct {
 	ConnectionID      *uuid.UUID `json:"connection_id"`
 	InternalProductID *uuid.UUID `json:"internal_product_id"`
 	SyncStatus        string     `json:"sync_status"`
+	ListingStatus     string     `json:"listing_status"`
 	Page              int        `json:"page"`
 	PageSize          int        `json:"page_size"`
 }
 
+// Listing reason constants
+const (
+	ListingReasonUnlisted          = "unlisted by admin"
+	ListingReasonCatalogInactive   = "catalog product is inactive"
+	ListingReasonMarketplaceChange = "status changed on the marketplace"
+	ListingReasonDraft             = "created unlisted as a draft"
+)
+
+// ListingActionRequest represents a bulk unlist or relist request
+type ListingActionRequest struct {
+	ProductIDs []string `json:"product_ids" binding:"required,min=1"`
+}
+
 // VariantMapping represents the mapping between internal and external variant IDs
 type VariantMapping struct {
 	ID                uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SaleCampaign maps a catalog sale of a product to the marketplace discount (Shopee) or
+// promotion (TikTok Shop) publishing it on one connection, so the listing keeps its regular
+// price with a strike-through and discount badge. A new campaign is created whenever the
+// sale price or window changes; the previous one is ended. A campaign capped by the
+// marketplace's maximum duration is renewed by a successor starting when it ends.
+type SaleCampaign struct {
+	ID                 uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConnectionID       uuid.UUID  `gorm:"type:uuid;not null" json:"connection_id"`
+	InternalProductID  uuid.UUID  `gorm:"type:uuid;not null" json:"internal_product_id"`
+	ExternalProductID  string     `gorm:"type:varchar(100);not null" json:"external_product_id"`
+	ExternalDiscountID string     `gorm:"type:varchar(100)" json:"external_discount_id,omitempty"` // Shopee discount_id, TikTok promotion activity_id
+	RegularPrice       float64    `gorm:"type:decimal(12,2)" json:"regular_price"`                 // Listed price, pricing rules applied
+	SalePrice          float64    `gorm:"type:decimal(12,2)" json:"sale_price"`                    // Discounted price, pricing rules applied
+	CatalogStartsAt    *time.Time `gorm:"type:timestamptz" json:"catalog_starts_at,omitempty"`     // Catalog sale window the campaign was created for
+	CatalogEndsAt      *time.Time `gorm:"type:timestamptz" json:"catalog_ends_at,omitempty"`
+	StartsAt           *time.Time `gorm:"type:timestamptz" json:"starts_at,omitempty"` // Window on the marketplace
+	EndsAt             *time.Time `gorm:"type:timestamptz" json:"ends_at,omitempty"`
+	Status             string     `gorm:"type:varchar(20);not null" json:"status"`
+	Error              string     `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt          time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for SaleCampaign
+func (SaleCampaign) TableName() string {
+	return "marketplace.sale_campaigns"
+}
+
+// Sale campaign status constants
+const (
+	SaleCampaignActive  = "active"  // Created on the marketplace, running or upcoming
+	SaleCampaignRenewed = "renewed" // Runs until its end time, continued by a newer campaign
+	SaleCampaignEnded   = "ended"   // Ended early or past its end time
+	SaleCampaignFailed  = "failed"  // The marketplace refused the discount
+)
+
+// SaleCampaignFilter represents filter options for sale campaigns
+type SaleCampaignFilter struct {
+	Status   string `json:"status"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
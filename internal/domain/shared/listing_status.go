@@ -17,6 +17,7 @@ const (
 	ListingPaused   ListingStatus = "paused"
 	ListingRejected ListingStatus = "rejected"
 	ListingSoldOut  ListingStatus = "sold_out"
+	ListingDeleted  ListingStatus = "deleted"
 )
 
 // ErrInvalidListingStatus is returned for invalid status values.
@@ -24,13 +25,13 @@ var ErrInvalidListingStatus = errors.New("invalid listing status")
 
 // AllListingStatuses returns all valid statuses.
 func AllListingStatuses() []ListingStatus {
-	return []ListingStatus{ListingDraft, ListingPending, ListingActive, ListingPaused, ListingRejected, ListingSoldOut}
+	return []ListingStatus{ListingDraft, ListingPending, ListingActive, ListingPaused, ListingRejected, ListingSoldOut, ListingDeleted}
 }
 
 // IsValid returns true if the status is valid.
 func (s ListingStatus) IsValid() bool {
 	switch s {
-	case ListingDraft, ListingPending, ListingActive, ListingPaused, ListingRejected, ListingSoldOut:
+	case ListingDraft, ListingPending, ListingActive, ListingPaused, ListingRejected, ListingSoldOut, ListingDeleted:
 		return true
 	default:
 		return false
@@ -43,6 +43,9 @@ const (
 	CodeNotFound            ErrorCode = "error_not_found"
 	CodeProductBanned       ErrorCode = "error_product_banned"
 	CodeOrderCancelled      ErrorCode = "error_order_cancelled"
+
+	// Listing errors
+	CodeExtendedDescriptionNotAllowed ErrorCode = "product.error_extended_description_not_allowed" // Shop is not eligible for extended descriptions
 )
 
 // String returns the string representation of the error code.
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockAllocationRule defines how much of the available stock is published to a connection.
+// A rule without InternalCategoryID is the connection default; category rules override it.
+type StockAllocationRule struct {
+	ID                 uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConnectionID       uuid.UUID  `gorm:"type:uuid;not null" json:"connection_id"`
+	InternalCategoryID *uuid.UUID `gorm:"type:uuid" json:"internal_category_id,omitempty"`
+	BufferQuantity     int        `gorm:"not null;default:0" json:"buffer_quantity"`            // Units held back from the channel
+	AllocationPercent  float64    `gorm:"type:decimal(7,4);not null" json:"allocation_percent"` // Share of the stock left after the buffer
+	MaxQuantity        *int       `json:"max_quantity,omitempty"`                               // Cap per channel, nil for none
+	SoldOutThreshold   int        `gorm:"not null;default:0" json:"sold_out_threshold"`         // Publish 0 when available stock is below this
+	IsActive           bool       `gorm:"not null" json:"is_active"`
+	CreatedAt          time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	Connection *Connection `gorm:"foreignKey:ConnectionID" json:"connection,omitempty"`
+}
+
+// TableName specifies the table name for StockAllocationRule
+func (StockAllocationRule) TableName() string {
+	return "marketplace.stock_allocation_rules"
+}
+
+// StockAllocation is the result of applying a stock allocation rule to available stock
+type StockAllocation struct {
+	Available int        `json:"available"`
+	Quantity  int        `json:"quantity"` // Stock published on the marketplace
+	RuleID    *uuid.UUID `json:"rule_id,omitempty"`
+	Capped    bool       `json:"capped"`   // Whether the max quantity lowered the stock
+	SoldOut   bool       `json:"sold_out"` // Whether the sold-out threshold zeroed the stock
+}
+
+// SaveStockAllocationRuleRequest represents a request to create or replace a stock allocation rule
+type SaveStockAllocationRuleRequest struct {
+	InternalCategoryID *uuid.UUID `json:"internal_category_id"`
+	BufferQuantity     int        `json:"buffer_quantity" binding:"gte=0"`
+	AllocationPercent  *float64   `json:"allocation_percent" binding:"omitempty,gte=0,lte=100"`
+	MaxQuantity        *int       `json:"max_quantity" binding:"omitempty,gte=0"`
+	SoldOutThreshold   int        `json:"sold_out_threshold" binding:"gte=0"`
+	IsActive           *bool      `json:"is_active"`
+}
+
+// Allocate computes the stock to publish from the available stock. Stock below the sold-out
+// threshold publishes nothing; otherwise the buffer is held back, the allocation percentage
+// is taken, rounded down, and the result is capped at the max quantity.
+// A nil rule publishes all available stock.
+func (r *StockAllocationRule) Allocate(available int) *StockAllocation {
+	allocation := &StockAllocation{
+		Available: available,
+		Quantity:  max(available, 0),
+	}
+	if r == nil || !r.IsActive {
+		return allocation
+	}
+
+	ruleID := r.ID
+	allocation.RuleID = &ruleID
+
+	if available < r.SoldOutThreshold {
+		allocation.Quantity = 0
+		allocation.SoldOut = true
+		return allocation
+	}
+
+	quantity := max(available-r.BufferQuantity, 0)
+	if r.AllocationPercent < 100 {
+		quantity = int(math.Floor(float64(quantity) * r.AllocationPercent / 100))
+	}
+	if r.MaxQuantity != nil && quantity > *r.MaxQuantity {
+		quantity = *r.MaxQuantity
+		allocation.Capped = true
+	}
+
+	allocation.Quantity = quantity
+	return allocation
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestStockAllocationRuleAllocate(t *testing.T) {
+	ruleID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	quantity := func(q int) *int { return &q }
+	rule := func(r StockAllocationRule) *StockAllocationRule {
+		r.ID = ruleID
+		r.IsActive = true
+		return &r
+	}
+
+	tests := []struct {
+		name         string
+		rule         *StockAllocationRule
+		available    int
+		wantQuantity int
+		wantCapped   bool
+		wantSoldOut  bool
+		wantRule     bool
+	}{
+		{
+			name:         "no rule publishes all stock",
+			available:    10,
+			wantQuantity: 10,
+		},
+		{
+			name:         "no rule never publishes negative stock",
+			available:    -3,
+			wantQuantity: 0,
+		},
+		{
+			name:         "inactive rule is ignored",
+			rule:         &StockAllocationRule{ID: ruleID, AllocationPercent: 50},
+			available:    10,
+			wantQuantity: 10,
+		},
+		{
+			name:         "full allocation",
+			rule:         rule(StockAllocationRule{AllocationPercent: 100}),
+			available:    10,
+			wantQuantity: 10,
+			wantRule:     true,
+		},
+		{
+			name:         "buffer is held back",
+			rule:         rule(StockAllocationRule{BufferQuantity: 3, AllocationPercent: 100}),
+			available:    10,
+			wantQuantity: 7,
+			wantRule:     true,
+		},
+		{
+			name:         "buffer larger than the stock publishes nothing",
+			rule:         rule(StockAllocationRule{BufferQuantity: 15, AllocationPercent: 100}),
+			available:    10,
+			wantQuantity: 0,
+			wantRule:     true,
+		},
+		{
+			name:         "percentage of the stock after the buffer, rounded down",
+			rule:         rule(StockAllocationRule{BufferQuantity: 2, AllocationPercent: 50}),
+			available:    9,
+			wantQuantity: 3,
+			wantRule:     true,
+		},
+		{
+			name:         "zero percent publishes nothing",
+			rule:         rule(StockAllocationRule{AllocationPercent: 0}),
+			available:    10,
+			wantQuantity: 0,
+			wantRule:     true,
+		},
+		{
+			name:         "max quantity caps the stock",
+			rule:         rule(StockAllocationRule{AllocationPercent: 100, MaxQuantity: quantity(5)}),
+			available:    10,
+			wantQuantity: 5,
+			wantCapped:   true,
+			wantRule:     true,
+		},
+		{
+			name:         "max quantity above the stock does not cap",
+			rule:         rule(StockAllocationRule{AllocationPercent: 100, MaxQuantity: quantity(20)}),
+			available:    10,
+			wantQuantity: 10,
+			wantRule:     true,
+		},
+		{
+			name:         "below the sold-out threshold",
+			rule:         rule(StockAllocationRule{AllocationPercent: 100, SoldOutThreshold: 5}),
+			available:    4,
+			wantQuantity: 0,
+			wantSoldOut:  true,
+			wantRule:     true,
+		},
+		{
+			name:         "at the sold-out threshold",
+			rule:         rule(StockAllocationRule{AllocationPercent: 100, SoldOutThreshold: 5}),
+			available:    5,
+			wantQuantity: 5,
+			wantRule:     true,
+		},
+		{
+			name:         "buffer, percentage and cap combined",
+			rule:         rule(StockAllocationRule{BufferQuantity: 10, AllocationPercent: 75, MaxQuantity: quantity(50), SoldOutThreshold: 2}),
+			available:    110,
+			wantQuantity: 50,
+			wantCapped:   true,
+			wantRule:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocation := tt.rule.Allocate(tt.available)
+			if allocation.Available != tt.available {
+				t.Errorf("Available = %d, want %d", allocation.Available, tt.available)
+			}
+			if allocation.Quantity != tt.wantQuantity {
+				t.Errorf("Quantity = %d, want %d", allocation.Quantity, tt.wantQuantity)
+			}
+			if allocation.Capped != tt.wantCapped {
+				t.Errorf("Capped = %v, want %v", allocation.Capped, tt.wantCapped)
+			}
+			if allocation.SoldOut != tt.wantSoldOut {
+				t.Errorf("SoldOut = %v, want %v", allocation.SoldOut, tt.wantSoldOut)
+			}
+			if applied := allocation.RuleID != nil && *allocation.RuleID == ruleID; applied != tt.wantRule {
+				t.Errorf("RuleID = %v, want rule applied %v", allocation.RuleID, tt.wantRule)
+			}
+		})
+	}
+}
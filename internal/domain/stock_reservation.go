@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockReservation holds back stock sold on one marketplace from every channel until the
+// inventory service reports stock that accounts for the order
+type StockReservation struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConnectionID      uuid.UUID  `gorm:"type:uuid;not null" json:"connection_id"` // Connection the order was placed on
+	ExternalOrderID   string     `gorm:"type:varchar(100);not null" json:"external_order_id"`
+	InternalProductID uuid.UUID  `gorm:"type:uuid;not null" json:"internal_product_id"`
+	ProductMappingID  uuid.UUID  `gorm:"type:uuid;not null" json:"product_mapping_id"`
+	Quantity          int        `gorm:"not null" json:"quantity"`
+	Status            string     `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	ReleaseReason     string     `gorm:"type:varchar(30)" json:"release_reason,omitempty"`
+	ReleasedAt        *time.Time `json:"released_at,omitempty"`
+	CreatedAt         time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for StockReservation
+func (StockReservation) TableName() string {
+	return "marketplace.stock_reservations"
+}
+
+// Stock reservation status constants
+const (
+	StockReservationPending  = "pending"
+	StockReservationReleased = "released"
+)
+
+// Stock reservation release reasons
+const (
+	ReservationReleaseStockEvent = "stock_event" // A stock event accounted for the order
+	ReservationReleaseCancelled  = "cancelled"   // The order was cancelled
+)
@@ -9,18 +9,21 @@ import (
 
 // SyncJob represents a background sync job in the queue
 type SyncJob struct {
-	ID           uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	ConnectionID uuid.UUID      `gorm:"type:uuid" json:"connection_id"`
-	JobType      string         `gorm:"type:varchar(50);not null" json:"job_type"` // product_push, inventory_sync, order_sync
-	Payload      datatypes.JSON `gorm:"type:jsonb;not null" json:"payload"`
-	Status       string         `gorm:"type:varchar(50);default:'pending'" json:"status"` // pending, processing, completed, failed
-	Attempts     int            `gorm:"default:0" json:"attempts"`
-	MaxAttempts  int            `gorm:"default:3" json:"max_attempts"`
-	ErrorMessage string         `gorm:"type:text" json:"error_message,omitempty"`
-	ScheduledAt  time.Time      `gorm:"type:timestamptz;default:CURRENT_TIMESTAMP" json:"scheduled_at"`
-	StartedAt    *time.Time     `gorm:"type:timestamptz" json:"started_at"`
-	CompletedAt  *time.Time     `gorm:"type:timestamptz" json:"completed_at"`
-	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	ID             uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConnectionID   uuid.UUID      `gorm:"type:uuid" json:"connection_id"`
+	JobType        string         `gorm:"type:varchar(50);not null" json:"job_type"` // product_push, inventory_sync, order_sync
+	Payload        datatypes.JSON `gorm:"type:jsonb;not null" json:"payload"`
+	Status         string         `gorm:"type:varchar(50);default:'pending'" json:"status"` // pending, processing, completed, failed
+	Attempts       int            `gorm:"default:0" json:"attempts"`
+	MaxAttempts    int            `gorm:"default:3" json:"max_attempts"`
+	TotalItems     int            `gorm:"default:0" json:"total_items"`
+	ProcessedItems int            `gorm:"default:0" json:"processed_items"`
+	FailedItems    int            `gorm:"default:0" json:"failed_items"`
+	ErrorMessage   string         `gorm:"type:text" json:"error_message,omitempty"`
+	ScheduledAt    time.Time      `gorm:"type:timestamptz;default:CURRENT_TIMESTAMP" json:"scheduled_at"`
+	StartedAt      *time.Time     `gorm:"type:timestamptz" json:"started_at"`
+	CompletedAt    *time.Time     `gorm:"type:timestamptz" json:"completed_at"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
 
 	// Relations
 	Connection *Connection `gorm:"foreignKey:ConnectionID" json:"connection,omitempty"`
@@ -33,11 +36,16 @@ func (SyncJob) TableName() string {
 
 // Job type constants
 const (
-	JobTypeProductPush   = "product_push"
-	JobTypeProductUpdate = "product_update"
-	JobTypeInventorySync = "inventory_sync"
-	JobTypeOrderSync     = "order_sync"
-	JobTypeTokenRefresh  = "token_refresh"
+	JobTypeProductPush        = "product_push"
+	JobTypeProductUpdate      = "product_update"
+	JobTypeProductImport      = "product_import"
+	JobTypeMappingImport      = "mapping_import"
+	JobTypeDriftCheck         = "drift_check"
+	JobTypeInventorySync      = "inventory_sync"
+	JobTypeInventoryReconcile = "inventory_reconcile"
+	JobTypeOrderSync          = "order_sync"
+	JobTypeOrderBackfill      = "order_backfill"
+	JobTypeTokenRefresh       = "token_refresh"
 )
 
 // Job status constants
@@ -48,10 +56,44 @@ const (
 	JobStatusFailed     = "failed"
 )
 
-// ProductPushPayload represents the payload for a product push job
+// ProductPushPayload represents the payload and results of a product push job
 type ProductPushPayload struct {
-	InternalProductIDs []uuid.UUID `json:"internal_product_ids"`
-	CategoryMappingID  uuid.UUID   `json:"category_mapping_id"`
+	ProductIDs []string            `json:"product_ids"`
+	PushAll    bool                `json:"push_all"`
+	AsDraft    bool                `json:"as_draft,omitempty"` // Create listings unpublished
+	Trigger    string              `json:"trigger,omitempty"`  // manual, auto_listing
+	Results    []ProductPushResult `json:"results,omitempty"`
+}
+
+// ProductPushResult is the outcome of pushing one product
+type ProductPushResult struct {
+	ProductID         string `json:"product_id"`
+	ExternalProductID string `json:"external_product_id,omitempty"`
+	Error             string `json:"error,omitempty"`
+}
+
+// Push job trigger constants
+const (
+	PushTriggerManual      = "manual"
+	PushTriggerAutoListing = "auto_listing"
+)
+
+// ProductImportPayload represents the payload and resumable state of a product import job
+type ProductImportPayload struct {
+	Status       string    `json:"status"`                  // Marketplace listing status to import, e.g. NORMAL or LIVE
+	Incremental  bool      `json:"incremental"`             // Only fetch details of listings changed since UpdatedSince
+	UpdatedSince int64     `json:"updated_since,omitempty"` // Unix time of the previous completed import
+	RunStartedAt time.Time `json:"run_started_at"`          // Listings not seen since then are marked removed
+	Cursor       int       `json:"cursor"`                  // Next page offset (Shopee) or page number (TikTok)
+	Fetched      int       `json:"fetched"`                 // Listings whose details were (re)imported
+	Unchanged    int       `json:"unchanged"`               // Listings skipped because they did not change
+	Removed      int       `json:"removed"`                 // Listings marked removed at the end of the run
+}
+
+// StartProductImportRequest represents a request to start a product import
+type StartProductImportRequest struct {
+	Status string `json:"status"` // Defaults to NORMAL (Shopee) or LIVE (TikTok)
+	Full   bool   `json:"full"`   // Re-fetch every listing instead of only changed ones
 }
 
 // InventorySyncPayload represents the payload for an inventory sync job
@@ -67,6 +109,29 @@ type OrderSyncPayload struct {
 	Action          string `json:"action"` // fetch, import, update_status
 }
 
+// OrderBackfillPayload represents the payload and resumable state of an order backfill job
+type OrderBackfillPayload struct {
+	TimeFrom         time.Time `json:"time_from"`          // Orders created from
+	TimeTo           time.Time `json:"time_to"`            // Orders created before
+	SkipOrderService bool      `json:"skip_order_service"` // Import without creating orders in service-order
+	WindowFrom       time.Time `json:"window_from"`        // Start of the window being imported
+	Cursor           string    `json:"cursor,omitempty"`   // Next page of the window being imported
+	Imported         int       `json:"imported"`           // Orders imported so far
+}
+
+// MappingImportPayload represents the payload and outcome of a product mapping CSV import job
+type MappingImportPayload struct {
+	CSV    string               `json:"csv,omitempty"`    // Uploaded file; dropped once the import has run
+	Report *MappingImportReport `json:"report,omitempty"` // Set once the import has run
+}
+
+// StartOrderBackfillRequest represents a request to import a connection's order history
+type StartOrderBackfillRequest struct {
+	TimeFrom         time.Time  `json:"time_from" binding:"required"`
+	TimeTo           *time.Time `json:"time_to"`            // Defaults to now
+	SkipOrderService bool       `json:"skip_order_service"` // Keep historical orders out of fulfilment
+}
+
 // SyncJobFilter represents filter options for sync jobs
 type SyncJobFilter struct {
 	ConnectionID *uuid.UUID `json:"connection_id"`
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WarehouseMapping maps an internal warehouse to a marketplace warehouse of a connection:
+// a Shopee seller_stock location_id or a TikTok Shop warehouse_id
+type WarehouseMapping struct {
+	ID                  uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ConnectionID        uuid.UUID `gorm:"type:uuid;not null" json:"connection_id"`
+	InternalWarehouseID string    `gorm:"type:varchar(100);not null" json:"internal_warehouse_id"`
+	ExternalWarehouseID string    `gorm:"type:varchar(100);not null" json:"external_warehouse_id"`
+	IsDefault           bool      `gorm:"not null" json:"is_default"` // Receives the stock of unmapped warehouses
+	CreatedAt           time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for WarehouseMapping
+func (WarehouseMapping) TableName() string {
+	return "marketplace.warehouse_mappings"
+}
+
+// WarehouseStockLevel is the last known stock of a product in an internal warehouse,
+// recorded from stock.changed events that carry a warehouse
+type WarehouseStockLevel struct {
+	InternalProductID uuid.UUID `gorm:"type:uuid;primaryKey" json:"internal_product_id"`
+	WarehouseID       string    `gorm:"type:varchar(100);primaryKey" json:"warehouse_id"`
+	Quantity          int       `gorm:"not null" json:"quantity"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName specifies the table name for WarehouseStockLevel
+func (WarehouseStockLevel) TableName() string {
+	return "marketplace.warehouse_stock_levels"
+}
+
+// Unmapped warehouse policy constants
+const (
+	UnmappedWarehousesDefault = "default" // Stock of unmapped warehouses is added to the default warehouse
+	UnmappedWarehousesIgnore  = "ignore"  // Stock of unmapped warehouses is not sold on the marketplace
+)
+
+// ConnectionSettings are the settings of a connection, stored in Connection.Settings
+type ConnectionSettings struct {
+	UnmappedWarehouses string `json:"unmapped_warehouses,omitempty"` // default, ignore
+}
+
+// GetSettings decodes the settings of a connection
+func (c *Connection) GetSettings() ConnectionSettings {
+	var settings ConnectionSettings
+	if len(c.Settings) > 0 {
+		_ = json.Unmarshal(c.Settings, &settings)
+	}
+	if settings.UnmappedWarehouses == "" {
+		settings.UnmappedWarehouses = UnmappedWarehousesDefault
+	}
+	return settings
+}
+
+// WarehouseMappingsResponse is the warehouse configuration of a connection
+type WarehouseMappingsResponse struct {
+	UnmappedPolicy string             `json:"unmapped_policy"`
+	Mappings       []WarehouseMapping `json:"mappings"`
+}
+
+// SaveWarehouseMappingsRequest represents a request to replace the warehouse mappings of a connection
+type SaveWarehouseMappingsRequest struct {
+	UnmappedPolicy string `json:"unmapped_policy" binding:"omitempty,oneof=default ignore"`
+	Mappings       []struct {
+		InternalWarehouseID string `json:"internal_warehouse_id" binding:"required"`
+		ExternalWarehouseID string `json:"external_warehouse_id" binding:"required"`
+		IsDefault           bool   `json:"is_default"`
+	} `json:"mappings" binding:"dive"`
+}
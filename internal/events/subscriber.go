@@ -11,9 +11,10 @@ import (
 
 // Event subjects
 const (
-	SubjectInventoryStockChanged = "inventory.stock.changed"
-	SubjectMarketplaceSyncOK     = "marketplace.sync.completed"
-	SubjectMarketplaceSyncFailed = "marketplace.sync.failed"
+	SubjectInventoryStockChanged    = "inventory.stock.changed"
+	SubjectMarketplaceSyncOK        = "marketplace.sync.completed"
+	SubjectMarketplaceSyncFailed    = "marketplace.sync.failed"
+	SubjectMarketplaceStockReserved = "marketplace.stock.reserved"
 
 	// Catalog events - subscribe to product changes for auto-sync
 	SubjectProductCreated = "product.created"
@@ -21,6 +22,9 @@ const (
 	SubjectProductDeleted = "product.deleted"
 )
 
+// StockReasonSale is the reason of a stock change caused by an order
+const StockReasonSale = "sale"
+
 // StockChangedEvent represents an inventory change event
 type StockChangedEvent struct {
 	ProductID   uuid.UUID  `json:"product_id"`
@@ -31,6 +35,20 @@ type StockChangedEvent struct {
 	WarehouseID string     `json:"warehouse_id,omitempty"`
 	Reason      string     `json:"reason"` // sale, adjustment, return, etc.
 	Timestamp   time.Time  `json:"timestamp"`
+
+	// Reservations from marketplace.stock.reserved the change accounts for
+	ReservationIDs []uuid.UUID `json:"reservation_ids,omitempty"`
+}
+
+// ProductCreatedEvent represents a product creation from catalog service
+type ProductCreatedEvent struct {
+	ProductID  string    `json:"product_id"`
+	SKU        string    `json:"sku"`
+	Name       string    `json:"name"`
+	CategoryID string    `json:"category_id"`
+	BasePrice  float64   `json:"base_price"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 // ProductUpdatedEvent represents a product update from catalog service
@@ -69,6 +87,24 @@ type SyncFailedEvent struct {
 	Timestamp    time.Time `json:"timestamp"`
 }
 
+// StockReservedEvent reports stock sold by a paid marketplace order, held back from every
+// channel until the inventory service accounts for it
+type StockReservedEvent struct {
+	ConnectionID    uuid.UUID              `json:"connection_id"`
+	Platform        string                 `json:"platform"`
+	ExternalOrderID string                 `json:"external_order_id"`
+	Items           []StockReservationItem `json:"items"`
+	Timestamp       time.Time              `json:"timestamp"`
+}
+
+// StockReservationItem is the reserved stock of one product
+type StockReservationItem struct {
+	ReservationID uuid.UUID `json:"reservation_id"`
+	ProductID     uuid.UUID `json:"product_id"`
+	SKU           string    `json:"sku"`
+	Quantity      int       `json:"quantity"`
+}
+
 // Subscriber handles NATS event subscriptions
 type Subscriber struct {
 	nc      *nats.Conn
@@ -80,6 +116,7 @@ type Subscriber struct {
 // EventHandler defines the interface for handling events
 type EventHandler interface {
 	HandleStockChanged(event *StockChangedEvent) error
+	HandleProductCreated(event *ProductCreatedEvent) error
 	HandleProductUpdated(event *ProductUpdatedEvent) error
 	HandleProductDeleted(event *ProductDeletedEvent) error
 }
@@ -104,6 +141,14 @@ func (s *Subscriber) Start() error {
 	s.subs = append(s.subs, sub)
 	s.logger.Info("Subscribed to event", zap.String("subject", SubjectInventoryStockChanged))
 
+	// Subscribe to product creations for auto-listing on opted-in connections
+	sub, err = s.nc.Subscribe(SubjectProductCreated, s.handleProductCreated)
+	if err != nil {
+		return err
+	}
+	s.subs = append(s.subs, sub)
+	s.logger.Info("Subscribed to event", zap.String("subject", SubjectProductCreated))
+
 	// Subscribe to product updates for auto-sync to marketplaces
 	sub, err = s.nc.Subscribe(SubjectProductUpdated, s.handleProductUpdated)
 	if err != nil {
@@ -150,6 +195,27 @@ func (s *Subscriber) handleStockChanged(msg *nats.Msg) {
 	}
 }
 
+// handleProductCreated processes product created events
+func (s *Subscriber) handleProductCreated(msg *nats.Msg) {
+	var event ProductCreatedEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		s.logger.Error("Failed to unmarshal product created event", zap.Error(err))
+		return
+	}
+
+	s.logger.Info("Received product created event",
+		zap.String("product_id", event.ProductID),
+		zap.String("name", event.Name),
+	)
+
+	if err := s.handler.HandleProductCreated(&event); err != nil {
+		s.logger.Error("Failed to handle product created event",
+			zap.String("product_id", event.ProductID),
+			zap.Error(err),
+		)
+	}
+}
+
 // handleProductUpdated processes product updated events
 func (s *Subscriber) handleProductUpdated(msg *nats.Msg) {
 	var event ProductUpdatedEvent
@@ -220,3 +286,12 @@ func (p *Publisher) PublishSyncFailed(event *SyncFailedEvent) error {
 	}
 	return p.nc.Publish(SubjectMarketplaceSyncFailed, data)
 }
+
+// PublishStockReserved publishes a stock reserved event
+func (p *Publisher) PublishStockReserved(event *StockReservedEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return p.nc.Publish(SubjectMarketplaceStockReserved, data)
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/application"
+)
+
+// BrandHandler handles brand mapping API requests
+type BrandHandler struct {
+	service *services.ProductSyncService
+	logger  *zap.Logger
+}
+
+// NewBrandHandler creates a new BrandHandler
+func NewBrandHandler(service *services.ProductSyncService, logger *zap.Logger) *BrandHandler {
+	return &BrandHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetExternalBrands fetches brands from the marketplace for a category
+// GET /api/v1/admin/marketplace/connections/:id/brands/external?category_id=
+func (h *BrandHandler) GetExternalBrands(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	brands, err := h.service.GetExternalBrands(c.Request.Context(), connectionID, c.Query("category_id"))
+	if err != nil {
+		if errors.Is(err, services.ErrBrandCategoryRequired) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to get external brands", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"brands": brands,
+		"total":  len(brands),
+	})
+}
+
+// GetBrandMappings lists brand mappings for a connection
+// GET /api/v1/admin/marketplace/connections/:id/brands
+func (h *BrandHandler) GetBrandMappings(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	filter := &domain.BrandMappingFilter{
+		Status:   c.Query("status"),
+		Search:   c.Query("search"),
+		Page:     1,
+		PageSize: 20,
+	}
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+			filter.Page = page
+		}
+	}
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 {
+			filter.PageSize = pageSize
+		}
+	}
+
+	mappings, total, err := h.service.GetBrandMappings(c.Request.Context(), connectionID, filter)
+	if err != nil {
+		h.logger.Error("Failed to get brand mappings", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get brand mappings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mappings": mappings,
+		"total":    total,
+		"page":     filter.Page,
+		"pageSize": filter.PageSize,
+	})
+}
+
+// SuggestBrandMappings fuzzy-matches internal brands against marketplace brands
+// POST /api/v1/admin/marketplace/connections/:id/brands/suggest
+func (h *BrandHandler) SuggestBrandMappings(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	var req domain.SuggestBrandMappingsRequest
+	_ = c.ShouldBindJSON(&req) // Empty body suggests for all catalog brands
+
+	suggestions, err := h.service.SuggestBrandMappings(c.Request.Context(), connectionID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrBrandCategoryRequired) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to suggest brand mappings", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"suggestions": suggestions,
+		"total":       len(suggestions),
+	})
+}
+
+// SetBrandMapping creates or overrides a brand mapping
+// POST /api/v1/admin/marketplace/connections/:id/brands
+func (h *BrandHandler) SetBrandMapping(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	var req domain.SetBrandMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	mapping, err := h.service.SetBrandMapping(c.Request.Context(), connectionID, &req)
+	if err != nil {
+		h.logger.Error("Failed to set brand mapping", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Brand mapping saved",
+		"mapping": mapping,
+	})
+}
+
+// ConfirmBrandMapping confirms a suggested brand mapping
+// POST /api/v1/admin/marketplace/connections/:id/brands/:mapping_id/confirm
+func (h *BrandHandler) ConfirmBrandMapping(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	mappingID, err := uuid.Parse(c.Param("mapping_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mapping ID"})
+		return
+	}
+
+	mapping, err := h.service.ConfirmBrandMapping(c.Request.Context(), connectionID, mappingID)
+	if err != nil {
+		if errors.Is(err, services.ErrBrandMappingNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to confirm brand mapping", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Brand mapping confirmed",
+		"mapping": mapping,
+	})
+}
+
+// DeleteBrandMapping deletes a brand mapping
+// DELETE /api/v1/admin/marketplace/connections/:id/brands/:mapping_id
+func (h *BrandHandler) DeleteBrandMapping(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	mappingID, err := uuid.Parse(c.Param("mapping_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mapping ID"})
+		return
+	}
+
+	if err := h.service.DeleteBrandMapping(c.Request.Context(), connectionID, mappingID); err != nil {
+		if errors.Is(err, services.ErrBrandMappingNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to delete brand mapping", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Brand mapping deleted"})
+}
@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -25,8 +29,8 @@ func NewCategoryHandler(service *services.ProductSyncService, logger *zap.Logger
 	}
 }
 
-// GetExternalCategories fetches categories from the marketplace
-// GET /api/v1/admin/marketplace/connections/:id/categories/external
+// GetExternalCategories returns the cached marketplace category tree
+// GET /api/v1/admin/marketplace/connections/:id/categories/external?leaf_only=
 func (h *CategoryHandler) GetExternalCategories(c *gin.Context) {
 	connectionID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -34,8 +38,14 @@ func (h *CategoryHandler) GetExternalCategories(c *gin.Context) {
 		return
 	}
 
-	categories, err := h.service.GetExternalCategories(c.Request.Context(), connectionID)
+	leafOnly := c.Query("leaf_only") == "true"
+
+	categories, err := h.service.GetExternalCategories(c.Request.Context(), connectionID, leafOnly)
 	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("Failed to get external categories", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -47,6 +57,100 @@ func (h *CategoryHandler) GetExternalCategories(c *gin.Context) {
 	})
 }
 
+// BrowseExternalCategories lists the children of a marketplace category (root categories without parent_id)
+// GET /api/v1/admin/marketplace/connections/:id/categories/external/children?parent_id=&leaf_only=
+func (h *CategoryHandler) BrowseExternalCategories(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	parentID := c.Query("parent_id")
+	leafOnly := c.Query("leaf_only") == "true"
+
+	categories, err := h.service.BrowseExternalCategories(c.Request.Context(), connectionID, parentID, leafOnly)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to browse external categories", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"parent_id":  parentID,
+		"categories": categories,
+		"total":      len(categories),
+	})
+}
+
+// SearchExternalCategories searches marketplace categories by name and breadcrumb
+// GET /api/v1/admin/marketplace/connections/:id/categories/external/search?q=&leaf_only=&page=&page_size=
+func (h *CategoryHandler) SearchExternalCategories(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	filter := &domain.ExternalCategoryFilter{
+		Query:    c.Query("q"),
+		LeafOnly: c.Query("leaf_only") == "true",
+		Page:     1,
+		PageSize: 20,
+	}
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		filter.Page = page
+	}
+	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 {
+		filter.PageSize = pageSize
+	}
+
+	categories, total, err := h.service.SearchExternalCategories(c.Request.Context(), connectionID, filter)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to search external categories", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"categories": categories,
+		"total":      total,
+		"page":       filter.Page,
+		"page_size":  filter.PageSize,
+	})
+}
+
+// RefreshExternalCategories re-fetches the marketplace category tree and flags stale mappings
+// POST /api/v1/admin/marketplace/connections/:id/categories/external/refresh
+func (h *CategoryHandler) RefreshExternalCategories(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	result, err := h.service.RefreshExternalCategories(c.Request.Context(), connectionID)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to refresh external categories", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // GetCategoryMappings lists category mappings for a connection
 // GET /api/v1/admin/marketplace/connections/:id/categories
 func (h *CategoryHandler) GetCategoryMappings(c *gin.Context) {
@@ -69,11 +173,47 @@ func (h *CategoryHandler) GetCategoryMappings(c *gin.Context) {
 	})
 }
 
+// GetEffectiveCategoryMappings shows the mapping that applies to every internal category,
+// including mappings inherited from parent categories
+// GET /api/v1/admin/marketplace/connections/:id/categories/effective
+func (h *CategoryHandler) GetEffectiveCategoryMappings(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	mappings, err := h.service.GetEffectiveCategoryMappings(c.Request.Context(), connectionID)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to get effective category mappings", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	unmapped := 0
+	for _, m := range mappings {
+		if m.Source == services.CategoryMappingNone {
+			unmapped++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mappings": mappings,
+		"total":    len(mappings),
+		"unmapped": unmapped,
+	})
+}
+
 // CreateCategoryMappingRequest represents the request to create a mapping
 type CreateCategoryMappingRequest struct {
 	InternalCategoryID   string `json:"internal_category_id" binding:"required"`
 	ExternalCategoryID   string `json:"external_category_id" binding:"required"`
 	ExternalCategoryName string `json:"external_category_name"`
+	Inherit              *bool  `json:"inherit"` // Apply to subcategories without their own mapping (default true)
 }
 
 // CreateCategoryMapping creates a new category mapping
@@ -101,6 +241,7 @@ func (h *CategoryHandler) CreateCategoryMapping(c *gin.Context) {
 		InternalCategoryID:   internalCatID,
 		ExternalCategoryID:   req.ExternalCategoryID,
 		ExternalCategoryName: req.ExternalCategoryName,
+		Inherit:              req.Inherit,
 	})
 	if err != nil {
 		h.logger.Error("Failed to create category mapping", zap.Error(err))
@@ -114,6 +255,104 @@ func (h *CategoryHandler) CreateCategoryMapping(c *gin.Context) {
 	})
 }
 
+// GetCategorySuggestions ranks marketplace categories for each internal category
+// GET /api/v1/admin/marketplace/connections/:id/categories/suggestions?limit=&include_mapped=
+func (h *CategoryHandler) GetCategorySuggestions(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	opts := services.CategorySuggestionOptions{
+		IncludeMapped: c.Query("include_mapped") == "true",
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			opts.Limit = limit
+		}
+	}
+
+	suggestions, err := h.service.SuggestCategoryMappings(c.Request.Context(), connectionID, opts)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to suggest category mappings", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"categories": suggestions,
+		"total":      len(suggestions),
+	})
+}
+
+// AcceptCategorySuggestions creates mappings from chosen suggestions
+// POST /api/v1/admin/marketplace/connections/:id/categories/suggestions/accept
+func (h *CategoryHandler) AcceptCategorySuggestions(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	var req domain.AcceptCategorySuggestionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.AcceptCategorySuggestions(c.Request.Context(), connectionID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to accept category suggestions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExportCategoryMappings downloads the category mappings of a connection as CSV
+// GET /api/v1/admin/marketplace/connections/:id/categories/export
+func (h *CategoryHandler) ExportCategoryMappings(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	writeCSVExport(c, h.logger, fmt.Sprintf("category-mappings-%s.csv", connectionID), func(w io.Writer) error {
+		return h.service.ExportCategoryMappings(c.Request.Context(), connectionID, w)
+	})
+}
+
+// ImportCategoryMappings validates and applies a category mapping CSV, sent as the "file" form
+// field or as the request body. With ?dry_run=true the file is only validated.
+// POST /api/v1/admin/marketplace/connections/:id/categories/import
+func (h *CategoryHandler) ImportCategoryMappings(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	file, ok := mappingCSVUpload(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	report, err := h.service.ImportCategoryMappings(c.Request.Context(), connectionID, file, c.Query("dry_run") == "true")
+	respondMappingImport(c, h.logger, report, err)
+}
+
 // DeleteCategoryMapping deletes a category mapping
 // DELETE /api/v1/admin/marketplace/connections/:id/categories/:mapping_id
 func (h *CategoryHandler) DeleteCategoryMapping(c *gin.Context) {
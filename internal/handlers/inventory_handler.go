@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
 	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
 	"github.com/Ecom-micro-template/service-marketplace/internal/application"
 )
@@ -112,3 +116,243 @@ func (h *InventoryHandler) GetInventoryStatus(c *gin.Context) {
 		"total":     len(items),
 	})
 }
+
+// GetWarehouseMappings returns the warehouse mappings of a connection
+// GET /api/v1/admin/marketplace/connections/:id/warehouses
+func (h *InventoryHandler) GetWarehouseMappings(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	resp, err := h.service.GetWarehouseMappings(c.Request.Context(), connectionID)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to get warehouse mappings", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// SaveWarehouseMappings replaces the warehouse mappings of a connection
+// PUT /api/v1/admin/marketplace/connections/:id/warehouses
+func (h *InventoryHandler) SaveWarehouseMappings(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	var req domain.SaveWarehouseMappingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.service.SaveWarehouseMappings(c.Request.Context(), connectionID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrConnectionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidWarehouseMapping):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to save warehouse mappings", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// StartReconciliation starts comparing the marketplace stock of every mapped listing with the inventory service
+// POST /api/v1/admin/marketplace/connections/:id/inventory/reconcile
+func (h *InventoryHandler) StartReconciliation(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	// The body is optional
+	var req domain.StartInventoryReconcileRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+
+	job, err := h.service.StartReconciliation(c.Request.Context(), connectionID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrConnectionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrReconcileInProgress):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "job": job})
+		case errors.Is(err, services.ErrInvalidPlatform):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to start inventory reconciliation", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Inventory reconciliation started",
+		"job":     job,
+	})
+}
+
+// GetReconcileJob returns the progress of an inventory reconciliation job
+// GET /api/v1/admin/marketplace/connections/:id/inventory/reconcile/jobs/:job_id
+func (h *InventoryHandler) GetReconcileJob(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("job_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := h.service.GetReconcileJob(c.Request.Context(), connectionID, jobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// GetInventoryDiscrepancies lists the per-SKU stock reports of a connection: expected and
+// marketplace stock, and the last correction pushed
+// GET /api/v1/admin/marketplace/connections/:id/inventory/discrepancies?has_discrepancy=&page=&page_size=
+func (h *InventoryHandler) GetInventoryDiscrepancies(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	filter := &domain.InventoryDiscrepancyFilter{}
+	if hasDiscrepancy := c.Query("has_discrepancy"); hasDiscrepancy != "" {
+		differs := hasDiscrepancy == "true"
+		filter.HasDiscrepancy = &differs
+	}
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		filter.Page = page
+	}
+	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 {
+		filter.PageSize = pageSize
+	}
+
+	reports, total, err := h.service.GetInventoryDiscrepancies(c.Request.Context(), connectionID, filter)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to get inventory discrepancies", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"discrepancies": reports,
+		"total":         total,
+		"page":          filter.Page,
+		"page_size":     filter.PageSize,
+	})
+}
+
+// GetInventorySyncLogs searches the stock push history of all connections, or of one
+// GET /api/v1/admin/marketplace/inventory/logs
+// GET /api/v1/admin/marketplace/connections/:id/inventory/logs
+func (h *InventoryHandler) GetInventorySyncLogs(c *gin.Context) {
+	filter := &domain.InventorySyncLogFilter{
+		ExternalProductID: c.Query("external_product_id"),
+		Source:            c.Query("source"),
+		Status:            c.Query("status"),
+		Page:              1,
+		PageSize:          20,
+	}
+
+	connectionParam := c.Param("id")
+	if connectionParam == "" {
+		connectionParam = c.Query("connection_id")
+	}
+	ids := []struct {
+		value  string
+		target **uuid.UUID
+		name   string
+	}{
+		{connectionParam, &filter.ConnectionID, "connection ID"},
+		{c.Query("mapping_id"), &filter.ProductMappingID, "mapping ID"},
+		{c.Query("product_id"), &filter.InternalProductID, "product ID"},
+	}
+	for _, id := range ids {
+		if id.value == "" {
+			continue
+		}
+		parsed, err := uuid.Parse(id.value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + id.name})
+			return
+		}
+		*id.target = &parsed
+	}
+
+	// Time range in RFC3339, e.g. 2024-05-01T15:00:00+08:00
+	dates := []struct {
+		value  string
+		target **time.Time
+		name   string
+	}{
+		{c.Query("start_date"), &filter.StartDate, "start_date"},
+		{c.Query("end_date"), &filter.EndDate, "end_date"},
+	}
+	for _, d := range dates {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, d.value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + d.name + ", use RFC3339"})
+			return
+		}
+		*d.target = &parsed
+	}
+
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		filter.Page = page
+	}
+	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 {
+		filter.PageSize = pageSize
+	}
+
+	logs, total, err := h.service.GetInventorySyncLogs(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error("Failed to get inventory sync logs", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"logs":      logs,
+		"total":     total,
+		"page":      filter.Page,
+		"page_size": filter.PageSize,
+	})
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/application"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+)
+
+// ListingHandler handles listing template and override API requests
+type ListingHandler struct {
+	service *services.ListingContentService
+	logger  *zap.Logger
+}
+
+// NewListingHandler creates a new ListingHandler
+func NewListingHandler(service *services.ListingContentService, logger *zap.Logger) *ListingHandler {
+	return &ListingHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetListingTemplate returns the listing template of a connection
+// GET /api/v1/admin/marketplace/connections/:id/listing/template
+func (h *ListingHandler) GetListingTemplate(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	template, err := h.service.GetTemplate(c.Request.Context(), connectionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// SaveListingTemplate creates or replaces the listing template of a connection
+// PUT /api/v1/admin/marketplace/connections/:id/listing/template
+func (h *ListingHandler) SaveListingTemplate(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	var req domain.SaveListingTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.service.SaveTemplate(c.Request.Context(), connectionID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrConnectionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrUnknownPlaceholder), errors.Is(err, services.ErrDescriptionBlockTooLong):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to save listing template", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteListingTemplate deletes the listing template of a connection
+// DELETE /api/v1/admin/marketplace/connections/:id/listing/template
+func (h *ListingHandler) DeleteListingTemplate(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	if err := h.service.DeleteTemplate(c.Request.Context(), connectionID); err != nil {
+		if errors.Is(err, services.ErrListingTemplateNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to delete listing template", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Listing template deleted successfully"})
+}
+
+// GetListingOverrides lists the listing overrides of a connection
+// GET /api/v1/admin/marketplace/connections/:id/listing/overrides
+func (h *ListingHandler) GetListingOverrides(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	filter := &domain.ListingOverrideFilter{}
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		filter.Page = page
+	}
+	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 {
+		filter.PageSize = pageSize
+	}
+
+	overrides, total, err := h.service.GetOverrides(c.Request.Context(), connectionID, filter)
+	if err != nil {
+		h.logger.Error("Failed to get listing overrides", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"overrides": overrides,
+		"total":     total,
+		"page":      filter.Page,
+		"page_size": filter.PageSize,
+	})
+}
+
+// GetListingOverride returns the listing override of a product
+// GET /api/v1/admin/marketplace/connections/:id/listing/overrides/:product_id
+func (h *ListingHandler) GetListingOverride(c *gin.Context) {
+	connectionID, productID, ok := parseConnectionAndProduct(c)
+	if !ok {
+		return
+	}
+
+	override, err := h.service.GetOverride(c.Request.Context(), connectionID, productID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, override)
+}
+
+// SaveListingOverride creates or replaces the listing override of a product
+// PUT /api/v1/admin/marketplace/connections/:id/listing/overrides/:product_id
+func (h *ListingHandler) SaveListingOverride(c *gin.Context) {
+	connectionID, productID, ok := parseConnectionAndProduct(c)
+	if !ok {
+		return
+	}
+
+	var req domain.SaveListingOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	override, err := h.service.SaveOverride(c.Request.Context(), connectionID, productID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to save listing override", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, override)
+}
+
+// DeleteListingOverride deletes the listing override of a product
+// DELETE /api/v1/admin/marketplace/connections/:id/listing/overrides/:product_id
+func (h *ListingHandler) DeleteListingOverride(c *gin.Context) {
+	connectionID, productID, ok := parseConnectionAndProduct(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteOverride(c.Request.Context(), connectionID, productID); err != nil {
+		if errors.Is(err, services.ErrListingOverrideNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to delete listing override", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Listing override deleted successfully"})
+}
+
+// PreviewListing shows the title, description, images and price published for a catalog product
+// GET /api/v1/admin/marketplace/connections/:id/listing/preview/:product_id
+func (h *ListingHandler) PreviewListing(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	content, err := h.service.Preview(c.Request.Context(), connectionID, c.Param("product_id"))
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to preview listing", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, content)
+}
+
+// parseConnectionAndProduct parses the connection and product IDs of an override route
+func parseConnectionAndProduct(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	productID, err := uuid.Parse(c.Param("product_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return connectionID, productID, true
+}
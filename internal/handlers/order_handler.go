@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -130,6 +131,104 @@ func (h *OrderHandler) SyncOrders(c *gin.Context) {
 	})
 }
 
+// StartOrderBackfill starts a background import of the orders created in a date range
+// POST /api/v1/admin/marketplace/connections/:id/orders/backfill
+func (h *OrderHandler) StartOrderBackfill(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	var req domain.StartOrderBackfillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := h.service.StartOrderBackfill(c.Request.Context(), connectionID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrConnectionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrBackfillInProgress):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "job": job})
+		case errors.Is(err, services.ErrInvalidPlatform), errors.Is(err, services.ErrInvalidBackfillRange):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to start order backfill", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Order backfill started",
+		"job":     job,
+	})
+}
+
+// GetBackfillJob returns the progress of an order backfill job
+// GET /api/v1/admin/marketplace/connections/:id/orders/backfill/jobs/:job_id
+func (h *OrderHandler) GetBackfillJob(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("job_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := h.service.GetBackfillJob(c.Request.Context(), connectionID, jobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// ResumeBackfillJob restarts a failed order backfill from its checkpoint
+// POST /api/v1/admin/marketplace/connections/:id/orders/backfill/jobs/:job_id/resume
+func (h *OrderHandler) ResumeBackfillJob(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("job_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := h.service.ResumeOrderBackfill(c.Request.Context(), connectionID, jobID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrJobNotFound), errors.Is(err, services.ErrConnectionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrBackfillInProgress):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "job": job})
+		case errors.Is(err, services.ErrJobNotResumable):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to resume order backfill", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Order backfill resumed",
+		"job":     job,
+	})
+}
+
 // UpdateOrderStatusRequest represents the request to update order status
 type UpdateOrderStatusRequest struct {
 	Status string `json:"status" binding:"required"`
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/application"
+)
+
+// PricingHandler handles pricing rule API requests
+type PricingHandler struct {
+	service *services.PricingService
+	logger  *zap.Logger
+}
+
+// NewPricingHandler creates a new PricingHandler
+func NewPricingHandler(service *services.PricingService, logger *zap.Logger) *PricingHandler {
+	return &PricingHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetPricingRules lists pricing rules for a connection
+// GET /api/v1/admin/marketplace/connections/:id/pricing/rules
+func (h *PricingHandler) GetPricingRules(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	rules, err := h.service.GetRules(c.Request.Context(), connectionID)
+	if err != nil {
+		h.logger.Error("Failed to get pricing rules", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rules": rules,
+		"total": len(rules),
+	})
+}
+
+// SavePricingRule creates or replaces the default rule or a category override
+// PUT /api/v1/admin/marketplace/connections/:id/pricing/rules
+func (h *PricingHandler) SavePricingRule(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	var req domain.CreatePricingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.service.SaveRule(c.Request.Context(), connectionID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to save pricing rule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeletePricingRule deletes a pricing rule
+// DELETE /api/v1/admin/marketplace/connections/:id/pricing/rules/:rule_id
+func (h *PricingHandler) DeletePricingRule(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	ruleID, err := uuid.Parse(c.Param("rule_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return
+	}
+
+	if err := h.service.DeleteRule(c.Request.Context(), connectionID, ruleID); err != nil {
+		if errors.Is(err, services.ErrPricingRuleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to delete pricing rule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pricing rule deleted successfully"})
+}
+
+// PreviewPrice shows the computed marketplace price for a catalog product
+// GET /api/v1/admin/marketplace/connections/:id/pricing/preview/:product_id
+func (h *PricingHandler) PreviewPrice(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	preview, err := h.service.PreviewPrice(c.Request.Context(), connectionID, c.Param("product_id"))
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to preview price", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -36,9 +39,10 @@ func (h *ProductHandler) GetMappedProducts(c *gin.Context) {
 	}
 
 	filter := &domain.ProductMappingFilter{
-		SyncStatus: c.Query("status"),
-		Page:       1,
-		PageSize:   20,
+		SyncStatus:    c.Query("status"),
+		ListingStatus: c.Query("listing_status"),
+		Page:          1,
+		PageSize:      20,
 	}
 
 	if pageStr := c.Query("page"); pageStr != "" {
@@ -86,8 +90,15 @@ func (h *ProductHandler) PushProducts(c *gin.Context) {
 	var req PushProductsRequest
 	_ = c.ShouldBindJSON(&req) // Ignore binding errors, empty is valid
 
-	job, err := h.service.PushProducts(c.Request.Context(), connectionID, req.ProductIDs)
+	job, report, err := h.service.PushProducts(c.Request.Context(), connectionID, req.ProductIDs)
 	if err != nil {
+		if errors.Is(err, services.ErrNoValidProducts) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":      err.Error(),
+				"validation": report,
+			})
+			return
+		}
 		h.logger.Error("Failed to push products", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -99,12 +110,40 @@ func (h *ProductHandler) PushProducts(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusAccepted, gin.H{
-		"message": message,
-		"job_id":  job.ID,
-		"status":  job.Status,
+		"message":    message,
+		"job_id":     job.ID,
+		"status":     job.Status,
+		"validation": report,
 	})
 }
 
+// ValidateProducts runs a dry-run of the push rules without calling marketplace write APIs
+// POST /api/v1/admin/marketplace/connections/:id/products/validate
+// If product_ids is empty, validates all active products from catalog
+func (h *ProductHandler) ValidateProducts(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	var req PushProductsRequest
+	_ = c.ShouldBindJSON(&req) // Ignore binding errors, empty is valid
+
+	report, err := h.service.ValidateProducts(c.Request.Context(), connectionID, req.ProductIDs)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to validate products", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 // UpdateProductMappingRequest represents the request to update a mapping
 type UpdateProductMappingRequest struct {
 	Status string `json:"status" binding:"required,oneof=synced pending error"`
@@ -152,7 +191,97 @@ func (h *ProductHandler) DeleteProductMapping(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Mapping deleted"})
 }
 
-// ImportProducts imports products from a marketplace
+// UnlistProducts pauses the marketplace listings of catalog products
+// POST /api/v1/admin/marketplace/connections/:id/products/unlist
+func (h *ProductHandler) UnlistProducts(c *gin.Context) {
+	h.setListingsActive(c, false)
+}
+
+// RelistProducts reactivates paused marketplace listings of catalog products
+// POST /api/v1/admin/marketplace/connections/:id/products/relist
+func (h *ProductHandler) RelistProducts(c *gin.Context) {
+	h.setListingsActive(c, true)
+}
+
+// setListingsActive handles bulk unlist and relist requests
+func (h *ProductHandler) setListingsActive(c *gin.Context, active bool) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	var req domain.ListingActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var result *services.ListingActionResult
+	if active {
+		result, err = h.service.RelistProducts(c.Request.Context(), connectionID, req.ProductIDs)
+	} else {
+		result, err = h.service.UnlistProducts(c.Request.Context(), connectionID, req.ProductIDs)
+	}
+	h.respondListingResult(c, result, err)
+}
+
+// UnlistProduct pauses the marketplace listing of a single product mapping
+// POST /api/v1/admin/marketplace/connections/:id/products/:mapping_id/unlist
+func (h *ProductHandler) UnlistProduct(c *gin.Context) {
+	h.setListingActive(c, false)
+}
+
+// RelistProduct reactivates the marketplace listing of a single product mapping
+// POST /api/v1/admin/marketplace/connections/:id/products/:mapping_id/relist
+func (h *ProductHandler) RelistProduct(c *gin.Context) {
+	h.setListingActive(c, true)
+}
+
+// setListingActive handles single unlist and relist requests
+func (h *ProductHandler) setListingActive(c *gin.Context, active bool) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	mappingID, err := uuid.Parse(c.Param("mapping_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mapping ID"})
+		return
+	}
+
+	var result *services.ListingActionResult
+	if active {
+		result, err = h.service.RelistMapping(c.Request.Context(), connectionID, mappingID)
+	} else {
+		result, err = h.service.UnlistMapping(c.Request.Context(), connectionID, mappingID)
+	}
+	h.respondListingResult(c, result, err)
+}
+
+// respondListingResult writes the outcome of an unlist or relist request
+func (h *ProductHandler) respondListingResult(c *gin.Context, result *services.ListingActionResult, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrConnectionNotFound), errors.Is(err, services.ErrProductMappingNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to update listing status", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if result.Succeeded == 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, result)
+}
+
+// ImportProducts starts a background import of the shop's existing listings
 // POST /api/v1/admin/marketplace/connections/:id/products/import
 func (h *ProductHandler) ImportProducts(c *gin.Context) {
 	connectionID, err := uuid.Parse(c.Param("id"))
@@ -161,16 +290,95 @@ func (h *ProductHandler) ImportProducts(c *gin.Context) {
 		return
 	}
 
-	count, err := h.service.ImportProducts(c.Request.Context(), connectionID)
+	// Body is optional
+	var req domain.StartProductImportRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	job, err := h.service.StartProductImport(c.Request.Context(), connectionID, &req)
 	if err != nil {
-		h.logger.Error("Failed to import products", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, services.ErrConnectionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrImportInProgress):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "job": job})
+		case errors.Is(err, services.ErrInvalidPlatform):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to start product import", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":          "Products imported successfully",
-		"products_imported": count,
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Product import started",
+		"job":     job,
+	})
+}
+
+// GetImportJob returns the progress of a product import job
+// GET /api/v1/admin/marketplace/connections/:id/products/import/jobs/:job_id
+func (h *ProductHandler) GetImportJob(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("job_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := h.service.GetImportJob(c.Request.Context(), connectionID, jobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// ResumeImportJob restarts a failed product import from where it stopped
+// POST /api/v1/admin/marketplace/connections/:id/products/import/jobs/:job_id/resume
+func (h *ProductHandler) ResumeImportJob(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("job_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := h.service.ResumeProductImport(c.Request.Context(), connectionID, jobID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrJobNotFound), errors.Is(err, services.ErrConnectionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrImportInProgress):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "job": job})
+		case errors.Is(err, services.ErrJobNotResumable):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to resume product import", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Product import resumed",
+		"job":     job,
 	})
 }
 
@@ -205,6 +413,10 @@ func (h *ProductHandler) GetImportedProducts(c *gin.Context) {
 		mapped := isMapped == "true"
 		filter.IsMapped = &mapped
 	}
+	if isRemoved := c.Query("is_removed"); isRemoved != "" {
+		removed := isRemoved == "true"
+		filter.IsRemoved = &removed
+	}
 
 	products, total, err := h.service.GetImportedProducts(c.Request.Context(), connectionID, filter)
 	if err != nil {
@@ -221,10 +433,36 @@ func (h *ProductHandler) GetImportedProducts(c *gin.Context) {
 	})
 }
 
-// CreateManualMappingRequest represents the request to create a manual mapping
+// GetImportedProduct returns a single imported product with its models, attributes and raw platform payload
+// GET /api/v1/admin/marketplace/connections/:id/products/imported/:imported_id
+func (h *ProductHandler) GetImportedProduct(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	importedProductID, err := uuid.Parse(c.Param("imported_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid imported product ID"})
+		return
+	}
+
+	product, err := h.service.GetImportedProduct(c.Request.Context(), connectionID, importedProductID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+// CreateManualMappingRequest represents the request to create a manual mapping.
+// Variants pairs imported models with catalog variants; when omitted they are paired by SKU and options.
 type CreateManualMappingRequest struct {
-	ImportedProductID string `json:"imported_product_id" binding:"required"`
-	InternalProductID string `json:"internal_product_id" binding:"required"`
+	ImportedProductID string                `json:"imported_product_id" binding:"required"`
+	InternalProductID string                `json:"internal_product_id" binding:"required"`
+	Variants          []domain.VariantMatch `json:"variants" binding:"omitempty,dive"`
 }
 
 // CreateManualMapping creates a manual mapping between an imported product and an internal product
@@ -254,8 +492,13 @@ func (h *ProductHandler) CreateManualMapping(c *gin.Context) {
 		return
 	}
 
-	mapping, err := h.service.CreateManualMapping(c.Request.Context(), connectionID, importedProductID, internalProductID)
+	mapping, err := h.service.CreateManualMapping(c.Request.Context(), connectionID, importedProductID, internalProductID, req.Variants)
 	if err != nil {
+		if errors.Is(err, services.ErrUnknownImportedModel) || errors.Is(err, services.ErrUnknownCatalogVariant) ||
+			errors.Is(err, services.ErrDuplicateVariantPair) || errors.Is(err, services.ErrVariantPairsRequired) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("Failed to create manual mapping", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -267,6 +510,69 @@ func (h *ProductHandler) CreateManualMapping(c *gin.Context) {
 	})
 }
 
+// GetProductMatches proposes catalog products for unmapped imported products
+// GET /api/v1/admin/marketplace/connections/:id/products/imported/matches?min_confidence=&limit=
+func (h *ProductHandler) GetProductMatches(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	var opts services.ProductMatchOptions
+	if minConfidence, err := strconv.ParseFloat(c.Query("min_confidence"), 64); err == nil && minConfidence > 0 {
+		opts.MinConfidence = minConfidence
+	}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 {
+		opts.Limit = limit
+	}
+
+	matches, err := h.service.SuggestProductMatches(c.Request.Context(), connectionID, opts)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to suggest product matches", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"matches": matches,
+		"total":   len(matches),
+	})
+}
+
+// ConfirmProductMatches creates mappings from reviewed product matches
+// POST /api/v1/admin/marketplace/connections/:id/products/imported/matches/confirm
+func (h *ProductHandler) ConfirmProductMatches(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	var req domain.ConfirmProductMatchesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.ConfirmProductMatches(c.Request.Context(), connectionID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to confirm product matches", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // DeleteManualMapping deletes a manual mapping
 // DELETE /api/v1/admin/marketplace/connections/:id/products/map/:mapping_id
 func (h *ProductHandler) DeleteManualMapping(c *gin.Context) {
@@ -284,3 +590,494 @@ func (h *ProductHandler) DeleteManualMapping(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Mapping deleted"})
 }
+
+// StartDriftCheck starts a background comparison of all mapped listings against the catalog
+// POST /api/v1/admin/marketplace/connections/:id/drift/check
+func (h *ProductHandler) StartDriftCheck(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	job, err := h.service.StartDriftCheck(c.Request.Context(), connectionID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrConnectionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrDriftCheckInProgress):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "job": job})
+		case errors.Is(err, services.ErrInvalidPlatform):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to start drift check", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Drift check started",
+		"job":     job,
+	})
+}
+
+// GetDriftCheckJob returns the progress of a drift check job
+// GET /api/v1/admin/marketplace/connections/:id/drift/jobs/:job_id
+func (h *ProductHandler) GetDriftCheckJob(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("job_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := h.service.GetDriftCheckJob(c.Request.Context(), connectionID, jobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// GetListingDrifts lists the drift reports of a connection
+// GET /api/v1/admin/marketplace/connections/:id/drift?has_drift=&field=&page=&page_size=
+func (h *ProductHandler) GetListingDrifts(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	filter := &domain.ListingDriftFilter{}
+	if hasDrift := c.Query("has_drift"); hasDrift != "" {
+		drifted := hasDrift == "true"
+		filter.HasDrift = &drifted
+	}
+	switch field := c.Query("field"); field {
+	case "", domain.DriftFieldName, domain.DriftFieldPrice, domain.DriftFieldStock, domain.DriftFieldStatus, domain.DriftFieldImages:
+		filter.Field = field
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid field"})
+		return
+	}
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		filter.Page = page
+	}
+	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 {
+		filter.PageSize = pageSize
+	}
+
+	drifts, total, err := h.service.GetListingDrifts(c.Request.Context(), connectionID, filter)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to get drift reports", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"drifts":    drifts,
+		"total":     total,
+		"page":      filter.Page,
+		"page_size": filter.PageSize,
+	})
+}
+
+// GetSaleCampaigns lists the discount campaigns publishing catalog sales on a connection
+// GET /api/v1/admin/marketplace/connections/:id/pricing/sales?status=&page=&page_size=
+func (h *ProductHandler) GetSaleCampaigns(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	filter := &domain.SaleCampaignFilter{}
+	switch status := c.Query("status"); status {
+	case "", domain.SaleCampaignActive, domain.SaleCampaignEnded, domain.SaleCampaignFailed:
+		filter.Status = status
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		filter.Page = page
+	}
+	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 {
+		filter.PageSize = pageSize
+	}
+
+	campaigns, total, err := h.service.GetSaleCampaigns(c.Request.Context(), connectionID, filter)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to get sale campaigns", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"campaigns": campaigns,
+		"total":     total,
+		"page":      filter.Page,
+		"page_size": filter.PageSize,
+	})
+}
+
+// GetListingDrift returns the drift report of a product mapping
+// GET /api/v1/admin/marketplace/connections/:id/drift/:mapping_id
+func (h *ProductHandler) GetListingDrift(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	mappingID, err := uuid.Parse(c.Param("mapping_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mapping ID"})
+		return
+	}
+
+	drift, err := h.service.GetListingDrift(c.Request.Context(), connectionID, mappingID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, drift)
+}
+
+// ResolveListingDrift pushes the catalog value of a drifted field to the marketplace or accepts the marketplace value
+// POST /api/v1/admin/marketplace/connections/:id/drift/:mapping_id/resolve
+func (h *ProductHandler) ResolveListingDrift(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	mappingID, err := uuid.Parse(c.Param("mapping_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mapping ID"})
+		return
+	}
+
+	var req domain.ResolveDriftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	drift, err := h.service.ResolveListingDrift(c.Request.Context(), connectionID, mappingID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrDriftNotFound), errors.Is(err, services.ErrConnectionNotFound), errors.Is(err, services.ErrProductMappingNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrFieldNotDrifted), errors.Is(err, services.ErrDriftNotResolvable):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("Failed to resolve listing drift", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Drift resolved",
+		"drift":   drift,
+	})
+}
+
+// GetPushJobs lists product push jobs, including those queued by auto-listing
+// GET /api/v1/admin/marketplace/connections/:id/products/push/jobs
+func (h *ProductHandler) GetPushJobs(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	filter := &domain.SyncJobFilter{
+		Status: c.Query("status"),
+	}
+	if page, err := strconv.Atoi(c.Query("page")); err == nil && page > 0 {
+		filter.Page = page
+	}
+	if pageSize, err := strconv.Atoi(c.Query("page_size")); err == nil && pageSize > 0 {
+		filter.PageSize = pageSize
+	}
+
+	jobs, total, err := h.service.GetPushJobs(c.Request.Context(), connectionID, filter)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to get push jobs", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":      jobs,
+		"total":     total,
+		"page":      filter.Page,
+		"page_size": filter.PageSize,
+	})
+}
+
+// GetPushJob returns a product push job with its per-product results
+// GET /api/v1/admin/marketplace/connections/:id/products/push/jobs/:job_id
+func (h *ProductHandler) GetPushJob(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("job_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := h.service.GetPushJob(c.Request.Context(), connectionID, jobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// GetAutoListingRule returns the auto-listing rule of a connection
+// GET /api/v1/admin/marketplace/connections/:id/auto-listing
+func (h *ProductHandler) GetAutoListingRule(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	rule, err := h.service.GetAutoListingRule(c.Request.Context(), connectionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// SaveAutoListingRule creates or replaces the auto-listing rule of a connection
+// PUT /api/v1/admin/marketplace/connections/:id/auto-listing
+func (h *ProductHandler) SaveAutoListingRule(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	var req domain.SaveAutoListingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.service.SaveAutoListingRule(c.Request.Context(), connectionID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to save auto-listing rule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteAutoListingRule deletes the auto-listing rule of a connection
+// DELETE /api/v1/admin/marketplace/connections/:id/auto-listing
+func (h *ProductHandler) DeleteAutoListingRule(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	if err := h.service.DeleteAutoListingRule(c.Request.Context(), connectionID); err != nil {
+		if errors.Is(err, services.ErrAutoListingRuleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to delete auto-listing rule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Auto-listing rule deleted successfully"})
+}
+
+// ExportProductMappings downloads the product and variant mappings of a connection as CSV
+// GET /api/v1/admin/marketplace/connections/:id/products/mappings/export
+func (h *ProductHandler) ExportProductMappings(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	writeCSVExport(c, h.logger, fmt.Sprintf("product-mappings-%s.csv", connectionID), func(w io.Writer) error {
+		return h.service.ExportProductMappings(c.Request.Context(), connectionID, w)
+	})
+}
+
+// ImportProductMappings starts a background import of a product mapping CSV, sent as the "file"
+// form field or as the request body. With ?dry_run=true the file is only validated, in the request.
+// POST /api/v1/admin/marketplace/connections/:id/products/mappings/import
+func (h *ProductHandler) ImportProductMappings(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	file, ok := mappingCSVUpload(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	if c.Query("dry_run") == "true" {
+		report, err := h.service.ValidateProductMappings(c.Request.Context(), connectionID, file)
+		respondMappingImport(c, h.logger, report, err)
+		return
+	}
+
+	job, err := h.service.StartProductMappingImport(c.Request.Context(), connectionID, file)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrConnectionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidMappingCSV):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrMappingImportInProgress):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "job": job})
+		default:
+			h.logger.Error("Failed to start mapping import", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Product mapping import started",
+		"job":     job,
+	})
+}
+
+// GetMappingImportJob returns the progress of a product mapping import job and, once it has
+// run, its report
+// GET /api/v1/admin/marketplace/connections/:id/products/mappings/import/jobs/:job_id
+func (h *ProductHandler) GetMappingImportJob(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("job_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := h.service.GetMappingImportJob(c.Request.Context(), connectionID, jobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// writeCSVExport streams a CSV download. Errors are reported as JSON until the first byte is written.
+func writeCSVExport(c *gin.Context, logger *zap.Logger, filename string, export func(w io.Writer) error) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	err := export(c.Writer)
+	if err == nil {
+		return
+	}
+	logger.Error("Failed to export mappings", zap.Error(err))
+	if c.Writer.Written() {
+		// The download is already under way and ends truncated
+		return
+	}
+
+	c.Header("Content-Disposition", "")
+	c.Header("Content-Type", "")
+	if errors.Is(err, services.ErrConnectionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// mappingCSVUpload opens an uploaded mapping CSV, from the "file" form field of a multipart
+// request or else the raw request body
+func mappingCSVUpload(c *gin.Context) (io.ReadCloser, bool) {
+	if c.ContentType() != "multipart/form-data" {
+		return c.Request.Body, true
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV file is required in the \"file\" field"})
+		return nil, false
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return file, true
+}
+
+// respondMappingImport writes the result of a mapping CSV import.
+// A report with row errors is returned with 422 Unprocessable Entity.
+func respondMappingImport(c *gin.Context, logger *zap.Logger, report *domain.MappingImportReport, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrConnectionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidMappingCSV):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			logger.Error("Failed to import mappings", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if report.InvalidRows > 0 {
+		c.JSON(http.StatusUnprocessableEntity, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
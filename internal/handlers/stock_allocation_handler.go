@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/application"
+)
+
+// StockAllocationHandler handles stock allocation rule API requests
+type StockAllocationHandler struct {
+	service *services.StockAllocationService
+	logger  *zap.Logger
+}
+
+// NewStockAllocationHandler creates a new StockAllocationHandler
+func NewStockAllocationHandler(service *services.StockAllocationService, logger *zap.Logger) *StockAllocationHandler {
+	return &StockAllocationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetAllocationRules lists stock allocation rules for a connection
+// GET /api/v1/admin/marketplace/connections/:id/inventory/allocation/rules
+func (h *StockAllocationHandler) GetAllocationRules(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	rules, err := h.service.GetRules(c.Request.Context(), connectionID)
+	if err != nil {
+		h.logger.Error("Failed to get stock allocation rules", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rules": rules,
+		"total": len(rules),
+	})
+}
+
+// SaveAllocationRule creates or replaces the default rule or a category override
+// PUT /api/v1/admin/marketplace/connections/:id/inventory/allocation/rules
+func (h *StockAllocationHandler) SaveAllocationRule(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	var req domain.SaveStockAllocationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.service.SaveRule(c.Request.Context(), connectionID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrConnectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to save stock allocation rule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteAllocationRule deletes a stock allocation rule
+// DELETE /api/v1/admin/marketplace/connections/:id/inventory/allocation/rules/:rule_id
+func (h *StockAllocationHandler) DeleteAllocationRule(c *gin.Context) {
+	connectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid connection ID"})
+		return
+	}
+
+	ruleID, err := uuid.Parse(c.Param("rule_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return
+	}
+
+	if err := h.service.DeleteRule(c.Request.Context(), connectionID, ruleID); err != nil {
+		if errors.Is(err, services.ErrStockAllocationRuleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("Failed to delete stock allocation rule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Stock allocation rule deleted successfully"})
+}
+
+// PreviewStock shows the stock each active connection would receive for a catalog product
+// GET /api/v1/admin/marketplace/inventory/allocation/preview/:product_id
+func (h *StockAllocationHandler) PreviewStock(c *gin.Context) {
+	preview, err := h.service.PreviewStock(c.Request.Context(), c.Param("product_id"))
+	if err != nil {
+		h.logger.Error("Failed to preview stock allocation", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
@@ -16,10 +16,11 @@ import (
 
 // WebhookHandler handles incoming webhooks from marketplaces
 type WebhookHandler struct {
-	orderService *services.OrderSyncService
-	shopeeKey    string
-	tiktokSecret string
-	logger       *zap.Logger
+	orderService   *services.OrderSyncService
+	productService *services.ProductSyncService
+	shopeeKey      string
+	tiktokSecret   string
+	logger         *zap.Logger
 }
 
 // WebhookConfig holds configuration for webhook handlers
@@ -29,12 +30,13 @@ type WebhookConfig struct {
 }
 
 // NewWebhookHandler creates a new WebhookHandler
-func NewWebhookHandler(orderService *services.OrderSyncService, cfg *WebhookConfig, logger *zap.Logger) *WebhookHandler {
+func NewWebhookHandler(orderService *services.OrderSyncService, productService *services.ProductSyncService, cfg *WebhookConfig, logger *zap.Logger) *WebhookHandler {
 	return &WebhookHandler{
-		orderService: orderService,
-		shopeeKey:    cfg.ShopeePartnerKey,
-		tiktokSecret: cfg.TikTokAppSecret,
-		logger:       logger,
+		orderService:   orderService,
+		productService: productService,
+		shopeeKey:      cfg.ShopeePartnerKey,
+		tiktokSecret:   cfg.TikTokAppSecret,
+		logger:         logger,
 	}
 }
 
@@ -90,6 +92,11 @@ func (h *WebhookHandler) HandleShopeeWebhook(c *gin.Context) {
 		Data      struct {
 			OrderSN string `json:"ordersn"`
 			Status  string `json:"status"`
+
+			// Item status pushes (banned, violation, review results)
+			ItemID          int64  `json:"item_id"`
+			ItemStatus      string `json:"item_status"`
+			ViolationReason string `json:"violation_reason"`
 		} `json:"data"`
 	}
 
@@ -112,6 +119,11 @@ func (h *WebhookHandler) HandleShopeeWebhook(c *gin.Context) {
 		go h.orderService.HandleShopeeOrderEvent(event.ShopID, event.Data.OrderSN, event.Data.Status)
 	}
 
+	// Process listing status event
+	if event.Data.ItemID != 0 && event.Data.ItemStatus != "" {
+		go h.productService.HandleShopeeItemStatusEvent(event.ShopID, event.Data.ItemID, event.Data.ItemStatus, event.Data.ViolationReason)
+	}
+
 	c.JSON(http.StatusOK, gin.H{"status": "received"})
 }
 
@@ -155,6 +167,11 @@ func (h *WebhookHandler) HandleTikTokWebhook(c *gin.Context) {
 		Data      struct {
 			OrderID     string `json:"order_id"`
 			OrderStatus int    `json:"order_status"`
+
+			// Product status changes
+			ProductID     string `json:"product_id"`
+			ProductStatus string `json:"status"`
+			Reason        string `json:"reason"`
 		} `json:"data"`
 	}
 
@@ -175,6 +192,11 @@ func (h *WebhookHandler) HandleTikTokWebhook(c *gin.Context) {
 		go h.orderService.HandleTikTokOrderEvent(event.ShopID, event.Data.OrderID, event.Data.OrderStatus)
 	}
 
+	// Process listing status event
+	if event.Type == "PRODUCT_STATUS_CHANGE" && event.Data.ProductID != "" {
+		go h.productService.HandleTikTokProductStatusEvent(event.ShopID, event.Data.ProductID, event.Data.ProductStatus, event.Data.Reason)
+	}
+
 	c.JSON(http.StatusOK, gin.H{"status": "received"})
 }
 
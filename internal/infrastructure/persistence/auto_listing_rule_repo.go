@@ -0,0 +1,46 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"gorm.io/gorm"
+)
+
+// AutoListingRuleRepository handles database operations for auto-listing rules
+type AutoListingRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewAutoListingRuleRepository creates a new AutoListingRuleRepository
+func NewAutoListingRuleRepository(db *gorm.DB) *AutoListingRuleRepository {
+	return &AutoListingRuleRepository{db: db}
+}
+
+// GetByConnectionID retrieves the auto-listing rule of a connection
+func (r *AutoListingRuleRepository) GetByConnectionID(ctx context.Context, connectionID uuid.UUID) (*domain.AutoListingRule, error) {
+	var rule domain.AutoListingRule
+	err := r.db.WithContext(ctx).First(&rule, "connection_id = ?", connectionID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// GetActive retrieves the active auto-listing rules of all connections
+func (r *AutoListingRuleRepository) GetActive(ctx context.Context) ([]domain.AutoListingRule, error) {
+	var rules []domain.AutoListingRule
+	err := r.db.WithContext(ctx).Where("is_active = true").Find(&rules).Error
+	return rules, err
+}
+
+// Save creates or updates an auto-listing rule
+func (r *AutoListingRuleRepository) Save(ctx context.Context, rule *domain.AutoListingRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+// Delete deletes the auto-listing rule of a connection
+func (r *AutoListingRuleRepository) Delete(ctx context.Context, connectionID uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&domain.AutoListingRule{}, "connection_id = ?", connectionID).Error
+}
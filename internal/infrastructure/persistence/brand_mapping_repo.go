@@ -0,0 +1,116 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BrandMappingRepository handles database operations for brand mappings
+type BrandMappingRepository struct {
+	db *gorm.DB
+}
+
+// NewBrandMappingRepository creates a new BrandMappingRepository
+func NewBrandMappingRepository(db *gorm.DB) *BrandMappingRepository {
+	return &BrandMappingRepository{db: db}
+}
+
+// Create creates a new brand mapping
+func (r *BrandMappingRepository) Create(ctx context.Context, mapping *domain.BrandMapping) error {
+	return r.db.WithContext(ctx).Create(mapping).Error
+}
+
+// Upsert creates or updates a brand mapping keyed by connection and internal brand, regardless
+// of the brand's case
+func (r *BrandMappingRepository) Upsert(ctx context.Context, mapping *domain.BrandMapping) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "connection_id"}, {Name: "LOWER(internal_brand)", Raw: true}},
+		DoUpdates: clause.AssignmentColumns([]string{"external_brand_id", "external_brand_name", "status", "confidence", "updated_at"}),
+	}).Create(mapping).Error
+}
+
+// GetByID retrieves a brand mapping by ID
+func (r *BrandMappingRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.BrandMapping, error) {
+	var mapping domain.BrandMapping
+	err := r.db.WithContext(ctx).First(&mapping, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+// GetByConnectionID retrieves brand mappings for a connection with filters
+func (r *BrandMappingRepository) GetByConnectionID(ctx context.Context, connectionID uuid.UUID, filter *domain.BrandMappingFilter) ([]domain.BrandMapping, int64, error) {
+	var mappings []domain.BrandMapping
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.BrandMapping{}).Where("connection_id = ?", connectionID)
+
+	if filter != nil {
+		if filter.Status != "" {
+			query = query.Where("status = ?", filter.Status)
+		}
+		if filter.Search != "" {
+			query = query.Where("internal_brand ILIKE ? OR external_brand_name ILIKE ?", "%"+filter.Search+"%", "%"+filter.Search+"%")
+		}
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination
+	page := 1
+	pageSize := 20
+	if filter != nil {
+		if filter.Page > 0 {
+			page = filter.Page
+		}
+		if filter.PageSize > 0 {
+			pageSize = filter.PageSize
+		}
+	}
+	offset := (page - 1) * pageSize
+
+	err := query.
+		Offset(offset).
+		Limit(pageSize).
+		Order("internal_brand ASC").
+		Find(&mappings).Error
+
+	return mappings, total, err
+}
+
+// GetByConnectionAndBrand retrieves a mapping by connection and internal brand (case-insensitive)
+func (r *BrandMappingRepository) GetByConnectionAndBrand(ctx context.Context, connectionID uuid.UUID, internalBrand string) (*domain.BrandMapping, error) {
+	var mapping domain.BrandMapping
+	err := r.db.WithContext(ctx).
+		Where("connection_id = ? AND LOWER(internal_brand) = LOWER(?)", connectionID, internalBrand).
+		First(&mapping).Error
+	if err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+// Update updates a brand mapping
+func (r *BrandMappingRepository) Update(ctx context.Context, mapping *domain.BrandMapping) error {
+	return r.db.WithContext(ctx).Save(mapping).Error
+}
+
+// Delete deletes a brand mapping
+func (r *BrandMappingRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&domain.BrandMapping{}, "id = ?", id).Error
+}
+
+// DeleteByConnectionID deletes all brand mappings for a connection
+func (r *BrandMappingRepository) DeleteByConnectionID(ctx context.Context, connectionID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("connection_id = ?", connectionID).
+		Delete(&domain.BrandMapping{}).Error
+}
@@ -67,6 +67,58 @@ func (r *CategoryMappingRepository) GetByConnectionAndExternalCategory(ctx conte
 	return &mapping, nil
 }
 
+// GetByPlatform retrieves the mappings of every other connection on a platform
+func (r *CategoryMappingRepository) GetByPlatform(ctx context.Context, platform string, excludeConnectionID uuid.UUID) ([]domain.CategoryMapping, error) {
+	var mappings []domain.CategoryMapping
+	err := r.db.WithContext(ctx).
+		Where("connection_id <> ?", excludeConnectionID).
+		Where("connection_id IN (?)", r.db.Table("marketplace.connections").Select("id").Where("platform = ?", platform)).
+		Find(&mappings).Error
+	return mappings, err
+}
+
+// UpdateStaleFlags flags mappings whose external category is no longer active in the cache
+// and clears the flag on mappings whose category is back. Returns the number of stale mappings.
+func (r *CategoryMappingRepository) UpdateStaleFlags(ctx context.Context, connectionID uuid.UUID) (int64, error) {
+	err := r.db.WithContext(ctx).Exec(`
+		UPDATE marketplace.category_mappings cm
+		SET is_stale = NOT EXISTS (
+			SELECT 1 FROM marketplace.external_categories ec
+			WHERE ec.connection_id = cm.connection_id
+			  AND ec.category_id = cm.external_category_id
+			  AND ec.is_active
+		)
+		WHERE cm.connection_id = ?`, connectionID).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var stale int64
+	err = r.db.WithContext(ctx).
+		Model(&domain.CategoryMapping{}).
+		Where("connection_id = ? AND is_stale = ?", connectionID, true).
+		Count(&stale).Error
+	return stale, err
+}
+
+// ImportMappings creates new mappings (zero ID) and updates existing ones in a single transaction
+func (r *CategoryMappingRepository) ImportMappings(ctx context.Context, mappings []*domain.CategoryMapping) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, m := range mappings {
+			if m.ID != uuid.Nil {
+				if err := tx.Save(m).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Create(m).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // Update updates a category mapping
 func (r *CategoryMappingRepository) Update(ctx context.Context, mapping *domain.CategoryMapping) error {
 	return r.db.WithContext(ctx).Save(mapping).Error
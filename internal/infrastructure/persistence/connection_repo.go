@@ -2,9 +2,11 @@ package persistence
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/google/uuid"
 	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -91,6 +93,18 @@ func (r *ConnectionRepository) UpdateTokens(ctx context.Context, id uuid.UUID, a
 		}).Error
 }
 
+// UpdateSettings replaces the settings of a connection
+func (r *ConnectionRepository) UpdateSettings(ctx context.Context, id uuid.UUID, settings domain.ConnectionSettings) error {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).
+		Model(&domain.Connection{}).
+		Where("id = ?", id).
+		Update("settings", datatypes.JSON(data)).Error
+}
+
 // Deactivate deactivates a connection
 func (r *ConnectionRepository) Deactivate(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).
@@ -0,0 +1,142 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExternalCategoryRepository handles database operations for cached marketplace categories
+type ExternalCategoryRepository struct {
+	db *gorm.DB
+}
+
+// NewExternalCategoryRepository creates a new ExternalCategoryRepository
+func NewExternalCategoryRepository(db *gorm.DB) *ExternalCategoryRepository {
+	return &ExternalCategoryRepository{db: db}
+}
+
+// UpsertBatch creates or refreshes cached categories keyed by connection and category ID
+func (r *ExternalCategoryRepository) UpsertBatch(ctx context.Context, categories []domain.ExternalCategory) error {
+	if len(categories) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "connection_id"}, {Name: "category_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"parent_id", "name", "breadcrumb", "depth", "is_leaf", "is_active", "last_seen_at", "updated_at",
+		}),
+	}).CreateInBatches(categories, 500).Error
+}
+
+// DeactivateMissing marks categories not seen since the given time as inactive.
+// Returns the IDs of categories that were active before.
+func (r *ExternalCategoryRepository) DeactivateMissing(ctx context.Context, connectionID uuid.UUID, seenAt time.Time) ([]string, error) {
+	var missing []string
+	err := r.db.WithContext(ctx).
+		Model(&domain.ExternalCategory{}).
+		Where("connection_id = ? AND is_active = ? AND last_seen_at < ?", connectionID, true, seenAt).
+		Pluck("category_id", &missing).Error
+	if err != nil || len(missing) == 0 {
+		return missing, err
+	}
+
+	err = r.db.WithContext(ctx).
+		Model(&domain.ExternalCategory{}).
+		Where("connection_id = ? AND category_id IN ?", connectionID, missing).
+		Update("is_active", false).Error
+	return missing, err
+}
+
+// GetActiveIDs returns the IDs of all active categories for a connection
+func (r *ExternalCategoryRepository) GetActiveIDs(ctx context.Context, connectionID uuid.UUID) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Model(&domain.ExternalCategory{}).
+		Where("connection_id = ? AND is_active = ?", connectionID, true).
+		Pluck("category_id", &ids).Error
+	return ids, err
+}
+
+// GetByConnectionID retrieves all active categories for a connection
+func (r *ExternalCategoryRepository) GetByConnectionID(ctx context.Context, connectionID uuid.UUID) ([]domain.ExternalCategory, error) {
+	var categories []domain.ExternalCategory
+	err := r.db.WithContext(ctx).
+		Where("connection_id = ? AND is_active = ?", connectionID, true).
+		Order("depth ASC, name ASC").
+		Find(&categories).Error
+	return categories, err
+}
+
+// GetChildren retrieves the active children of a category, or the root categories if parentID is empty
+func (r *ExternalCategoryRepository) GetChildren(ctx context.Context, connectionID uuid.UUID, parentID string, leafOnly bool) ([]domain.ExternalCategory, error) {
+	var categories []domain.ExternalCategory
+	query := r.db.WithContext(ctx).
+		Where("connection_id = ? AND is_active = ?", connectionID, true).
+		Where("COALESCE(parent_id, '') = ?", parentID)
+	if leafOnly {
+		query = query.Where("is_leaf = ?", true)
+	}
+	err := query.Order("name ASC").Find(&categories).Error
+	return categories, err
+}
+
+// Search finds active categories by name or breadcrumb
+func (r *ExternalCategoryRepository) Search(ctx context.Context, connectionID uuid.UUID, filter *domain.ExternalCategoryFilter) ([]domain.ExternalCategory, int64, error) {
+	var categories []domain.ExternalCategory
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.ExternalCategory{}).
+		Where("connection_id = ? AND is_active = ?", connectionID, true)
+
+	if filter != nil {
+		if filter.Query != "" {
+			query = query.Where("to_tsvector('simple', breadcrumb) @@ plainto_tsquery('simple', ?) OR name ILIKE ?",
+				filter.Query, "%"+filter.Query+"%")
+		}
+		if filter.LeafOnly {
+			query = query.Where("is_leaf = ?", true)
+		}
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination
+	page := 1
+	pageSize := 20
+	if filter != nil {
+		if filter.Page > 0 {
+			page = filter.Page
+		}
+		if filter.PageSize > 0 {
+			pageSize = filter.PageSize
+		}
+	}
+	offset := (page - 1) * pageSize
+
+	err := query.
+		Offset(offset).
+		Limit(pageSize).
+		Order("is_leaf DESC, depth ASC, name ASC").
+		Find(&categories).Error
+
+	return categories, total, err
+}
+
+// GetLastRefreshedAt returns when the connection's categories were last refreshed, or nil if never
+func (r *ExternalCategoryRepository) GetLastRefreshedAt(ctx context.Context, connectionID uuid.UUID) (*time.Time, error) {
+	var last *time.Time
+	err := r.db.WithContext(ctx).
+		Model(&domain.ExternalCategory{}).
+		Where("connection_id = ?", connectionID).
+		Select("MAX(last_seen_at)").
+		Scan(&last).Error
+	return last, err
+}
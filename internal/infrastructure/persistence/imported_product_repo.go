@@ -2,6 +2,7 @@ package persistence
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
@@ -23,7 +24,7 @@ func NewImportedProductRepository(db *gorm.DB) *ImportedProductRepository {
 func (r *ImportedProductRepository) Upsert(ctx context.Context, product *domain.ImportedProduct) error {
 	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "connection_id"}, {Name: "external_product_id"}},
-		DoUpdates: clause.AssignmentColumns([]string{"name", "description", "price", "stock", "category_id", "status", "image_url", "external_sku", "updated_at"}),
+		DoUpdates: clause.AssignmentColumns([]string{"name", "description", "price", "stock", "category_id", "status", "image_url", "images", "external_sku", "skus", "attributes", "brand", "weight", "length", "width", "height", "raw_payload", "is_removed", "external_updated_at", "last_seen_at", "updated_at"}),
 	}).Create(product).Error
 }
 
@@ -31,7 +32,7 @@ func (r *ImportedProductRepository) Upsert(ctx context.Context, product *domain.
 func (r *ImportedProductRepository) UpsertBatch(ctx context.Context, products []domain.ImportedProduct) error {
 	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "connection_id"}, {Name: "external_product_id"}},
-		DoUpdates: clause.AssignmentColumns([]string{"name", "description", "price", "stock", "category_id", "status", "image_url", "external_sku", "updated_at"}),
+		DoUpdates: clause.AssignmentColumns([]string{"name", "description", "price", "stock", "category_id", "status", "image_url", "images", "external_sku", "skus", "attributes", "brand", "weight", "length", "width", "height", "raw_payload", "is_removed", "external_updated_at", "last_seen_at", "updated_at"}),
 	}).CreateInBatches(products, 50).Error
 }
 
@@ -56,6 +57,9 @@ func (r *ImportedProductRepository) GetByConnectionID(ctx context.Context, conne
 		if filter.IsMapped != nil {
 			query = query.Where("is_mapped = ?", *filter.IsMapped)
 		}
+		if filter.IsRemoved != nil {
+			query = query.Where("is_removed = ?", *filter.IsRemoved)
+		}
 		if filter.Status != "" {
 			query = query.Where("status = ?", filter.Status)
 		}
@@ -82,7 +86,9 @@ func (r *ImportedProductRepository) GetByConnectionID(ctx context.Context, conne
 	}
 	offset := (page - 1) * pageSize
 
+	// The raw platform payload is only returned for a single product
 	err := query.
+		Omit("raw_payload").
 		Offset(offset).
 		Limit(pageSize).
 		Order("imported_at DESC").
@@ -103,6 +109,75 @@ func (r *ImportedProductRepository) GetByExternalProductID(ctx context.Context,
 	return &product, nil
 }
 
+// GetByExternalProductIDs retrieves the imported products of a connection with the given external product IDs
+func (r *ImportedProductRepository) GetByExternalProductIDs(ctx context.Context, connectionID uuid.UUID, externalProductIDs []string) ([]domain.ImportedProduct, error) {
+	var products []domain.ImportedProduct
+	if len(externalProductIDs) == 0 {
+		return products, nil
+	}
+	err := r.db.WithContext(ctx).
+		Omit("raw_payload").
+		Where("connection_id = ? AND external_product_id IN ?", connectionID, externalProductIDs).
+		Find(&products).Error
+	return products, err
+}
+
+// GetUnmapped retrieves all unmapped imported products for a connection
+func (r *ImportedProductRepository) GetUnmapped(ctx context.Context, connectionID uuid.UUID) ([]domain.ImportedProduct, error) {
+	var products []domain.ImportedProduct
+	err := r.db.WithContext(ctx).
+		Where("connection_id = ? AND is_mapped = false AND is_removed = false", connectionID).
+		Order("imported_at DESC").
+		Find(&products).Error
+	return products, err
+}
+
+// GetExistingExternalIDs returns which of the given external product IDs have already been imported
+func (r *ImportedProductRepository) GetExistingExternalIDs(ctx context.Context, connectionID uuid.UUID, externalProductIDs []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(externalProductIDs) == 0 {
+		return existing, nil
+	}
+
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Model(&domain.ImportedProduct{}).
+		Where("connection_id = ? AND external_product_id IN ?", connectionID, externalProductIDs).
+		Pluck("external_product_id", &ids).Error
+	for _, id := range ids {
+		existing[id] = true
+	}
+	return existing, err
+}
+
+// TouchSeen records that listings are still present on the marketplace
+func (r *ImportedProductRepository) TouchSeen(ctx context.Context, connectionID uuid.UUID, externalProductIDs []string, seenAt time.Time) error {
+	if len(externalProductIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Model(&domain.ImportedProduct{}).
+		Where("connection_id = ? AND external_product_id IN ?", connectionID, externalProductIDs).
+		Updates(map[string]interface{}{
+			"last_seen_at": seenAt,
+			"is_removed":   false,
+		}).Error
+}
+
+// MarkRemovedNotSeenSince marks imported listings with the given status (all if empty)
+// that were not seen since the given time as removed. Returns the number of listings marked.
+func (r *ImportedProductRepository) MarkRemovedNotSeenSince(ctx context.Context, connectionID uuid.UUID, status string, since time.Time) (int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&domain.ImportedProduct{}).
+		Where("connection_id = ? AND is_removed = false", connectionID).
+		Where("last_seen_at IS NULL OR last_seen_at < ?", since)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	result := query.Update("is_removed", true)
+	return result.RowsAffected, result.Error
+}
+
 // SetMapped marks an imported product as mapped to an internal product
 func (r *ImportedProductRepository) SetMapped(ctx context.Context, id uuid.UUID, internalProductID uuid.UUID) error {
 	return r.db.WithContext(ctx).
@@ -0,0 +1,71 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InventoryDiscrepancyRepository handles database operations for inventory discrepancy reports
+type InventoryDiscrepancyRepository struct {
+	db *gorm.DB
+}
+
+// NewInventoryDiscrepancyRepository creates a new InventoryDiscrepancyRepository
+func NewInventoryDiscrepancyRepository(db *gorm.DB) *InventoryDiscrepancyRepository {
+	return &InventoryDiscrepancyRepository{db: db}
+}
+
+// Upsert creates or replaces the report of a listed SKU. The last correction is only
+// overwritten when one was pushed during this check.
+func (r *InventoryDiscrepancyRepository) Upsert(ctx context.Context, report *domain.InventoryDiscrepancy, corrected bool) error {
+	columns := []string{"internal_product_id", "internal_variant_id", "external_product_id", "sku", "expected_quantity", "actual_quantity", "has_discrepancy", "check_error", "uncorrectable", "checked_at", "updated_at"}
+	if corrected {
+		columns = append(columns, "last_corrected_at", "last_correction_quantity", "last_correction_error")
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_mapping_id"}, {Name: "external_variant_id"}},
+		DoUpdates: clause.AssignmentColumns(columns),
+	}).Create(report).Error
+}
+
+// GetByConnectionID retrieves inventory discrepancy reports for a connection with filters
+func (r *InventoryDiscrepancyRepository) GetByConnectionID(ctx context.Context, connectionID uuid.UUID, filter *domain.InventoryDiscrepancyFilter) ([]domain.InventoryDiscrepancy, int64, error) {
+	var reports []domain.InventoryDiscrepancy
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.InventoryDiscrepancy{}).Where("connection_id = ?", connectionID)
+
+	if filter != nil && filter.HasDiscrepancy != nil {
+		query = query.Where("has_discrepancy = ?", *filter.HasDiscrepancy)
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination
+	page := 1
+	pageSize := 20
+	if filter != nil {
+		if filter.Page > 0 {
+			page = filter.Page
+		}
+		if filter.PageSize > 0 {
+			pageSize = filter.PageSize
+		}
+	}
+	offset := (page - 1) * pageSize
+
+	err := query.
+		Offset(offset).
+		Limit(pageSize).
+		Order("has_discrepancy DESC, checked_at DESC").
+		Find(&reports).Error
+
+	return reports, total, err
+}
@@ -0,0 +1,105 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InventorySyncLogRepository handles database operations for inventory sync logs
+type InventorySyncLogRepository struct {
+	db *gorm.DB
+}
+
+// NewInventorySyncLogRepository creates a new InventorySyncLogRepository
+func NewInventorySyncLogRepository(db *gorm.DB) *InventorySyncLogRepository {
+	return &InventorySyncLogRepository{db: db}
+}
+
+// CreateBatch creates inventory sync log entries
+func (r *InventorySyncLogRepository) CreateBatch(ctx context.Context, logs []domain.InventorySyncLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&logs).Error
+}
+
+// GetLatestSuccessful retrieves the latest successful push of each listing and variant of
+// the given listings on a connection
+func (r *InventorySyncLogRepository) GetLatestSuccessful(ctx context.Context, connectionID uuid.UUID, externalProductIDs []string) ([]domain.InventorySyncLog, error) {
+	var logs []domain.InventorySyncLog
+	if len(externalProductIDs) == 0 {
+		return logs, nil
+	}
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT DISTINCT ON (external_product_id, external_variant_id) *
+			FROM marketplace.inventory_sync_logs
+			WHERE connection_id = ? AND external_product_id IN ? AND sync_status = ?
+			ORDER BY external_product_id, external_variant_id, created_at DESC`,
+			connectionID, externalProductIDs, domain.InventorySyncStatusSuccess).
+		Scan(&logs).Error
+	return logs, err
+}
+
+// Search retrieves inventory sync logs with filters, newest first
+func (r *InventorySyncLogRepository) Search(ctx context.Context, filter *domain.InventorySyncLogFilter) ([]domain.InventorySyncLog, int64, error) {
+	var logs []domain.InventorySyncLog
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.InventorySyncLog{})
+
+	if filter != nil {
+		if filter.ConnectionID != nil {
+			query = query.Where("connection_id = ?", *filter.ConnectionID)
+		}
+		if filter.ProductMappingID != nil {
+			query = query.Where("product_mapping_id = ?", *filter.ProductMappingID)
+		}
+		if filter.InternalProductID != nil {
+			query = query.Where("internal_product_id = ?", *filter.InternalProductID)
+		}
+		if filter.ExternalProductID != "" {
+			query = query.Where("external_product_id = ?", filter.ExternalProductID)
+		}
+		if filter.Source != "" {
+			query = query.Where("source = ?", filter.Source)
+		}
+		if filter.Status != "" {
+			query = query.Where("sync_status = ?", filter.Status)
+		}
+		if filter.StartDate != nil {
+			query = query.Where("created_at >= ?", *filter.StartDate)
+		}
+		if filter.EndDate != nil {
+			query = query.Where("created_at <= ?", *filter.EndDate)
+		}
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination
+	page := 1
+	pageSize := 20
+	if filter != nil {
+		if filter.Page > 0 {
+			page = filter.Page
+		}
+		if filter.PageSize > 0 {
+			pageSize = filter.PageSize
+		}
+	}
+	offset := (page - 1) * pageSize
+
+	err := query.
+		Offset(offset).
+		Limit(pageSize).
+		Order("created_at DESC").
+		Find(&logs).Error
+
+	return logs, total, err
+}
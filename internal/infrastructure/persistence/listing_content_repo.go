@@ -0,0 +1,96 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"gorm.io/gorm"
+)
+
+// ListingContentRepository handles database operations for listing templates and overrides
+type ListingContentRepository struct {
+	db *gorm.DB
+}
+
+// NewListingContentRepository creates a new ListingContentRepository
+func NewListingContentRepository(db *gorm.DB) *ListingContentRepository {
+	return &ListingContentRepository{db: db}
+}
+
+// GetTemplate retrieves the listing template of a connection
+func (r *ListingContentRepository) GetTemplate(ctx context.Context, connectionID uuid.UUID) (*domain.ListingTemplate, error) {
+	var template domain.ListingTemplate
+	err := r.db.WithContext(ctx).First(&template, "connection_id = ?", connectionID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// SaveTemplate creates or updates a listing template
+func (r *ListingContentRepository) SaveTemplate(ctx context.Context, template *domain.ListingTemplate) error {
+	return r.db.WithContext(ctx).Save(template).Error
+}
+
+// DeleteTemplate deletes the listing template of a connection
+func (r *ListingContentRepository) DeleteTemplate(ctx context.Context, connectionID uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&domain.ListingTemplate{}, "connection_id = ?", connectionID).Error
+}
+
+// GetOverride retrieves the listing override of a product on a connection
+func (r *ListingContentRepository) GetOverride(ctx context.Context, connectionID, internalProductID uuid.UUID) (*domain.ListingOverride, error) {
+	var override domain.ListingOverride
+	err := r.db.WithContext(ctx).
+		Where("connection_id = ? AND internal_product_id = ?", connectionID, internalProductID).
+		First(&override).Error
+	if err != nil {
+		return nil, err
+	}
+	return &override, nil
+}
+
+// GetOverrides retrieves the listing overrides of a connection
+func (r *ListingContentRepository) GetOverrides(ctx context.Context, connectionID uuid.UUID, filter *domain.ListingOverrideFilter) ([]domain.ListingOverride, int64, error) {
+	var overrides []domain.ListingOverride
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.ListingOverride{}).Where("connection_id = ?", connectionID)
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination
+	page := 1
+	pageSize := 20
+	if filter != nil {
+		if filter.Page > 0 {
+			page = filter.Page
+		}
+		if filter.PageSize > 0 {
+			pageSize = filter.PageSize
+		}
+	}
+	offset := (page - 1) * pageSize
+
+	err := query.
+		Offset(offset).
+		Limit(pageSize).
+		Order("updated_at DESC").
+		Find(&overrides).Error
+
+	return overrides, total, err
+}
+
+// SaveOverride creates or updates a listing override
+func (r *ListingContentRepository) SaveOverride(ctx context.Context, override *domain.ListingOverride) error {
+	return r.db.WithContext(ctx).Save(override).Error
+}
+
+// DeleteOverride deletes the listing override of a product on a connection
+func (r *ListingContentRepository) DeleteOverride(ctx context.Context, connectionID, internalProductID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Delete(&domain.ListingOverride{}, "connection_id = ? AND internal_product_id = ?", connectionID, internalProductID).Error
+}
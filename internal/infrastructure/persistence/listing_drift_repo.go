@@ -0,0 +1,105 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListingDriftRepository handles database operations for listing drift reports
+type ListingDriftRepository struct {
+	db *gorm.DB
+}
+
+// NewListingDriftRepository creates a new ListingDriftRepository
+func NewListingDriftRepository(db *gorm.DB) *ListingDriftRepository {
+	return &ListingDriftRepository{db: db}
+}
+
+// Upsert creates or replaces the drift report of a product mapping
+func (r *ListingDriftRepository) Upsert(ctx context.Context, drift *domain.ListingDrift) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_mapping_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"internal_product_id", "external_product_id", "fields", "has_drift", "check_error", "image_baseline", "checked_at", "updated_at"}),
+	}).Create(drift).Error
+}
+
+// GetByMappingID retrieves the drift report of a product mapping
+func (r *ListingDriftRepository) GetByMappingID(ctx context.Context, productMappingID uuid.UUID) (*domain.ListingDrift, error) {
+	var drift domain.ListingDrift
+	err := r.db.WithContext(ctx).First(&drift, "product_mapping_id = ?", productMappingID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &drift, nil
+}
+
+// GetByMappingIDs retrieves the drift reports of several product mappings
+func (r *ListingDriftRepository) GetByMappingIDs(ctx context.Context, productMappingIDs []uuid.UUID) ([]domain.ListingDrift, error) {
+	var drifts []domain.ListingDrift
+	if len(productMappingIDs) == 0 {
+		return drifts, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("product_mapping_id IN ?", productMappingIDs).
+		Find(&drifts).Error
+	return drifts, err
+}
+
+// GetByConnectionID retrieves drift reports for a connection with filters
+func (r *ListingDriftRepository) GetByConnectionID(ctx context.Context, connectionID uuid.UUID, filter *domain.ListingDriftFilter) ([]domain.ListingDrift, int64, error) {
+	var drifts []domain.ListingDrift
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.ListingDrift{}).Where("connection_id = ?", connectionID)
+
+	if filter != nil {
+		if filter.HasDrift != nil {
+			query = query.Where("has_drift = ?", *filter.HasDrift)
+		}
+		if filter.Field != "" {
+			query = query.Where("fields @> ?::jsonb", `[{"field": "`+filter.Field+`"}]`)
+		}
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination
+	page := 1
+	pageSize := 20
+	if filter != nil {
+		if filter.Page > 0 {
+			page = filter.Page
+		}
+		if filter.PageSize > 0 {
+			pageSize = filter.PageSize
+		}
+	}
+	offset := (page - 1) * pageSize
+
+	err := query.
+		Offset(offset).
+		Limit(pageSize).
+		Order("checked_at DESC").
+		Find(&drifts).Error
+
+	return drifts, total, err
+}
+
+// UpdateFields stores the remaining drifted fields and the image baseline of a report
+func (r *ListingDriftRepository) UpdateFields(ctx context.Context, drift *domain.ListingDrift) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.ListingDrift{}).
+		Where("id = ?", drift.ID).
+		Updates(map[string]interface{}{
+			"fields":         drift.Fields,
+			"has_drift":      drift.HasDrift,
+			"image_baseline": drift.ImageBaseline,
+		}).Error
+}
@@ -0,0 +1,42 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrderSyncWatermarkRepository handles database operations for order sync watermarks
+type OrderSyncWatermarkRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderSyncWatermarkRepository creates a new OrderSyncWatermarkRepository
+func NewOrderSyncWatermarkRepository(db *gorm.DB) *OrderSyncWatermarkRepository {
+	return &OrderSyncWatermarkRepository{db: db}
+}
+
+// GetByConnectionID retrieves the watermark of a connection. Returns nil if its orders were never polled.
+func (r *OrderSyncWatermarkRepository) GetByConnectionID(ctx context.Context, connectionID uuid.UUID) (*domain.OrderSyncWatermark, error) {
+	var watermark domain.OrderSyncWatermark
+	err := r.db.WithContext(ctx).First(&watermark, "connection_id = ?", connectionID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &watermark, nil
+}
+
+// Upsert creates or updates the watermark of a connection
+func (r *OrderSyncWatermarkRepository) Upsert(ctx context.Context, watermark *domain.OrderSyncWatermark) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "connection_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"synced_to", "last_polled_at", "last_error", "failed_order_ids", "updated_at"}),
+	}).Create(watermark).Error
+}
@@ -0,0 +1,69 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PricingRuleRepository handles database operations for pricing rules
+type PricingRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewPricingRuleRepository creates a new PricingRuleRepository
+func NewPricingRuleRepository(db *gorm.DB) *PricingRuleRepository {
+	return &PricingRuleRepository{db: db}
+}
+
+// Create creates a new pricing rule
+func (r *PricingRuleRepository) Create(ctx context.Context, rule *domain.PricingRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+// GetByID retrieves a pricing rule by ID
+func (r *PricingRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.PricingRule, error) {
+	var rule domain.PricingRule
+	err := r.db.WithContext(ctx).First(&rule, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// GetByConnectionID retrieves all pricing rules for a connection, default rule first
+func (r *PricingRuleRepository) GetByConnectionID(ctx context.Context, connectionID uuid.UUID) ([]domain.PricingRule, error) {
+	var rules []domain.PricingRule
+	err := r.db.WithContext(ctx).
+		Where("connection_id = ?", connectionID).
+		Order("internal_category_id NULLS FIRST, created_at ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+// GetByConnectionAndCategory retrieves the rule for a category, or the default rule if categoryID is nil
+func (r *PricingRuleRepository) GetByConnectionAndCategory(ctx context.Context, connectionID uuid.UUID, categoryID *uuid.UUID) (*domain.PricingRule, error) {
+	var rule domain.PricingRule
+	query := r.db.WithContext(ctx).Where("connection_id = ?", connectionID)
+	if categoryID == nil {
+		query = query.Where("internal_category_id IS NULL")
+	} else {
+		query = query.Where("internal_category_id = ?", *categoryID)
+	}
+	if err := query.First(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Update updates a pricing rule
+func (r *PricingRuleRepository) Update(ctx context.Context, rule *domain.PricingRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+// Delete deletes a pricing rule
+func (r *PricingRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&domain.PricingRule{}, "id = ?", id).Error
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain/shared"
 	"gorm.io/gorm"
 )
 
@@ -76,6 +77,9 @@ func (r *ProductMappingRepository) GetByConnectionID(ctx context.Context, connec
 		if filter.InternalProductID != nil {
 			query = query.Where("internal_product_id = ?", *filter.InternalProductID)
 		}
+		if filter.ListingStatus != "" {
+			query = query.Where("listing_status = ?", filter.ListingStatus)
+		}
 	}
 
 	// Count total
@@ -105,6 +109,116 @@ func (r *ProductMappingRepository) GetByConnectionID(ctx context.Context, connec
 	return mappings, total, err
 }
 
+// GetByConnectionAndInternalProducts retrieves the mappings of several internal products with their variant mappings
+func (r *ProductMappingRepository) GetByConnectionAndInternalProducts(ctx context.Context, connectionID uuid.UUID, internalProductIDs []uuid.UUID) ([]domain.ProductMapping, error) {
+	var mappings []domain.ProductMapping
+	if len(internalProductIDs) == 0 {
+		return mappings, nil
+	}
+	err := r.db.WithContext(ctx).
+		Preload("VariantMappings").
+		Where("connection_id = ? AND internal_product_id IN ?", connectionID, internalProductIDs).
+		Find(&mappings).Error
+	return mappings, err
+}
+
+// GetByConnectionAndExternalProducts retrieves the mappings of several external products
+func (r *ProductMappingRepository) GetByConnectionAndExternalProducts(ctx context.Context, connectionID uuid.UUID, externalProductIDs []string) ([]domain.ProductMapping, error) {
+	var mappings []domain.ProductMapping
+	if len(externalProductIDs) == 0 {
+		return mappings, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("connection_id = ? AND external_product_id IN ?", connectionID, externalProductIDs).
+		Find(&mappings).Error
+	return mappings, err
+}
+
+// FindInBatchesByConnectionID calls fn with successive batches of a connection's mappings,
+// variant mappings included, so large connections can be streamed without loading them at once
+func (r *ProductMappingRepository) FindInBatchesByConnectionID(ctx context.Context, connectionID uuid.UUID, batchSize int, fn func([]domain.ProductMapping) error) error {
+	var mappings []domain.ProductMapping
+	return r.db.WithContext(ctx).
+		Preload("VariantMappings").
+		Where("connection_id = ?", connectionID).
+		FindInBatches(&mappings, batchSize, func(_ *gorm.DB, _ int) error {
+			return fn(mappings)
+		}).Error
+}
+
+// ImportMappings creates new mappings (zero ID) and updates the external IDs of existing ones
+// in a single transaction. The variant mappings of an updated mapping are replaced when it
+// carries any or moves to another listing, whose variants the old ones cannot belong to.
+// External IDs are released before being reassigned so mappings can swap them.
+func (r *ProductMappingRepository) ImportMappings(ctx context.Context, mappings []*domain.ProductMapping) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var moved []uuid.UUID
+		for _, m := range mappings {
+			if m.ID != uuid.Nil {
+				moved = append(moved, m.ID)
+			}
+		}
+		previous := make(map[uuid.UUID]string, len(moved))
+		if len(moved) > 0 {
+			var current []domain.ProductMapping
+			if err := tx.Select("id", "external_product_id").Where("id IN ?", moved).Find(&current).Error; err != nil {
+				return err
+			}
+			for _, c := range current {
+				previous[c.ID] = c.ExternalProductID
+			}
+
+			// Park external IDs on the (unique) mapping ID until the new ones are written
+			if err := tx.Model(&domain.ProductMapping{}).
+				Where("id IN ?", moved).
+				Update("external_product_id", gorm.Expr("id::text")).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, m := range mappings {
+			if m.ID == uuid.Nil {
+				continue
+			}
+			if err := tx.Model(&domain.ProductMapping{}).
+				Where("id = ?", m.ID).
+				Updates(map[string]interface{}{
+					"external_product_id": m.ExternalProductID,
+					"external_sku":        m.ExternalSKU,
+				}).Error; err != nil {
+				return err
+			}
+			if len(m.VariantMappings) == 0 && previous[m.ID] == m.ExternalProductID {
+				continue
+			}
+			if err := tx.Where("product_mapping_id = ?", m.ID).Delete(&domain.VariantMapping{}).Error; err != nil {
+				return err
+			}
+			if len(m.VariantMappings) == 0 {
+				continue
+			}
+			for i := range m.VariantMappings {
+				m.VariantMappings[i].ID = uuid.Nil
+				m.VariantMappings[i].ProductMappingID = m.ID
+			}
+			if err := tx.Create(&m.VariantMappings).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, m := range mappings {
+			if m.ID != uuid.Nil {
+				continue
+			}
+			// Variant mappings are created with the mapping
+			if err := tx.Create(m).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // GetByInternalProductID retrieves all mappings for an internal product across all connections
 func (r *ProductMappingRepository) GetByInternalProductID(ctx context.Context, internalProductID uuid.UUID) ([]domain.ProductMapping, error) {
 	var mappings []domain.ProductMapping
@@ -115,6 +229,16 @@ func (r *ProductMappingRepository) GetByInternalProductID(ctx context.Context, i
 	return mappings, err
 }
 
+// GetMappedInternalProductIDs returns the internal product IDs that have a mapping on a connection
+func (r *ProductMappingRepository) GetMappedInternalProductIDs(ctx context.Context, connectionID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&domain.ProductMapping{}).
+		Where("connection_id = ?", connectionID).
+		Pluck("internal_product_id", &ids).Error
+	return ids, err
+}
+
 // Update updates a product mapping
 func (r *ProductMappingRepository) Update(ctx context.Context, mapping *domain.ProductMapping) error {
 	return r.db.WithContext(ctx).Save(mapping).Error
@@ -136,6 +260,18 @@ func (r *ProductMappingRepository) UpdateSyncStatus(ctx context.Context, id uuid
 		Updates(updates).Error
 }
 
+// UpdateListingStatus updates the marketplace listing status of a mapping
+func (r *ProductMappingRepository) UpdateListingStatus(ctx context.Context, id uuid.UUID, status shared.ListingStatus, reason string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.ProductMapping{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"listing_status":     status,
+			"listing_reason":     reason,
+			"listing_updated_at": gorm.Expr("NOW()"),
+		}).Error
+}
+
 // Delete deletes a product mapping
 func (r *ProductMappingRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&domain.ProductMapping{}, "id = ?", id).Error
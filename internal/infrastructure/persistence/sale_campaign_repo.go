@@ -0,0 +1,104 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"gorm.io/gorm"
+)
+
+// SaleCampaignRepository handles database operations for sale campaigns
+type SaleCampaignRepository struct {
+	db *gorm.DB
+}
+
+// NewSaleCampaignRepository creates a new SaleCampaignRepository
+func NewSaleCampaignRepository(db *gorm.DB) *SaleCampaignRepository {
+	return &SaleCampaignRepository{db: db}
+}
+
+// Create creates a new sale campaign
+func (r *SaleCampaignRepository) Create(ctx context.Context, campaign *domain.SaleCampaign) error {
+	return r.db.WithContext(ctx).Create(campaign).Error
+}
+
+// Update updates a sale campaign
+func (r *SaleCampaignRepository) Update(ctx context.Context, campaign *domain.SaleCampaign) error {
+	return r.db.WithContext(ctx).Save(campaign).Error
+}
+
+// GetLatest retrieves the most recent sale campaign of a product on a connection
+func (r *SaleCampaignRepository) GetLatest(ctx context.Context, connectionID, internalProductID uuid.UUID) (*domain.SaleCampaign, error) {
+	var campaign domain.SaleCampaign
+	err := r.db.WithContext(ctx).
+		Where("connection_id = ? AND internal_product_id = ?", connectionID, internalProductID).
+		Order("created_at DESC").
+		First(&campaign).Error
+	if err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// GetActiveEndingBefore retrieves the active campaigns of all connections with an end time
+// before the given time
+func (r *SaleCampaignRepository) GetActiveEndingBefore(ctx context.Context, before time.Time) ([]domain.SaleCampaign, error) {
+	var campaigns []domain.SaleCampaign
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND ends_at < ?", domain.SaleCampaignActive, before).
+		Order("ends_at").
+		Find(&campaigns).Error
+	return campaigns, err
+}
+
+// GetRenewedRunning retrieves the renewed campaigns of a product on a connection that run
+// past the given time, latest end first
+func (r *SaleCampaignRepository) GetRenewedRunning(ctx context.Context, connectionID, internalProductID uuid.UUID, now time.Time) ([]domain.SaleCampaign, error) {
+	var campaigns []domain.SaleCampaign
+	err := r.db.WithContext(ctx).
+		Where("connection_id = ? AND internal_product_id = ? AND status = ? AND ends_at > ?",
+			connectionID, internalProductID, domain.SaleCampaignRenewed, now).
+		Order("ends_at DESC").
+		Find(&campaigns).Error
+	return campaigns, err
+}
+
+// GetByConnectionID retrieves sale campaigns for a connection with filters
+func (r *SaleCampaignRepository) GetByConnectionID(ctx context.Context, connectionID uuid.UUID, filter *domain.SaleCampaignFilter) ([]domain.SaleCampaign, int64, error) {
+	var campaigns []domain.SaleCampaign
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.SaleCampaign{}).Where("connection_id = ?", connectionID)
+
+	if filter != nil && filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination
+	page := 1
+	pageSize := 20
+	if filter != nil {
+		if filter.Page > 0 {
+			page = filter.Page
+		}
+		if filter.PageSize > 0 {
+			pageSize = filter.PageSize
+		}
+	}
+	offset := (page - 1) * pageSize
+
+	err := query.
+		Offset(offset).
+		Limit(pageSize).
+		Order("created_at DESC").
+		Find(&campaigns).Error
+
+	return campaigns, total, err
+}
@@ -0,0 +1,84 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StockAllocationRuleRepository handles database operations for stock allocation rules
+type StockAllocationRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewStockAllocationRuleRepository creates a new StockAllocationRuleRepository
+func NewStockAllocationRuleRepository(db *gorm.DB) *StockAllocationRuleRepository {
+	return &StockAllocationRuleRepository{db: db}
+}
+
+// Create creates a new stock allocation rule
+func (r *StockAllocationRuleRepository) Create(ctx context.Context, rule *domain.StockAllocationRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+// GetByID retrieves a stock allocation rule by ID
+func (r *StockAllocationRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.StockAllocationRule, error) {
+	var rule domain.StockAllocationRule
+	err := r.db.WithContext(ctx).First(&rule, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// GetByConnectionID retrieves all stock allocation rules for a connection, default rule first
+func (r *StockAllocationRuleRepository) GetByConnectionID(ctx context.Context, connectionID uuid.UUID) ([]domain.StockAllocationRule, error) {
+	var rules []domain.StockAllocationRule
+	err := r.db.WithContext(ctx).
+		Where("connection_id = ?", connectionID).
+		Order("internal_category_id NULLS FIRST, created_at ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+// GetActiveByConnectionID retrieves the active stock allocation rules for a connection
+func (r *StockAllocationRuleRepository) GetActiveByConnectionID(ctx context.Context, connectionID uuid.UUID) ([]domain.StockAllocationRule, error) {
+	var rules []domain.StockAllocationRule
+	err := r.db.WithContext(ctx).
+		Where("connection_id = ? AND is_active = true", connectionID).
+		Find(&rules).Error
+	return rules, err
+}
+
+// GetByConnectionAndCategory retrieves the rule for a category, or the default rule if categoryID is nil.
+// Returns nil if there is no such rule.
+func (r *StockAllocationRuleRepository) GetByConnectionAndCategory(ctx context.Context, connectionID uuid.UUID, categoryID *uuid.UUID) (*domain.StockAllocationRule, error) {
+	var rule domain.StockAllocationRule
+	query := r.db.WithContext(ctx).Where("connection_id = ?", connectionID)
+	if categoryID == nil {
+		query = query.Where("internal_category_id IS NULL")
+	} else {
+		query = query.Where("internal_category_id = ?", *categoryID)
+	}
+	err := query.First(&rule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Update updates a stock allocation rule
+func (r *StockAllocationRuleRepository) Update(ctx context.Context, rule *domain.StockAllocationRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+// Delete deletes a stock allocation rule
+func (r *StockAllocationRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&domain.StockAllocationRule{}, "id = ?", id).Error
+}
@@ -0,0 +1,135 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StockReservationRepository handles database operations for stock reservations
+type StockReservationRepository struct {
+	db *gorm.DB
+}
+
+// NewStockReservationRepository creates a new StockReservationRepository
+func NewStockReservationRepository(db *gorm.DB) *StockReservationRepository {
+	return &StockReservationRepository{db: db}
+}
+
+// CreateIfAbsent creates a reservation unless the order already reserved the product.
+// Returns whether it was created.
+func (r *StockReservationRepository) CreateIfAbsent(ctx context.Context, reservation *domain.StockReservation) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "connection_id"}, {Name: "external_order_id"}, {Name: "internal_product_id"}},
+			DoNothing: true,
+		}).
+		Create(reservation)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetPendingQuantity sums the pending reservations of a product created since the given time
+func (r *StockReservationRepository) GetPendingQuantity(ctx context.Context, productID uuid.UUID, since time.Time) (int, error) {
+	var quantity int
+	err := r.db.WithContext(ctx).
+		Model(&domain.StockReservation{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("internal_product_id = ? AND status = ? AND created_at >= ?", productID, domain.StockReservationPending, since).
+		Scan(&quantity).Error
+	return quantity, err
+}
+
+// GetPendingQuantities sums the pending reservations of products created since the given time,
+// keyed by product ID. Products without reservations are left out.
+func (r *StockReservationRepository) GetPendingQuantities(ctx context.Context, productIDs []uuid.UUID, since time.Time) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		InternalProductID uuid.UUID
+		Quantity          int
+	}
+	err := r.db.WithContext(ctx).
+		Model(&domain.StockReservation{}).
+		Select("internal_product_id, SUM(quantity) AS quantity").
+		Where("internal_product_id IN ? AND status = ? AND created_at >= ?", productIDs, domain.StockReservationPending, since).
+		Group("internal_product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	quantities := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		quantities[row.InternalProductID] = row.Quantity
+	}
+	return quantities, nil
+}
+
+// ReleaseByIDs releases pending reservations of a product by ID
+func (r *StockReservationRepository) ReleaseByIDs(ctx context.Context, productID uuid.UUID, ids []uuid.UUID, reason string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.StockReservation{}).
+		Where("id IN ? AND internal_product_id = ? AND status = ?", ids, productID, domain.StockReservationPending).
+		Updates(map[string]interface{}{
+			"status":         domain.StockReservationReleased,
+			"release_reason": reason,
+			"released_at":    time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ReleaseCovered releases the oldest pending reservations of a product created before the
+// given time, as long as their quantities add up to at most the given quantity
+func (r *StockReservationRepository) ReleaseCovered(ctx context.Context, productID uuid.UUID, before time.Time, quantity int, reason string) (int64, error) {
+	var released int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var pending []domain.StockReservation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("internal_product_id = ? AND status = ? AND created_at < ?", productID, domain.StockReservationPending, before).
+			Order("created_at ASC").
+			Find(&pending).Error
+		if err != nil {
+			return err
+		}
+
+		var ids []uuid.UUID
+		for _, reservation := range pending {
+			if reservation.Quantity > quantity {
+				break
+			}
+			quantity -= reservation.Quantity
+			ids = append(ids, reservation.ID)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		result := tx.Model(&domain.StockReservation{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":         domain.StockReservationReleased,
+				"release_reason": reason,
+				"released_at":    time.Now(),
+			})
+		released = result.RowsAffected
+		return result.Error
+	})
+	return released, err
+}
+
+// ReleaseByOrder releases the pending reservations of an order. Returns the released reservations.
+func (r *StockReservationRepository) ReleaseByOrder(ctx context.Context, connectionID uuid.UUID, externalOrderID, reason string) ([]domain.StockReservation, error) {
+	var reservations []domain.StockReservation
+	err := r.db.WithContext(ctx).
+		Model(&reservations).
+		Clauses(clause.Returning{}).
+		Where("connection_id = ? AND external_order_id = ? AND status = ?", connectionID, externalOrderID, domain.StockReservationPending).
+		Updates(map[string]interface{}{
+			"status":         domain.StockReservationReleased,
+			"release_reason": reason,
+			"released_at":    time.Now(),
+		}).Error
+	return reservations, err
+}
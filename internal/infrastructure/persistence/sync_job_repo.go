@@ -2,13 +2,19 @@ package persistence
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
 	"gorm.io/gorm"
 )
 
+// ErrActiveJobExists is returned when a connection already has a pending or processing job of an
+// exclusive type (see migration 026)
+var ErrActiveJobExists = errors.New("an active job of this type already exists for the connection")
+
 // SyncJobRepository handles database operations for sync jobs
 type SyncJobRepository struct {
 	db *gorm.DB
@@ -24,6 +30,56 @@ func (r *SyncJobRepository) Create(ctx context.Context, job *domain.SyncJob) err
 	return r.db.WithContext(ctx).Create(job).Error
 }
 
+// CreateExclusive creates a job of an exclusive type. If the connection already has an active job
+// of that type, nothing is created and the active job is returned with ErrActiveJobExists.
+// The unique index on active jobs decides, so concurrent requests cannot both create a job.
+func (r *SyncJobRepository) CreateExclusive(ctx context.Context, job *domain.SyncJob) (*domain.SyncJob, error) {
+	if err := r.db.WithContext(ctx).Create(job).Error; err != nil {
+		if isUniqueViolation(err) {
+			return r.activeConflict(ctx, job)
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// Requeue marks a failed job of an exclusive type pending again so it can be resumed. If the
+// job or another job of its type is already active for the connection, the active job is
+// returned with ErrActiveJobExists.
+func (r *SyncJobRepository) Requeue(ctx context.Context, job *domain.SyncJob) (*domain.SyncJob, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.SyncJob{}).
+		Where("id = ? AND status = ?", job.ID, domain.JobStatusFailed).
+		Update("status", domain.JobStatusPending)
+	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return r.activeConflict(ctx, job)
+		}
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		// Resumed by a concurrent request
+		return r.activeConflict(ctx, job)
+	}
+	job.Status = domain.JobStatusPending
+	return job, nil
+}
+
+// activeConflict returns the active job that kept job from becoming active, if it is still running
+func (r *SyncJobRepository) activeConflict(ctx context.Context, job *domain.SyncJob) (*domain.SyncJob, error) {
+	active, err := r.GetActiveByType(ctx, job.ConnectionID, job.JobType)
+	if err != nil {
+		return nil, ErrActiveJobExists
+	}
+	return active, ErrActiveJobExists
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
 // GetByID retrieves a sync job by ID
 func (r *SyncJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.SyncJob, error) {
 	var job domain.SyncJob
@@ -97,6 +153,56 @@ func (r *SyncJobRepository) GetFailedJobs(ctx context.Context, connectionID uuid
 	return jobs, err
 }
 
+// GetActiveByType retrieves the pending or processing job of a type for a connection
+func (r *SyncJobRepository) GetActiveByType(ctx context.Context, connectionID uuid.UUID, jobType string) (*domain.SyncJob, error) {
+	var job domain.SyncJob
+	err := r.db.WithContext(ctx).
+		Where("connection_id = ? AND job_type = ? AND status IN ?", connectionID, jobType,
+			[]string{domain.JobStatusPending, domain.JobStatusProcessing}).
+		Order("created_at DESC").
+		First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetLastCompletedByType retrieves the most recently completed job of a type for a connection
+func (r *SyncJobRepository) GetLastCompletedByType(ctx context.Context, connectionID uuid.UUID, jobType string) (*domain.SyncJob, error) {
+	var job domain.SyncJob
+	err := r.db.WithContext(ctx).
+		Where("connection_id = ? AND job_type = ? AND status = ?", connectionID, jobType, domain.JobStatusCompleted).
+		Order("completed_at DESC").
+		First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetUnfinishedByType retrieves pending or processing jobs of a type, e.g. to resume them after a restart
+func (r *SyncJobRepository) GetUnfinishedByType(ctx context.Context, jobType string) ([]domain.SyncJob, error) {
+	var jobs []domain.SyncJob
+	err := r.db.WithContext(ctx).
+		Where("job_type = ? AND status IN ?", jobType, []string{domain.JobStatusPending, domain.JobStatusProcessing}).
+		Order("created_at ASC").
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// UpdateProgress stores the payload (resume state) and item counters of a running job
+func (r *SyncJobRepository) UpdateProgress(ctx context.Context, job *domain.SyncJob) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.SyncJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]interface{}{
+			"payload":         job.Payload,
+			"total_items":     job.TotalItems,
+			"processed_items": job.ProcessedItems,
+			"failed_items":    job.FailedItems,
+		}).Error
+}
+
 // Update updates a sync job
 func (r *SyncJobRepository) Update(ctx context.Context, job *domain.SyncJob) error {
 	return r.db.WithContext(ctx).Save(job).Error
@@ -138,6 +244,19 @@ func (r *SyncJobRepository) MarkFailed(ctx context.Context, id uuid.UUID, errorM
 		}).Error
 }
 
+// FailUnfinishedByType marks pending or processing jobs of a type as failed, e.g. jobs whose
+// goroutine was lost to a restart. Returns how many jobs were failed.
+func (r *SyncJobRepository) FailUnfinishedByType(ctx context.Context, jobType, errorMessage string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.SyncJob{}).
+		Where("job_type = ? AND status IN ?", jobType, []string{domain.JobStatusPending, domain.JobStatusProcessing}).
+		Updates(map[string]interface{}{
+			"status":        domain.JobStatusFailed,
+			"error_message": errorMessage,
+		})
+	return result.RowsAffected, result.Error
+}
+
 // Delete deletes a sync job
 func (r *SyncJobRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&domain.SyncJob{}, "id = ?", id).Error
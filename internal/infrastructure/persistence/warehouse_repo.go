@@ -0,0 +1,60 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/Ecom-micro-template/service-marketplace/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WarehouseRepository handles database operations for warehouse mappings and stock levels
+type WarehouseRepository struct {
+	db *gorm.DB
+}
+
+// NewWarehouseRepository creates a new WarehouseRepository
+func NewWarehouseRepository(db *gorm.DB) *WarehouseRepository {
+	return &WarehouseRepository{db: db}
+}
+
+// GetMappings retrieves the warehouse mappings of a connection
+func (r *WarehouseRepository) GetMappings(ctx context.Context, connectionID uuid.UUID) ([]domain.WarehouseMapping, error) {
+	var mappings []domain.WarehouseMapping
+	err := r.db.WithContext(ctx).
+		Where("connection_id = ?", connectionID).
+		Order("is_default DESC, internal_warehouse_id").
+		Find(&mappings).Error
+	return mappings, err
+}
+
+// ReplaceMappings replaces the warehouse mappings of a connection in a single transaction
+func (r *WarehouseRepository) ReplaceMappings(ctx context.Context, connectionID uuid.UUID, mappings []domain.WarehouseMapping) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&domain.WarehouseMapping{}, "connection_id = ?", connectionID).Error; err != nil {
+			return err
+		}
+		if len(mappings) == 0 {
+			return nil
+		}
+		return tx.Create(&mappings).Error
+	})
+}
+
+// UpsertStockLevel records the stock of a product in a warehouse
+func (r *WarehouseRepository) UpsertStockLevel(ctx context.Context, level *domain.WarehouseStockLevel) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "internal_product_id"}, {Name: "warehouse_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"quantity", "updated_at"}),
+	}).Create(level).Error
+}
+
+// GetStockLevels retrieves the known warehouse stock of a product
+func (r *WarehouseRepository) GetStockLevels(ctx context.Context, productID uuid.UUID) ([]domain.WarehouseStockLevel, error) {
+	var levels []domain.WarehouseStockLevel
+	err := r.db.WithContext(ctx).
+		Where("internal_product_id = ?", productID).
+		Find(&levels).Error
+	return levels, err
+}
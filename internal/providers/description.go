@@ -0,0 +1,437 @@
+package providers
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// Description block styles
+const (
+	BlockParagraph = "paragraph"
+	BlockHeading   = "heading"
+	BlockBullet    = "bullet"
+	BlockNumbered  = "numbered"
+	BlockImage     = "image"
+)
+
+// DescriptionBlock is one paragraph, heading, list item or image of a product description.
+// Descriptions are kept as blocks so each platform can render its own format and shorten
+// them without breaking markup.
+type DescriptionBlock struct {
+	Style    string `json:"style"`
+	Text     string `json:"text,omitempty"`      // Plain text, line breaks kept
+	Number   int    `json:"number,omitempty"`    // Position in a numbered list
+	ImageURL string `json:"image_url,omitempty"` // Image blocks only
+	Pinned   bool   `json:"pinned,omitempty"`    // Header and footer blocks, kept when shortening
+}
+
+// minShortenedBlockRunes is the shortest cut-down block worth keeping when shortening
+const minShortenedBlockRunes = 20
+
+var (
+	whitespacePattern = regexp.MustCompile(`\s+`)
+	htmlTagPattern    = regexp.MustCompile(`(?i)<(p|div|br|span|ul|ol|li|h[1-6]|img|strong|b|em|i|u|a|table|section)[\s>/]`)
+	linkPattern       = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+	mdImagePattern    = regexp.MustCompile(`!\[([^\]]*)\]\(\s*(\S+?)(?:\s+"[^"]*")?\s*\)`)
+	mdLinkPattern     = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	mdStrongPattern   = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	mdEmPattern       = regexp.MustCompile(`\*([^*\s][^*]*)\*|\b_([^_]+)_\b`)
+	mdCodePattern     = regexp.MustCompile("`([^`]*)`")
+	mdEscapePattern   = regexp.MustCompile(`\\([\\` + "`" + `*_{}\[\]()#+\-.!])`)
+	mdHeadingPattern  = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*$`)
+	mdBulletPattern   = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	mdNumberPattern   = regexp.MustCompile(`^(\d{1,4})[.)]\s+(.*)$`)
+	mdRulePattern     = regexp.MustCompile(`^(?:-{3,}|\*{3,}|_{3,})$`)
+)
+
+// BuildDescription parses a product description with a header and footer (shipping notes,
+// warranty text, ...) into blocks. Header and footer blocks are pinned.
+func BuildDescription(header, body, footer string) []DescriptionBlock {
+	var blocks []DescriptionBlock
+	for _, b := range ParseDescription(header) {
+		b.Pinned = true
+		blocks = append(blocks, b)
+	}
+	blocks = append(blocks, ParseDescription(body)...)
+	for _, b := range ParseDescription(footer) {
+		b.Pinned = true
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+// DescriptionContent returns the description blocks of a push request
+func (r *ProductPushRequest) DescriptionContent() []DescriptionBlock {
+	if len(r.DescriptionBlocks) > 0 {
+		return r.DescriptionBlocks
+	}
+	return ParseDescription(r.Description)
+}
+
+// DescriptionContent returns the description blocks of an update request
+func (r *ProductUpdateRequest) DescriptionContent() []DescriptionBlock {
+	if len(r.DescriptionBlocks) > 0 {
+		return r.DescriptionBlocks
+	}
+	return ParseDescription(r.Description)
+}
+
+// ParseDescription breaks an HTML or Markdown description into blocks. Plain text is read
+// as Markdown. Scripts, styles and other markup are stripped, and so are links: marketplaces
+// refuse external links, so only the link text is kept.
+func ParseDescription(source string) []DescriptionBlock {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil
+	}
+	if htmlTagPattern.MatchString(source) {
+		return parseHTMLDescription(source)
+	}
+	return parseMarkdownDescription(source)
+}
+
+// listState tracks the list an HTML list item belongs to
+type listState struct {
+	ordered bool
+	count   int
+}
+
+func parseHTMLDescription(source string) []DescriptionBlock {
+	var (
+		blocks []DescriptionBlock
+		text   strings.Builder
+		style  = BlockParagraph
+		number int
+		lists  []listState
+		skip   int // Depth inside elements whose content is never published
+	)
+	flush := func() {
+		if t := cleanDescriptionText(text.String()); t != "" {
+			blocks = append(blocks, DescriptionBlock{Style: style, Text: t, Number: number})
+		}
+		text.Reset()
+		style = BlockParagraph
+		number = 0
+	}
+	// Generic containers end the current block without resetting a list item or heading style
+	breakBlock := func() {
+		if strings.TrimSpace(text.String()) != "" {
+			flush()
+		}
+	}
+
+	z := html.NewTokenizer(strings.NewReader(source))
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			flush()
+			return blocks
+
+		case html.TextToken:
+			if skip == 0 {
+				// Source line breaks are plain whitespace in HTML; only <br> breaks lines
+				text.WriteString(whitespacePattern.ReplaceAllString(string(z.Text()), " "))
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch tag := string(name); tag {
+			case "script", "style", "iframe", "noscript", "object", "svg", "template":
+				if tt == html.StartTagToken {
+					skip++
+				}
+			case "br":
+				text.WriteByte('\n')
+			case "img":
+				breakBlock()
+				if src := tagAttr(z, hasAttr, "src"); strings.HasPrefix(src, "http") {
+					blocks = append(blocks, DescriptionBlock{Style: BlockImage, ImageURL: src})
+				}
+			case "ul", "ol":
+				flush()
+				lists = append(lists, listState{ordered: tag == "ol"})
+			case "li":
+				flush()
+				style = BlockBullet
+				if n := len(lists); n > 0 && lists[n-1].ordered {
+					lists[n-1].count++
+					style = BlockNumbered
+					number = lists[n-1].count
+				}
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				flush()
+				style = BlockHeading
+			case "p", "div", "section", "article", "blockquote", "table", "tr", "hr", "header", "footer":
+				breakBlock()
+			}
+
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch tag := string(name); tag {
+			case "script", "style", "iframe", "noscript", "object", "svg", "template":
+				if skip > 0 {
+					skip--
+				}
+			case "ul", "ol":
+				flush()
+				if len(lists) > 0 {
+					lists = lists[:len(lists)-1]
+				}
+			case "li", "h1", "h2", "h3", "h4", "h5", "h6":
+				flush()
+			case "p", "div", "section", "article", "blockquote", "table", "tr", "header", "footer":
+				breakBlock()
+			}
+		}
+	}
+}
+
+// tagAttr returns an attribute of the current tag
+func tagAttr(z *html.Tokenizer, hasAttr bool, name string) string {
+	for hasAttr {
+		var key, value []byte
+		key, value, hasAttr = z.TagAttr()
+		if string(key) == name {
+			return strings.TrimSpace(string(value))
+		}
+	}
+	return ""
+}
+
+func parseMarkdownDescription(source string) []DescriptionBlock {
+	var (
+		blocks    []DescriptionBlock
+		paragraph []string
+	)
+	add := func(style, text string, number int) {
+		if t := cleanDescriptionText(markdownInline(text)); t != "" {
+			blocks = append(blocks, DescriptionBlock{Style: style, Text: t, Number: number})
+		}
+	}
+	flush := func() {
+		// Lines of a paragraph are soft-wrapped
+		add(BlockParagraph, strings.Join(paragraph, " "), 0)
+		paragraph = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if m := mdImagePattern.FindStringSubmatch(line); m != nil && m[0] == line {
+			flush()
+			if strings.HasPrefix(m[2], "http") {
+				blocks = append(blocks, DescriptionBlock{Style: BlockImage, ImageURL: m[2]})
+			}
+			continue
+		}
+		switch {
+		case line == "" || mdRulePattern.MatchString(line):
+			flush()
+		case mdHeadingPattern.MatchString(line):
+			flush()
+			add(BlockHeading, mdHeadingPattern.FindStringSubmatch(line)[1], 0)
+		case mdBulletPattern.MatchString(line):
+			flush()
+			add(BlockBullet, mdBulletPattern.FindStringSubmatch(line)[1], 0)
+		case mdNumberPattern.MatchString(line):
+			flush()
+			m := mdNumberPattern.FindStringSubmatch(line)
+			number, _ := strconv.Atoi(m[1])
+			add(BlockNumbered, m[2], number)
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// markdownInline strips inline Markdown, keeping link and emphasis text
+func markdownInline(text string) string {
+	text = mdImagePattern.ReplaceAllString(text, "")
+	text = mdLinkPattern.ReplaceAllString(text, "$1")
+	text = mdCodePattern.ReplaceAllString(text, "$1")
+	text = mdStrongPattern.ReplaceAllString(text, "$2")
+	text = mdEmPattern.ReplaceAllString(text, "$1$2")
+	return mdEscapePattern.ReplaceAllString(text, "$1")
+}
+
+// cleanDescriptionText removes links and collapses whitespace, keeping single line breaks
+func cleanDescriptionText(text string) string {
+	text = linkPattern.ReplaceAllString(text, "")
+	lines := strings.Split(text, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// RenderPlainText renders description blocks as plain text. Images are left out.
+func RenderPlainText(blocks []DescriptionBlock) string {
+	var b strings.Builder
+	var prev *DescriptionBlock
+	for i := range blocks {
+		block := &blocks[i]
+		if block.Style == BlockImage {
+			continue
+		}
+		if prev != nil {
+			// List items stay together; other blocks are separated by a blank line
+			if isListItem(block) && prev.Style == block.Style {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+		switch block.Style {
+		case BlockBullet:
+			b.WriteString("• ")
+		case BlockNumbered:
+			b.WriteString(strconv.Itoa(block.Number) + ". ")
+		}
+		b.WriteString(block.Text)
+		prev = block
+	}
+	return b.String()
+}
+
+// RenderHTML renders description blocks as basic HTML: paragraphs, bold headings, lists and
+// line breaks. Images are left out as they must be hosted by the marketplace.
+func RenderHTML(blocks []DescriptionBlock) string {
+	var b strings.Builder
+	list := ""
+	for _, block := range blocks {
+		if block.Style == BlockImage {
+			continue
+		}
+		tag := ""
+		switch block.Style {
+		case BlockBullet:
+			tag = "ul"
+		case BlockNumbered:
+			tag = "ol"
+		}
+		if tag != list {
+			if list != "" {
+				b.WriteString("</" + list + ">")
+			}
+			if tag != "" {
+				b.WriteString("<" + tag + ">")
+			}
+			list = tag
+		}
+
+		text := strings.ReplaceAll(html.EscapeString(block.Text), "\n", "<br>")
+		switch block.Style {
+		case BlockHeading:
+			b.WriteString("<p><strong>" + text + "</strong></p>")
+		case BlockBullet, BlockNumbered:
+			b.WriteString("<li>" + text + "</li>")
+		default:
+			b.WriteString("<p>" + text + "</p>")
+		}
+	}
+	if list != "" {
+		b.WriteString("</" + list + ">")
+	}
+	return b.String()
+}
+
+// FitDescription shortens a description until render fits in maxRunes (0 for no limit).
+// Pinned blocks are always kept; the other blocks are kept in order while they fit and the
+// one crossing the limit is cut at a sentence boundary. Blocks after it are dropped.
+// The result can still be too long when the pinned blocks alone exceed the limit.
+func FitDescription(blocks []DescriptionBlock, maxRunes int, render func([]DescriptionBlock) string) []DescriptionBlock {
+	if maxRunes <= 0 || utf8.RuneCountInString(render(blocks)) <= maxRunes {
+		return blocks
+	}
+
+	working := make([]DescriptionBlock, len(blocks))
+	copy(working, blocks)
+	keep := make([]bool, len(blocks))
+	for i := range working {
+		keep[i] = working[i].Pinned
+	}
+	kept := func() []DescriptionBlock {
+		result := make([]DescriptionBlock, 0, len(working))
+		for i := range working {
+			if keep[i] {
+				result = append(result, working[i])
+			}
+		}
+		return result
+	}
+	fits := func() bool {
+		return utf8.RuneCountInString(render(kept())) <= maxRunes
+	}
+
+	for i := range working {
+		if working[i].Pinned {
+			continue
+		}
+		keep[i] = true
+		if fits() {
+			continue
+		}
+
+		// Shorten the block crossing the limit to the room left for its text
+		text := working[i].Text
+		keep[i] = false
+		if working[i].Style != BlockImage && text != "" {
+			working[i].Text = ""
+			keep[i] = true
+			room := maxRunes - utf8.RuneCountInString(render(kept()))
+			keep[i] = false
+			if room >= minShortenedBlockRunes {
+				working[i].Text = TruncateText(text, room)
+				keep[i] = true
+				if !fits() {
+					keep[i] = false
+				}
+			}
+		}
+		break
+	}
+	return kept()
+}
+
+// TruncateText shortens text to at most maxRunes characters without splitting a character.
+// It ends on the last complete sentence or line when one ends past half of the limit,
+// otherwise on a word boundary followed by an ellipsis.
+func TruncateText(text string, maxRunes int) string {
+	runes := []rune(text)
+	if maxRunes <= 0 || len(runes) <= maxRunes {
+		return text
+	}
+
+	for i := maxRunes - 1; i >= maxRunes/2; i-- {
+		// Full-width punctuation ends a sentence without a following space
+		if runes[i+1] == '\n' || strings.ContainsRune("。！？", runes[i]) ||
+			strings.ContainsRune(".!?", runes[i]) && unicode.IsSpace(runes[i+1]) {
+			return strings.TrimRightFunc(string(runes[:i+1]), unicode.IsSpace)
+		}
+	}
+
+	cut := runes[:maxRunes-1] // Room for the ellipsis
+	for i := len(cut) - 1; i >= len(cut)/2; i-- {
+		if unicode.IsSpace(cut[i]) {
+			cut = cut[:i]
+			break
+		}
+	}
+	return strings.TrimRightFunc(string(cut), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
+
+func isListItem(block *DescriptionBlock) bool {
+	return block.Style == BlockBullet || block.Style == BlockNumbered
+}
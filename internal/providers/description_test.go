@@ -0,0 +1,247 @@
+package providers
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxRunes int
+		want     string
+	}{
+		{
+			name:     "fits",
+			text:     "Short text.",
+			maxRunes: 20,
+			want:     "Short text.",
+		},
+		{
+			name:     "no limit",
+			text:     "Short text.",
+			maxRunes: 0,
+			want:     "Short text.",
+		},
+		{
+			name:     "ends on the last sentence",
+			text:     "First sentence here. Second sentence is longer than the limit.",
+			maxRunes: 30,
+			want:     "First sentence here.",
+		},
+		{
+			name:     "ends on a line break",
+			text:     "Cotton shirt for daily wear\nMachine washable at 30 degrees",
+			maxRunes: 40,
+			want:     "Cotton shirt for daily wear",
+		},
+		{
+			name:     "sentence before half of the limit is ignored",
+			text:     "Hi. This description goes on without any sentence end",
+			maxRunes: 30,
+			want:     "Hi. This description goes on…",
+		},
+		{
+			name:     "cuts on a word boundary with an ellipsis",
+			text:     "one two three four five six seven",
+			maxRunes: 16,
+			want:     "one two three…",
+		},
+		{
+			name:     "trailing punctuation is dropped before the ellipsis",
+			text:     "apples, pears, plums and cherries",
+			maxRunes: 16,
+			want:     "apples, pears…",
+		},
+		{
+			name:     "counts characters, not bytes",
+			text:     "Kemeja katun ringan。Sangat nyaman dipakai setiap hari",
+			maxRunes: 25,
+			want:     "Kemeja katun ringan。",
+		},
+		{
+			name:     "never splits a multibyte character",
+			text:     "日本語の説明文はとても長いです日本語の説明文",
+			maxRunes: 10,
+			want:     "日本語の説明文はと…",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateText(tt.text, tt.maxRunes)
+			if got != tt.want {
+				t.Errorf("TruncateText() = %q, want %q", got, tt.want)
+			}
+			if tt.maxRunes > 0 && utf8.RuneCountInString(got) > tt.maxRunes {
+				t.Errorf("TruncateText() is %d characters, limit %d", utf8.RuneCountInString(got), tt.maxRunes)
+			}
+		})
+	}
+}
+
+func TestParseDescription(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []DescriptionBlock
+	}{
+		{
+			name:   "empty",
+			source: "  \n ",
+			want:   nil,
+		},
+		{
+			name:   "plain text paragraphs",
+			source: "First line\nsoft wrapped\n\nSecond paragraph",
+			want: []DescriptionBlock{
+				{Style: BlockParagraph, Text: "First line soft wrapped"},
+				{Style: BlockParagraph, Text: "Second paragraph"},
+			},
+		},
+		{
+			name:   "markdown",
+			source: "## Features\n- **Soft** cotton\n- [Easy care](https://example.com/care)\n\n1. Wash cold\n2) Dry flat\n\n---\n![front](https://cdn.example.com/a.jpg)",
+			want: []DescriptionBlock{
+				{Style: BlockHeading, Text: "Features"},
+				{Style: BlockBullet, Text: "Soft cotton"},
+				{Style: BlockBullet, Text: "Easy care"},
+				{Style: BlockNumbered, Text: "Wash cold", Number: 1},
+				{Style: BlockNumbered, Text: "Dry flat", Number: 2},
+				{Style: BlockImage, ImageURL: "https://cdn.example.com/a.jpg"},
+			},
+		},
+		{
+			name:   "markdown links are removed",
+			source: "Visit https://shop.example.com for more",
+			want: []DescriptionBlock{
+				{Style: BlockParagraph, Text: "Visit for more"},
+			},
+		},
+		{
+			name:   "html",
+			source: `<h2>Details</h2><p>Soft <b>cotton</b><br>shirt</p><ul><li>Light</li><li>Breathable</li></ul><ol><li>Wash</li><li>Dry</li></ol><img src="https://cdn.example.com/b.jpg">`,
+			want: []DescriptionBlock{
+				{Style: BlockHeading, Text: "Details"},
+				{Style: BlockParagraph, Text: "Soft cotton\nshirt"},
+				{Style: BlockBullet, Text: "Light"},
+				{Style: BlockBullet, Text: "Breathable"},
+				{Style: BlockNumbered, Text: "Wash", Number: 1},
+				{Style: BlockNumbered, Text: "Dry", Number: 2},
+				{Style: BlockImage, ImageURL: "https://cdn.example.com/b.jpg"},
+			},
+		},
+		{
+			name:   "html scripts, styles and relative images are dropped",
+			source: `<p>Kept</p><script>alert(1)</script><style>p{}</style><img src="/local.jpg"><p>Also <a href="https://example.com">kept</a></p>`,
+			want: []DescriptionBlock{
+				{Style: BlockParagraph, Text: "Kept"},
+				{Style: BlockParagraph, Text: "Also kept"},
+			},
+		},
+		{
+			name:   "html whitespace collapses",
+			source: "<p>Line\n   wrapped   in source</p>",
+			want: []DescriptionBlock{
+				{Style: BlockParagraph, Text: "Line wrapped in source"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseDescription(tt.source)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDescription() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitDescription(t *testing.T) {
+	header := DescriptionBlock{Style: BlockParagraph, Text: "Free shipping.", Pinned: true}
+	footer := DescriptionBlock{Style: BlockParagraph, Text: "1 year warranty.", Pinned: true}
+	long := DescriptionBlock{Style: BlockParagraph, Text: "A breathable cotton shirt. It keeps you cool all day long. Wash at thirty degrees."}
+
+	tests := []struct {
+		name     string
+		blocks   []DescriptionBlock
+		maxRunes int
+		want     []DescriptionBlock
+	}{
+		{
+			name:     "fits",
+			blocks:   []DescriptionBlock{header, long, footer},
+			maxRunes: 200,
+			want:     []DescriptionBlock{header, long, footer},
+		},
+		{
+			name:     "no limit",
+			blocks:   []DescriptionBlock{header, long, footer},
+			maxRunes: 0,
+			want:     []DescriptionBlock{header, long, footer},
+		},
+		{
+			name:     "crossing block is cut at a sentence and pinned blocks are kept",
+			blocks:   []DescriptionBlock{header, long, footer},
+			maxRunes: 95,
+			want: []DescriptionBlock{
+				header,
+				{Style: BlockParagraph, Text: "A breathable cotton shirt. It keeps you cool all day long."},
+				footer,
+			},
+		},
+		{
+			name: "blocks after the crossing block are dropped",
+			blocks: []DescriptionBlock{
+				{Style: BlockParagraph, Text: "Short intro."},
+				long,
+				{Style: BlockBullet, Text: "Dropped"},
+			},
+			maxRunes: 45,
+			want: []DescriptionBlock{
+				{Style: BlockParagraph, Text: "Short intro."},
+				{Style: BlockParagraph, Text: "A breathable cotton shirt."},
+			},
+		},
+		{
+			name:     "crossing block without enough room is dropped",
+			blocks:   []DescriptionBlock{header, long, footer},
+			maxRunes: 45,
+			want:     []DescriptionBlock{header, footer},
+		},
+		{
+			name:     "pinned blocks alone may exceed the limit",
+			blocks:   []DescriptionBlock{header, long, footer},
+			maxRunes: 10,
+			want:     []DescriptionBlock{header, footer},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := append([]DescriptionBlock(nil), tt.blocks...)
+			got := FitDescription(tt.blocks, tt.maxRunes, RenderPlainText)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FitDescription() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.blocks, original) {
+				t.Errorf("FitDescription() modified its input")
+			}
+			if text := RenderPlainText(got); tt.maxRunes > 0 && utf8.RuneCountInString(text) > tt.maxRunes && !allPinned(got) {
+				t.Errorf("FitDescription() rendered %d characters, limit %d: %q", utf8.RuneCountInString(text), tt.maxRunes, text)
+			}
+		})
+	}
+}
+
+func allPinned(blocks []DescriptionBlock) bool {
+	for _, b := range blocks {
+		if !b.Pinned {
+			return false
+		}
+	}
+	return true
+}
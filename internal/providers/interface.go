@@ -66,6 +66,7 @@ type ProductPushRequest struct {
 	Price         float64           `json:"price"`
 	OriginalPrice float64           `json:"original_price,omitempty"`
 	Stock         int               `json:"stock"`
+	Warehouses    []WarehouseStock  `json:"warehouses,omitempty"` // Stock per marketplace warehouse; Stock is their total
 	SKU           string            `json:"sku"`
 	CategoryID    string            `json:"category_id"`
 	Images        []string          `json:"images"`
@@ -74,7 +75,15 @@ type ProductPushRequest struct {
 	Variants      []VariantRequest  `json:"variants,omitempty"`
 	Attributes    map[string]string `json:"attributes,omitempty"`
 	Brand         string            `json:"brand,omitempty"`
-	Condition     string            `json:"condition,omitempty"` // new, used
+	BrandID       string            `json:"brand_id,omitempty"`   // Resolved marketplace brand ID, empty for no brand
+	BrandName     string            `json:"brand_name,omitempty"` // Marketplace name of BrandID
+	Condition     string            `json:"condition,omitempty"`  // new, used
+	AsDraft       bool              `json:"as_draft,omitempty"`   // Create the listing unpublished
+
+	// DescriptionBlocks is the structured description each platform renders in its own
+	// format; when empty Description is parsed instead
+	DescriptionBlocks   []DescriptionBlock `json:"description_blocks,omitempty"`
+	ExtendedDescription bool               `json:"extended_description,omitempty"` // Shopee: publish description images as an extended description
 }
 
 // Dimensions represents product dimensions
@@ -117,6 +126,33 @@ type ProductUpdateRequest struct {
 	Stock         *int              `json:"stock,omitempty"`
 	Images        []string          `json:"images,omitempty"`
 	Attributes    map[string]string `json:"attributes,omitempty"`
+
+	DescriptionBlocks   []DescriptionBlock `json:"description_blocks,omitempty"`   // See ProductPushRequest
+	ExtendedDescription bool               `json:"extended_description,omitempty"` // See ProductPushRequest
+}
+
+// DiscountRequest represents a time-limited sale price of one listing, published as a
+// Shopee discount or a TikTok Shop promotion
+type DiscountRequest struct {
+	Name              string             `json:"name"`
+	ExternalProductID string             `json:"external_product_id"`
+	Prices            map[string]float64 `json:"prices"` // Sale price by model / SKU ID; "0" is a Shopee item without models
+	StartTime         time.Time          `json:"start_time"`
+	EndTime           time.Time          `json:"end_time"` // Zero for the longest duration the marketplace allows
+}
+
+// DiscountResponse represents a created discount. The window may differ from the requested
+// one where the marketplace requires a lead time or limits the duration.
+type DiscountResponse struct {
+	DiscountID string    `json:"discount_id"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+}
+
+// ListingStatusFailure reports a listing the marketplace refused to unlist or relist
+type ListingStatusFailure struct {
+	ExternalProductID string `json:"external_product_id"`
+	Reason            string `json:"reason"`
 }
 
 // ExternalCategory represents a marketplace category
@@ -128,11 +164,20 @@ type ExternalCategory struct {
 	Children     []ExternalCategory `json:"children,omitempty"`
 }
 
+// ExternalBrand represents a marketplace brand
+type ExternalBrand struct {
+	BrandID     string `json:"brand_id"`
+	BrandName   string `json:"brand_name"`
+	DisplayName string `json:"display_name,omitempty"`
+}
+
 // InventoryUpdate represents a stock update
 type InventoryUpdate struct {
-	ExternalProductID string `json:"external_product_id"`
-	ExternalSKU       string `json:"external_sku,omitempty"`
-	Quantity          int    `json:"quantity"`
+	ExternalProductID string           `json:"external_product_id"`
+	ExternalSKU       string           `json:"external_sku,omitempty"`
+	ExternalVariantID string           `json:"external_variant_id,omitempty"` // Shopee model or TikTok SKU to update; the whole listing when empty
+	Quantity          int              `json:"quantity"`
+	Warehouses        []WarehouseStock `json:"warehouses,omitempty"` // Stock per marketplace warehouse; Quantity is their total
 }
 
 // InventoryItem represents inventory status from marketplace
@@ -1,9 +1,53 @@
 package providers
 
+// WarehouseStock is the stock of a listing in one marketplace warehouse: a Shopee
+// seller_stock location_id or a TikTok Shop warehouse_id
+type WarehouseStock struct {
+	WarehouseID string `json:"warehouse_id"`
+	Quantity    int    `json:"quantity"`
+}
+
 // InventoryUpdateResult represents the result of an inventory update
 type InventoryUpdateResult struct {
 	ExternalProductID string `json:"external_product_id"`
 	ExternalSKU       string `json:"external_sku,omitempty"`
+	ExternalVariantID string `json:"external_variant_id,omitempty"`
 	Success           bool   `json:"success"`
 	Error             string `json:"error,omitempty"`
 }
+
+// NewInventoryUpdateResult creates the result of an update, failed when err is not nil
+func NewInventoryUpdateResult(update InventoryUpdate, err error) InventoryUpdateResult {
+	result := InventoryUpdateResult{
+		ExternalProductID: update.ExternalProductID,
+		ExternalSKU:       update.ExternalSKU,
+		ExternalVariantID: update.ExternalVariantID,
+		Success:           err == nil,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// GroupInventoryUpdates returns the indexes of updates grouped by listing, in order of first
+// appearance, each group split into chunks of at most size updates
+func GroupInventoryUpdates(updates []InventoryUpdate, size int) [][]int {
+	var order []string
+	groups := make(map[string][]int)
+	for i, u := range updates {
+		if _, ok := groups[u.ExternalProductID]; !ok {
+			order = append(order, u.ExternalProductID)
+		}
+		groups[u.ExternalProductID] = append(groups[u.ExternalProductID], i)
+	}
+
+	var chunks [][]int
+	for _, id := range order {
+		indexes := groups[id]
+		for start := 0; start < len(indexes); start += size {
+			chunks = append(chunks, indexes[start:min(start+size, len(indexes))])
+		}
+	}
+	return chunks
+}
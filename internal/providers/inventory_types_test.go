@@ -0,0 +1,64 @@
+package providers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupInventoryUpdates(t *testing.T) {
+	update := func(productID string) InventoryUpdate {
+		return InventoryUpdate{ExternalProductID: productID}
+	}
+
+	tests := []struct {
+		name    string
+		updates []InventoryUpdate
+		size    int
+		want    [][]int
+	}{
+		{
+			name: "no updates",
+			size: 50,
+			want: nil,
+		},
+		{
+			name:    "one listing",
+			updates: []InventoryUpdate{update("1"), update("1"), update("1")},
+			size:    50,
+			want:    [][]int{{0, 1, 2}},
+		},
+		{
+			name:    "listings in order of first appearance",
+			updates: []InventoryUpdate{update("2"), update("1"), update("2"), update("3"), update("1")},
+			size:    50,
+			want:    [][]int{{0, 2}, {1, 4}, {3}},
+		},
+		{
+			name:    "large listings are split into chunks",
+			updates: []InventoryUpdate{update("1"), update("1"), update("2"), update("1"), update("1"), update("1")},
+			size:    2,
+			want:    [][]int{{0, 1}, {3, 4}, {5}, {2}},
+		},
+		{
+			name:    "listing filling chunks exactly",
+			updates: []InventoryUpdate{update("1"), update("1"), update("1"), update("1")},
+			size:    2,
+			want:    [][]int{{0, 1}, {2, 3}},
+		},
+		{
+			name:    "chunks of one",
+			updates: []InventoryUpdate{update("1"), update("2"), update("1")},
+			size:    1,
+			want:    [][]int{{0}, {2}, {1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GroupInventoryUpdates(tt.updates, tt.size)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GroupInventoryUpdates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -11,8 +11,17 @@ type OrderListParams struct {
 	Status   string    `json:"status,omitempty"`
 	PageSize int       `json:"page_size"`
 	Cursor   string    `json:"cursor,omitempty"`
+
+	// TimeField is the order time TimeFrom and TimeTo apply to; empty means OrderTimeFieldCreate
+	TimeField string `json:"time_field,omitempty"`
 }
 
+// Order time fields an order list can be queried by
+const (
+	OrderTimeFieldCreate = "create_time"
+	OrderTimeFieldUpdate = "update_time"
+)
+
 // ExternalOrderItem represents an order line item
 type ExternalOrderItem struct {
 	ExternalProductID string  `json:"external_product_id"`
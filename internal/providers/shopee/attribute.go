@@ -0,0 +1,135 @@
+package shopee
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Attribute API paths
+	GetAttributesPath = "/api/v2/product/get_attributes"
+)
+
+// Attribute is a product attribute of a Shopee category, e.g. Material
+type Attribute struct {
+	AttributeID int64            `json:"attribute_id"`
+	Name        string           `json:"name"`
+	DisplayName string           `json:"display_name"`
+	Values      []AttributeValue `json:"values"` // Predefined values; empty for free text attributes
+}
+
+// AttributeValue is a predefined value of a Shopee attribute
+type AttributeValue struct {
+	ValueID     int64  `json:"value_id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// GetAttributes fetches the product attributes of a category
+func (p *ProductProvider) GetAttributes(ctx context.Context, categoryID int64) ([]Attribute, error) {
+	req := &Request{
+		Method: http.MethodGet,
+		Path:   GetAttributesPath,
+		Query: map[string]string{
+			"category_id": strconv.FormatInt(categoryID, 10),
+			"language":    "en",
+		},
+		NeedAuth: true,
+	}
+
+	var resp struct {
+		BaseResponse
+		Response struct {
+			AttributeList []struct {
+				AttributeID           int64  `json:"attribute_id"`
+				OriginalAttributeName string `json:"original_attribute_name"`
+				DisplayAttributeName  string `json:"display_attribute_name"`
+				AttributeValueList    []struct {
+					ValueID           int64  `json:"value_id"`
+					OriginalValueName string `json:"original_value_name"`
+					DisplayValueName  string `json:"display_value_name"`
+				} `json:"attribute_value_list"`
+			} `json:"attribute_list"`
+		} `json:"response"`
+	}
+
+	if err := p.client.Do(ctx, req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get attributes: %w", err)
+	}
+
+	if resp.HasError() {
+		return nil, fmt.Errorf("shopee error: %s", resp.GetError())
+	}
+
+	attributes := make([]Attribute, len(resp.Response.AttributeList))
+	for i, attr := range resp.Response.AttributeList {
+		attributes[i] = Attribute{
+			AttributeID: attr.AttributeID,
+			Name:        attr.OriginalAttributeName,
+			DisplayName: attr.DisplayAttributeName,
+		}
+		for _, v := range attr.AttributeValueList {
+			attributes[i].Values = append(attributes[i].Values, AttributeValue{
+				ValueID:     v.ValueID,
+				Name:        v.OriginalValueName,
+				DisplayName: v.DisplayValueName,
+			})
+		}
+	}
+
+	return attributes, nil
+}
+
+// attributeList builds the attribute_list of an item from attribute values keyed by name.
+// Names and predefined values are matched case-insensitively; other values are sent as free
+// text. Names the category does not have are an error.
+func (p *ProductProvider) attributeList(ctx context.Context, categoryID int64, values map[string]string) ([]map[string]interface{}, error) {
+	attributes, err := p.GetAttributes(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		attr := findAttribute(attributes, name)
+		if attr == nil {
+			return nil, fmt.Errorf("category %d has no attribute %q", categoryID, name)
+		}
+		list = append(list, map[string]interface{}{
+			"attribute_id":         attr.AttributeID,
+			"attribute_value_list": []map[string]interface{}{attributeValue(attr, values[name])},
+		})
+	}
+	return list, nil
+}
+
+// findAttribute returns the attribute with a name, or nil if there is none
+func findAttribute(attributes []Attribute, name string) *Attribute {
+	for i := range attributes {
+		if strings.EqualFold(attributes[i].Name, name) || strings.EqualFold(attributes[i].DisplayName, name) {
+			return &attributes[i]
+		}
+	}
+	return nil
+}
+
+// attributeValue returns the predefined value of an attribute matching a value, or the value
+// as free text
+func attributeValue(attr *Attribute, value string) map[string]interface{} {
+	for _, v := range attr.Values {
+		if strings.EqualFold(v.Name, value) || strings.EqualFold(v.DisplayName, value) {
+			return map[string]interface{}{"value_id": v.ValueID}
+		}
+	}
+	return map[string]interface{}{"value_id": 0, "original_value_name": value}
+}
@@ -0,0 +1,109 @@
+package shopee
+
+import (
+	"context"
+	"errors"
+
+	shopeedomain "github.com/Ecom-micro-template/service-marketplace/internal/domain/shopee"
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+// maxExtendedDescriptionImages caps the images uploaded for an extended description
+const maxExtendedDescriptionImages = MaxImages
+
+// FormatDescription renders a description as the plain text Shopee accepts, shortened on a
+// sentence boundary to MaxDescriptionLength characters. Header and footer blocks are kept.
+func FormatDescription(blocks []providers.DescriptionBlock) string {
+	text := providers.RenderPlainText(providers.FitDescription(blocks, MaxDescriptionLength, providers.RenderPlainText))
+	// Only reached when the header and footer alone are too long
+	return providers.TruncateText(text, MaxDescriptionLength)
+}
+
+// extendedDescription builds the description_info of an extended description: the text of
+// the description split around its images, uploaded to Shopee Media Space. Returns nil when
+// the description has no image that could be uploaded, as a normal description is then enough.
+func (p *ProductProvider) extendedDescription(ctx context.Context, blocks []providers.DescriptionBlock) map[string]interface{} {
+	fitted := providers.FitDescription(blocks, MaxDescriptionLength, providers.RenderPlainText)
+
+	var (
+		fields []map[string]interface{}
+		text   []providers.DescriptionBlock
+		images int
+	)
+	flushText := func() {
+		if rendered := providers.RenderPlainText(text); rendered != "" {
+			fields = append(fields, map[string]interface{}{
+				"field_type": "text",
+				"text":       rendered,
+			})
+		}
+		text = nil
+	}
+
+	for _, block := range fitted {
+		if block.Style != providers.BlockImage {
+			text = append(text, block)
+			continue
+		}
+		if images == maxExtendedDescriptionImages {
+			continue
+		}
+		imageID, err := p.UploadImageByURL(ctx, block.ImageURL)
+		if err != nil || imageID == "" {
+			// A missing description image is not worth failing the listing for
+			continue
+		}
+		flushText()
+		fields = append(fields, map[string]interface{}{
+			"field_type": "image",
+			"image_info": map[string]interface{}{"image_id": imageID},
+		})
+		images++
+	}
+	flushText()
+
+	if images == 0 {
+		return nil
+	}
+	return map[string]interface{}{
+		"extended_description": map[string]interface{}{
+			"field_list": fields,
+		},
+	}
+}
+
+// applyDescription sets the description of an add_item or update_item body: an extended
+// description when requested and the description has images, otherwise normal text.
+// Returns whether an extended description was set.
+func (p *ProductProvider) applyDescription(ctx context.Context, body map[string]interface{}, blocks []providers.DescriptionBlock, extended bool) bool {
+	body["description"] = FormatDescription(blocks)
+	body["description_type"] = "normal"
+	if !extended {
+		return false
+	}
+
+	info := p.extendedDescription(ctx, blocks)
+	if info == nil {
+		return false
+	}
+	delete(body, "description")
+	body["description_type"] = "extended"
+	body["description_info"] = info
+	return true
+}
+
+// extendedDescriptionRejected reports whether Shopee rejected an item because the shop is not
+// eligible for extended descriptions. Other errors, timeouts included, may have left the item
+// created or updated, so only this one is safe to retry with a normal description.
+func extendedDescriptionRejected(err error) bool {
+	var apiErr *shopeedomain.APIError
+	return errors.As(err, &apiErr) && apiErr.Code == shopeedomain.CodeExtendedDescriptionNotAllowed
+}
+
+// useNormalDescription switches a body back to a normal description, for shops that are
+// not eligible for extended descriptions
+func useNormalDescription(body map[string]interface{}, blocks []providers.DescriptionBlock) {
+	delete(body, "description_info")
+	body["description"] = FormatDescription(blocks)
+	body["description_type"] = "normal"
+}
@@ -0,0 +1,182 @@
+package shopee
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+const (
+	// Discount API paths
+	AddDiscountPath     = "/api/v2/discount/add_discount"
+	AddDiscountItemPath = "/api/v2/discount/add_discount_item"
+	EndDiscountPath     = "/api/v2/discount/end_discount"
+	DeleteDiscountPath  = "/api/v2/discount/delete_discount"
+)
+
+// Shopee discount limits.
+const (
+	DiscountMinLeadTime   = time.Hour            // A discount must start at least one hour from now
+	DiscountMinDuration   = time.Hour            // and last at least one hour
+	DiscountMaxDuration   = 180 * 24 * time.Hour // and less than 180 days
+	MaxDiscountNameLength = 150
+)
+
+// CreateDiscount creates a discount for the models of one item. The window is moved to
+// respect Shopee's lead time and duration limits. The discount is deleted again if the
+// item cannot be added to it.
+func (p *ProductProvider) CreateDiscount(ctx context.Context, discount *providers.DiscountRequest) (*providers.DiscountResponse, error) {
+	itemID, err := strconv.ParseInt(discount.ExternalProductID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid item id: %w", err)
+	}
+
+	// Leave a margin so the request does not fall under the lead time on its way
+	start := discount.StartTime
+	if earliest := time.Now().Add(DiscountMinLeadTime + time.Minute); start.Before(earliest) {
+		start = earliest
+	}
+	end := discount.EndTime
+	if end.IsZero() || end.Sub(start) >= DiscountMaxDuration {
+		end = start.Add(DiscountMaxDuration - time.Minute)
+	}
+	if end.Sub(start) < DiscountMinDuration {
+		return nil, fmt.Errorf("discount would last less than %s after the required lead time", DiscountMinDuration)
+	}
+
+	name := discount.Name
+	if utf8.RuneCountInString(name) > MaxDiscountNameLength {
+		name = string([]rune(name)[:MaxDiscountNameLength])
+	}
+
+	req := &Request{
+		Method: http.MethodPost,
+		Path:   AddDiscountPath,
+		Body: map[string]interface{}{
+			"discount_name": name,
+			"start_time":    start.Unix(),
+			"end_time":      end.Unix(),
+		},
+		NeedAuth: true,
+	}
+
+	var resp struct {
+		BaseResponse
+		Response struct {
+			DiscountID int64 `json:"discount_id"`
+		} `json:"response"`
+	}
+	if err := p.client.Do(ctx, req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to add discount: %w", err)
+	}
+	if resp.HasError() {
+		return nil, fmt.Errorf("shopee error: %s", resp.GetError())
+	}
+	discountID := resp.Response.DiscountID
+
+	if err := p.addDiscountItem(ctx, discountID, itemID, discount.Prices); err != nil {
+		// An empty upcoming discount is useless; remove it so it does not pile up
+		_ = p.EndDiscount(ctx, strconv.FormatInt(discountID, 10), false)
+		return nil, err
+	}
+
+	return &providers.DiscountResponse{
+		DiscountID: strconv.FormatInt(discountID, 10),
+		StartTime:  start,
+		EndTime:    end,
+	}, nil
+}
+
+// addDiscountItem adds the models of an item to a discount; model "0" is an item without models
+func (p *ProductProvider) addDiscountItem(ctx context.Context, discountID, itemID int64, prices map[string]float64) error {
+	item := map[string]interface{}{
+		"item_id":        itemID,
+		"purchase_limit": 0, // No limit
+	}
+	if price, ok := prices["0"]; ok && len(prices) == 1 {
+		item["item_promotion_price"] = price
+	} else {
+		models := make([]map[string]interface{}, 0, len(prices))
+		for id, price := range prices {
+			modelID, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid model id %s: %w", id, err)
+			}
+			models = append(models, map[string]interface{}{
+				"model_id":              modelID,
+				"model_promotion_price": price,
+			})
+		}
+		item["model_list"] = models
+	}
+
+	req := &Request{
+		Method: http.MethodPost,
+		Path:   AddDiscountItemPath,
+		Body: map[string]interface{}{
+			"discount_id": discountID,
+			"item_list":   []map[string]interface{}{item},
+		},
+		NeedAuth: true,
+	}
+
+	var resp struct {
+		BaseResponse
+		Response struct {
+			ErrorList []struct {
+				ItemID      int64  `json:"item_id"`
+				ModelID     int64  `json:"model_id"`
+				FailMessage string `json:"fail_message"`
+			} `json:"error_list"`
+		} `json:"response"`
+	}
+	if err := p.client.Do(ctx, req, &resp); err != nil {
+		return fmt.Errorf("failed to add discount item: %w", err)
+	}
+	if resp.HasError() {
+		return fmt.Errorf("shopee error: %s", resp.GetError())
+	}
+	if len(resp.Response.ErrorList) > 0 {
+		return fmt.Errorf("shopee refused the discount price: %s", resp.Response.ErrorList[0].FailMessage)
+	}
+
+	return nil
+}
+
+// EndDiscount ends an ongoing discount, or deletes one that has not started yet, as Shopee
+// only allows ending ongoing discounts
+func (p *ProductProvider) EndDiscount(ctx context.Context, discountID string, started bool) error {
+	id, err := strconv.ParseInt(discountID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid discount id: %w", err)
+	}
+
+	path := DeleteDiscountPath
+	if started {
+		path = EndDiscountPath
+	}
+	req := &Request{
+		Method: http.MethodPost,
+		Path:   path,
+		Body: map[string]interface{}{
+			"discount_id": id,
+		},
+		NeedAuth: true,
+	}
+
+	var resp BaseResponse
+	if err := p.client.Do(ctx, req, &resp); err != nil {
+		return fmt.Errorf("failed to end discount: %w", err)
+	}
+
+	if resp.HasError() {
+		return fmt.Errorf("shopee error: %s", resp.GetError())
+	}
+
+	return nil
+}
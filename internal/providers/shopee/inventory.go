@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
 )
@@ -18,51 +19,119 @@ func NewInventoryProvider(client *Client) *InventoryProvider {
 	return &InventoryProvider{client: client}
 }
 
-// UpdateStock updates stock for a single product
-func (p *InventoryProvider) UpdateStock(ctx context.Context, externalProductID string, quantity int) error {
+// sellerStock builds the seller_stock of an item: one entry per warehouse location, or a
+// single entry for the shop's default location
+func sellerStock(quantity int, warehouses []providers.WarehouseStock) []map[string]interface{} {
+	if len(warehouses) == 0 {
+		return []map[string]interface{}{
+			{"stock": quantity},
+		}
+	}
+	stock := make([]map[string]interface{}, 0, len(warehouses))
+	for _, w := range warehouses {
+		stock = append(stock, map[string]interface{}{
+			"location_id": w.WarehouseID,
+			"stock":       w.Quantity,
+		})
+	}
+	return stock
+}
+
+// MaxStockListSize is the maximum number of models per update_stock call
+const MaxStockListSize = 50
+
+// UpdateStock updates stock for a single product, or one of its models, per warehouse
+// location when given
+func (p *InventoryProvider) UpdateStock(ctx context.Context, update providers.InventoryUpdate) error {
+	return p.updateStockList(ctx, []providers.InventoryUpdate{update})[0]
+}
+
+// UpdateBatchStock updates stock for multiple products. Updates of the same item are sent
+// together, up to MaxStockListSize models per call. Results are in the order of updates.
+func (p *InventoryProvider) UpdateBatchStock(ctx context.Context, updates []providers.InventoryUpdate) ([]providers.InventoryUpdateResult, error) {
+	results := make([]providers.InventoryUpdateResult, len(updates))
+
+	for _, chunk := range providers.GroupInventoryUpdates(updates, MaxStockListSize) {
+		batch := make([]providers.InventoryUpdate, len(chunk))
+		for j, i := range chunk {
+			batch[j] = updates[i]
+		}
+		errs := p.updateStockList(ctx, batch)
+		for j, i := range chunk {
+			results[i] = providers.NewInventoryUpdateResult(updates[i], errs[j])
+		}
+	}
+
+	return results, nil
+}
+
+// updateStockList updates the stock of models of one item in a single update_stock call.
+// Returns the error of each update, nil for those Shopee accepted.
+func (p *InventoryProvider) updateStockList(ctx context.Context, updates []providers.InventoryUpdate) []error {
+	errs := make([]error, len(updates))
+
+	stockList := make([]map[string]interface{}, 0, len(updates))
+	models := make(map[int64]int, len(updates))
+	for i, update := range updates {
+		var modelID int64
+		if update.ExternalVariantID != "" {
+			var err error
+			modelID, err = strconv.ParseInt(update.ExternalVariantID, 10, 64)
+			if err != nil {
+				errs[i] = fmt.Errorf("invalid model id: %w", err)
+				continue
+			}
+		}
+		models[modelID] = i
+		stockList = append(stockList, map[string]interface{}{
+			"model_id":     modelID,
+			"seller_stock": sellerStock(update.Quantity, update.Warehouses),
+		})
+	}
+	if len(stockList) == 0 {
+		return errs
+	}
+
 	req := &Request{
 		Method: http.MethodPost,
 		Path:   UpdateStockPath,
 		Body: map[string]interface{}{
-			"item_id": externalProductID,
-			"stock_list": []map[string]interface{}{
-				{
-					"model_id":     0,
-					"normal_stock": quantity,
-				},
-			},
+			"item_id":    updates[0].ExternalProductID,
+			"stock_list": stockList,
 		},
 		NeedAuth: true,
 	}
 
-	var resp BaseResponse
-	if err := p.client.Do(ctx, req, &resp); err != nil {
-		return fmt.Errorf("failed to update stock: %w", err)
+	var resp struct {
+		BaseResponse
+		Response struct {
+			FailureList []struct {
+				ModelID      int64  `json:"model_id"`
+				FailedReason string `json:"failed_reason"`
+			} `json:"failure_list"`
+		} `json:"response"`
 	}
 
-	if resp.HasError() {
-		return fmt.Errorf("shopee error: %s", resp.GetError())
+	var callErr error
+	if err := p.client.Do(ctx, req, &resp); err != nil {
+		callErr = fmt.Errorf("failed to update stock: %w", err)
+	} else if resp.HasError() {
+		callErr = fmt.Errorf("shopee error: %s", resp.GetError())
 	}
-
-	return nil
-}
-
-// UpdateBatchStock updates stock for multiple products
-func (p *InventoryProvider) UpdateBatchStock(ctx context.Context, updates []providers.InventoryUpdate) ([]providers.InventoryUpdateResult, error) {
-	results := make([]providers.InventoryUpdateResult, len(updates))
-
-	for i, update := range updates {
-		err := p.UpdateStock(ctx, update.ExternalProductID, update.Quantity)
-		results[i] = providers.InventoryUpdateResult{
-			ExternalProductID: update.ExternalProductID,
-			Success:           err == nil,
+	if callErr != nil {
+		for _, i := range models {
+			errs[i] = callErr
 		}
-		if err != nil {
-			results[i].Error = err.Error()
+		return errs
+	}
+
+	for _, f := range resp.Response.FailureList {
+		if i, ok := models[f.ModelID]; ok {
+			errs[i] = fmt.Errorf("shopee error: %s", f.FailedReason)
 		}
 	}
 
-	return results, nil
+	return errs
 }
 
 // GetStock fetches current stock levels
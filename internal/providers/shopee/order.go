@@ -25,15 +25,22 @@ type OrderProvider struct {
 	client *Client
 }
 
+// MaxOrderListWindow is the longest time range Shopee accepts in one order list query
+const MaxOrderListWindow = 15 * 24 * time.Hour
+
 // NewOrderProvider creates a new Shopee order provider
 func NewOrderProvider(client *Client) *OrderProvider {
 	return &OrderProvider{client: client}
 }
 
-// GetOrders fetches orders from Shopee
+// GetOrders fetches orders from Shopee. The time range may span at most MaxOrderListWindow.
 func (p *OrderProvider) GetOrders(ctx context.Context, params *providers.OrderListParams) ([]providers.ExternalOrder, string, error) {
+	timeField := params.TimeField
+	if timeField == "" {
+		timeField = providers.OrderTimeFieldCreate
+	}
 	query := map[string]string{
-		"time_range_field": "create_time",
+		"time_range_field": timeField,
 		"time_from":        fmt.Sprintf("%d", params.TimeFrom.Unix()),
 		"time_to":          fmt.Sprintf("%d", params.TimeTo.Unix()),
 		"page_size":        fmt.Sprintf("%d", params.PageSize),
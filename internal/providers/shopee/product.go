@@ -10,7 +10,9 @@ import (
 	"net/http"
 	"path"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
 )
@@ -20,10 +22,14 @@ const (
 	AddItemPath         = "/api/v2/product/add_item"
 	UpdateItemPath      = "/api/v2/product/update_item"
 	DeleteItemPath      = "/api/v2/product/delete_item"
+	UnlistItemPath      = "/api/v2/product/unlist_item"
 	GetItemListPath     = "/api/v2/product/get_item_list"
 	GetItemInfoPath     = "/api/v2/product/get_item_base_info"
+	GetModelListPath    = "/api/v2/product/get_model_list"
 	GetCategoryPath     = "/api/v2/product/get_category"
+	GetBrandListPath    = "/api/v2/product/get_brand_list"
 	UpdateStockPath     = "/api/v2/product/update_stock"
+	UpdatePricePath     = "/api/v2/product/update_price"
 	UploadImagePath     = "/api/v2/media_space/upload_image"
 	InitVideoUploadPath = "/api/v2/media_space/init_video_upload"
 
@@ -211,9 +217,71 @@ func (p *ProductProvider) GetCategories(ctx context.Context) ([]providers.Extern
 	return categories, nil
 }
 
+// GetBrandList fetches all brands available for a category.
+// Returns the brands and whether a brand is mandatory for the category.
+func (p *ProductProvider) GetBrandList(ctx context.Context, categoryID string) ([]providers.ExternalBrand, bool, error) {
+	var brands []providers.ExternalBrand
+	isMandatory := false
+	offset := 0
+
+	for {
+		req := &Request{
+			Method: http.MethodGet,
+			Path:   GetBrandListPath,
+			Query: map[string]string{
+				"category_id": categoryID,
+				"offset":      strconv.Itoa(offset),
+				"page_size":   "100",
+				"status":      "1", // Normal brands only
+				"language":    "en",
+			},
+			NeedAuth: true,
+		}
+
+		var resp struct {
+			BaseResponse
+			Response struct {
+				BrandList []struct {
+					BrandID           int64  `json:"brand_id"`
+					OriginalBrandName string `json:"original_brand_name"`
+					DisplayBrandName  string `json:"display_brand_name"`
+				} `json:"brand_list"`
+				HasNextPage bool `json:"has_next_page"`
+				NextOffset  int  `json:"next_offset"`
+				IsMandatory bool `json:"is_mandatory"`
+			} `json:"response"`
+		}
+
+		if err := p.client.Do(ctx, req, &resp); err != nil {
+			return nil, false, fmt.Errorf("failed to get brand list: %w", err)
+		}
+
+		if resp.HasError() {
+			return nil, false, fmt.Errorf("shopee error: %s", resp.GetError())
+		}
+
+		isMandatory = resp.Response.IsMandatory
+		for _, b := range resp.Response.BrandList {
+			brands = append(brands, providers.ExternalBrand{
+				BrandID:     fmt.Sprintf("%d", b.BrandID),
+				BrandName:   b.OriginalBrandName,
+				DisplayName: b.DisplayBrandName,
+			})
+		}
+
+		if !resp.Response.HasNextPage || resp.Response.NextOffset <= offset {
+			break
+		}
+		offset = resp.Response.NextOffset
+	}
+
+	return brands, isMandatory, nil
+}
+
 // isSpamDescription checks if description appears to be placeholder/spam text
 func isSpamDescription(desc string) bool {
-	if len(desc) < 20 {
+	length := utf8.RuneCountInString(desc)
+	if length < 20 {
 		return true
 	}
 	// Check if description is mostly repeated characters
@@ -222,7 +290,7 @@ func isSpamDescription(desc string) bool {
 		charCount[r]++
 	}
 	// If any single character makes up more than 50% of the description, it's spam
-	threshold := len(desc) / 2
+	threshold := length / 2
 	for _, count := range charCount {
 		if count > threshold {
 			return true
@@ -233,12 +301,14 @@ func isSpamDescription(desc string) bool {
 
 // PushProduct creates a new product on Shopee
 func (p *ProductProvider) PushProduct(ctx context.Context, product *providers.ProductPushRequest) (*providers.ProductPushResponse, error) {
-	// Get description and apply Shopee limits (10-1200 characters)
-	description := product.Description
+	// Convert the description to plain text within Shopee limits (10-1200 characters),
+	// shortened on a sentence boundary
+	descriptionBlocks := product.DescriptionContent()
+	description := FormatDescription(descriptionBlocks)
 
 	// Validate minimum length
-	if len(description) < 10 {
-		return nil, fmt.Errorf("description too short - Shopee requires at least 10 characters (current: %d)", len(description))
+	if descLen := utf8.RuneCountInString(description); descLen < MinDescriptionLength {
+		return nil, fmt.Errorf("description too short - Shopee requires at least %d characters (current: %d)", MinDescriptionLength, descLen)
 	}
 
 	// Check for spam/placeholder patterns (repeated characters)
@@ -246,12 +316,6 @@ func (p *ProductProvider) PushProduct(ctx context.Context, product *providers.Pr
 		return nil, fmt.Errorf("description appears to be placeholder text - Shopee requires meaningful product descriptions")
 	}
 
-	// Truncate if too long (Shopee max 1200 characters)
-	if len(description) > 1200 {
-		description = description[:1197] + "..."
-		fmt.Printf("Warning: description truncated from %d to 1200 characters\n", len(product.Description))
-	}
-
 	// Convert category_id from string to int64 (Shopee requires uint64)
 	categoryID, err := strconv.ParseInt(product.CategoryID, 10, 64)
 	if err != nil {
@@ -260,21 +324,24 @@ func (p *ProductProvider) PushProduct(ctx context.Context, product *providers.Pr
 
 	// Calculate weight in kg (minimum 0.1kg for Shopee)
 	weightKg := product.Weight / 1000
-	if weightKg < 0.1 {
-		weightKg = 0.1
+	if weightKg < MinWeightKg {
+		weightKg = MinWeightKg
 	}
 
 	// Build request body
 	itemBody := map[string]interface{}{
-		"original_price":   product.OriginalPrice,
-		"description":      description, // Use truncated description
-		"description_type": "normal",    // Required by Shopee API - "normal" for text only
-		"item_name":        product.Name,
-		"weight":           weightKg,
-		"category_id":      categoryID,
-		"item_sku":         product.SKU,
-		"condition":        "NEW",
-		"item_status":      "NORMAL",
+		"original_price": product.OriginalPrice,
+		"item_name":      product.Name,
+		"weight":         weightKg,
+		"category_id":    categoryID,
+		"item_sku":       product.SKU,
+		"condition":      "NEW",
+		"item_status":    "NORMAL",
+	}
+	extended := p.applyDescription(ctx, itemBody, descriptionBlocks, product.ExtendedDescription)
+	// Shopee has no drafts; draft listings are created unlisted
+	if product.AsDraft {
+		itemBody["item_status"] = "UNLIST"
 	}
 
 	// Add dimension (required by Shopee for shipping)
@@ -300,11 +367,7 @@ func (p *ProductProvider) PushProduct(ctx context.Context, product *providers.Pr
 	}
 
 	// Add seller_stock - Shopee API v2 requires this format
-	itemBody["seller_stock"] = []map[string]interface{}{
-		{
-			"stock": product.Stock,
-		},
-	}
+	itemBody["seller_stock"] = sellerStock(product.Stock, product.Warehouses)
 
 	// Add images - must upload to Shopee Media Space first
 	// Shopee requires at least 1 image
@@ -334,16 +397,34 @@ func (p *ProductProvider) PushProduct(ctx context.Context, product *providers.Pr
 	}
 
 	// Add brand - Shopee requires brand for most categories
-	// Use "No Brand" (brand_id: 0) if no brand specified
+	// Use the resolved brand_id and its Shopee name if available, otherwise "No Brand" (brand_id: 0)
+	var brandID int64
 	brandName := product.Brand
+	if product.BrandID != "" {
+		brandID, err = strconv.ParseInt(product.BrandID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid brand_id: %w", err)
+		}
+		if product.BrandName != "" {
+			brandName = product.BrandName
+		}
+	}
 	if brandName == "" {
 		brandName = "No Brand"
 	}
 	itemBody["brand"] = map[string]interface{}{
-		"brand_id":             0,
+		"brand_id":            brandID,
 		"original_brand_name": brandName,
 	}
 
+	if len(product.Attributes) > 0 {
+		attributeList, err := p.attributeList(ctx, categoryID, product.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		itemBody["attribute_list"] = attributeList
+	}
+
 	// Add logistic channels - Required by Shopee
 	// Fetch available logistics channels from the shop
 	logisticsChannels, err := p.GetLogisticsChannels(ctx)
@@ -380,14 +461,22 @@ func (p *ProductProvider) PushProduct(ctx context.Context, product *providers.Pr
 		NeedAuth: true,
 	}
 
-	var resp struct {
+	type addItemResponse struct {
 		BaseResponse
 		Response struct {
 			ItemID int64 `json:"item_id"`
 		} `json:"response"`
 	}
 
-	if err := p.client.Do(ctx, req, &resp); err != nil {
+	var resp addItemResponse
+	err = p.client.Do(ctx, req, &resp)
+	// Extended descriptions are limited to eligible shops; retry once with a normal description
+	if extended && extendedDescriptionRejected(err) {
+		useNormalDescription(itemBody, descriptionBlocks)
+		resp = addItemResponse{}
+		err = p.client.Do(ctx, req, &resp)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to push product: %w", err)
 	}
 
@@ -411,14 +500,50 @@ func (p *ProductProvider) UpdateProduct(ctx context.Context, externalID string,
 	if product.Name != "" {
 		updateBody["item_name"] = product.Name
 	}
-	if product.Description != "" {
-		updateBody["description"] = product.Description
+	var descriptionBlocks []providers.DescriptionBlock
+	extended := false
+	if product.Description != "" || len(product.DescriptionBlocks) > 0 {
+		descriptionBlocks = product.DescriptionContent()
+		extended = p.applyDescription(ctx, updateBody, descriptionBlocks, product.ExtendedDescription)
 	}
 	if product.Price != nil {
 		updateBody["price_info"] = []map[string]interface{}{
 			{"current_price": *product.Price},
 		}
 	}
+	// Images must be uploaded to Shopee Media Space first
+	if len(product.Images) > 0 {
+		imageIDs := make([]string, 0, len(product.Images))
+		for _, imageURL := range product.Images {
+			imageID, err := p.UploadImageByURL(ctx, imageURL)
+			if err != nil {
+				return fmt.Errorf("failed to upload image %s: %w", imageURL, err)
+			}
+			imageIDs = append(imageIDs, imageID)
+		}
+		updateBody["image"] = map[string]interface{}{
+			"image_id_list": imageIDs,
+		}
+	}
+	// Attributes are resolved against the item's category
+	if len(product.Attributes) > 0 {
+		itemID, err := strconv.ParseInt(externalID, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid item id: %w", err)
+		}
+		items, err := p.GetItemBaseInfo(ctx, []int64{itemID})
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return fmt.Errorf("item %s not found", externalID)
+		}
+		attributeList, err := p.attributeList(ctx, items[0].CategoryID, product.Attributes)
+		if err != nil {
+			return err
+		}
+		updateBody["attribute_list"] = attributeList
+	}
 
 	req := &Request{
 		Method:   http.MethodPost,
@@ -428,7 +553,14 @@ func (p *ProductProvider) UpdateProduct(ctx context.Context, externalID string,
 	}
 
 	var resp BaseResponse
-	if err := p.client.Do(ctx, req, &resp); err != nil {
+	err := p.client.Do(ctx, req, &resp)
+	// Extended descriptions are limited to eligible shops; retry once with a normal description
+	if extended && extendedDescriptionRejected(err) {
+		useNormalDescription(updateBody, descriptionBlocks)
+		resp = BaseResponse{}
+		err = p.client.Do(ctx, req, &resp)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to update product: %w", err)
 	}
 
@@ -439,6 +571,38 @@ func (p *ProductProvider) UpdateProduct(ctx context.Context, externalID string,
 	return nil
 }
 
+// UpdatePrice sets the original price of an item's models; model ID 0 is an item without models
+func (p *ProductProvider) UpdatePrice(ctx context.Context, itemID int64, prices map[int64]float64) error {
+	priceList := make([]map[string]interface{}, 0, len(prices))
+	for modelID, price := range prices {
+		priceList = append(priceList, map[string]interface{}{
+			"model_id":       modelID,
+			"original_price": price,
+		})
+	}
+
+	req := &Request{
+		Method: http.MethodPost,
+		Path:   UpdatePricePath,
+		Body: map[string]interface{}{
+			"item_id":    itemID,
+			"price_list": priceList,
+		},
+		NeedAuth: true,
+	}
+
+	var resp BaseResponse
+	if err := p.client.Do(ctx, req, &resp); err != nil {
+		return fmt.Errorf("failed to update price: %w", err)
+	}
+
+	if resp.HasError() {
+		return fmt.Errorf("shopee error: %s", resp.GetError())
+	}
+
+	return nil
+}
+
 // DeleteProduct deletes a product from Shopee
 func (p *ProductProvider) DeleteProduct(ctx context.Context, externalID string) error {
 	req := &Request{
@@ -462,6 +626,74 @@ func (p *ProductProvider) DeleteProduct(ctx context.Context, externalID string)
 	return nil
 }
 
+// MaxUnlistBatchSize is the maximum number of items per unlist_item call
+const MaxUnlistBatchSize = 50
+
+// UnlistItems unlists (unlist=true) or relists (unlist=false) items on Shopee.
+// Returns the items Shopee refused to change.
+func (p *ProductProvider) UnlistItems(ctx context.Context, itemIDs []string, unlist bool) ([]providers.ListingStatusFailure, error) {
+	var failures []providers.ListingStatusFailure
+
+	for start := 0; start < len(itemIDs); start += MaxUnlistBatchSize {
+		end := min(start+MaxUnlistBatchSize, len(itemIDs))
+
+		itemList := make([]map[string]interface{}, 0, end-start)
+		for _, id := range itemIDs[start:end] {
+			itemID, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				failures = append(failures, providers.ListingStatusFailure{
+					ExternalProductID: id,
+					Reason:            "invalid item ID",
+				})
+				continue
+			}
+			itemList = append(itemList, map[string]interface{}{
+				"item_id": itemID,
+				"unlist":  unlist,
+			})
+		}
+		if len(itemList) == 0 {
+			continue
+		}
+
+		req := &Request{
+			Method: http.MethodPost,
+			Path:   UnlistItemPath,
+			Body: map[string]interface{}{
+				"item_list": itemList,
+			},
+			NeedAuth: true,
+		}
+
+		var resp struct {
+			BaseResponse
+			Response struct {
+				FailureList []struct {
+					ItemID       int64  `json:"item_id"`
+					FailedReason string `json:"failed_reason"`
+				} `json:"failure_list"`
+			} `json:"response"`
+		}
+
+		if err := p.client.Do(ctx, req, &resp); err != nil {
+			return nil, fmt.Errorf("failed to unlist items: %w", err)
+		}
+
+		if resp.HasError() {
+			return nil, fmt.Errorf("shopee error: %s", resp.GetError())
+		}
+
+		for _, f := range resp.Response.FailureList {
+			failures = append(failures, providers.ListingStatusFailure{
+				ExternalProductID: fmt.Sprintf("%d", f.ItemID),
+				Reason:            f.FailedReason,
+			})
+		}
+	}
+
+	return failures, nil
+}
+
 // UpdateInventory updates stock for products
 func (p *ProductProvider) UpdateInventory(ctx context.Context, updates []providers.InventoryUpdate) error {
 	for _, update := range updates {
@@ -473,7 +705,7 @@ func (p *ProductProvider) UpdateInventory(ctx context.Context, updates []provide
 				"stock_list": []map[string]interface{}{
 					{
 						"model_id":     0, // Main product, not variation
-						"normal_stock": update.Quantity,
+						"seller_stock": sellerStock(update.Quantity, update.Warehouses),
 					},
 				},
 			},
@@ -505,17 +737,46 @@ type ShopeeItem struct {
 
 // ShopeeItemDetail represents detailed product info from Shopee
 type ShopeeItemDetail struct {
-	ItemID        int64    `json:"item_id"`
-	ItemName      string   `json:"item_name"`
-	ItemSKU       string   `json:"item_sku"`
-	ItemStatus    string   `json:"item_status"`
-	Description   string   `json:"description"`
-	CategoryID    int64    `json:"category_id"`
-	OriginalPrice float64  `json:"original_price"`
-	Images        []string `json:"images"`
-	Stock         int      `json:"stock"`
-	UpdateTime    int64    `json:"update_time"`
-	CreateTime    int64    `json:"create_time"`
+	ItemID        int64             `json:"item_id"`
+	ItemName      string            `json:"item_name"`
+	ItemSKU       string            `json:"item_sku"`
+	ItemStatus    string            `json:"item_status"`
+	Description   string            `json:"description"`
+	CategoryID    int64             `json:"category_id"`
+	OriginalPrice float64           `json:"original_price"`
+	Images        []string          `json:"images"`
+	Stock         int               `json:"stock"`
+	Brand         string            `json:"brand"`
+	Weight        float64           `json:"weight"` // kg
+	Dimension     Dimension         `json:"dimension"`
+	Attributes    map[string]string `json:"attributes"`
+	HasModel      bool              `json:"has_model"`
+	UpdateTime    int64             `json:"update_time"`
+	CreateTime    int64             `json:"create_time"`
+	Raw           json.RawMessage   `json:"-"` // Item as returned by get_item_base_info
+}
+
+// Dimension is the package size of a Shopee item in cm
+type Dimension struct {
+	PackageLength float64 `json:"package_length"`
+	PackageWidth  float64 `json:"package_width"`
+	PackageHeight float64 `json:"package_height"`
+}
+
+// ShopeeModel represents a model (variation) of a Shopee item
+type ShopeeModel struct {
+	ModelID  int64             `json:"model_id"`
+	ModelSKU string            `json:"model_sku"`
+	Name     string            `json:"name"`    // Tier options joined by commas, e.g. "Red,M"
+	Options  map[string]string `json:"options"` // Tier variation name to option, e.g. {"Color": "Red"}
+	Price    float64           `json:"price"`
+	Stock    int               `json:"stock"`
+}
+
+// ShopeeModelList is the model list of a Shopee item
+type ShopeeModelList struct {
+	Models []ShopeeModel   `json:"models"`
+	Raw    json.RawMessage `json:"-"` // Response as returned by get_model_list
 }
 
 // GetItemList fetches list of items from Shopee shop
@@ -592,28 +853,11 @@ func (p *ProductProvider) GetItemBaseInfo(ctx context.Context, itemIDs []int64)
 		NeedAuth: true,
 	}
 
+	// Items are decoded one by one so the raw payload of each can be kept
 	var resp struct {
 		BaseResponse
 		Response struct {
-			ItemList []struct {
-				ItemID        int64   `json:"item_id"`
-				ItemName      string  `json:"item_name"`
-				ItemSKU       string  `json:"item_sku"`
-				ItemStatus    string  `json:"item_status"`
-				Description   string  `json:"description"`
-				CategoryID    int64   `json:"category_id"`
-				OriginalPrice float64 `json:"original_price"`
-				Image         struct {
-					ImageURLList []string `json:"image_url_list"`
-				} `json:"image"`
-				StockInfoV2 struct {
-					SummaryInfo struct {
-						TotalAvailableStock int `json:"total_available_stock"`
-					} `json:"summary_info"`
-				} `json:"stock_info_v2"`
-				UpdateTime int64 `json:"update_time"`
-				CreateTime int64 `json:"create_time"`
-			} `json:"item_list"`
+			ItemList []json.RawMessage `json:"item_list"`
 		} `json:"response"`
 	}
 
@@ -625,9 +869,48 @@ func (p *ProductProvider) GetItemBaseInfo(ctx context.Context, itemIDs []int64)
 		return nil, fmt.Errorf("shopee error: %s", resp.GetError())
 	}
 
-	items := make([]ShopeeItemDetail, len(resp.Response.ItemList))
-	for i, item := range resp.Response.ItemList {
-		items[i] = ShopeeItemDetail{
+	items := make([]ShopeeItemDetail, 0, len(resp.Response.ItemList))
+	for _, raw := range resp.Response.ItemList {
+		var item struct {
+			ItemID        int64   `json:"item_id"`
+			ItemName      string  `json:"item_name"`
+			ItemSKU       string  `json:"item_sku"`
+			ItemStatus    string  `json:"item_status"`
+			Description   string  `json:"description"`
+			CategoryID    int64   `json:"category_id"`
+			OriginalPrice float64 `json:"original_price"`
+			PriceInfo     []struct {
+				OriginalPrice float64 `json:"original_price"`
+			} `json:"price_info"`
+			Image struct {
+				ImageURLList []string `json:"image_url_list"`
+			} `json:"image"`
+			StockInfoV2 struct {
+				SummaryInfo struct {
+					TotalAvailableStock int `json:"total_available_stock"`
+				} `json:"summary_info"`
+			} `json:"stock_info_v2"`
+			Brand struct {
+				OriginalBrandName string `json:"original_brand_name"`
+			} `json:"brand"`
+			Weight     json.RawMessage `json:"weight"` // Number or numeric string depending on API version
+			Dimension  Dimension       `json:"dimension"`
+			Attributes []struct {
+				OriginalAttributeName string `json:"original_attribute_name"`
+				AttributeValueList    []struct {
+					OriginalValueName string `json:"original_value_name"`
+					ValueUnit         string `json:"value_unit"`
+				} `json:"attribute_value_list"`
+			} `json:"attribute_list"`
+			HasModel   bool  `json:"has_model"`
+			UpdateTime int64 `json:"update_time"`
+			CreateTime int64 `json:"create_time"`
+		}
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return nil, fmt.Errorf("failed to decode item: %w", err)
+		}
+
+		detail := ShopeeItemDetail{
 			ItemID:        item.ItemID,
 			ItemName:      item.ItemName,
 			ItemSKU:       item.ItemSKU,
@@ -637,14 +920,133 @@ func (p *ProductProvider) GetItemBaseInfo(ctx context.Context, itemIDs []int64)
 			OriginalPrice: item.OriginalPrice,
 			Images:        item.Image.ImageURLList,
 			Stock:         item.StockInfoV2.SummaryInfo.TotalAvailableStock,
+			Brand:         item.Brand.OriginalBrandName,
+			Weight:        parseNumber(item.Weight),
+			Dimension:     item.Dimension,
+			HasModel:      item.HasModel,
 			UpdateTime:    item.UpdateTime,
 			CreateTime:    item.CreateTime,
+			Raw:           raw,
+		}
+		if detail.OriginalPrice == 0 && len(item.PriceInfo) > 0 {
+			detail.OriginalPrice = item.PriceInfo[0].OriginalPrice
+		}
+		// "No Brand" is Shopee's placeholder for unbranded items
+		if detail.Brand == "NoBrand" || detail.Brand == "No Brand" {
+			detail.Brand = ""
+		}
+		for _, attr := range item.Attributes {
+			values := make([]string, 0, len(attr.AttributeValueList))
+			for _, v := range attr.AttributeValueList {
+				values = append(values, strings.TrimSpace(v.OriginalValueName+" "+v.ValueUnit))
+			}
+			if attr.OriginalAttributeName == "" || len(values) == 0 {
+				continue
+			}
+			if detail.Attributes == nil {
+				detail.Attributes = make(map[string]string)
+			}
+			detail.Attributes[attr.OriginalAttributeName] = strings.Join(values, ", ")
 		}
+
+		items = append(items, detail)
 	}
 
 	return items, nil
 }
 
+// GetModelList fetches the models (variations) of an item
+func (p *ProductProvider) GetModelList(ctx context.Context, itemID int64) (*ShopeeModelList, error) {
+	req := &Request{
+		Method: http.MethodGet,
+		Path:   GetModelListPath,
+		Query: map[string]string{
+			"item_id": strconv.FormatInt(itemID, 10),
+		},
+		NeedAuth: true,
+	}
+
+	var resp struct {
+		BaseResponse
+		Response json.RawMessage `json:"response"`
+	}
+
+	if err := p.client.Do(ctx, req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get model list: %w", err)
+	}
+
+	if resp.HasError() {
+		return nil, fmt.Errorf("shopee error: %s", resp.GetError())
+	}
+
+	var data struct {
+		TierVariation []struct {
+			Name       string `json:"name"`
+			OptionList []struct {
+				Option string `json:"option"`
+			} `json:"option_list"`
+		} `json:"tier_variation"`
+		Model []struct {
+			ModelID   int64  `json:"model_id"`
+			ModelSKU  string `json:"model_sku"`
+			TierIndex []int  `json:"tier_index"`
+			PriceInfo []struct {
+				OriginalPrice float64 `json:"original_price"`
+			} `json:"price_info"`
+			StockInfoV2 struct {
+				SummaryInfo struct {
+					TotalAvailableStock int `json:"total_available_stock"`
+				} `json:"summary_info"`
+			} `json:"stock_info_v2"`
+		} `json:"model"`
+	}
+	if len(resp.Response) > 0 {
+		if err := json.Unmarshal(resp.Response, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode model list: %w", err)
+		}
+	}
+
+	list := &ShopeeModelList{
+		Models: make([]ShopeeModel, 0, len(data.Model)),
+		Raw:    resp.Response,
+	}
+	for _, m := range data.Model {
+		model := ShopeeModel{
+			ModelID:  m.ModelID,
+			ModelSKU: m.ModelSKU,
+			Stock:    m.StockInfoV2.SummaryInfo.TotalAvailableStock,
+		}
+		if len(m.PriceInfo) > 0 {
+			model.Price = m.PriceInfo[0].OriginalPrice
+		}
+
+		// tier_index selects one option per tier variation
+		names := make([]string, 0, len(m.TierIndex))
+		for tier, idx := range m.TierIndex {
+			if tier >= len(data.TierVariation) || idx < 0 || idx >= len(data.TierVariation[tier].OptionList) {
+				continue
+			}
+			option := data.TierVariation[tier].OptionList[idx].Option
+			if model.Options == nil {
+				model.Options = make(map[string]string)
+			}
+			model.Options[data.TierVariation[tier].Name] = option
+			names = append(names, option)
+		}
+		model.Name = strings.Join(names, ",")
+
+		list.Models = append(list.Models, model)
+	}
+
+	return list, nil
+}
+
+// parseNumber decodes a JSON number that may also be sent as a string
+func parseNumber(raw json.RawMessage) float64 {
+	value, _ := strconv.ParseFloat(strings.Trim(string(raw), `"`), 64)
+	return value
+}
+
 // GetInventory fetches inventory levels for products
 func (p *ProductProvider) GetInventory(ctx context.Context, externalProductIDs []string) ([]providers.InventoryItem, error) {
 	// Build comma-separated item IDs
@@ -0,0 +1,130 @@
+package shopee
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+// Shopee listing limits.
+const (
+	MinDescriptionLength = 10
+	MaxDescriptionLength = 1200
+	MaxItemNameLength    = 120
+	MaxImages            = 9
+	MinWeightKg          = 0.1
+)
+
+// ValidateProduct checks a push request against Shopee listing rules without calling the API.
+func (p *ProductProvider) ValidateProduct(product *providers.ProductPushRequest) *providers.ValidationResult {
+	result := providers.NewValidationResult()
+
+	// Name
+	nameLen := utf8.RuneCountInString(product.Name)
+	if nameLen == 0 {
+		result.AddError(providers.IssueNameMissing, "name", "product name is empty", "Set a product name in the catalog")
+	} else if nameLen > MaxItemNameLength {
+		result.AddError(providers.IssueNameTooLong, "name",
+			fmt.Sprintf("product name is %d characters, Shopee allows at most %d", nameLen, MaxItemNameLength),
+			"Shorten the product name or set a channel-specific title")
+	}
+
+	// Description, measured as the plain text Shopee receives
+	description := providers.RenderPlainText(product.DescriptionContent())
+	descLen := utf8.RuneCountInString(description)
+	if descLen < MinDescriptionLength {
+		result.AddError(providers.IssueDescriptionTooShort, "description",
+			fmt.Sprintf("description is %d characters, Shopee requires at least %d", descLen, MinDescriptionLength),
+			"Write a product description of at least a full sentence")
+	} else if isSpamDescription(description) {
+		result.AddError(providers.IssueDescriptionSpam, "description",
+			"description appears to be placeholder text",
+			"Replace placeholder or repeated-character text with a meaningful description")
+	}
+	if descLen > MaxDescriptionLength {
+		result.AddWarning(providers.IssueDescriptionTooLong, "description",
+			fmt.Sprintf("description is %d characters and will be shortened at a sentence boundary to %d", descLen, MaxDescriptionLength),
+			"Shorten the description to keep the ending intact")
+	}
+
+	// Images
+	if len(product.Images) == 0 {
+		result.AddError(providers.IssueImagesMissing, "images", "product has no images, Shopee requires at least 1",
+			"Upload at least one product image in the catalog")
+	} else if len(product.Images) > MaxImages {
+		result.AddWarning(providers.IssueTooManyImages, "images",
+			fmt.Sprintf("product has %d images, only the first %d will be accepted", len(product.Images), MaxImages),
+			"Reorder images so the most important ones come first")
+	}
+
+	// Category
+	if product.CategoryID == "" {
+		result.AddError(providers.IssueCategoryUnmapped, "category_id", "product category is not mapped to a Shopee category",
+			"Create a category mapping for this product's category")
+	} else if _, err := strconv.ParseInt(product.CategoryID, 10, 64); err != nil {
+		result.AddError(providers.IssueCategoryInvalid, "category_id",
+			fmt.Sprintf("mapped category %q is not a valid Shopee category ID", product.CategoryID),
+			"Re-map the category to a Shopee leaf category")
+	}
+
+	// Price
+	if product.Price <= 0 || product.OriginalPrice <= 0 {
+		result.AddError(providers.IssuePriceInvalid, "price", "product price must be greater than zero",
+			"Set a base price in the catalog")
+	}
+
+	// Weight
+	if product.Weight <= 0 {
+		result.AddWarning(providers.IssueWeightMissing, "weight",
+			fmt.Sprintf("product has no weight, %.1fkg will be used", MinWeightKg),
+			"Set the shipping weight in grams to get accurate shipping fees")
+	} else if product.Weight/1000 < MinWeightKg {
+		result.AddWarning(providers.IssueWeightTooLow, "weight",
+			fmt.Sprintf("weight %.0fg is below Shopee's minimum and will be raised to %.1fkg", product.Weight, MinWeightKg),
+			"Check the weight is in grams")
+	}
+
+	// Dimensions
+	if product.Dimensions == nil {
+		result.AddWarning(providers.IssueDimensionsMissing, "dimensions", "product has no dimensions, 10x10x5cm will be used",
+			"Set package dimensions to get accurate shipping fees")
+	}
+
+	// SKU and stock
+	if product.SKU == "" {
+		result.AddWarning(providers.IssueSKUMissing, "sku", "product has no SKU",
+			"Set a SKU so orders and stock can be matched reliably")
+	}
+	if product.Stock <= 0 {
+		result.AddWarning(providers.IssueOutOfStock, "stock", "product has no stock and will be listed as sold out", "")
+	}
+
+	return result
+}
+
+// ValidateShop checks shop-level prerequisites for listing products, such as enabled logistics channels.
+// Only read APIs are called.
+func (p *ProductProvider) ValidateShop(ctx context.Context) *providers.ValidationResult {
+	result := providers.NewValidationResult()
+
+	channels, err := p.GetLogisticsChannels(ctx)
+	if err != nil {
+		result.AddError(providers.IssueLogisticsUnavailable, "logistics",
+			fmt.Sprintf("could not fetch logistics channels: %v", err),
+			"Check the connection is authorized and try again")
+		return result
+	}
+
+	for _, ch := range channels {
+		if ch.Enabled {
+			return result
+		}
+	}
+
+	result.AddError(providers.IssueLogisticsUnavailable, "logistics", "no enabled logistics channels found for this shop",
+		"Enable at least one shipping channel in Shopee Seller Center")
+	return result
+}
@@ -0,0 +1,119 @@
+package tiktok
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	// Attribute API paths
+	GetAttributesPath = "/api/products/attributes"
+)
+
+// Attribute is a product attribute of a TikTok Shop category, e.g. Material
+type Attribute struct {
+	ID     string           `json:"id"`
+	Name   string           `json:"name"`
+	Values []AttributeValue `json:"values"` // Predefined values; empty for free text attributes
+}
+
+// AttributeValue is a predefined value of a TikTok Shop attribute
+type AttributeValue struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetAttributes fetches the product attributes of a category
+func (p *ProductProvider) GetAttributes(ctx context.Context, categoryID string) ([]Attribute, error) {
+	req := &Request{
+		Method:   http.MethodGet,
+		Path:     GetAttributesPath,
+		Query:    map[string]string{"category_id": categoryID},
+		NeedAuth: true,
+	}
+
+	var resp struct {
+		BaseResponse
+		Data struct {
+			Attributes []struct {
+				ID     string `json:"id"`
+				Name   string `json:"name"`
+				Values []struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"values"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+
+	if err := p.client.Do(ctx, req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get attributes: %w", err)
+	}
+
+	if resp.HasError() {
+		return nil, fmt.Errorf("tiktok error: %s", resp.GetError())
+	}
+
+	attributes := make([]Attribute, len(resp.Data.Attributes))
+	for i, attr := range resp.Data.Attributes {
+		attributes[i] = Attribute{ID: attr.ID, Name: attr.Name}
+		for _, v := range attr.Values {
+			attributes[i].Values = append(attributes[i].Values, AttributeValue{ID: v.ID, Name: v.Name})
+		}
+	}
+
+	return attributes, nil
+}
+
+// productAttributes builds the product_attributes of a product from attribute values keyed
+// by name. Names and predefined values are matched case-insensitively; other values are sent
+// as free text. Names the category does not have are an error.
+func (p *ProductProvider) productAttributes(ctx context.Context, categoryID string, values map[string]string) ([]map[string]interface{}, error) {
+	attributes, err := p.GetAttributes(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		attr := findAttribute(attributes, name)
+		if attr == nil {
+			return nil, fmt.Errorf("category %s has no attribute %q", categoryID, name)
+		}
+		list = append(list, map[string]interface{}{
+			"attribute_id":     attr.ID,
+			"attribute_values": []map[string]string{attributeValue(attr, values[name])},
+		})
+	}
+	return list, nil
+}
+
+// findAttribute returns the attribute with a name, or nil if there is none
+func findAttribute(attributes []Attribute, name string) *Attribute {
+	for i := range attributes {
+		if strings.EqualFold(attributes[i].Name, name) {
+			return &attributes[i]
+		}
+	}
+	return nil
+}
+
+// attributeValue returns the predefined value of an attribute matching a value, or the value
+// as free text
+func attributeValue(attr *Attribute, value string) map[string]string {
+	for _, v := range attr.Values {
+		if strings.EqualFold(v.Name, value) {
+			return map[string]string{"value_id": v.ID, "value_name": v.Name}
+		}
+	}
+	return map[string]string{"value_name": value}
+}
@@ -0,0 +1,10 @@
+package tiktok
+
+import "github.com/Ecom-micro-template/service-marketplace/internal/providers"
+
+// FormatDescription renders a description as the HTML TikTok Shop accepts: paragraphs,
+// headings and lists only, without links or images, shortened on a sentence boundary to
+// MaxDescriptionLength characters. Header and footer blocks are kept.
+func FormatDescription(blocks []providers.DescriptionBlock) string {
+	return providers.RenderHTML(providers.FitDescription(blocks, MaxDescriptionLength, providers.RenderHTML))
+}
@@ -18,53 +18,110 @@ func NewInventoryProvider(client *Client) *InventoryProvider {
 	return &InventoryProvider{client: client}
 }
 
-// UpdateStock updates stock for a single product
-func (p *InventoryProvider) UpdateStock(ctx context.Context, productID, skuID string, quantity int) error {
+// stockInfos builds the stock_infos of a SKU: one entry per warehouse, or a single entry
+// for the shop's default warehouse
+func stockInfos(quantity int, warehouses []providers.WarehouseStock) []map[string]interface{} {
+	if len(warehouses) == 0 {
+		return []map[string]interface{}{
+			{"available_stock": quantity},
+		}
+	}
+	infos := make([]map[string]interface{}, 0, len(warehouses))
+	for _, w := range warehouses {
+		infos = append(infos, map[string]interface{}{
+			"warehouse_id":    w.WarehouseID,
+			"available_stock": w.Quantity,
+		})
+	}
+	return infos
+}
+
+// MaxStockUpdateSKUs is the maximum number of SKUs per stock update call
+const MaxStockUpdateSKUs = 100
+
+// UpdateStock updates stock for a single SKU, per warehouse when given
+func (p *InventoryProvider) UpdateStock(ctx context.Context, update providers.InventoryUpdate) error {
+	return p.updateSKUStock(ctx, []providers.InventoryUpdate{update})[0]
+}
+
+// UpdateBatchStock updates stock for multiple products. SKUs of the same product are sent
+// together, up to MaxStockUpdateSKUs per call. Results are in the order of updates.
+func (p *InventoryProvider) UpdateBatchStock(ctx context.Context, updates []providers.InventoryUpdate) ([]providers.InventoryUpdateResult, error) {
+	results := make([]providers.InventoryUpdateResult, len(updates))
+
+	for _, chunk := range providers.GroupInventoryUpdates(updates, MaxStockUpdateSKUs) {
+		batch := make([]providers.InventoryUpdate, len(chunk))
+		for j, i := range chunk {
+			batch[j] = updates[i]
+		}
+		errs := p.updateSKUStock(ctx, batch)
+		for j, i := range chunk {
+			results[i] = providers.NewInventoryUpdateResult(updates[i], errs[j])
+		}
+	}
+
+	return results, nil
+}
+
+// updateSKUStock updates the stock of SKUs of one product in a single call.
+// Returns the error of each update, nil for those TikTok Shop accepted.
+func (p *InventoryProvider) updateSKUStock(ctx context.Context, updates []providers.InventoryUpdate) []error {
+	errs := make([]error, len(updates))
+
+	skus := make([]map[string]interface{}, 0, len(updates))
+	skuIndex := make(map[string]int, len(updates))
+	for i, update := range updates {
+		skuID := update.ExternalSKU
+		if update.ExternalVariantID != "" {
+			skuID = update.ExternalVariantID
+		}
+		skuIndex[skuID] = i
+		skus = append(skus, map[string]interface{}{
+			"product_id":  update.ExternalProductID,
+			"id":          skuID,
+			"stock_infos": stockInfos(update.Quantity, update.Warehouses),
+		})
+	}
+
 	req := &Request{
 		Method: http.MethodPut,
 		Path:   UpdateInventoryPath,
 		Body: map[string]interface{}{
-			"skus": []map[string]interface{}{
-				{
-					"product_id": productID,
-					"id":         skuID,
-					"stock_infos": []map[string]interface{}{
-						{"available_stock": quantity},
-					},
-				},
-			},
+			"skus": skus,
 		},
 		NeedAuth: true,
 	}
 
-	var resp BaseResponse
-	if err := p.client.Do(ctx, req, &resp); err != nil {
-		return fmt.Errorf("failed to update stock: %w", err)
+	var resp struct {
+		BaseResponse
+		Data struct {
+			FailedSKUs []struct {
+				ID                 string   `json:"id"`
+				FailedWarehouseIDs []string `json:"failed_warehouse_ids"`
+			} `json:"failed_skus"`
+		} `json:"data"`
 	}
 
-	if resp.HasError() {
-		return fmt.Errorf("tiktok error: %s", resp.GetError())
+	var callErr error
+	if err := p.client.Do(ctx, req, &resp); err != nil {
+		callErr = fmt.Errorf("failed to update stock: %w", err)
+	} else if resp.HasError() {
+		callErr = fmt.Errorf("tiktok error: %s", resp.GetError())
 	}
-
-	return nil
-}
-
-// UpdateBatchStock updates stock for multiple products
-func (p *InventoryProvider) UpdateBatchStock(ctx context.Context, updates []providers.InventoryUpdate) ([]providers.InventoryUpdateResult, error) {
-	results := make([]providers.InventoryUpdateResult, len(updates))
-
-	for i, update := range updates {
-		err := p.UpdateStock(ctx, update.ExternalProductID, update.ExternalSKU, update.Quantity)
-		results[i] = providers.InventoryUpdateResult{
-			ExternalProductID: update.ExternalProductID,
-			Success:           err == nil,
+	if callErr != nil {
+		for i := range errs {
+			errs[i] = callErr
 		}
-		if err != nil {
-			results[i].Error = err.Error()
+		return errs
+	}
+
+	for _, f := range resp.Data.FailedSKUs {
+		if i, ok := skuIndex[f.ID]; ok {
+			errs[i] = fmt.Errorf("tiktok error: stock update failed for warehouses %v", f.FailedWarehouseIDs)
 		}
 	}
 
-	return results, nil
+	return errs
 }
 
 // GetStock fetches current stock levels
@@ -27,10 +27,14 @@ func NewOrderProvider(client *Client) *OrderProvider {
 
 // GetOrders fetches orders from TikTok
 func (p *OrderProvider) GetOrders(ctx context.Context, params *providers.OrderListParams) ([]providers.ExternalOrder, string, error) {
+	timeField := params.TimeField
+	if timeField == "" {
+		timeField = providers.OrderTimeFieldCreate
+	}
 	body := map[string]interface{}{
-		"create_time_ge": params.TimeFrom.Unix(),
-		"create_time_lt": params.TimeTo.Unix(),
-		"page_size":      params.PageSize,
+		timeField + "_ge": params.TimeFrom.Unix(),
+		timeField + "_lt": params.TimeTo.Unix(),
+		"page_size":       params.PageSize,
 	}
 
 	if params.Cursor != "" {
@@ -2,20 +2,31 @@ package tiktok
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
 )
 
 const (
 	// Product API paths
-	CreateProductPath   = "/api/products"
-	UpdateProductPath   = "/api/products"
-	DeleteProductPath   = "/api/products"
-	GetCategoriesPath   = "/api/products/categories"
-	UpdateInventoryPath = "/api/products/stocks"
-	GetProductsPath     = "/api/products/search"
+	CreateProductPath     = "/api/products"
+	UpdateProductPath     = "/api/products"
+	DeleteProductPath     = "/api/products"
+	ActivateProductPath   = "/api/products/activate"
+	DeactivateProductPath = "/api/products/inactivated_products"
+	GetCategoriesPath     = "/api/products/categories"
+	UpdateInventoryPath   = "/api/products/stocks"
+	UpdatePricePath       = "/api/products/prices"
+	GetProductsPath       = "/api/products/search"
+	GetProductDetailPath  = "/api/products/details"
+	GetBrandsPath         = "/api/products/brands"
+
+	// MaxSearchPageSize is the largest page size accepted by product search
+	MaxSearchPageSize = 100
 )
 
 // ProductProvider implements product operations for TikTok Shop
@@ -69,13 +80,68 @@ func (p *ProductProvider) GetCategories(ctx context.Context) ([]providers.Extern
 	return categories, nil
 }
 
+// GetBrands fetches all brands available for a category (or all shop brands if categoryID is empty)
+func (p *ProductProvider) GetBrands(ctx context.Context, categoryID string) ([]providers.ExternalBrand, error) {
+	var brands []providers.ExternalBrand
+	pageSize := 100
+
+	for page := 1; ; page++ {
+		query := map[string]string{
+			"page_number": strconv.Itoa(page),
+			"page_size":   strconv.Itoa(pageSize),
+		}
+		if categoryID != "" {
+			query["category_id"] = categoryID
+		}
+
+		req := &Request{
+			Method:   http.MethodGet,
+			Path:     GetBrandsPath,
+			Query:    query,
+			NeedAuth: true,
+		}
+
+		var resp struct {
+			BaseResponse
+			Data struct {
+				BrandList []struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"brand_list"`
+				Total int `json:"total"`
+			} `json:"data"`
+		}
+
+		if err := p.client.Do(ctx, req, &resp); err != nil {
+			return nil, fmt.Errorf("failed to get brands: %w", err)
+		}
+
+		if resp.HasError() {
+			return nil, fmt.Errorf("tiktok error: %s", resp.GetError())
+		}
+
+		for _, b := range resp.Data.BrandList {
+			brands = append(brands, providers.ExternalBrand{
+				BrandID:   b.ID,
+				BrandName: b.Name,
+			})
+		}
+
+		if len(resp.Data.BrandList) < pageSize || len(brands) >= resp.Data.Total {
+			break
+		}
+	}
+
+	return brands, nil
+}
+
 // PushProduct creates a new product on TikTok Shop
 func (p *ProductProvider) PushProduct(ctx context.Context, product *providers.ProductPushRequest) (*providers.ProductPushResponse, error) {
 	productBody := map[string]interface{}{
 		"title":       product.Name,
-		"description": product.Description,
+		"description": FormatDescription(product.DescriptionContent()),
 		"category_id": product.CategoryID,
-		"brand_id":    "",
+		"brand_id":    product.BrandID,
 		"images": func() []map[string]string {
 			images := make([]map[string]string, len(product.Images))
 			for i, img := range product.Images {
@@ -88,17 +154,24 @@ func (p *ProductProvider) PushProduct(ctx context.Context, product *providers.Pr
 				"seller_sku":       product.SKU,
 				"original_price":   fmt.Sprintf("%.2f", product.OriginalPrice),
 				"sales_attributes": []interface{}{},
-				"stock_infos": []map[string]interface{}{
-					{
-						"available_stock": product.Stock,
-						"warehouse_id":    "",
-					},
-				},
+				"stock_infos":      stockInfos(product.Stock, product.Warehouses),
 			},
 		},
 		"package_weight": fmt.Sprintf("%.2f", product.Weight/1000), // Convert g to kg
 	}
 
+	if product.AsDraft {
+		productBody["save_mode"] = "AS_DRAFT"
+	}
+
+	if len(product.Attributes) > 0 {
+		attributes, err := p.productAttributes(ctx, product.CategoryID, product.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		productBody["product_attributes"] = attributes
+	}
+
 	// Add dimensions if provided
 	if product.Dimensions != nil {
 		productBody["package_dimensions"] = map[string]interface{}{
@@ -156,8 +229,27 @@ func (p *ProductProvider) UpdateProduct(ctx context.Context, externalID string,
 	if product.Name != "" {
 		updateBody["title"] = product.Name
 	}
-	if product.Description != "" {
-		updateBody["description"] = product.Description
+	if product.Description != "" || len(product.DescriptionBlocks) > 0 {
+		updateBody["description"] = FormatDescription(product.DescriptionContent())
+	}
+	if len(product.Images) > 0 {
+		images := make([]map[string]string, len(product.Images))
+		for i, img := range product.Images {
+			images[i] = map[string]string{"id": img}
+		}
+		updateBody["images"] = images
+	}
+	// Attributes are resolved against the product's category
+	if len(product.Attributes) > 0 {
+		detail, err := p.GetProductDetail(ctx, externalID)
+		if err != nil {
+			return err
+		}
+		attributes, err := p.productAttributes(ctx, detail.CategoryID, product.Attributes)
+		if err != nil {
+			return err
+		}
+		updateBody["product_attributes"] = attributes
 	}
 
 	req := &Request{
@@ -179,6 +271,38 @@ func (p *ProductProvider) UpdateProduct(ctx context.Context, externalID string,
 	return nil
 }
 
+// UpdatePrices sets the original price of a product's SKUs, keyed by SKU ID
+func (p *ProductProvider) UpdatePrices(ctx context.Context, productID string, prices map[string]float64) error {
+	skus := make([]map[string]interface{}, 0, len(prices))
+	for skuID, price := range prices {
+		skus = append(skus, map[string]interface{}{
+			"id":             skuID,
+			"original_price": fmt.Sprintf("%.2f", price),
+		})
+	}
+
+	req := &Request{
+		Method: http.MethodPut,
+		Path:   UpdatePricePath,
+		Body: map[string]interface{}{
+			"product_id": productID,
+			"skus":       skus,
+		},
+		NeedAuth: true,
+	}
+
+	var resp BaseResponse
+	if err := p.client.Do(ctx, req, &resp); err != nil {
+		return fmt.Errorf("failed to update prices: %w", err)
+	}
+
+	if resp.HasError() {
+		return fmt.Errorf("tiktok error: %s", resp.GetError())
+	}
+
+	return nil
+}
+
 // DeleteProduct deletes a product from TikTok Shop
 func (p *ProductProvider) DeleteProduct(ctx context.Context, externalID string) error {
 	req := &Request{
@@ -199,18 +323,63 @@ func (p *ProductProvider) DeleteProduct(ctx context.Context, externalID string)
 	return nil
 }
 
+// ActivateProducts relists deactivated products on TikTok Shop.
+// Returns the products TikTok refused to activate.
+func (p *ProductProvider) ActivateProducts(ctx context.Context, productIDs []string) ([]providers.ListingStatusFailure, error) {
+	return p.setProductsActive(ctx, ActivateProductPath, productIDs)
+}
+
+// DeactivateProducts unlists products on TikTok Shop without deleting them.
+// Returns the products TikTok refused to deactivate.
+func (p *ProductProvider) DeactivateProducts(ctx context.Context, productIDs []string) ([]providers.ListingStatusFailure, error) {
+	return p.setProductsActive(ctx, DeactivateProductPath, productIDs)
+}
+
+// setProductsActive calls the activate or deactivate endpoint
+func (p *ProductProvider) setProductsActive(ctx context.Context, path string, productIDs []string) ([]providers.ListingStatusFailure, error) {
+	req := &Request{
+		Method: http.MethodPost,
+		Path:   path,
+		Body: map[string]interface{}{
+			"product_ids": productIDs,
+		},
+		NeedAuth: true,
+	}
+
+	var resp struct {
+		BaseResponse
+		Data struct {
+			FailedProductIDs []string `json:"failed_product_ids"`
+		} `json:"data"`
+	}
+
+	if err := p.client.Do(ctx, req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to update product status: %w", err)
+	}
+
+	if resp.HasError() {
+		return nil, fmt.Errorf("tiktok error: %s", resp.GetError())
+	}
+
+	failures := make([]providers.ListingStatusFailure, 0, len(resp.Data.FailedProductIDs))
+	for _, id := range resp.Data.FailedProductIDs {
+		failures = append(failures, providers.ListingStatusFailure{
+			ExternalProductID: id,
+			Reason:            "rejected by TikTok Shop",
+		})
+	}
+
+	return failures, nil
+}
+
 // UpdateInventory updates stock for products
 func (p *ProductProvider) UpdateInventory(ctx context.Context, updates []providers.InventoryUpdate) error {
 	stockUpdates := make([]map[string]interface{}, len(updates))
 	for i, update := range updates {
 		stockUpdates[i] = map[string]interface{}{
-			"product_id": update.ExternalProductID,
-			"sku_id":     update.ExternalSKU,
-			"stock_infos": []map[string]interface{}{
-				{
-					"available_stock": update.Quantity,
-				},
-			},
+			"product_id":  update.ExternalProductID,
+			"sku_id":      update.ExternalSKU,
+			"stock_infos": stockInfos(update.Quantity, update.Warehouses),
 		}
 	}
 
@@ -287,3 +456,275 @@ func (p *ProductProvider) GetInventory(ctx context.Context, externalProductIDs [
 
 	return items, nil
 }
+
+// productStatusNames maps TikTok numeric product statuses to their names
+var productStatusNames = map[int]string{
+	1: "DRAFT",
+	2: "PENDING",
+	3: "FAILED",
+	4: "LIVE",
+	5: "SELLER_DEACTIVATED",
+	6: "PLATFORM_DEACTIVATED",
+	7: "FREEZE",
+	8: "DELETED",
+}
+
+// ProductStatusName returns the name of a TikTok numeric product status
+func ProductStatusName(status int) string {
+	if name, ok := productStatusNames[status]; ok {
+		return name
+	}
+	return strconv.Itoa(status)
+}
+
+// productStatusCode returns the numeric search status for a status name (0 = all)
+func productStatusCode(name string) int {
+	for code, n := range productStatusNames {
+		if n == name {
+			return code
+		}
+	}
+	return 0
+}
+
+// TikTokProduct represents a product summary from TikTok Shop product search
+type TikTokProduct struct {
+	ProductID  string      `json:"product_id"`
+	Name       string      `json:"name"`
+	Status     string      `json:"status"` // LIVE, SELLER_DEACTIVATED, etc.
+	SKUs       []TikTokSKU `json:"skus"`
+	UpdateTime int64       `json:"update_time"`
+	CreateTime int64       `json:"create_time"`
+}
+
+// TikTokProductDetail represents detailed product info from TikTok Shop
+type TikTokProductDetail struct {
+	ProductID   string            `json:"product_id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	CategoryID  string            `json:"category_id"`
+	Status      string            `json:"status"`
+	Images      []string          `json:"images"`
+	SKUs        []TikTokSKU       `json:"skus"`
+	UpdateTime  int64             `json:"update_time"`
+	CreateTime  int64             `json:"create_time"`
+	Brand       string            `json:"brand"`
+	Weight      float64           `json:"weight"` // Package weight in kg
+	Length      float64           `json:"length"` // Package length in cm
+	Width       float64           `json:"width"`  // Package width in cm
+	Height      float64           `json:"height"` // Package height in cm
+	Attributes  map[string]string `json:"attributes"`
+	Raw         json.RawMessage   `json:"-"` // Product as returned by the detail API
+}
+
+// TikTokSKU represents a single SKU of a TikTok Shop product
+type TikTokSKU struct {
+	ID              string                 `json:"id"`
+	SellerSKU       string                 `json:"seller_sku"`
+	Price           float64                `json:"price"`
+	Stock           int                    `json:"stock"`
+	SalesAttributes []TikTokSalesAttribute `json:"sales_attributes"`
+}
+
+// TikTokSalesAttribute is a variation attribute of a SKU, e.g. Size = M
+type TikTokSalesAttribute struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// tiktokSKUPayload is the SKU structure shared by search and detail responses
+type tiktokSKUPayload struct {
+	ID        string `json:"id"`
+	SellerSKU string `json:"seller_sku"`
+	Price     struct {
+		OriginalPrice string `json:"original_price"`
+	} `json:"price"`
+	StockInfos []struct {
+		AvailableStock int `json:"available_stock"`
+	} `json:"stock_infos"`
+	SalesAttributes []struct {
+		Name      string `json:"name"`
+		ValueName string `json:"value_name"`
+	} `json:"sales_attributes"`
+}
+
+// toSKU converts a SKU payload, summing stock across warehouses
+func (s *tiktokSKUPayload) toSKU() TikTokSKU {
+	sku := TikTokSKU{
+		ID:        s.ID,
+		SellerSKU: s.SellerSKU,
+	}
+	sku.Price, _ = strconv.ParseFloat(s.Price.OriginalPrice, 64)
+	for _, info := range s.StockInfos {
+		sku.Stock += info.AvailableStock
+	}
+	for _, attr := range s.SalesAttributes {
+		sku.SalesAttributes = append(sku.SalesAttributes, TikTokSalesAttribute{
+			Name:  attr.Name,
+			Value: attr.ValueName,
+		})
+	}
+	return sku
+}
+
+// SearchProducts fetches a page of products from the TikTok shop.
+// pageNumber starts at 1; status filters by product status name (empty = all).
+// Returns the products and the total number of matching products.
+func (p *ProductProvider) SearchProducts(ctx context.Context, pageNumber, pageSize int, status string) ([]TikTokProduct, int, error) {
+	body := map[string]interface{}{
+		"page_number": pageNumber,
+		"page_size":   min(pageSize, MaxSearchPageSize),
+	}
+	if code := productStatusCode(status); code != 0 {
+		body["search_status"] = code
+	}
+
+	req := &Request{
+		Method:   http.MethodPost,
+		Path:     GetProductsPath,
+		Body:     body,
+		NeedAuth: true,
+	}
+
+	var resp struct {
+		BaseResponse
+		Data struct {
+			Products []struct {
+				ID         string             `json:"id"`
+				Name       string             `json:"name"`
+				Status     int                `json:"status"`
+				SKUs       []tiktokSKUPayload `json:"skus"`
+				CreateTime int64              `json:"create_time"`
+				UpdateTime int64              `json:"update_time"`
+			} `json:"products"`
+			Total int `json:"total"`
+		} `json:"data"`
+	}
+
+	if err := p.client.Do(ctx, req, &resp); err != nil {
+		return nil, 0, fmt.Errorf("failed to search products: %w", err)
+	}
+
+	if resp.HasError() {
+		return nil, 0, fmt.Errorf("tiktok error: %s", resp.GetError())
+	}
+
+	products := make([]TikTokProduct, len(resp.Data.Products))
+	for i, product := range resp.Data.Products {
+		products[i] = TikTokProduct{
+			ProductID:  product.ID,
+			Name:       product.Name,
+			Status:     ProductStatusName(product.Status),
+			UpdateTime: product.UpdateTime,
+			CreateTime: product.CreateTime,
+		}
+		for j := range product.SKUs {
+			products[i].SKUs = append(products[i].SKUs, product.SKUs[j].toSKU())
+		}
+	}
+
+	return products, resp.Data.Total, nil
+}
+
+// GetProductDetail fetches the full details of a single product
+func (p *ProductProvider) GetProductDetail(ctx context.Context, productID string) (*TikTokProductDetail, error) {
+	req := &Request{
+		Method: http.MethodGet,
+		Path:   GetProductDetailPath,
+		Query: map[string]string{
+			"product_id": productID,
+		},
+		NeedAuth: true,
+	}
+
+	// Data is decoded separately so the raw payload can be kept
+	var resp struct {
+		BaseResponse
+		Data json.RawMessage `json:"data"`
+	}
+
+	if err := p.client.Do(ctx, req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get product detail: %w", err)
+	}
+
+	if resp.HasError() {
+		return nil, fmt.Errorf("tiktok error: %s", resp.GetError())
+	}
+
+	var data struct {
+		ProductID     string `json:"product_id"`
+		ProductName   string `json:"product_name"`
+		Description   string `json:"description"`
+		ProductStatus int    `json:"product_status"`
+		CategoryList  []struct {
+			ID     string `json:"id"`
+			IsLeaf bool   `json:"is_leaf"`
+		} `json:"category_list"`
+		Brand struct {
+			Name string `json:"name"`
+		} `json:"brand"`
+		Images []struct {
+			URLList []string `json:"url_list"`
+		} `json:"images"`
+		PackageWeight     string `json:"package_weight"`
+		PackageLength     int    `json:"package_length"`
+		PackageWidth      int    `json:"package_width"`
+		PackageHeight     int    `json:"package_height"`
+		ProductAttributes []struct {
+			Name   string `json:"name"`
+			Values []struct {
+				Name string `json:"name"`
+			} `json:"values"`
+		} `json:"product_attributes"`
+		SKUs       []tiktokSKUPayload `json:"skus"`
+		CreateTime int64              `json:"create_time"`
+		UpdateTime int64              `json:"update_time"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode product detail: %w", err)
+	}
+
+	detail := &TikTokProductDetail{
+		ProductID:   data.ProductID,
+		Name:        data.ProductName,
+		Description: data.Description,
+		Status:      ProductStatusName(data.ProductStatus),
+		UpdateTime:  data.UpdateTime,
+		CreateTime:  data.CreateTime,
+		Brand:       data.Brand.Name,
+		Weight:      parseFloat(data.PackageWeight),
+		Length:      float64(data.PackageLength),
+		Width:       float64(data.PackageWidth),
+		Height:      float64(data.PackageHeight),
+		Raw:         resp.Data,
+	}
+	// The leaf is the product's actual category
+	for _, cat := range data.CategoryList {
+		if cat.IsLeaf || detail.CategoryID == "" {
+			detail.CategoryID = cat.ID
+		}
+	}
+	for _, img := range data.Images {
+		if len(img.URLList) > 0 {
+			detail.Images = append(detail.Images, img.URLList[0])
+		}
+	}
+	for _, attr := range data.ProductAttributes {
+		values := make([]string, 0, len(attr.Values))
+		for _, v := range attr.Values {
+			values = append(values, v.Name)
+		}
+		if attr.Name == "" || len(values) == 0 {
+			continue
+		}
+		if detail.Attributes == nil {
+			detail.Attributes = make(map[string]string)
+		}
+		detail.Attributes[attr.Name] = strings.Join(values, ", ")
+	}
+	for i := range data.SKUs {
+		detail.SKUs = append(detail.SKUs, data.SKUs[i].toSKU())
+	}
+
+	return detail, nil
+}
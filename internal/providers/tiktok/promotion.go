@@ -0,0 +1,153 @@
+package tiktok
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+const (
+	// Promotion API paths
+	CreatePromotionPath      = "/api/promotion/activity/create"
+	UpdatePromotionItemsPath = "/api/promotion/activity/items/addOrUpdate"
+	DeactivatePromotionPath  = "/api/promotion/activity/deactivate"
+)
+
+// TikTok Shop promotion limits.
+const (
+	PromotionMinLeadTime    = 5 * time.Minute      // A promotion must start in the future
+	PromotionMaxDuration    = 365 * 24 * time.Hour // and last at most a year
+	MaxPromotionTitleLength = 50
+)
+
+// Promotion settings: a fixed sale price set per SKU
+const (
+	promotionTypeFixedPrice = 2
+	promotionProductTypeSKU = 2
+)
+
+// CreateDiscount creates a fixed price promotion for the SKUs of one product. The window is
+// moved to respect TikTok's lead time and duration limits. The promotion is deactivated
+// again if the product cannot be added to it.
+func (p *ProductProvider) CreateDiscount(ctx context.Context, discount *providers.DiscountRequest) (*providers.DiscountResponse, error) {
+	start := discount.StartTime
+	if earliest := time.Now().Add(PromotionMinLeadTime); start.Before(earliest) {
+		start = earliest
+	}
+	end := discount.EndTime
+	if end.IsZero() || end.Sub(start) > PromotionMaxDuration {
+		end = start.Add(PromotionMaxDuration)
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("promotion would end before the required lead time")
+	}
+
+	title := discount.Name
+	if utf8.RuneCountInString(title) > MaxPromotionTitleLength {
+		title = string([]rune(title)[:MaxPromotionTitleLength])
+	}
+
+	req := &Request{
+		Method: http.MethodPost,
+		Path:   CreatePromotionPath,
+		Body: map[string]interface{}{
+			"title":             title,
+			"promotion_type":    promotionTypeFixedPrice,
+			"product_type":      promotionProductTypeSKU,
+			"begin_time":        start.Unix(),
+			"end_time":          end.Unix(),
+			"request_serial_no": fmt.Sprintf("%s-%d", discount.ExternalProductID, time.Now().UnixNano()),
+		},
+		NeedAuth: true,
+	}
+
+	var resp struct {
+		BaseResponse
+		Data struct {
+			ActivityID string `json:"activity_id"`
+		} `json:"data"`
+	}
+	if err := p.client.Do(ctx, req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to create promotion: %w", err)
+	}
+	if resp.HasError() {
+		return nil, fmt.Errorf("tiktok error: %s", resp.GetError())
+	}
+	activityID := resp.Data.ActivityID
+
+	if err := p.addPromotionProduct(ctx, activityID, discount.ExternalProductID, discount.Prices); err != nil {
+		// An empty promotion is useless; remove it so it does not pile up
+		_ = p.EndDiscount(ctx, activityID, false)
+		return nil, err
+	}
+
+	return &providers.DiscountResponse{
+		DiscountID: activityID,
+		StartTime:  start,
+		EndTime:    end,
+	}, nil
+}
+
+// addPromotionProduct adds the SKUs of a product to a promotion, keyed by SKU ID
+func (p *ProductProvider) addPromotionProduct(ctx context.Context, activityID, productID string, prices map[string]float64) error {
+	skus := make([]map[string]interface{}, 0, len(prices))
+	for skuID, price := range prices {
+		skus = append(skus, map[string]interface{}{
+			"sku_id":      skuID,
+			"fixed_price": fmt.Sprintf("%.2f", price),
+		})
+	}
+
+	req := &Request{
+		Method: http.MethodPost,
+		Path:   UpdatePromotionItemsPath,
+		Body: map[string]interface{}{
+			"activity_id": activityID,
+			"product_list": []map[string]interface{}{
+				{
+					"product_id": productID,
+					"sku_list":   skus,
+				},
+			},
+		},
+		NeedAuth: true,
+	}
+
+	var resp BaseResponse
+	if err := p.client.Do(ctx, req, &resp); err != nil {
+		return fmt.Errorf("failed to add promotion product: %w", err)
+	}
+
+	if resp.HasError() {
+		return fmt.Errorf("tiktok error: %s", resp.GetError())
+	}
+
+	return nil
+}
+
+// EndDiscount deactivates a promotion, whether or not it has started
+func (p *ProductProvider) EndDiscount(ctx context.Context, activityID string, started bool) error {
+	req := &Request{
+		Method: http.MethodPost,
+		Path:   DeactivatePromotionPath,
+		Body: map[string]interface{}{
+			"activity_id": activityID,
+		},
+		NeedAuth: true,
+	}
+
+	var resp BaseResponse
+	if err := p.client.Do(ctx, req, &resp); err != nil {
+		return fmt.Errorf("failed to deactivate promotion: %w", err)
+	}
+
+	if resp.HasError() {
+		return fmt.Errorf("tiktok error: %s", resp.GetError())
+	}
+
+	return nil
+}
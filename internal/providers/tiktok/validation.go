@@ -0,0 +1,92 @@
+package tiktok
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/Ecom-micro-template/service-marketplace/internal/providers"
+)
+
+// TikTok Shop listing limits
+const (
+	MinTitleLength       = 25
+	MaxTitleLength       = 255
+	MaxDescriptionLength = 10000
+	MaxImages            = 9
+)
+
+// ValidateProduct checks a push request against TikTok Shop listing rules without calling the API
+func (p *ProductProvider) ValidateProduct(product *providers.ProductPushRequest) *providers.ValidationResult {
+	result := providers.NewValidationResult()
+
+	// Title
+	titleLen := utf8.RuneCountInString(product.Name)
+	switch {
+	case titleLen == 0:
+		result.AddError(providers.IssueNameMissing, "name", "product name is empty", "Set a product name in the catalog")
+	case titleLen > MaxTitleLength:
+		result.AddError(providers.IssueNameTooLong, "name",
+			fmt.Sprintf("product name is %d characters, TikTok allows at most %d", titleLen, MaxTitleLength),
+			"Shorten the product name or set a channel-specific title")
+	case titleLen < MinTitleLength:
+		result.AddWarning(providers.IssueNameTooShort, "name",
+			fmt.Sprintf("product name is %d characters, TikTok recommends at least %d", titleLen, MinTitleLength),
+			"Add key attributes such as brand, material or size to the title")
+	}
+
+	// Description
+	description := providers.RenderHTML(product.DescriptionContent())
+	if description == "" {
+		result.AddError(providers.IssueDescriptionTooShort, "description", "product has no description",
+			"Write a product description in the catalog")
+	} else if utf8.RuneCountInString(description) > MaxDescriptionLength {
+		result.AddWarning(providers.IssueDescriptionTooLong, "description",
+			fmt.Sprintf("description exceeds %d characters and will be shortened at a sentence boundary", MaxDescriptionLength),
+			"Shorten the description to keep the ending intact")
+	}
+
+	// Images
+	if len(product.Images) == 0 {
+		result.AddError(providers.IssueImagesMissing, "images", "product has no images, TikTok requires at least 1",
+			"Upload at least one product image in the catalog")
+	} else if len(product.Images) > MaxImages {
+		result.AddWarning(providers.IssueTooManyImages, "images",
+			fmt.Sprintf("product has %d images, only the first %d will be accepted", len(product.Images), MaxImages),
+			"Reorder images so the most important ones come first")
+	}
+
+	// Category
+	if product.CategoryID == "" {
+		result.AddError(providers.IssueCategoryUnmapped, "category_id", "product category is not mapped to a TikTok category",
+			"Create a category mapping for this product's category")
+	}
+
+	// Price
+	if product.OriginalPrice <= 0 {
+		result.AddError(providers.IssuePriceInvalid, "price", "product price must be greater than zero",
+			"Set a base price in the catalog")
+	}
+
+	// Weight is mandatory for TikTok package info
+	if product.Weight <= 0 {
+		result.AddError(providers.IssueWeightMissing, "weight", "product has no weight, TikTok requires a package weight",
+			"Set the shipping weight in grams")
+	}
+
+	// Dimensions
+	if product.Dimensions == nil {
+		result.AddWarning(providers.IssueDimensionsMissing, "dimensions", "product has no package dimensions",
+			"Set package dimensions to get accurate shipping fees")
+	}
+
+	// SKU and stock
+	if product.SKU == "" {
+		result.AddWarning(providers.IssueSKUMissing, "sku", "product has no SKU",
+			"Set a SKU so orders and stock can be matched reliably")
+	}
+	if product.Stock <= 0 {
+		result.AddWarning(providers.IssueOutOfStock, "stock", "product has no stock and will be listed as sold out", "")
+	}
+
+	return result
+}
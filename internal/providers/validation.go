@@ -0,0 +1,70 @@
+package providers
+
+// Validation issue codes shared across platforms
+const (
+	IssueDescriptionTooShort  = "description_too_short"
+	IssueDescriptionTooLong   = "description_too_long"
+	IssueDescriptionSpam      = "description_placeholder"
+	IssueNameMissing          = "name_missing"
+	IssueNameTooLong          = "name_too_long"
+	IssueNameTooShort         = "name_too_short"
+	IssueImagesMissing        = "images_missing"
+	IssueTooManyImages        = "too_many_images"
+	IssueCategoryUnmapped     = "category_unmapped"
+	IssueCategoryInvalid      = "category_invalid"
+	IssueWeightMissing        = "weight_missing"
+	IssueWeightTooLow         = "weight_too_low"
+	IssueDimensionsMissing    = "dimensions_missing"
+	IssuePriceInvalid         = "price_invalid"
+	IssueSKUMissing           = "sku_missing"
+	IssueOutOfStock           = "out_of_stock"
+	IssueBrandRequired        = "brand_required"
+	IssueBrandUnresolved      = "brand_unresolved"
+	IssueLogisticsUnavailable = "logistics_unavailable"
+)
+
+// ValidationIssue describes a rule a product violates for a marketplace
+type ValidationIssue struct {
+	Code    string `json:"code"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// ValidationResult holds blocking errors and non-blocking warnings
+type ValidationResult struct {
+	Errors   []ValidationIssue `json:"errors"`
+	Warnings []ValidationIssue `json:"warnings"`
+}
+
+// NewValidationResult creates an empty ValidationResult
+func NewValidationResult() *ValidationResult {
+	return &ValidationResult{
+		Errors:   []ValidationIssue{},
+		Warnings: []ValidationIssue{},
+	}
+}
+
+// AddError records a blocking issue
+func (r *ValidationResult) AddError(code, field, message, hint string) {
+	r.Errors = append(r.Errors, ValidationIssue{Code: code, Field: field, Message: message, Hint: hint})
+}
+
+// AddWarning records a non-blocking issue
+func (r *ValidationResult) AddWarning(code, field, message, hint string) {
+	r.Warnings = append(r.Warnings, ValidationIssue{Code: code, Field: field, Message: message, Hint: hint})
+}
+
+// Merge appends the issues of another result
+func (r *ValidationResult) Merge(other *ValidationResult) {
+	if other == nil {
+		return
+	}
+	r.Errors = append(r.Errors, other.Errors...)
+	r.Warnings = append(r.Warnings, other.Warnings...)
+}
+
+// IsValid returns true if there are no blocking errors
+func (r *ValidationResult) IsValid() bool {
+	return len(r.Errors) == 0
+}
@@ -13,7 +13,11 @@ type RouteConfig struct {
 	ConnectionHandler *handlers.ConnectionHandler
 	ProductHandler    *handlers.ProductHandler
 	CategoryHandler   *handlers.CategoryHandler
+	BrandHandler      *handlers.BrandHandler
+	PricingHandler    *handlers.PricingHandler
+	ListingHandler    *handlers.ListingHandler
 	InventoryHandler  *handlers.InventoryHandler
+	StockHandler      *handlers.StockAllocationHandler
 	OrderHandler      *handlers.OrderHandler
 	WebhookHandler    *handlers.WebhookHandler
 	JWTManager        *libauth.JWTManager
@@ -53,33 +57,111 @@ func SetupRoutes(router *gin.Engine, cfg *RouteConfig) {
 			// Product sync routes
 			connections.GET("/:id/products", cfg.ProductHandler.GetMappedProducts)
 			connections.POST("/:id/products/push", cfg.ProductHandler.PushProducts)
+			connections.GET("/:id/products/push/jobs", cfg.ProductHandler.GetPushJobs)
+			connections.GET("/:id/products/push/jobs/:job_id", cfg.ProductHandler.GetPushJob)
+			connections.POST("/:id/products/validate", cfg.ProductHandler.ValidateProducts)
+			connections.POST("/:id/products/unlist", cfg.ProductHandler.UnlistProducts)
+			connections.POST("/:id/products/relist", cfg.ProductHandler.RelistProducts)
+			connections.GET("/:id/products/mappings/export", cfg.ProductHandler.ExportProductMappings)
+			connections.POST("/:id/products/mappings/import", cfg.ProductHandler.ImportProductMappings)
+			connections.GET("/:id/products/mappings/import/jobs/:job_id", cfg.ProductHandler.GetMappingImportJob)
+			connections.POST("/:id/products/:mapping_id/unlist", cfg.ProductHandler.UnlistProduct)
+			connections.POST("/:id/products/:mapping_id/relist", cfg.ProductHandler.RelistProduct)
 			connections.PUT("/:id/products/:mapping_id", cfg.ProductHandler.UpdateProductMapping)
 			connections.DELETE("/:id/products/:mapping_id", cfg.ProductHandler.DeleteProductMapping)
 
 			// Import & Map routes (for linking existing marketplace products to admin products)
 			connections.POST("/:id/products/import", cfg.ProductHandler.ImportProducts)
+			connections.GET("/:id/products/import/jobs/:job_id", cfg.ProductHandler.GetImportJob)
+			connections.POST("/:id/products/import/jobs/:job_id/resume", cfg.ProductHandler.ResumeImportJob)
 			connections.GET("/:id/products/imported", cfg.ProductHandler.GetImportedProducts)
+			connections.GET("/:id/products/imported/matches", cfg.ProductHandler.GetProductMatches)
+			connections.POST("/:id/products/imported/matches/confirm", cfg.ProductHandler.ConfirmProductMatches)
+			connections.GET("/:id/products/imported/:imported_id", cfg.ProductHandler.GetImportedProduct)
 			connections.POST("/:id/products/map", cfg.ProductHandler.CreateManualMapping)
 			connections.DELETE("/:id/products/map/:mapping_id", cfg.ProductHandler.DeleteManualMapping)
 
+			// Listing drift routes
+			connections.POST("/:id/drift/check", cfg.ProductHandler.StartDriftCheck)
+			connections.GET("/:id/drift/jobs/:job_id", cfg.ProductHandler.GetDriftCheckJob)
+			connections.GET("/:id/drift", cfg.ProductHandler.GetListingDrifts)
+			connections.GET("/:id/drift/:mapping_id", cfg.ProductHandler.GetListingDrift)
+			connections.POST("/:id/drift/:mapping_id/resolve", cfg.ProductHandler.ResolveListingDrift)
+
 			// Category mapping routes
 			connections.GET("/:id/categories/external", cfg.CategoryHandler.GetExternalCategories)
+			connections.GET("/:id/categories/external/children", cfg.CategoryHandler.BrowseExternalCategories)
+			connections.GET("/:id/categories/external/search", cfg.CategoryHandler.SearchExternalCategories)
+			connections.POST("/:id/categories/external/refresh", cfg.CategoryHandler.RefreshExternalCategories)
 			connections.GET("/:id/categories", cfg.CategoryHandler.GetCategoryMappings)
 			connections.POST("/:id/categories", cfg.CategoryHandler.CreateCategoryMapping)
+			connections.GET("/:id/categories/effective", cfg.CategoryHandler.GetEffectiveCategoryMappings)
+			connections.GET("/:id/categories/suggestions", cfg.CategoryHandler.GetCategorySuggestions)
+			connections.POST("/:id/categories/suggestions/accept", cfg.CategoryHandler.AcceptCategorySuggestions)
+			connections.GET("/:id/categories/export", cfg.CategoryHandler.ExportCategoryMappings)
+			connections.POST("/:id/categories/import", cfg.CategoryHandler.ImportCategoryMappings)
 			connections.DELETE("/:id/categories/:mapping_id", cfg.CategoryHandler.DeleteCategoryMapping)
 
+			// Brand mapping routes
+			connections.GET("/:id/brands/external", cfg.BrandHandler.GetExternalBrands)
+			connections.GET("/:id/brands", cfg.BrandHandler.GetBrandMappings)
+			connections.POST("/:id/brands", cfg.BrandHandler.SetBrandMapping)
+			connections.POST("/:id/brands/suggest", cfg.BrandHandler.SuggestBrandMappings)
+			connections.POST("/:id/brands/:mapping_id/confirm", cfg.BrandHandler.ConfirmBrandMapping)
+			connections.DELETE("/:id/brands/:mapping_id", cfg.BrandHandler.DeleteBrandMapping)
+
+			// Pricing rule routes
+			connections.GET("/:id/pricing/rules", cfg.PricingHandler.GetPricingRules)
+			connections.PUT("/:id/pricing/rules", cfg.PricingHandler.SavePricingRule)
+			connections.DELETE("/:id/pricing/rules/:rule_id", cfg.PricingHandler.DeletePricingRule)
+			connections.GET("/:id/pricing/preview/:product_id", cfg.PricingHandler.PreviewPrice)
+			connections.GET("/:id/pricing/sales", cfg.ProductHandler.GetSaleCampaigns)
+
+			// Auto-listing routes
+			connections.GET("/:id/auto-listing", cfg.ProductHandler.GetAutoListingRule)
+			connections.PUT("/:id/auto-listing", cfg.ProductHandler.SaveAutoListingRule)
+			connections.DELETE("/:id/auto-listing", cfg.ProductHandler.DeleteAutoListingRule)
+
+			// Listing template & override routes
+			connections.GET("/:id/listing/template", cfg.ListingHandler.GetListingTemplate)
+			connections.PUT("/:id/listing/template", cfg.ListingHandler.SaveListingTemplate)
+			connections.DELETE("/:id/listing/template", cfg.ListingHandler.DeleteListingTemplate)
+			connections.GET("/:id/listing/overrides", cfg.ListingHandler.GetListingOverrides)
+			connections.GET("/:id/listing/overrides/:product_id", cfg.ListingHandler.GetListingOverride)
+			connections.PUT("/:id/listing/overrides/:product_id", cfg.ListingHandler.SaveListingOverride)
+			connections.DELETE("/:id/listing/overrides/:product_id", cfg.ListingHandler.DeleteListingOverride)
+			connections.GET("/:id/listing/preview/:product_id", cfg.ListingHandler.PreviewListing)
+
 			// Inventory sync routes
 			connections.POST("/:id/inventory/push", cfg.InventoryHandler.PushInventory)
 			connections.POST("/:id/inventory/status", cfg.InventoryHandler.GetInventoryStatus)
+			connections.POST("/:id/inventory/reconcile", cfg.InventoryHandler.StartReconciliation)
+			connections.GET("/:id/inventory/reconcile/jobs/:job_id", cfg.InventoryHandler.GetReconcileJob)
+			connections.GET("/:id/inventory/discrepancies", cfg.InventoryHandler.GetInventoryDiscrepancies)
+			connections.GET("/:id/inventory/logs", cfg.InventoryHandler.GetInventorySyncLogs)
+			connections.GET("/:id/warehouses", cfg.InventoryHandler.GetWarehouseMappings)
+			connections.PUT("/:id/warehouses", cfg.InventoryHandler.SaveWarehouseMappings)
+
+			// Stock allocation rule routes
+			connections.GET("/:id/inventory/allocation/rules", cfg.StockHandler.GetAllocationRules)
+			connections.PUT("/:id/inventory/allocation/rules", cfg.StockHandler.SaveAllocationRule)
+			connections.DELETE("/:id/inventory/allocation/rules/:rule_id", cfg.StockHandler.DeleteAllocationRule)
 
 			// Order sync routes
 			connections.GET("/:id/orders", cfg.OrderHandler.GetOrders)
 			connections.POST("/:id/orders/sync", cfg.OrderHandler.SyncOrders)
+			connections.POST("/:id/orders/backfill", cfg.OrderHandler.StartOrderBackfill)
+			connections.GET("/:id/orders/backfill/jobs/:job_id", cfg.OrderHandler.GetBackfillJob)
+			connections.POST("/:id/orders/backfill/jobs/:job_id/resume", cfg.OrderHandler.ResumeBackfillJob)
 			connections.PUT("/:id/orders/:order_id/status", cfg.OrderHandler.UpdateOrderStatus)
 			connections.POST("/:id/orders/:order_id/ship", cfg.OrderHandler.ArrangeShipment)
 			connections.POST("/:id/orders/:order_id/awb", cfg.OrderHandler.GetAWB)
 		}
 
+		// Stock allocation preview and stock push history across all connections
+		admin.GET("/inventory/allocation/preview/:product_id", cfg.StockHandler.PreviewStock)
+		admin.GET("/inventory/logs", cfg.InventoryHandler.GetInventorySyncLogs)
+
 		// OAuth flow
 		admin.POST("/:platform/auth-url", cfg.ConnectionHandler.GetAuthURL)
 		admin.GET("/shopee/callback", cfg.ConnectionHandler.HandleShopeeCallback)
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizeName lowercases a name and collapses everything that is not a letter or digit into single spaces
+func NormalizeName(s string) string {
+	var b strings.Builder
+	space := true
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		if !space {
+			b.WriteRune(' ')
+			space = true
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Tokenize splits a name into normalized tokens
+func Tokenize(s string) []string {
+	return strings.Fields(NormalizeName(s))
+}
+
+// TokenSimilarity returns the Jaccard similarity (0-1) between the token sets of two names
+func TokenSimilarity(a, b string) float64 {
+	ta, tb := Tokenize(a), Tokenize(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	set := make(map[string]bool, len(ta))
+	for _, t := range ta {
+		set[t] = true
+	}
+
+	inter := 0
+	union := len(set)
+	seen := make(map[string]bool, len(tb))
+	for _, t := range tb {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		if set[t] {
+			inter++
+		} else {
+			union++
+		}
+	}
+
+	return float64(inter) / float64(union)
+}
+
+// EditSimilarity returns 1 - normalized Levenshtein distance (0-1) between two names
+func EditSimilarity(a, b string) float64 {
+	ra := []rune(strings.ReplaceAll(NormalizeName(a), " ", ""))
+	rb := []rune(strings.ReplaceAll(NormalizeName(b), " ", ""))
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	maxLen := max(len(ra), len(rb))
+	return 1 - float64(prev[len(rb)])/float64(maxLen)
+}
+
+// NameSimilarity combines token and edit similarity into a single 0-1 score
+func NameSimilarity(a, b string) float64 {
+	if NormalizeName(a) == NormalizeName(b) && NormalizeName(a) != "" {
+		return 1
+	}
+	return max(TokenSimilarity(a, b), EditSimilarity(a, b))
+}
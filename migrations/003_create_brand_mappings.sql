@@ -0,0 +1,26 @@
+-- Brand Mappings Table
+-- Maps internal brand names to marketplace brand IDs per connection
+
+CREATE TABLE IF NOT EXISTS marketplace.brand_mappings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    connection_id UUID NOT NULL REFERENCES marketplace.connections(id) ON DELETE CASCADE,
+    internal_brand VARCHAR(255) NOT NULL,
+    external_brand_id VARCHAR(100) NOT NULL,
+    external_brand_name VARCHAR(255),
+    status VARCHAR(50) DEFAULT 'suggested', -- suggested, confirmed
+    confidence DECIMAL(5, 4) DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_connection_internal_brand UNIQUE (connection_id, internal_brand)
+);
+
+CREATE INDEX idx_brand_mappings_connection ON marketplace.brand_mappings(connection_id);
+CREATE INDEX idx_brand_mappings_status ON marketplace.brand_mappings(status);
+
+-- Apply update trigger
+CREATE TRIGGER update_brand_mappings_updated_at
+    BEFORE UPDATE ON marketplace.brand_mappings
+    FOR EACH ROW EXECUTE FUNCTION marketplace.update_updated_at_column();
+
+COMMENT ON TABLE marketplace.brand_mappings IS 'Internal brand to marketplace brand ID mappings, suggested by fuzzy matching and confirmed by admins';
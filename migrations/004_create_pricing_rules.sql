@@ -0,0 +1,30 @@
+-- Pricing Rules Table
+-- Per-connection rules that convert catalog prices into marketplace prices
+
+CREATE TABLE IF NOT EXISTS marketplace.pricing_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    connection_id UUID NOT NULL REFERENCES marketplace.connections(id) ON DELETE CASCADE,
+    internal_category_id UUID, -- NULL for the connection default rule
+    markup_type VARCHAR(20) DEFAULT 'percent', -- percent, fixed
+    markup_value DECIMAL(12, 4) DEFAULT 0,
+    rounding VARCHAR(20) DEFAULT 'none', -- none, integer, end_90, end_99
+    min_margin_percent DECIMAL(8, 4) DEFAULT 0,
+    strike_through VARCHAR(20) DEFAULT 'catalog', -- catalog, none, percent
+    strike_through_percent DECIMAL(8, 4) DEFAULT 0,
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_pricing_rules_connection ON marketplace.pricing_rules(connection_id);
+CREATE UNIQUE INDEX idx_pricing_rules_connection_default
+    ON marketplace.pricing_rules(connection_id) WHERE internal_category_id IS NULL;
+CREATE UNIQUE INDEX idx_pricing_rules_connection_category
+    ON marketplace.pricing_rules(connection_id, internal_category_id) WHERE internal_category_id IS NOT NULL;
+
+-- Apply update trigger
+CREATE TRIGGER update_pricing_rules_updated_at
+    BEFORE UPDATE ON marketplace.pricing_rules
+    FOR EACH ROW EXECUTE FUNCTION marketplace.update_updated_at_column();
+
+COMMENT ON TABLE marketplace.pricing_rules IS 'Per-connection pricing rules (markup, rounding, margin floor, strike-through) with per-category overrides';
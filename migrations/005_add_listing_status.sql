@@ -0,0 +1,12 @@
+-- Listing Status
+-- Tracks the marketplace listing lifecycle (draft, pending, active, paused, rejected, sold_out)
+-- of each product mapping, driven by platform responses and webhooks
+
+ALTER TABLE marketplace.product_mappings
+    ADD COLUMN IF NOT EXISTS listing_status VARCHAR(50) DEFAULT 'active',
+    ADD COLUMN IF NOT EXISTS listing_reason TEXT,
+    ADD COLUMN IF NOT EXISTS listing_updated_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_product_mappings_listing_status ON marketplace.product_mappings(listing_status);
+
+COMMENT ON COLUMN marketplace.product_mappings.listing_status IS 'Marketplace listing state: draft, pending, active, paused, rejected, sold_out';
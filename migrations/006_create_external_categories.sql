@@ -0,0 +1,34 @@
+-- External Categories Table
+-- Caches marketplace category trees per connection; refreshed on a schedule
+
+CREATE TABLE IF NOT EXISTS marketplace.external_categories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    connection_id UUID NOT NULL REFERENCES marketplace.connections(id) ON DELETE CASCADE,
+    category_id VARCHAR(100) NOT NULL,
+    parent_id VARCHAR(100), -- Empty for root categories
+    name VARCHAR(255) NOT NULL,
+    breadcrumb TEXT, -- "Parent > Child > Leaf"
+    depth INTEGER DEFAULT 0,
+    is_leaf BOOLEAN DEFAULT false,
+    is_active BOOLEAN DEFAULT true, -- false once the category disappears from the marketplace
+    last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT unique_connection_external_category UNIQUE (connection_id, category_id)
+);
+
+CREATE INDEX idx_external_categories_connection ON marketplace.external_categories(connection_id);
+CREATE INDEX idx_external_categories_parent ON marketplace.external_categories(connection_id, parent_id);
+CREATE INDEX idx_external_categories_breadcrumb ON marketplace.external_categories USING gin (to_tsvector('simple', breadcrumb));
+
+-- Apply update trigger
+CREATE TRIGGER update_external_categories_updated_at
+    BEFORE UPDATE ON marketplace.external_categories
+    FOR EACH ROW EXECUTE FUNCTION marketplace.update_updated_at_column();
+
+-- Flag mappings whose external category no longer exists
+ALTER TABLE marketplace.category_mappings
+    ADD COLUMN IF NOT EXISTS is_stale BOOLEAN DEFAULT false;
+
+COMMENT ON TABLE marketplace.external_categories IS 'Cached marketplace category trees per connection';
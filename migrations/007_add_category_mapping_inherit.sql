@@ -0,0 +1,8 @@
+-- Category Mapping Inheritance
+-- A mapping on a parent internal category applies to descendant categories
+-- without their own mapping unless inherit is disabled
+
+ALTER TABLE marketplace.category_mappings
+    ADD COLUMN IF NOT EXISTS inherit BOOLEAN DEFAULT true;
+
+COMMENT ON COLUMN marketplace.category_mappings.inherit IS 'Apply this mapping to subcategories that have no mapping of their own';
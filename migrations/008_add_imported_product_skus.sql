@@ -0,0 +1,8 @@
+-- Imported Product SKUs
+-- Stores the SKU list (ID, seller SKU, price, stock, sales attributes) of imported
+-- marketplace products so variant mappings can be created when an import is mapped
+
+ALTER TABLE marketplace.imported_products
+    ADD COLUMN IF NOT EXISTS skus JSONB DEFAULT '[]';
+
+COMMENT ON COLUMN marketplace.imported_products.skus IS 'SKU list of the imported product: external_sku_id, seller_sku, price, stock, attributes';
@@ -0,0 +1,14 @@
+-- Incremental Product Import
+-- Tracks when imported listings were last seen and changed on the marketplace so
+-- re-imports only fetch changed listings and flag listings that disappeared
+
+ALTER TABLE marketplace.imported_products
+    ADD COLUMN IF NOT EXISTS is_removed BOOLEAN DEFAULT false,
+    ADD COLUMN IF NOT EXISTS external_updated_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_imported_products_last_seen ON marketplace.imported_products(connection_id, last_seen_at);
+CREATE INDEX IF NOT EXISTS idx_sync_jobs_type_status ON marketplace.sync_jobs(connection_id, job_type, status);
+
+COMMENT ON COLUMN marketplace.imported_products.is_removed IS 'Listing was not returned by the latest import';
+COMMENT ON COLUMN marketplace.imported_products.external_updated_at IS 'Marketplace update_time of the listing when it was last fetched';
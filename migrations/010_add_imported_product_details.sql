@@ -0,0 +1,24 @@
+-- Imported Product Details
+-- Stores the full listing of imported marketplace products (all images, attributes,
+-- brand, package weight and dimensions) plus the unmodified platform payload so no
+-- data is lost when an import is later mapped to a catalog product
+
+ALTER TABLE marketplace.imported_products
+    ADD COLUMN IF NOT EXISTS images JSONB DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS attributes JSONB DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS brand VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS weight DECIMAL(10,2),
+    ADD COLUMN IF NOT EXISTS length DECIMAL(10,2),
+    ADD COLUMN IF NOT EXISTS width DECIMAL(10,2),
+    ADD COLUMN IF NOT EXISTS height DECIMAL(10,2),
+    ADD COLUMN IF NOT EXISTS raw_payload JSONB;
+
+-- Existing imports only stored their first image
+UPDATE marketplace.imported_products
+SET images = jsonb_build_array(image_url)
+WHERE image_url IS NOT NULL AND image_url <> '' AND images = '[]'::jsonb;
+
+COMMENT ON COLUMN marketplace.imported_products.images IS 'All image URLs of the listing, in display order';
+COMMENT ON COLUMN marketplace.imported_products.attributes IS 'Product attributes by name, multiple values comma-separated';
+COMMENT ON COLUMN marketplace.imported_products.weight IS 'Package weight in grams';
+COMMENT ON COLUMN marketplace.imported_products.raw_payload IS 'Unmodified platform response (Shopee: item and model_list, TikTok: product detail)';
@@ -0,0 +1,30 @@
+-- Listing Drifts
+-- Latest comparison of each mapped marketplace listing against its catalog product
+-- (name, price, stock, status, images), written by drift_check sync jobs
+
+CREATE TABLE IF NOT EXISTS marketplace.listing_drifts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    connection_id UUID NOT NULL REFERENCES marketplace.connections(id) ON DELETE CASCADE,
+    product_mapping_id UUID NOT NULL REFERENCES marketplace.product_mappings(id) ON DELETE CASCADE,
+    internal_product_id UUID NOT NULL,
+    external_product_id VARCHAR(100) NOT NULL,
+    fields JSONB DEFAULT '[]',
+    has_drift BOOLEAN DEFAULT false,
+    check_error TEXT,
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(product_mapping_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_listing_drifts_connection ON marketplace.listing_drifts(connection_id);
+CREATE INDEX IF NOT EXISTS idx_listing_drifts_has_drift ON marketplace.listing_drifts(connection_id, has_drift);
+
+DROP TRIGGER IF EXISTS update_listing_drifts_updated_at ON marketplace.listing_drifts;
+CREATE TRIGGER update_listing_drifts_updated_at
+    BEFORE UPDATE ON marketplace.listing_drifts
+    FOR EACH ROW
+    EXECUTE FUNCTION marketplace.update_updated_at_column();
+
+COMMENT ON TABLE marketplace.listing_drifts IS 'Per-mapping differences between live marketplace listings and catalog products';
+COMMENT ON COLUMN marketplace.listing_drifts.fields IS 'Drifted fields: [{field, catalog_value, marketplace_value}]';
@@ -0,0 +1,46 @@
+-- Listing Templates & Overrides
+-- Per-connection title/description templates and per-product content overrides
+-- applied when products are pushed and updated on a marketplace
+
+CREATE TABLE IF NOT EXISTS marketplace.listing_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    connection_id UUID NOT NULL REFERENCES marketplace.connections(id) ON DELETE CASCADE,
+    title_template TEXT, -- e.g. '{{brand}} {{name}} - {{variant}}'
+    description_template TEXT,
+    max_title_length INTEGER DEFAULT 0, -- 0 for no limit
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(connection_id)
+);
+
+CREATE TABLE IF NOT EXISTS marketplace.listing_overrides (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    connection_id UUID NOT NULL REFERENCES marketplace.connections(id) ON DELETE CASCADE,
+    internal_product_id UUID NOT NULL,
+    title TEXT,
+    description TEXT,
+    image_order JSONB DEFAULT '[]',
+    attributes JSONB DEFAULT '{}',
+    price DECIMAL(12, 2),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(connection_id, internal_product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_listing_overrides_connection ON marketplace.listing_overrides(connection_id);
+
+DROP TRIGGER IF EXISTS update_listing_templates_updated_at ON marketplace.listing_templates;
+CREATE TRIGGER update_listing_templates_updated_at
+    BEFORE UPDATE ON marketplace.listing_templates
+    FOR EACH ROW
+    EXECUTE FUNCTION marketplace.update_updated_at_column();
+
+DROP TRIGGER IF EXISTS update_listing_overrides_updated_at ON marketplace.listing_overrides;
+CREATE TRIGGER update_listing_overrides_updated_at
+    BEFORE UPDATE ON marketplace.listing_overrides
+    FOR EACH ROW
+    EXECUTE FUNCTION marketplace.update_updated_at_column();
+
+COMMENT ON TABLE marketplace.listing_templates IS 'Per-connection title and description templates with {{placeholder}} substitution';
+COMMENT ON TABLE marketplace.listing_overrides IS 'Per-product listing content (title, description, image order, attributes, price) for one connection';
+COMMENT ON COLUMN marketplace.listing_overrides.image_order IS 'Catalog image URLs published first, in this order';
@@ -0,0 +1,25 @@
+-- Auto-Listing Rules
+-- Per-connection rules that push newly created catalog products automatically
+-- (triggered by product.created events)
+
+CREATE TABLE IF NOT EXISTS marketplace.auto_listing_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    connection_id UUID NOT NULL REFERENCES marketplace.connections(id) ON DELETE CASCADE,
+    category_ids JSONB DEFAULT '[]', -- internal category IDs, subcategories included; empty for all
+    min_stock INTEGER DEFAULT 0,
+    require_images BOOLEAN NOT NULL DEFAULT true,
+    initial_state VARCHAR(20) DEFAULT 'draft', -- draft, active
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(connection_id)
+);
+
+DROP TRIGGER IF EXISTS update_auto_listing_rules_updated_at ON marketplace.auto_listing_rules;
+CREATE TRIGGER update_auto_listing_rules_updated_at
+    BEFORE UPDATE ON marketplace.auto_listing_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION marketplace.update_updated_at_column();
+
+COMMENT ON TABLE marketplace.auto_listing_rules IS 'Which new catalog products are pushed to a connection automatically, and in which initial state';
+COMMENT ON COLUMN marketplace.auto_listing_rules.initial_state IS 'draft creates TikTok drafts and unlisted Shopee items; active publishes immediately';
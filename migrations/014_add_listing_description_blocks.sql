@@ -0,0 +1,12 @@
+-- Listing Description Blocks
+-- Per-connection header and footer blocks framing every published description,
+-- and opt-in Shopee extended descriptions with images
+
+ALTER TABLE marketplace.listing_templates
+    ADD COLUMN IF NOT EXISTS description_header TEXT,
+    ADD COLUMN IF NOT EXISTS description_footer TEXT,
+    ADD COLUMN IF NOT EXISTS extended_description BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN marketplace.listing_templates.description_header IS 'Block placed before every description, kept when the description is shortened';
+COMMENT ON COLUMN marketplace.listing_templates.description_footer IS 'Block placed after every description, kept when the description is shortened';
+COMMENT ON COLUMN marketplace.listing_templates.extended_description IS 'Publish Shopee extended descriptions with images where the shop is eligible';
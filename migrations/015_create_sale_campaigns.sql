@@ -0,0 +1,33 @@
+-- Sale Campaigns
+-- Catalog sale prices published as Shopee discounts and TikTok Shop promotions,
+-- mapping each internal sale to the external discount ID
+
+CREATE TABLE IF NOT EXISTS marketplace.sale_campaigns (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    connection_id UUID NOT NULL REFERENCES marketplace.connections(id) ON DELETE CASCADE,
+    internal_product_id UUID NOT NULL,
+    external_product_id VARCHAR(100) NOT NULL,
+    external_discount_id VARCHAR(100), -- Shopee discount_id, TikTok promotion activity_id
+    regular_price DECIMAL(12, 2),
+    sale_price DECIMAL(12, 2),
+    catalog_starts_at TIMESTAMP WITH TIME ZONE,
+    catalog_ends_at TIMESTAMP WITH TIME ZONE,
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(20) NOT NULL, -- active, ended, failed
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_sale_campaigns_product ON marketplace.sale_campaigns(connection_id, internal_product_id, created_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sale_campaigns_active ON marketplace.sale_campaigns(connection_id, internal_product_id) WHERE status = 'active';
+
+DROP TRIGGER IF EXISTS update_sale_campaigns_updated_at ON marketplace.sale_campaigns;
+CREATE TRIGGER update_sale_campaigns_updated_at
+    BEFORE UPDATE ON marketplace.sale_campaigns
+    FOR EACH ROW
+    EXECUTE FUNCTION marketplace.update_updated_at_column();
+
+COMMENT ON TABLE marketplace.sale_campaigns IS 'Catalog sales published as marketplace discounts; at most one active campaign per product and connection';
+COMMENT ON COLUMN marketplace.sale_campaigns.catalog_starts_at IS 'Catalog sale window the campaign was created for; the marketplace window may start later or end earlier';
@@ -0,0 +1,35 @@
+-- Warehouse Mappings
+-- Per-connection mapping of internal warehouses to Shopee seller_stock locations and
+-- TikTok Shop warehouses, and the per-warehouse stock they are pushed from
+
+CREATE TABLE IF NOT EXISTS marketplace.warehouse_mappings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    connection_id UUID NOT NULL REFERENCES marketplace.connections(id) ON DELETE CASCADE,
+    internal_warehouse_id VARCHAR(100) NOT NULL,
+    external_warehouse_id VARCHAR(100) NOT NULL, -- Shopee location_id, TikTok warehouse_id
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(connection_id, internal_warehouse_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_warehouse_mappings_default
+    ON marketplace.warehouse_mappings(connection_id) WHERE is_default;
+
+DROP TRIGGER IF EXISTS update_warehouse_mappings_updated_at ON marketplace.warehouse_mappings;
+CREATE TRIGGER update_warehouse_mappings_updated_at
+    BEFORE UPDATE ON marketplace.warehouse_mappings
+    FOR EACH ROW
+    EXECUTE FUNCTION marketplace.update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS marketplace.warehouse_stock_levels (
+    internal_product_id UUID NOT NULL,
+    warehouse_id VARCHAR(100) NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (internal_product_id, warehouse_id)
+);
+
+COMMENT ON TABLE marketplace.warehouse_mappings IS 'Which marketplace warehouse the stock of an internal warehouse is pushed to';
+COMMENT ON COLUMN marketplace.warehouse_mappings.is_default IS 'Receives the stock of unmapped warehouses when the connection settings unmapped_warehouses is default';
+COMMENT ON TABLE marketplace.warehouse_stock_levels IS 'Last known stock per product and internal warehouse, from stock.changed events';
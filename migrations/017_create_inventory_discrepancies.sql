@@ -0,0 +1,36 @@
+-- Inventory Discrepancies
+-- Latest comparison of the marketplace stock of each listed SKU against the inventory
+-- service, written by inventory_reconcile sync jobs, with the last correction pushed
+
+CREATE TABLE IF NOT EXISTS marketplace.inventory_discrepancies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    connection_id UUID NOT NULL REFERENCES marketplace.connections(id) ON DELETE CASCADE,
+    product_mapping_id UUID NOT NULL REFERENCES marketplace.product_mappings(id) ON DELETE CASCADE,
+    internal_product_id UUID NOT NULL,
+    internal_variant_id UUID,
+    external_product_id VARCHAR(100) NOT NULL,
+    external_variant_id VARCHAR(100) NOT NULL DEFAULT '', -- Shopee model_id, TikTok sku id; empty for the whole listing
+    sku VARCHAR(100),
+    expected_quantity INTEGER NOT NULL,
+    actual_quantity INTEGER NOT NULL,
+    has_discrepancy BOOLEAN NOT NULL DEFAULT false,
+    check_error TEXT,
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_corrected_at TIMESTAMP WITH TIME ZONE,
+    last_correction_quantity INTEGER,
+    last_correction_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(product_mapping_id, external_variant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_discrepancies_connection ON marketplace.inventory_discrepancies(connection_id, has_discrepancy);
+
+DROP TRIGGER IF EXISTS update_inventory_discrepancies_updated_at ON marketplace.inventory_discrepancies;
+CREATE TRIGGER update_inventory_discrepancies_updated_at
+    BEFORE UPDATE ON marketplace.inventory_discrepancies
+    FOR EACH ROW
+    EXECUTE FUNCTION marketplace.update_updated_at_column();
+
+COMMENT ON TABLE marketplace.inventory_discrepancies IS 'Per-SKU differences between marketplace stock and the inventory service';
+COMMENT ON COLUMN marketplace.inventory_discrepancies.expected_quantity IS 'Inventory service stock after warehouse mappings are applied';
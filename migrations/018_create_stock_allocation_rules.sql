@@ -0,0 +1,28 @@
+-- Stock Allocation Rules Table
+-- Per-connection rules that limit how much of the available stock each marketplace receives
+
+CREATE TABLE IF NOT EXISTS marketplace.stock_allocation_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    connection_id UUID NOT NULL REFERENCES marketplace.connections(id) ON DELETE CASCADE,
+    internal_category_id UUID, -- NULL for the connection default rule
+    buffer_quantity INTEGER NOT NULL DEFAULT 0,
+    allocation_percent DECIMAL(7, 4) DEFAULT 100,
+    max_quantity INTEGER, -- NULL for no cap
+    sold_out_threshold INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_stock_allocation_rules_connection ON marketplace.stock_allocation_rules(connection_id);
+CREATE UNIQUE INDEX idx_stock_allocation_rules_connection_default
+    ON marketplace.stock_allocation_rules(connection_id) WHERE internal_category_id IS NULL;
+CREATE UNIQUE INDEX idx_stock_allocation_rules_connection_category
+    ON marketplace.stock_allocation_rules(connection_id, internal_category_id) WHERE internal_category_id IS NOT NULL;
+
+-- Apply update trigger
+CREATE TRIGGER update_stock_allocation_rules_updated_at
+    BEFORE UPDATE ON marketplace.stock_allocation_rules
+    FOR EACH ROW EXECUTE FUNCTION marketplace.update_updated_at_column();
+
+COMMENT ON TABLE marketplace.stock_allocation_rules IS 'Per-connection stock allocation rules (safety buffer, percentage, cap, sold-out threshold) with per-category overrides';
@@ -0,0 +1,22 @@
+-- Inventory Sync Logs
+-- Records what triggered each stock push and which listing or variant it went to
+
+ALTER TABLE marketplace.inventory_sync_logs
+    ADD COLUMN IF NOT EXISTS external_product_id VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS external_variant_id VARCHAR(100) NOT NULL DEFAULT '', -- Empty for the whole listing
+    ADD COLUMN IF NOT EXISTS source VARCHAR(30) NOT NULL DEFAULT 'event'; -- event, manual, reconciliation, product_push, drift
+
+-- Manual pushes may target listings without a product mapping
+ALTER TABLE marketplace.inventory_sync_logs ALTER COLUMN internal_product_id DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_inventory_logs_connection_created
+    ON marketplace.inventory_sync_logs(connection_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_inventory_logs_mapping_created
+    ON marketplace.inventory_sync_logs(product_mapping_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_inventory_logs_product_created
+    ON marketplace.inventory_sync_logs(internal_product_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_inventory_logs_listing
+    ON marketplace.inventory_sync_logs(connection_id, external_product_id, external_variant_id, created_at DESC);
+
+COMMENT ON COLUMN marketplace.inventory_sync_logs.source IS 'What triggered the push: event, manual, reconciliation, product_push or drift';
+COMMENT ON COLUMN marketplace.inventory_sync_logs.previous_quantity IS 'Last quantity pushed to the listing, or the marketplace stock seen by reconciliation';
@@ -0,0 +1,21 @@
+-- Stock Reservations Table
+-- Stock sold on one marketplace, held back from every channel until the inventory service catches up
+
+CREATE TABLE IF NOT EXISTS marketplace.stock_reservations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    connection_id UUID NOT NULL REFERENCES marketplace.connections(id) ON DELETE CASCADE, -- Connection the order was placed on
+    external_order_id VARCHAR(100) NOT NULL,
+    internal_product_id UUID NOT NULL,
+    product_mapping_id UUID NOT NULL REFERENCES marketplace.product_mappings(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, released
+    release_reason VARCHAR(30), -- stock_event, cancelled
+    released_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(connection_id, external_order_id, internal_product_id)
+);
+
+CREATE INDEX idx_stock_reservations_pending
+    ON marketplace.stock_reservations(internal_product_id, created_at) WHERE status = 'pending';
+
+COMMENT ON TABLE marketplace.stock_reservations IS 'Stock of new marketplace orders held back from other channels until the inventory service reports it';
@@ -0,0 +1,17 @@
+-- Order Sync Watermarks Table
+-- How far the order poller has synced each connection's orders by update time
+
+CREATE TABLE IF NOT EXISTS marketplace.order_sync_watermarks (
+    connection_id UUID PRIMARY KEY REFERENCES marketplace.connections(id) ON DELETE CASCADE,
+    synced_to TIMESTAMP WITH TIME ZONE NOT NULL, -- Orders updated before this time are synced
+    last_polled_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TRIGGER update_order_sync_watermarks_updated_at
+    BEFORE UPDATE ON marketplace.order_sync_watermarks
+    FOR EACH ROW
+    EXECUTE FUNCTION marketplace.update_updated_at_column();
+
+COMMENT ON TABLE marketplace.order_sync_watermarks IS 'Last order update time synced by the order poller, per connection';
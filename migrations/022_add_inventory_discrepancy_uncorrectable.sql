@@ -0,0 +1,5 @@
+-- Inventory Discrepancies: uncorrectable reason
+-- Discrepancies auto-correct cannot fix, e.g. multi-SKU listings without variant mappings
+
+ALTER TABLE marketplace.inventory_discrepancies
+    ADD COLUMN IF NOT EXISTS uncorrectable TEXT; -- Why auto-correct cannot push the expected stock
@@ -0,0 +1,5 @@
+-- Order Sync Watermarks: failed orders
+-- Orders the poller failed to import; the watermark moves past them and they are retried by ID
+
+ALTER TABLE marketplace.order_sync_watermarks
+    ADD COLUMN IF NOT EXISTS failed_order_ids JSONB DEFAULT '[]'; -- External IDs of orders whose import failed
@@ -0,0 +1,17 @@
+-- Brand Mappings: case-insensitive internal brands
+-- Brands are looked up regardless of case, so "Nike" and "NIKE" must share one mapping
+
+-- Keep one mapping per brand, preferring confirmed ones, then the most recently updated
+DELETE FROM marketplace.brand_mappings a
+USING marketplace.brand_mappings b
+WHERE a.connection_id = b.connection_id
+  AND LOWER(a.internal_brand) = LOWER(b.internal_brand)
+  AND a.id <> b.id
+  AND ((a.status = 'confirmed') < (b.status = 'confirmed')
+    OR ((a.status = 'confirmed') = (b.status = 'confirmed') AND (a.updated_at, a.id) < (b.updated_at, b.id)));
+
+ALTER TABLE marketplace.brand_mappings
+    DROP CONSTRAINT IF EXISTS unique_connection_internal_brand;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_brand_mappings_connection_brand
+    ON marketplace.brand_mappings(connection_id, LOWER(internal_brand));
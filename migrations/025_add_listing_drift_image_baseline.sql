@@ -0,0 +1,5 @@
+-- Listing Drifts: image baseline
+-- Normalized image URLs of the catalog and the listing as of the last check that found them matching
+
+ALTER TABLE marketplace.listing_drifts
+    ADD COLUMN IF NOT EXISTS image_baseline JSONB; -- {"catalog": [...], "marketplace": [...]}, NULL until images first match
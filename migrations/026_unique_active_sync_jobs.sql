@@ -0,0 +1,21 @@
+-- Sync Jobs: one active job per connection for exclusive job types
+-- Imports, backfills, reconciliations and drift checks must not run twice at once for a
+-- connection, which a check before inserting cannot guarantee under concurrent requests
+
+-- Fail all but the most recent active job of each connection and type
+UPDATE marketplace.sync_jobs a
+SET status = 'failed',
+    error_message = 'superseded by a concurrent job of the same type'
+FROM marketplace.sync_jobs b
+WHERE a.connection_id = b.connection_id
+  AND a.job_type = b.job_type
+  AND a.id <> b.id
+  AND a.status IN ('pending', 'processing')
+  AND b.status IN ('pending', 'processing')
+  AND a.job_type IN ('product_import', 'mapping_import', 'drift_check', 'inventory_reconcile', 'order_backfill')
+  AND (a.created_at, a.id) < (b.created_at, b.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sync_jobs_active_exclusive
+    ON marketplace.sync_jobs(connection_id, job_type)
+    WHERE status IN ('pending', 'processing')
+      AND job_type IN ('product_import', 'mapping_import', 'drift_check', 'inventory_reconcile', 'order_backfill');